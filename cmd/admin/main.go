@@ -0,0 +1,132 @@
+// Command admin runs support tasks against the gophermart database.
+//
+// Post a balance adjustment:
+//
+//	admin adjust -login alice -amount -150 -reason CORRECTION -comment "duplicate accrual"
+//
+// Grant a role, e.g. to bootstrap the first admin:
+//
+//	admin set-role -login alice -role admin
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/accounts"
+	"github.com/dkmelnik/go-musthave-diploma/internal/adjustments"
+	"github.com/dkmelnik/go-musthave-diploma/internal/adjustments/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/db/pg"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/users"
+)
+
+const usage = `usage: admin <command> [flags]
+
+commands:
+  adjust    post a signed credit or debit to a user's balance
+  set-role  change the role of a user (user, support or admin)`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(args []string) error {
+	godotenv.Load()
+
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	switch args[0] {
+	case "adjust":
+		return adjust(args[1:])
+	case "set-role":
+		return setRole(args[1:])
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+}
+
+func adjust(args []string) error {
+	fs := flag.NewFlagSet("adjust", flag.ExitOnError)
+	dsn := fs.String("d", os.Getenv("DATABASE_URI"), "string for db connect")
+	login := fs.String("login", "", "login of the user to adjust")
+	amount := fs.Float64("amount", 0, "signed amount, negative for debits")
+	reason := fs.String("reason", "", "reason code")
+	comment := fs.String("comment", "", "free-text comment")
+	operator := fs.String("operator", os.Getenv("USER"), "operator identity recorded with the adjustment")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := pg.NewConnection(*dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	userRepository := users.NewRepository(db)
+
+	user, err := userRepository.FindOneByLogin(ctx, *login)
+	if err != nil {
+		return fmt.Errorf("find user %q: %w", *login, err)
+	}
+
+	service := adjustments.NewService(userRepository, adjustments.NewRepository(db))
+	out, err := service.Post(ctx, *operator, user.ID, dto.AdjustmentPayload{
+		Amount:  *amount,
+		Reason:  *reason,
+		Comment: *comment,
+	})
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func setRole(args []string) error {
+	fs := flag.NewFlagSet("set-role", flag.ExitOnError)
+	dsn := fs.String("d", os.Getenv("DATABASE_URI"), "string for db connect")
+	login := fs.String("login", "", "login of the user")
+	role := fs.String("role", "", "new role: user, support or admin")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !models.IsRole(*role) {
+		return fmt.Errorf("unknown role %q", *role)
+	}
+
+	db, err := pg.NewConnection(*dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	user, err := users.NewRepository(db).FindOneByLogin(ctx, *login)
+	if err != nil {
+		return fmt.Errorf("find user %q: %w", *login, err)
+	}
+
+	if err = accounts.NewRepository(db).SetRole(ctx, user.ID, *role); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is now %s, effective from the next login or token refresh\n", *login, *role)
+	return nil
+}
@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/gofiber/fiber/v2"
 	fiberlogger "github.com/gofiber/fiber/v2/middleware/logger"
@@ -19,14 +23,36 @@ import (
 	"github.com/joho/godotenv"
 
 	"github.com/dkmelnik/go-musthave-diploma/configs"
+	"github.com/dkmelnik/go-musthave-diploma/internal/accounts"
+	"github.com/dkmelnik/go-musthave-diploma/internal/adjustments"
+	"github.com/dkmelnik/go-musthave-diploma/internal/apikeys"
 	"github.com/dkmelnik/go-musthave-diploma/internal/balance"
+	"github.com/dkmelnik/go-musthave-diploma/internal/credentials"
 	"github.com/dkmelnik/go-musthave-diploma/internal/db/pg"
+	"github.com/dkmelnik/go-musthave-diploma/internal/hasher"
 	"github.com/dkmelnik/go-musthave-diploma/internal/jwt"
+	"github.com/dkmelnik/go-musthave-diploma/internal/ledger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/lockout"
 	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/magiclinks"
+	"github.com/dkmelnik/go-musthave-diploma/internal/notify"
+	"github.com/dkmelnik/go-musthave-diploma/internal/oidc"
 	"github.com/dkmelnik/go-musthave-diploma/internal/orders"
+	"github.com/dkmelnik/go-musthave-diploma/internal/passwords"
+	"github.com/dkmelnik/go-musthave-diploma/internal/privacy"
+	"github.com/dkmelnik/go-musthave-diploma/internal/profiles"
+	"github.com/dkmelnik/go-musthave-diploma/internal/promos"
+	"github.com/dkmelnik/go-musthave-diploma/internal/referrals"
+	"github.com/dkmelnik/go-musthave-diploma/internal/sealer"
 	"github.com/dkmelnik/go-musthave-diploma/internal/server"
+	"github.com/dkmelnik/go-musthave-diploma/internal/statements"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tiers"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens"
+	"github.com/dkmelnik/go-musthave-diploma/internal/transfers"
+	"github.com/dkmelnik/go-musthave-diploma/internal/twofactor"
 	"github.com/dkmelnik/go-musthave-diploma/internal/users"
 	"github.com/dkmelnik/go-musthave-diploma/internal/withdrawals"
+	"github.com/dkmelnik/go-musthave-diploma/internal/withdrawals/policy"
 )
 
 func main() {
@@ -45,6 +71,9 @@ func run() error {
 
 	// LOGGER -----------------------
 	logger.Setup(conf.LogLevel, os.Stdout)
+	if conf.JWTExp > 0 {
+		logger.Log.Warn("run", "JWT_EXP", "deprecated, use JWT_ACCESS_TTL", "accessTTL", conf.JWTAccessTTL)
+	}
 	// LOGGER -----------------------
 
 	// PG -----------------------
@@ -58,40 +87,324 @@ func run() error {
 	// PG -----------------------
 
 	// SERVER -----------------------
-	srv := server.NewServer(conf.ServerAddr)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+	defer stop()
+
+	srv := server.NewServer(conf.ServerAddr, conf.ProxyHeader, conf.TrustedProxies)
 
-	if err = setupRouting(conf, srv.GetApp(), pgConnection); err != nil {
+	if err = setupRouting(ctx, conf, srv.GetApp(), pgConnection); err != nil {
 		return err
 	}
 
 	return srv.Run()
 }
 
-func setupRouting(conf configs.Server, s *fiber.App, db *sql.DB) error {
+// setupRouting wires the API; background jobs that need a clean stop run
+// until ctx is done.
+func setupRouting(ctx context.Context, conf configs.Server, s *fiber.App, db *sql.DB) error {
 	api := s.Group("/api/user")
 	api.Use(requestid.New())
 	api.Use(fiberlogger.New())
 	api.Use(recover.New())
-	exp := time.Duration(conf.JWTExp) * time.Hour
+
+	adminAPI := s.Group("/api/admin")
+	adminAPI.Use(requestid.New())
+	adminAPI.Use(fiberlogger.New())
+	adminAPI.Use(recover.New())
+
+	withdrawalRules := policy.Rules{
+		MinAmount:   conf.WithdrawMinAmount,
+		MaxAmount:   conf.WithdrawMaxAmount,
+		DailyCap:    conf.WithdrawDailyCap,
+		MonthlyCap:  conf.WithdrawMonthlyCap,
+		Increment:   conf.WithdrawIncrement,
+		LargeAmount: conf.WithdrawLargeAmount,
+		Cooldown:    policy.Duration(conf.WithdrawCooldown),
+	}
+	if conf.WithdrawPolicyFile != "" {
+		rules, err := policy.Load(conf.WithdrawPolicyFile, withdrawalRules)
+		if err != nil {
+			return err
+		}
+		withdrawalRules = rules
+	}
+
+	tiersConf := tiers.DefaultConfig(conf.TierWindow)
+	if conf.TiersFile != "" {
+		tc, err := tiers.LoadConfig(conf.TiersFile, conf.TierWindow)
+		if err != nil {
+			return err
+		}
+		tiersConf = tc
+	}
+
+	passwordHasher, err := hasher.New(hasher.Config{
+		Algorithm:  conf.PasswordHash,
+		Memory:     conf.PasswordArgon2Memory,
+		Time:       conf.PasswordArgon2Time,
+		Threads:    conf.PasswordArgon2Threads,
+		BcryptCost: conf.PasswordBcryptCost,
+	})
+	if err != nil {
+		return err
+	}
+
+	credentialsConf := credentials.Config{
+		LoginMinLength:     conf.LoginMinLength,
+		LoginMaxLength:     conf.LoginMaxLength,
+		LoginCharset:       conf.LoginCharset,
+		PasswordMinLength:  conf.PasswordMinLength,
+		PasswordMaxLength:  conf.PasswordMaxLength,
+		PasswordMinClasses: conf.PasswordMinClasses,
+		BanCommon:          conf.PasswordBanCommon,
+	}
+	if conf.PasswordHash == hasher.AlgBcrypt {
+		// bcrypt rejects longer input
+		credentialsConf.PasswordMaxBytes = 72
+	}
+	credentialsPolicy, err := credentials.New(credentialsConf)
+	if err != nil {
+		return err
+	}
+
+	notifier, err := notify.New(conf.Notifier, conf.NotifierFile, db)
+	if err != nil {
+		return err
+	}
+
+	tokenTransport, err := tokens.NewTransport(tokens.CookieConfig{
+		SameSite:       conf.CookieSameSite,
+		HTTPOnly:       conf.CookieHTTPOnly,
+		Secure:         conf.CookieSecure,
+		CSRF:           conf.CSRFMode,
+		TrustedOrigins: conf.CSRFTrustedOrigins,
+	})
+	if err != nil {
+		return err
+	}
+
+	secretSealer, err := newSealer(conf)
+	if err != nil {
+		return err
+	}
 
 	// repositories
 	userRepository := users.NewRepository(db)
 	orderRepository := orders.NewRepository(db)
 	withdrawalRepository := withdrawals.NewRepository(db)
+	entryRepository := ledger.NewRepository(db)
+	transferRepository := transfers.NewRepository(db)
+	tierRepository := tiers.NewRepository(db)
+	promoRepository := promos.NewRepository(db)
+	referralRepository := referrals.NewRepository(db)
+	statementRepository := statements.NewRepository(db)
+	adjustmentRepository := adjustments.NewRepository(db)
+	tokenRepository := tokens.NewRepository(db)
+	passwordRepository := passwords.NewRepository(db)
+	oidcRepository := oidc.NewRepository(db)
+	attemptRepository := lockout.NewRepository(db)
+	twoFactorRepository := twofactor.NewRepository(db)
 
 	//infrastructure services
-	jwtService := jwt.NewJwt(conf.JWTSecret, exp)
-	userMiddleware := users.NewMiddlewareManager(jwtService)
-	balanceService := balance.NewService(withdrawalRepository, orderRepository)
+	signingKeys, err := newSigningKeys(ctx, conf, db, secretSealer)
+	if err != nil {
+		return err
+	}
+	jwtService := jwt.NewJwt(signingKeys, conf.JWTAccessTTL)
+	denylist := tokens.NewDenylist(conf.TokenDenylistCacheTTL, conf.TokenDenylistCacheSize, tokenRepository)
+	denylist.CollectGarbage(ctx, conf.TokenGCInterval)
+	tokenService := tokens.NewService(
+		conf.JWTAccessTTL,
+		conf.JWTRefreshTTL,
+		jwtService,
+		tokenRepository,
+		tokenRepository,
+		tokenRepository,
+		denylist,
+	)
+	sessionTracker := tokens.NewSessionTracker(time.Minute, tokenRepository)
+	apiKeyService := apikeys.NewService(conf.APIKeyTTL, apikeys.NewRepository(db))
+	userMiddleware := users.NewMiddlewareManager(jwtService, denylist, sessionTracker, apiKeyService, tokenTransport)
+	loginGuard := lockout.NewGuard(lockout.Config{
+		LoginMaxFailures: conf.LoginMaxFailures,
+		IPMaxFailures:    conf.LoginIPMaxFailures,
+		Window:           conf.LoginFailureWindow,
+		BaseDelay:        conf.LoginBaseDelay,
+		MaxDelay:         conf.LoginMaxDelay,
+		Lockout:          conf.LoginLockout,
+	}, attemptRepository)
+	loginGuard.CollectGarbage(ctx, conf.TokenGCInterval)
+	twoFactorService := twofactor.NewService(
+		conf.TOTPIssuer,
+		conf.TwoFactorChallengeTTL,
+		secretSealer,
+		twoFactorRepository,
+		tokenService,
+		loginGuard,
+	)
+	twoFactorService.CollectGarbage(ctx, conf.TokenGCInterval)
+	balanceService := balance.NewService(withdrawalRepository, orderRepository, entryRepository)
+	tierService := tiers.NewService(tiersConf, tierRepository)
+	referralService := referrals.NewService(conf.ReferrerBonus, conf.RefereeBonus, referralRepository)
 
-	users.SetupRouter(api, exp, jwtService, userRepository)
-	orders.SetupRouter(api, conf.AccrualAddr, userMiddleware, orderRepository)
-	withdrawals.SetupRouter(api, userMiddleware, balanceService, withdrawalRepository)
+	users.SetupRouter(
+		api,
+		tokenTransport,
+		credentialsPolicy,
+		passwordHasher,
+		tokenService,
+		loginGuard,
+		twoFactorService,
+		userRepository,
+	)
+	twofactor.SetupRouter(api, userMiddleware, tokenTransport, twoFactorService)
+	if conf.OIDCIssuer != "" {
+		oidcService := oidc.NewService(
+			oidc.Config{
+				StateTTL:    conf.OIDCStateTTL,
+				StatesPerIP: conf.OIDCStatesPerIP,
+				ReauthTTL:   conf.OIDCReauthTTL,
+			},
+			oidc.NewProvider(oidc.ProviderConfig{
+				Issuer:       conf.OIDCIssuer,
+				ClientID:     conf.OIDCClientID,
+				ClientSecret: conf.OIDCClientSecret,
+				RedirectURL:  conf.OIDCRedirectURL,
+				Scopes:       conf.OIDCScopes,
+			}),
+			oidcRepository,
+			credentialsPolicy,
+			tokenService,
+			twoFactorService,
+		)
+		oidcService.CollectGarbage(ctx, conf.TokenGCInterval)
+		oidc.SetupRouter(api, userMiddleware, tokenTransport, oidcService)
+	}
+	magiclinks.SetupRouter(api, tokenTransport, conf.MagicLinkRedirectURL, magiclinks.NewService(
+		magiclinks.Config{
+			URL:          conf.MagicLinkURL,
+			TTL:          conf.MagicLinkTTL,
+			MaxPerWindow: conf.MagicLinkMaxPerWindow,
+			Window:       conf.MagicLinkWindow,
+			RequestTime:  conf.MagicLinkRequestTime,
+		},
+		magiclinks.NewRepository(db),
+		tokenService,
+		twoFactorService,
+		notifier,
+	))
+	tokens.SetupRouter(api, userMiddleware, tokenTransport, tokenService)
+	apikeys.SetupRouter(api, userMiddleware, apiKeyService)
+	profiles.SetupRouter(api, userMiddleware, profiles.NewService(
+		conf.VerificationCodeTTL,
+		conf.VerificationMaxAttempts,
+		profiles.NewRepository(db),
+		notifier,
+	))
+	privacy.SetupRouter(api, userMiddleware, tokenTransport, privacy.NewService(
+		passwordHasher,
+		privacy.NewRepository(db),
+		orderRepository,
+		withdrawalRepository,
+		tokenRepository,
+		tokenService,
+		oidcRepository,
+		loginGuard,
+	))
+	passwords.SetupRouter(
+		api,
+		credentialsPolicy,
+		passwordHasher,
+		passwords.ResetConfig{
+			TTL:          conf.PasswordResetTTL,
+			MaxPerWindow: conf.PasswordResetMaxPerWindow,
+			Window:       conf.PasswordResetWindow,
+		},
+		userMiddleware,
+		userRepository,
+		passwordRepository,
+		tokenService,
+		oidcRepository,
+		notifier,
+	)
+	orders.SetupRouter(
+		api,
+		conf.AccrualAddr,
+		userMiddleware,
+		orderRepository,
+		tierService,
+		referralService,
+	)
+	orders.WatchRevisions(
+		ctx,
+		conf.AccrualAddr,
+		conf.AccrualRevisionWindow,
+		conf.AccrualRevisionInterval,
+		orderRepository,
+		tierService,
+		referralService,
+	)
+	withdrawals.SetupRouter(api, userMiddleware, policy.New(withdrawalRules), withdrawalRepository)
 	balance.SetupRouter(api, userMiddleware, balanceService)
+	transfers.SetupRouter(
+		api,
+		conf.TransferMinAmount,
+		conf.TransferDailyLimit,
+		userMiddleware,
+		userRepository,
+		transferRepository,
+	)
+	tiers.SetupRouter(api, userMiddleware, tierService)
+	promos.SetupRouter(api, adminAPI, userMiddleware, promoRepository)
+	referrals.SetupRouter(api, userMiddleware, referralService)
+	statements.SetupRouter(api, userMiddleware, statementRepository)
+	adjustments.SetupRouter(adminAPI, userMiddleware, userRepository, adjustmentRepository)
+	lockout.SetupRouter(adminAPI, userMiddleware, loginGuard)
+	accounts.SetupRouter(adminAPI, userMiddleware, accounts.NewService(
+		accounts.NewRepository(db),
+		orderRepository,
+		withdrawalRepository,
+		balanceService,
+		tokenService,
+	))
+	jwt.SetupRouter(s, jwtService)
 
 	return nil
 }
 
+// newSigningKeys builds the JWT keys of conf.JWTAlg. Rotated keys are loaded
+// before serving and refreshed in the background until ctx is done.
+func newSigningKeys(ctx context.Context, conf configs.Server, db *sql.DB, secretSealer *sealer.Sealer) (jwt.KeySource, error) {
+	if conf.JWTAlg == jwt.AlgHS256 {
+		return jwt.NewHMACKeys(conf.JWTSecret), nil
+	}
+
+	keyring, err := jwt.NewKeyring(conf.JWTAlg, conf.JWTKeyRotation, conf.JWTKeyOverlap, jwt.NewRepository(db), secretSealer)
+	if err != nil {
+		return nil, err
+	}
+	if err = keyring.Refresh(ctx); err != nil {
+		return nil, fmt.Errorf("load signing keys: %w", err)
+	}
+	keyring.Run(ctx, time.Minute)
+
+	return keyring, nil
+}
+
+// newSealer keys the encryption of secrets at rest, e.g. TOTP secrets. The
+// key derived from the JWT secret is only allowed in development.
+func newSealer(conf configs.Server) (*sealer.Sealer, error) {
+	if conf.SecretsKey != "" {
+		return sealer.New(conf.SecretsKey)
+	}
+	if !conf.IsDev() {
+		return nil, fmt.Errorf("SECRETS_KEY must be set when APP_ENV=%s", conf.Env)
+	}
+
+	logger.Log.Warn("run", "SECRETS_KEY", "not set, deriving it from JWT_SECRET")
+	return sealer.Derive(conf.JWTSecret)
+}
+
 func migrateDB(dsn string) error {
 	m, err := migrate.New("file://internal/db/pg/migrate", dsn)
 	if err != nil {
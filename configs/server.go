@@ -1,18 +1,163 @@
 package configs
 
 import (
+	"errors"
 	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
 
+// DefaultJWTSecret signs HS256 tokens in development when JWT_SECRET is not
+// set. Validate refuses it elsewhere.
+const DefaultJWTSecret = "some_secret"
+
 type Server struct {
+	// Env is "dev" for local development, anything else, including the
+	// default, is treated as a production-like deployment.
+	Env         string `envconfig:"APP_ENV" default:"prod"`
 	ServerAddr  string `envconfig:"RUN_ADDRESS"`
 	PGUri       string `envconfig:"DATABASE_URI"`
 	AccrualAddr string `envconfig:"ACCRUAL_SYSTEM_ADDRESS"`
 	LogLevel    string `envconfig:"LOG_LEVEL" default:"debug"`
-	JWTSecret   string `envconfig:"JWT_SECRET" default:"some_secret"`
-	JWTExp      int    `envconfig:"JWT_EXP" default:"1"`
+	// The client IP is taken from ProxyHeader only for requests coming from
+	// one of TrustedProxies (IPs or CIDRs). Without trusted proxies it is
+	// the address of the connection.
+	ProxyHeader    string   `envconfig:"PROXY_HEADER" default:"X-Real-IP"`
+	TrustedProxies []string `envconfig:"TRUSTED_PROXIES"`
+	JWTSecret      string   `envconfig:"JWT_SECRET"`
+	// JWTAlg is EdDSA or RS256 with keys rotated every JWTKeyRotation and
+	// published JWTKeyOverlap ahead of and after their use, or HS256 signed
+	// with JWTSecret.
+	JWTAlg         string        `envconfig:"JWT_ALG" default:"EdDSA"`
+	JWTKeyRotation time.Duration `envconfig:"JWT_KEY_ROTATION" default:"720h"`
+	JWTKeyOverlap  time.Duration `envconfig:"JWT_KEY_OVERLAP" default:"24h"`
+	// Access tokens are short-lived, refresh tokens renew them and rotate on use.
+	JWTAccessTTL  time.Duration `envconfig:"JWT_ACCESS_TTL" default:"15m"`
+	JWTRefreshTTL time.Duration `envconfig:"JWT_REFRESH_TTL" default:"720h"`
+	// JWTExp is the access token lifetime in hours of older deployments. It
+	// is deprecated and only used when JWT_ACCESS_TTL is not set.
+	JWTExp int `envconfig:"JWT_EXP"`
+	// Revoked token lookups are cached for TokenDenylistCacheTTL, at most
+	// TokenDenylistCacheSize of them. Expired revocations and stale login
+	// counters are deleted every TokenGCInterval.
+	TokenDenylistCacheTTL  time.Duration `envconfig:"TOKEN_DENYLIST_CACHE_TTL" default:"30s"`
+	TokenDenylistCacheSize int           `envconfig:"TOKEN_DENYLIST_CACHE_SIZE" default:"100000"`
+	TokenGCInterval        time.Duration `envconfig:"TOKEN_GC_INTERVAL" default:"1h"`
+	// Flags of the access token cookie, relax them for local development only.
+	CookieSameSite string `envconfig:"COOKIE_SAMESITE" default:"Lax"`
+	CookieHTTPOnly bool   `envconfig:"COOKIE_HTTPONLY" default:"true"`
+	CookieSecure   bool   `envconfig:"COOKIE_SECURE" default:"true"`
+	// CSRFMode protects state-changing requests authenticated by the cookie:
+	// "origin" checks scheme and host of Origin/Referer against the server
+	// (behind a TLS terminating proxy list it in TrustedProxies) and
+	// CSRFTrustedOrigins, "double_submit" requires the X-CSRF-Token header to
+	// repeat the csrf_token cookie, "off" disables the check. Bearer tokens
+	// and API keys are never checked.
+	CSRFMode           string   `envconfig:"CSRF_MODE" default:"origin"`
+	CSRFTrustedOrigins []string `envconfig:"CSRF_TRUSTED_ORIGINS"`
+	// New passwords are hashed with PasswordHash, "argon2id" (memory in KiB)
+	// or "bcrypt". Hashes of the other algorithm or older parameters are
+	// upgraded on login.
+	PasswordHash          string `envconfig:"PASSWORD_HASH" default:"argon2id"`
+	PasswordArgon2Memory  uint32 `envconfig:"PASSWORD_ARGON2_MEMORY" default:"65536"`
+	PasswordArgon2Time    uint32 `envconfig:"PASSWORD_ARGON2_TIME" default:"3"`
+	PasswordArgon2Threads uint8  `envconfig:"PASSWORD_ARGON2_THREADS" default:"2"`
+	PasswordBcryptCost    int    `envconfig:"PASSWORD_BCRYPT_COST" default:"10"`
+	// Rules for new logins and passwords. LoginCharset is the body of a
+	// regexp character class; passwords mix at least PasswordMinClasses of
+	// lower case, upper case, digits and symbols and, with
+	// PasswordBanCommon, are not on the embedded list of common ones.
+	LoginMinLength     int    `envconfig:"LOGIN_MIN_LENGTH" default:"3"`
+	LoginMaxLength     int    `envconfig:"LOGIN_MAX_LENGTH" default:"64"`
+	LoginCharset       string `envconfig:"LOGIN_CHARSET" default:"a-zA-Z0-9._@+-"`
+	PasswordMinLength  int    `envconfig:"PASSWORD_MIN_LENGTH" default:"8"`
+	PasswordMaxLength  int    `envconfig:"PASSWORD_MAX_LENGTH" default:"128"`
+	PasswordMinClasses int    `envconfig:"PASSWORD_MIN_CLASSES" default:"2"`
+	PasswordBanCommon  bool   `envconfig:"PASSWORD_BAN_COMMON" default:"true"`
+	// Notifier delivers messages to users: "stdout", "file" (NotifierFile) or
+	// "outbox", the notification_outbox table.
+	Notifier     string `envconfig:"NOTIFIER" default:"stdout"`
+	NotifierFile string `envconfig:"NOTIFIER_FILE" default:"notifications.log"`
+	// Password reset tokens expire after PasswordResetTTL and are sent at
+	// most PasswordResetMaxPerWindow times per login within
+	// PasswordResetWindow.
+	PasswordResetTTL          time.Duration `envconfig:"PASSWORD_RESET_TTL" default:"30m"`
+	PasswordResetMaxPerWindow int           `envconfig:"PASSWORD_RESET_MAX_PER_WINDOW" default:"3"`
+	PasswordResetWindow       time.Duration `envconfig:"PASSWORD_RESET_WINDOW" default:"1h"`
+	// Email and phone verification codes expire after VerificationCodeTTL and
+	// burn after VerificationMaxAttempts wrong guesses.
+	VerificationCodeTTL     time.Duration `envconfig:"VERIFICATION_CODE_TTL" default:"15m"`
+	VerificationMaxAttempts int           `envconfig:"VERIFICATION_MAX_ATTEMPTS" default:"5"`
+	// Magic login links point to MagicLinkURL, work once within MagicLinkTTL
+	// and are sent at most MagicLinkMaxPerWindow times per login within
+	// MagicLinkWindow. Browsers using a link end up at MagicLinkRedirectURL,
+	// the app. Link requests take MagicLinkRequestTime whether or not a link
+	// is sent.
+	MagicLinkURL          string        `envconfig:"MAGIC_LINK_URL" default:"http://localhost:8080/api/user/login/magic"`
+	MagicLinkRedirectURL  string        `envconfig:"MAGIC_LINK_REDIRECT_URL" default:"http://localhost:8080/"`
+	MagicLinkTTL          time.Duration `envconfig:"MAGIC_LINK_TTL" default:"15m"`
+	MagicLinkMaxPerWindow int           `envconfig:"MAGIC_LINK_MAX_PER_WINDOW" default:"3"`
+	MagicLinkWindow       time.Duration `envconfig:"MAGIC_LINK_WINDOW" default:"1h"`
+	MagicLinkRequestTime  time.Duration `envconfig:"MAGIC_LINK_REQUEST_TIME" default:"500ms"`
+	// Failed logins are counted per login and per IP within LoginFailureWindow.
+	// Past half of the allowed failures attempts are delayed progressively,
+	// reaching the maximum locks out for LoginLockout.
+	LoginMaxFailures   int           `envconfig:"LOGIN_MAX_FAILURES" default:"5"`
+	LoginIPMaxFailures int           `envconfig:"LOGIN_IP_MAX_FAILURES" default:"50"`
+	LoginFailureWindow time.Duration `envconfig:"LOGIN_FAILURE_WINDOW" default:"15m"`
+	LoginBaseDelay     time.Duration `envconfig:"LOGIN_BASE_DELAY" default:"1s"`
+	LoginMaxDelay      time.Duration `envconfig:"LOGIN_MAX_DELAY" default:"30s"`
+	LoginLockout       time.Duration `envconfig:"LOGIN_LOCKOUT" default:"15m"`
+	// SecretsKey is the base64 AES-256 key for secrets stored in the database,
+	// such as TOTP secrets. It is required outside development.
+	SecretsKey            string        `envconfig:"SECRETS_KEY"`
+	TOTPIssuer            string        `envconfig:"TOTP_ISSUER" default:"Gophermart"`
+	TwoFactorChallengeTTL time.Duration `envconfig:"TWO_FACTOR_CHALLENGE_TTL" default:"5m"`
+	// Users may log in with the OpenID provider at OIDCIssuer, the login is
+	// off when it is empty. OIDCRedirectURL is the public address of
+	// /api/user/oidc/callback registered with the provider; a login must be
+	// completed within OIDCStateTTL, and one address may have at most
+	// OIDCStatesPerIP logins pending. A fresh login confirming a sensitive
+	// change of an account without a password is good for OIDCReauthTTL.
+	// Expired ones are deleted every TokenGCInterval.
+	OIDCIssuer       string        `envconfig:"OIDC_ISSUER"`
+	OIDCClientID     string        `envconfig:"OIDC_CLIENT_ID"`
+	OIDCClientSecret string        `envconfig:"OIDC_CLIENT_SECRET"`
+	OIDCRedirectURL  string        `envconfig:"OIDC_REDIRECT_URL" default:"http://localhost:8080/api/user/oidc/callback"`
+	OIDCScopes       []string      `envconfig:"OIDC_SCOPES" default:"openid,profile,email"`
+	OIDCStateTTL     time.Duration `envconfig:"OIDC_STATE_TTL" default:"10m"`
+	OIDCStatesPerIP  int           `envconfig:"OIDC_STATES_PER_IP" default:"20"`
+	OIDCReauthTTL    time.Duration `envconfig:"OIDC_REAUTH_TTL" default:"5m"`
+	// APIKeyTTL is the lifetime of API keys created without an expiry.
+	APIKeyTTL time.Duration `envconfig:"API_KEY_TTL" default:"8760h"`
+
+	TransferMinAmount  float64 `envconfig:"TRANSFER_MIN_AMOUNT" default:"1"`
+	TransferDailyLimit float64 `envconfig:"TRANSFER_DAILY_LIMIT" default:"10000"`
+
+	WithdrawMinAmount   float64       `envconfig:"WITHDRAW_MIN_AMOUNT"`
+	WithdrawMaxAmount   float64       `envconfig:"WITHDRAW_MAX_AMOUNT"`
+	WithdrawDailyCap    float64       `envconfig:"WITHDRAW_DAILY_CAP"`
+	WithdrawMonthlyCap  float64       `envconfig:"WITHDRAW_MONTHLY_CAP"`
+	WithdrawIncrement   float64       `envconfig:"WITHDRAW_INCREMENT"`
+	WithdrawLargeAmount float64       `envconfig:"WITHDRAW_LARGE_AMOUNT"`
+	WithdrawCooldown    time.Duration `envconfig:"WITHDRAW_COOLDOWN"`
+	// WithdrawPolicyFile is a JSON file overriding the WITHDRAW_* values.
+	WithdrawPolicyFile string `envconfig:"WITHDRAW_POLICY_FILE"`
+
+	TiersFile  string        `envconfig:"TIERS_FILE"`
+	TierWindow time.Duration `envconfig:"TIER_WINDOW" default:"8760h"`
+
+	ReferrerBonus float64 `envconfig:"REFERRAL_REFERRER_BONUS" default:"100"`
+	RefereeBonus  float64 `envconfig:"REFERRAL_REFEREE_BONUS" default:"50"`
+
+	// Orders processed within AccrualRevisionWindow are re-polled every
+	// AccrualRevisionInterval to pick up re-calculated accruals.
+	AccrualRevisionWindow   time.Duration `envconfig:"ACCRUAL_REVISION_WINDOW" default:"168h"`
+	AccrualRevisionInterval time.Duration `envconfig:"ACCRUAL_REVISION_INTERVAL" default:"1h"`
 }
 
 func NewServer() (Server, error) {
@@ -23,7 +168,56 @@ func NewServer() (Server, error) {
 	flag.StringVar(&cb.AccrualAddr, "r", "", "string of accrual system address")
 	flag.Parse()
 
-	err := envconfig.Process("", &cb)
+	if err := envconfig.Process("", &cb); err != nil {
+		return cb, err
+	}
+	_, accessTTLSet := os.LookupEnv("JWT_ACCESS_TTL")
+	cb.applyJWTExp(accessTTLSet)
+	if cb.IsDev() && cb.JWTSecret == "" {
+		cb.JWTSecret = DefaultJWTSecret
+	}
+
+	return cb, cb.Validate()
+}
+
+// applyJWTExp keeps deployments configured with JWT_EXP on their access
+// token lifetime until they move to JWT_ACCESS_TTL.
+func (s *Server) applyJWTExp(accessTTLSet bool) {
+	if s.JWTExp > 0 && !accessTTLSet {
+		s.JWTAccessTTL = time.Duration(s.JWTExp) * time.Hour
+	}
+}
+
+// IsDev reports whether the server runs in development mode.
+func (s Server) IsDev() bool {
+	return s.Env == "dev"
+}
+
+// Validate refuses settings that are unsafe outside development.
+func (s Server) Validate() error {
+	if s.JWTAlg == "HS256" && !s.IsDev() && (s.JWTSecret == "" || s.JWTSecret == DefaultJWTSecret) {
+		return fmt.Errorf("JWT_SECRET must be set to a non-default value for HS256 when APP_ENV=%s", s.Env)
+	}
+	if s.JWTAlg != "HS256" && s.JWTKeyOverlap < s.JWTAccessTTL {
+		return errors.New("JWT_KEY_OVERLAP must not be shorter than JWT_ACCESS_TTL")
+	}
+	if u, err := url.Parse(s.MagicLinkURL); err != nil || u.Scheme == "" || u.Host == "" || u.RawQuery != "" {
+		return errors.New("MAGIC_LINK_URL must be an absolute URL without a query")
+	}
+	if u, err := url.Parse(s.MagicLinkRedirectURL); err != nil || u.Scheme == "" || u.Host == "" || u.Fragment != "" {
+		return errors.New("MAGIC_LINK_REDIRECT_URL must be an absolute URL without a fragment")
+	}
+	if s.OIDCIssuer != "" {
+		if s.OIDCClientID == "" {
+			return errors.New("OIDC_CLIENT_ID must be set with OIDC_ISSUER")
+		}
+		if u, err := url.Parse(s.OIDCRedirectURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("OIDC_REDIRECT_URL must be an absolute URL")
+		}
+		if s.OIDCStatesPerIP < 1 {
+			return errors.New("OIDC_STATES_PER_IP must be at least 1")
+		}
+	}
 
-	return cb, err
+	return nil
 }
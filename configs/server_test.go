@@ -0,0 +1,74 @@
+package configs
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_applyJWTExp(t *testing.T) {
+	tests := []struct {
+		name         string
+		jwtExp       int
+		accessTTLSet bool
+		want         time.Duration
+	}{
+		{name: "positive test #1, JWT_EXP alone sets the access TTL", jwtExp: 2, want: 2 * time.Hour},
+		{name: "positive test #2, JWT_ACCESS_TTL wins over JWT_EXP", jwtExp: 2, accessTTLSet: true, want: 15 * time.Minute},
+		{name: "positive test #3, neither is set", want: 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Server{JWTAccessTTL: 15 * time.Minute, JWTExp: tt.jwtExp}
+			s.applyJWTExp(tt.accessTTLSet)
+			assert.Equal(t, tt.want, s.JWTAccessTTL)
+		})
+	}
+}
+
+func TestServer_defaultEnv(t *testing.T) {
+	// Setenv restores the variable after the test
+	t.Setenv("APP_ENV", "")
+	require.NoError(t, os.Unsetenv("APP_ENV"))
+
+	var s Server
+	require.NoError(t, envconfig.Process("", &s))
+
+	assert.Equal(t, "prod", s.Env)
+	assert.False(t, s.IsDev())
+	assert.Empty(t, s.JWTSecret)
+}
+
+func TestServer_ValidateJWTSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		alg     string
+		secret  string
+		wantErr bool
+	}{
+		{name: "positive test #1, asymmetric keys need no secret", env: "prod", alg: "EdDSA"},
+		{name: "positive test #2, HS256 with a real secret", env: "prod", alg: "HS256", secret: "s3cr3t-from-vault"},
+		{name: "positive test #3, HS256 with the default secret in dev", env: "dev", alg: "HS256", secret: DefaultJWTSecret},
+		{name: "negative test #4, HS256 without a secret", env: "prod", alg: "HS256", wantErr: true},
+		{name: "negative test #5, HS256 with the default secret", env: "prod", alg: "HS256", secret: DefaultJWTSecret, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Server
+			require.NoError(t, envconfig.Process("", &s))
+			s.Env, s.JWTAlg, s.JWTSecret = tt.env, tt.alg, tt.secret
+
+			err := s.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type (
+	AccountResponse struct {
+		ID         string     `json:"id"`
+		Login      string     `json:"login"`
+		Role       string     `json:"role"`
+		DisabledAt *time.Time `json:"disabled_at,omitempty"`
+		CreatedAt  time.Time  `json:"created_at"`
+	}
+	SearchQuery struct {
+		Query  string `query:"q"`
+		Limit  int    `query:"limit"`
+		Offset int    `query:"offset"`
+	}
+	RolePayload struct {
+		Role string `json:"role"`
+	}
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+func (q *SearchQuery) Normalize() {
+	if q.Limit <= 0 {
+		q.Limit = DefaultLimit
+	}
+	if q.Limit > MaxLimit {
+		q.Limit = MaxLimit
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+}
+
+func (r *RolePayload) Validate() error {
+	if !models.IsRole(r.Role) {
+		return fmt.Errorf("role must be one of %s, %s, %s", models.RoleUser, models.RoleSupport, models.RoleAdmin)
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/accounts/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	ordersdto "github.com/dkmelnik/go-musthave-diploma/internal/orders/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+	withdrawalsdto "github.com/dkmelnik/go-musthave-diploma/internal/withdrawals/dto"
+)
+
+type (
+	accountService interface {
+		Search(ctx context.Context, q dto.SearchQuery) ([]dto.AccountResponse, error)
+		Get(ctx context.Context, id models.ModelID) (dto.AccountResponse, error)
+		Orders(ctx context.Context, id models.ModelID) ([]ordersdto.OrderResponse, error)
+		Withdrawals(ctx context.Context, id models.ModelID) ([]withdrawalsdto.WithdrawalResponse, error)
+		Balance(ctx context.Context, id models.ModelID) (appdto.Balance, error)
+		Disable(ctx context.Context, actorID, id models.ModelID) error
+		Enable(ctx context.Context, actorID, id models.ModelID) error
+		SetRole(ctx context.Context, actorID, id models.ModelID, d dto.RolePayload) error
+	}
+	handler struct {
+		service accountService
+	}
+)
+
+func newHandler(as accountService) *handler {
+	return &handler{as}
+}
+
+func (h *handler) search(c *fiber.Ctx) error {
+	var q dto.SearchQuery
+	if err := c.QueryParser(&q); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(http.StatusText(fiber.StatusBadRequest))
+	}
+
+	out, err := h.service.Search(c.Context(), q)
+	if err != nil {
+		return h.fail(c, "accounts:handler:search", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(out)
+}
+
+func (h *handler) get(c *fiber.Ctx) error {
+	id := models.ModelID(c.Params("id"))
+	if !utils.IsGUID(string(id)) {
+		return c.Status(fiber.StatusNotFound).SendString(http.StatusText(fiber.StatusNotFound))
+	}
+
+	out, err := h.service.Get(c.Context(), id)
+	if err != nil {
+		return h.fail(c, "accounts:handler:get", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(out)
+}
+
+func (h *handler) orders(c *fiber.Ctx) error {
+	id := models.ModelID(c.Params("id"))
+	if !utils.IsGUID(string(id)) {
+		return c.Status(fiber.StatusNotFound).SendString(http.StatusText(fiber.StatusNotFound))
+	}
+
+	out, err := h.service.Orders(c.Context(), id)
+	if err != nil {
+		return h.fail(c, "accounts:handler:orders", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(out)
+}
+
+func (h *handler) withdrawals(c *fiber.Ctx) error {
+	id := models.ModelID(c.Params("id"))
+	if !utils.IsGUID(string(id)) {
+		return c.Status(fiber.StatusNotFound).SendString(http.StatusText(fiber.StatusNotFound))
+	}
+
+	out, err := h.service.Withdrawals(c.Context(), id)
+	if err != nil {
+		return h.fail(c, "accounts:handler:withdrawals", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(out)
+}
+
+func (h *handler) balance(c *fiber.Ctx) error {
+	id := models.ModelID(c.Params("id"))
+	if !utils.IsGUID(string(id)) {
+		return c.Status(fiber.StatusNotFound).SendString(http.StatusText(fiber.StatusNotFound))
+	}
+
+	out, err := h.service.Balance(c.Context(), id)
+	if err != nil {
+		return h.fail(c, "accounts:handler:balance", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(out)
+}
+
+func (h *handler) disable(c *fiber.Ctx) error {
+	id := models.ModelID(c.Params("id"))
+	if !utils.IsGUID(string(id)) {
+		return c.Status(fiber.StatusNotFound).SendString(http.StatusText(fiber.StatusNotFound))
+	}
+
+	actorID, _ := c.Locals("user_id").(string)
+	if err := h.service.Disable(c.Context(), models.ModelID(actorID), id); err != nil {
+		return h.fail(c, "accounts:handler:disable", err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *handler) enable(c *fiber.Ctx) error {
+	id := models.ModelID(c.Params("id"))
+	if !utils.IsGUID(string(id)) {
+		return c.Status(fiber.StatusNotFound).SendString(http.StatusText(fiber.StatusNotFound))
+	}
+
+	actorID, _ := c.Locals("user_id").(string)
+	if err := h.service.Enable(c.Context(), models.ModelID(actorID), id); err != nil {
+		return h.fail(c, "accounts:handler:enable", err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *handler) setRole(c *fiber.Ctx) error {
+	id := models.ModelID(c.Params("id"))
+	if !utils.IsGUID(string(id)) {
+		return c.Status(fiber.StatusNotFound).SendString(http.StatusText(fiber.StatusNotFound))
+	}
+
+	actorID, _ := c.Locals("user_id").(string)
+
+	var body dto.RolePayload
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	}
+	if err := body.Validate(); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(err.Error())
+	}
+
+	if err := h.service.SetRole(c.Context(), models.ModelID(actorID), id, body); err != nil {
+		return h.fail(c, "accounts:handler:setRole", err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *handler) fail(c *fiber.Ctx, op string, err error) error {
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).SendString(http.StatusText(fiber.StatusNotFound))
+	case errors.Is(err, apperrors.ErrInvalidRecipient):
+		return c.Status(fiber.StatusUnprocessableEntity).SendString("admins can't change their own account")
+	default:
+		logger.Log.Error(op, "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+}
@@ -0,0 +1,191 @@
+package accounts
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/accounts/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/accounts/mocks"
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+const (
+	testAdminID = "7f1c7c8e-2a8c-4c39-9d7e-0c5a5d8f1a11"
+	testUserID  = "0b6f3a52-6d0e-4f0b-8a5e-3c1d2e4f5a6b"
+)
+
+func newTestApp(as accountService) *fiber.App {
+	app := fiber.New()
+	h := newHandler(as)
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", testAdminID)
+		return c.Next()
+	})
+	app.Post("/users/:id/disable", h.disable)
+	app.Post("/users/:id/enable", h.enable)
+	app.Put("/users/:id/role", h.setRole)
+	return app
+}
+
+func Test_disable(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		service bool
+		err     error
+		code    int
+	}{
+		{
+			name: "negative test #1, malformed id",
+			id:   "user",
+			code: http.StatusNotFound,
+		},
+		{
+			name:    "negative test #2, unknown account",
+			id:      testUserID,
+			service: true,
+			err:     apperrors.ErrNotFound,
+			code:    http.StatusNotFound,
+		},
+		{
+			name:    "negative test #3, own account",
+			id:      testAdminID,
+			service: true,
+			err:     apperrors.ErrInvalidRecipient,
+			code:    http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "negative test #4, unknown service error",
+			id:      testUserID,
+			service: true,
+			err:     errors.New("db is down"),
+			code:    http.StatusInternalServerError,
+		},
+		{
+			name:    "positive test #5, disabled",
+			id:      testUserID,
+			service: true,
+			code:    http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			as := mocks.NewMockaccountService(ctrl)
+			if tt.service {
+				as.EXPECT().Disable(gomock.Any(), models.ModelID(testAdminID), models.ModelID(tt.id)).Return(tt.err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/users/"+tt.id+"/disable", nil)
+			resp, err := newTestApp(as).Test(req, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+
+func Test_enable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{
+			name: "negative test #1, unknown account",
+			err:  apperrors.ErrNotFound,
+			code: http.StatusNotFound,
+		},
+		{
+			name: "positive test #2, enabled",
+			code: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			as := mocks.NewMockaccountService(ctrl)
+			as.EXPECT().Enable(gomock.Any(), models.ModelID(testAdminID), models.ModelID(testUserID)).Return(tt.err)
+
+			req := httptest.NewRequest(http.MethodPost, "/users/"+testUserID+"/enable", nil)
+			resp, err := newTestApp(as).Test(req, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+
+func Test_setRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		service bool
+		err     error
+		code    int
+	}{
+		{
+			name: "negative test #1, bad entity",
+			body: `{"role":`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "negative test #2, unknown role",
+			body: `{"role":"root"}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "negative test #3, own account",
+			body:    `{"role":"support"}`,
+			service: true,
+			err:     apperrors.ErrInvalidRecipient,
+			code:    http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "positive test #4, role changed",
+			body:    `{"role":"support"}`,
+			service: true,
+			code:    http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			as := mocks.NewMockaccountService(ctrl)
+			if tt.service {
+				as.EXPECT().SetRole(gomock.Any(), models.ModelID(testAdminID), models.ModelID(testUserID), dto.RolePayload{Role: models.RoleSupport}).Return(tt.err)
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/users/"+testUserID+"/role", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := newTestApp(as).Test(req, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/dkmelnik/go-musthave-diploma/internal/accounts/dto"
+	dto0 "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	dto1 "github.com/dkmelnik/go-musthave-diploma/internal/orders/dto"
+	dto2 "github.com/dkmelnik/go-musthave-diploma/internal/withdrawals/dto"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockaccountService is a mock of accountService interface.
+type MockaccountService struct {
+	ctrl     *gomock.Controller
+	recorder *MockaccountServiceMockRecorder
+}
+
+// MockaccountServiceMockRecorder is the mock recorder for MockaccountService.
+type MockaccountServiceMockRecorder struct {
+	mock *MockaccountService
+}
+
+// NewMockaccountService creates a new mock instance.
+func NewMockaccountService(ctrl *gomock.Controller) *MockaccountService {
+	mock := &MockaccountService{ctrl: ctrl}
+	mock.recorder = &MockaccountServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockaccountService) EXPECT() *MockaccountServiceMockRecorder {
+	return m.recorder
+}
+
+// Balance mocks base method.
+func (m *MockaccountService) Balance(ctx context.Context, id models.ModelID) (dto0.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Balance", ctx, id)
+	ret0, _ := ret[0].(dto0.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Balance indicates an expected call of Balance.
+func (mr *MockaccountServiceMockRecorder) Balance(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Balance", reflect.TypeOf((*MockaccountService)(nil).Balance), ctx, id)
+}
+
+// Disable mocks base method.
+func (m *MockaccountService) Disable(ctx context.Context, actorID, id models.ModelID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", ctx, actorID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockaccountServiceMockRecorder) Disable(ctx, actorID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockaccountService)(nil).Disable), ctx, actorID, id)
+}
+
+// Enable mocks base method.
+func (m *MockaccountService) Enable(ctx context.Context, actorID, id models.ModelID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable", ctx, actorID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *MockaccountServiceMockRecorder) Enable(ctx, actorID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockaccountService)(nil).Enable), ctx, actorID, id)
+}
+
+// Get mocks base method.
+func (m *MockaccountService) Get(ctx context.Context, id models.ModelID) (dto.AccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(dto.AccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockaccountServiceMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockaccountService)(nil).Get), ctx, id)
+}
+
+// Orders mocks base method.
+func (m *MockaccountService) Orders(ctx context.Context, id models.ModelID) ([]dto1.OrderResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Orders", ctx, id)
+	ret0, _ := ret[0].([]dto1.OrderResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Orders indicates an expected call of Orders.
+func (mr *MockaccountServiceMockRecorder) Orders(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Orders", reflect.TypeOf((*MockaccountService)(nil).Orders), ctx, id)
+}
+
+// Search mocks base method.
+func (m *MockaccountService) Search(ctx context.Context, q dto.SearchQuery) ([]dto.AccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, q)
+	ret0, _ := ret[0].([]dto.AccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockaccountServiceMockRecorder) Search(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockaccountService)(nil).Search), ctx, q)
+}
+
+// SetRole mocks base method.
+func (m *MockaccountService) SetRole(ctx context.Context, actorID, id models.ModelID, d dto.RolePayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRole", ctx, actorID, id, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRole indicates an expected call of SetRole.
+func (mr *MockaccountServiceMockRecorder) SetRole(ctx, actorID, id, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRole", reflect.TypeOf((*MockaccountService)(nil).SetRole), ctx, actorID, id, d)
+}
+
+// Withdrawals mocks base method.
+func (m *MockaccountService) Withdrawals(ctx context.Context, id models.ModelID) ([]dto2.WithdrawalResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Withdrawals", ctx, id)
+	ret0, _ := ret[0].([]dto2.WithdrawalResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Withdrawals indicates an expected call of Withdrawals.
+func (mr *MockaccountServiceMockRecorder) Withdrawals(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdrawals", reflect.TypeOf((*MockaccountService)(nil).Withdrawals), ctx, id)
+}
@@ -0,0 +1,98 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+// Search finds users whose login contains q, case-insensitively, or whose
+// id is q. An empty q lists everyone, newest first.
+func (r *Repository) Search(ctx context.Context, q string, limit, offset int) ([]*models.User, error) {
+	query := `
+		SELECT id, login, role, disabled_at, created_at
+		FROM users
+		WHERE $1 = ''
+			OR LOWER(login) LIKE '%' || LOWER($2) || '%'
+			OR id::text = $1
+		ORDER BY created_at DESC, id
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.db.QueryContext(ctx, query, q, likeEscaper.Replace(q), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*models.User, 0)
+	for rows.Next() {
+		var u models.User
+		if err = rows.Scan(&u.ID, &u.Login, &u.Role, &u.DisabledAt, &u.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, &u)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *Repository) FindOneByID(ctx context.Context, id models.ModelID) (*models.User, error) {
+	var u models.User
+	query := `
+		SELECT id, login, role, disabled_at, created_at FROM users WHERE id = $1
+	`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&u.ID, &u.Login, &u.Role, &u.DisabledAt, &u.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+// SetDisabled disables or re-enables the account. Disabling keeps the
+// original timestamp when repeated.
+func (r *Repository) SetDisabled(ctx context.Context, id models.ModelID, disabled bool) error {
+	query := `
+		UPDATE users SET disabled_at = CASE WHEN $2 THEN COALESCE(disabled_at, NOW()) ELSE NULL END
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	return r.exec(ctx, query, id, disabled)
+}
+
+func (r *Repository) SetRole(ctx context.Context, id models.ModelID, role string) error {
+	query := `
+		UPDATE users SET role = $2 WHERE id = $1 AND deleted_at IS NULL
+	`
+	return r.exec(ctx, query, id, role)
+}
+
+func (r *Repository) exec(ctx context.Context, query string, args ...any) error {
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
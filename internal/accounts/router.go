@@ -0,0 +1,34 @@
+package accounts
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type UserMiddleware interface {
+	Auth(c *fiber.Ctx) error
+	RequireRole(roles ...string) fiber.Handler
+}
+
+// SetupRouter serves the back office under the admin group. Support staff
+// can look accounts up, only admins can change them.
+func SetupRouter(
+	ar fiber.Router,
+	mw UserMiddleware,
+	as accountService,
+) {
+	handle := newHandler(as)
+
+	staff := mw.RequireRole(models.RoleSupport, models.RoleAdmin)
+	admins := mw.RequireRole(models.RoleAdmin)
+
+	ar.Get("users", mw.Auth, staff, handle.search)
+	ar.Get("users/:id", mw.Auth, staff, handle.get)
+	ar.Get("users/:id/orders", mw.Auth, staff, handle.orders)
+	ar.Get("users/:id/withdrawals", mw.Auth, staff, handle.withdrawals)
+	ar.Get("users/:id/balance", mw.Auth, staff, handle.balance)
+	ar.Post("users/:id/disable", mw.Auth, admins, handle.disable)
+	ar.Post("users/:id/enable", mw.Auth, admins, handle.enable)
+	ar.Put("users/:id/role", mw.Auth, admins, handle.setRole)
+}
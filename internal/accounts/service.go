@@ -0,0 +1,186 @@
+package accounts
+
+import (
+	"context"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/accounts/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	ordersdto "github.com/dkmelnik/go-musthave-diploma/internal/orders/dto"
+	withdrawalsdto "github.com/dkmelnik/go-musthave-diploma/internal/withdrawals/dto"
+)
+
+type (
+	accountRepository interface {
+		Search(ctx context.Context, q string, limit, offset int) ([]*models.User, error)
+		FindOneByID(ctx context.Context, id models.ModelID) (*models.User, error)
+		SetDisabled(ctx context.Context, id models.ModelID, disabled bool) error
+		SetRole(ctx context.Context, id models.ModelID, role string) error
+	}
+	OrderRepository interface {
+		FindByUserID(ctx context.Context, userID models.ModelID) ([]*models.Order, error)
+	}
+	WithdrawalRepository interface {
+		Find(ctx context.Context, userID models.ModelID) ([]*models.Withdrawal, error)
+	}
+	BalanceService interface {
+		GetCurrentBalance(ctx context.Context, userID models.ModelID) (appdto.Balance, error)
+	}
+	SessionRevoker interface {
+		RevokeSessions(ctx context.Context, userID models.ModelID, exceptJTI string) error
+	}
+	Service struct {
+		accountRepository    accountRepository
+		orderRepository      OrderRepository
+		withdrawalRepository WithdrawalRepository
+		balanceService       BalanceService
+		sessionRevoker       SessionRevoker
+	}
+)
+
+func NewService(
+	ar accountRepository,
+	or OrderRepository,
+	wr WithdrawalRepository,
+	bs BalanceService,
+	sr SessionRevoker,
+) *Service {
+	return &Service{ar, or, wr, bs, sr}
+}
+
+func (s *Service) Search(ctx context.Context, q dto.SearchQuery) ([]dto.AccountResponse, error) {
+	q.Normalize()
+
+	users, err := s.accountRepository.Search(ctx, q.Query, q.Limit, q.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]dto.AccountResponse, 0, len(users))
+	for _, u := range users {
+		out = append(out, account(u))
+	}
+
+	return out, nil
+}
+
+func (s *Service) Get(ctx context.Context, id models.ModelID) (dto.AccountResponse, error) {
+	u, err := s.accountRepository.FindOneByID(ctx, id)
+	if err != nil {
+		return dto.AccountResponse{}, err
+	}
+
+	return account(u), nil
+}
+
+func (s *Service) Orders(ctx context.Context, id models.ModelID) ([]ordersdto.OrderResponse, error) {
+	if _, err := s.accountRepository.FindOneByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	orders, err := s.orderRepository.FindByUserID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]ordersdto.OrderResponse, 0, len(orders))
+	for _, v := range orders {
+		d := ordersdto.OrderResponse{
+			Number:     v.Number,
+			Status:     string(v.Status),
+			UploadedAt: v.CreatedAt,
+		}
+		if v.Accrual.Valid {
+			d.SetAccrual(v.Accrual.Float64)
+		}
+		out = append(out, d)
+	}
+
+	return out, nil
+}
+
+func (s *Service) Withdrawals(ctx context.Context, id models.ModelID) ([]withdrawalsdto.WithdrawalResponse, error) {
+	if _, err := s.accountRepository.FindOneByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	withdrawals, err := s.withdrawalRepository.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]withdrawalsdto.WithdrawalResponse, 0, len(withdrawals))
+	for _, v := range withdrawals {
+		out = append(out, withdrawalsdto.WithdrawalResponse{
+			Order:       v.OrderNumber,
+			Sum:         v.Amount,
+			ProcessedAT: v.CreatedAt,
+		})
+	}
+
+	return out, nil
+}
+
+func (s *Service) Balance(ctx context.Context, id models.ModelID) (appdto.Balance, error) {
+	if _, err := s.accountRepository.FindOneByID(ctx, id); err != nil {
+		return appdto.Balance{}, err
+	}
+
+	return s.balanceService.GetCurrentBalance(ctx, id)
+}
+
+// Disable blocks logins and refreshes of the account and ends its sessions.
+// Admins can't disable themselves.
+func (s *Service) Disable(ctx context.Context, actorID, id models.ModelID) error {
+	if actorID == id {
+		return apperrors.ErrInvalidRecipient
+	}
+
+	if err := s.accountRepository.SetDisabled(ctx, id, true); err != nil {
+		return err
+	}
+	logger.Log.Info("accounts:disable", "actor", actorID, "user", id)
+
+	return s.sessionRevoker.RevokeSessions(ctx, id, "")
+}
+
+func (s *Service) Enable(ctx context.Context, actorID, id models.ModelID) error {
+	if err := s.accountRepository.SetDisabled(ctx, id, false); err != nil {
+		return err
+	}
+	logger.Log.Info("accounts:enable", "actor", actorID, "user", id)
+
+	return nil
+}
+
+// SetRole changes the role of the account and ends its sessions, so tokens
+// carrying the old role stop working. Admins can't change their own role, so
+// there is always one left.
+func (s *Service) SetRole(ctx context.Context, actorID, id models.ModelID, d dto.RolePayload) error {
+	if actorID == id {
+		return apperrors.ErrInvalidRecipient
+	}
+
+	if err := s.accountRepository.SetRole(ctx, id, d.Role); err != nil {
+		return err
+	}
+	logger.Log.Info("accounts:setRole", "actor", actorID, "user", id, "role", d.Role)
+
+	return s.sessionRevoker.RevokeSessions(ctx, id, "")
+}
+
+func account(u *models.User) dto.AccountResponse {
+	out := dto.AccountResponse{
+		ID:        string(u.ID),
+		Login:     u.Login,
+		Role:      u.Role,
+		CreatedAt: u.CreatedAt,
+	}
+	if u.DisabledAt.Valid {
+		disabledAt := u.DisabledAt.Time
+		out.DisabledAt = &disabledAt
+	}
+	return out
+}
@@ -0,0 +1,145 @@
+package accounts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/accounts/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/accounts/mocks"
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type serviceMocks struct {
+	accounts *mocks.MockaccountRepository
+	revoker  *mocks.MockSessionRevoker
+}
+
+func newTestService(ctrl *gomock.Controller) (*Service, serviceMocks) {
+	m := serviceMocks{
+		accounts: mocks.NewMockaccountRepository(ctrl),
+		revoker:  mocks.NewMockSessionRevoker(ctrl),
+	}
+	return NewService(
+		m.accounts,
+		mocks.NewMockOrderRepository(ctrl),
+		mocks.NewMockWithdrawalRepository(ctrl),
+		mocks.NewMockBalanceService(ctrl),
+		m.revoker,
+	), m
+}
+
+func TestService_Disable(t *testing.T) {
+	tests := []struct {
+		name    string
+		actorID models.ModelID
+		prepare func(m serviceMocks)
+		want    error
+	}{
+		{
+			name:    "positive test #1, disabled and sessions ended",
+			actorID: "admin",
+			prepare: func(m serviceMocks) {
+				gomock.InOrder(
+					m.accounts.EXPECT().SetDisabled(gomock.Any(), models.ModelID("user"), true).Return(nil),
+					m.revoker.EXPECT().RevokeSessions(gomock.Any(), models.ModelID("user"), "").Return(nil),
+				)
+			},
+		},
+		{
+			name:    "negative test #2, admins can't disable themselves",
+			actorID: "user",
+			prepare: func(m serviceMocks) {},
+			want:    apperrors.ErrInvalidRecipient,
+		},
+		{
+			name:    "negative test #3, unknown account",
+			actorID: "admin",
+			prepare: func(m serviceMocks) {
+				m.accounts.EXPECT().SetDisabled(gomock.Any(), models.ModelID("user"), true).Return(apperrors.ErrNotFound)
+			},
+			want: apperrors.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newTestService(ctrl)
+			tt.prepare(m)
+
+			err := s.Disable(context.Background(), tt.actorID, "user")
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestService_Enable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, m := newTestService(ctrl)
+
+	// enabling leaves sessions alone, there are none left after disabling
+	m.accounts.EXPECT().SetDisabled(gomock.Any(), models.ModelID("user"), false).Return(nil)
+
+	assert.NoError(t, s.Enable(context.Background(), "admin", "user"))
+}
+
+func TestService_SetRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		actorID models.ModelID
+		prepare func(m serviceMocks)
+		want    error
+	}{
+		{
+			name:    "positive test #1, role changed and sessions ended",
+			actorID: "admin",
+			prepare: func(m serviceMocks) {
+				gomock.InOrder(
+					m.accounts.EXPECT().SetRole(gomock.Any(), models.ModelID("user"), models.RoleSupport).Return(nil),
+					m.revoker.EXPECT().RevokeSessions(gomock.Any(), models.ModelID("user"), "").Return(nil),
+				)
+			},
+		},
+		{
+			name:    "negative test #2, admins can't change their own role",
+			actorID: "user",
+			prepare: func(m serviceMocks) {},
+			want:    apperrors.ErrInvalidRecipient,
+		},
+		{
+			name:    "negative test #3, unknown account",
+			actorID: "admin",
+			prepare: func(m serviceMocks) {
+				m.accounts.EXPECT().SetRole(gomock.Any(), models.ModelID("user"), models.RoleSupport).Return(apperrors.ErrNotFound)
+			},
+			want: apperrors.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newTestService(ctrl)
+			tt.prepare(m)
+
+			err := s.SetRole(context.Background(), tt.actorID, "user", dto.RolePayload{Role: models.RoleSupport})
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReasonCodes lists the accepted adjustment reasons.
+var ReasonCodes = []string{"GOODWILL", "COMPENSATION", "CORRECTION", "FRAUD", "OTHER"}
+
+type (
+	AdjustmentPayload struct {
+		// Amount is signed: credits are positive, debits negative.
+		Amount  float64 `json:"amount"`
+		Reason  string  `json:"reason"`
+		Comment string  `json:"comment"`
+	}
+	AdjustmentResponse struct {
+		ID        string    `json:"id"`
+		Amount    float64   `json:"amount"`
+		Reason    string    `json:"reason"`
+		Comment   string    `json:"comment"`
+		Operator  string    `json:"operator"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+)
+
+func (p *AdjustmentPayload) Validate() error {
+	if p.Amount == 0 {
+		return fmt.Errorf("amount must not be zero")
+	}
+
+	p.Reason = strings.ToUpper(strings.TrimSpace(p.Reason))
+	valid := false
+	for _, code := range ReasonCodes {
+		if p.Reason == code {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("reason must be one of %s", strings.Join(ReasonCodes, ", "))
+	}
+
+	p.Comment = strings.TrimSpace(p.Comment)
+	if p.Comment == "" {
+		return fmt.Errorf("comment is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package adjustments
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/adjustments/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+type (
+	adjustmentService interface {
+		Post(ctx context.Context, operator string, userID models.ModelID, d dto.AdjustmentPayload) (dto.AdjustmentResponse, error)
+		GetAll(ctx context.Context, userID models.ModelID) ([]dto.AdjustmentResponse, error)
+	}
+	handler struct {
+		service adjustmentService
+	}
+)
+
+func newHandler(as adjustmentService) *handler {
+	return &handler{as}
+}
+
+func (h *handler) post(c *fiber.Ctx) error {
+	operator, _ := c.Locals("user_id").(string)
+
+	userID := c.Params("id")
+	if !utils.IsGUID(userID) {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+
+	var body dto.AdjustmentPayload
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	}
+
+	if err := body.Validate(); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(err.Error())
+	}
+
+	switch out, err := h.service.Post(c.Context(), operator, models.ModelID(userID), body); {
+	case err == nil:
+		return c.Status(fiber.StatusCreated).JSON(out)
+	case errors.Is(err, apperrors.ErrNotFound):
+		return c.SendStatus(fiber.StatusNotFound)
+	default:
+		logger.Log.Error("adjustments:handler:post", "StatusInternalServerError", err)
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+}
+
+func (h *handler) getAll(c *fiber.Ctx) error {
+	userID := c.Params("id")
+	if !utils.IsGUID(userID) {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+
+	switch out, err := h.service.GetAll(c.Context(), models.ModelID(userID)); {
+	case errors.Is(err, apperrors.ErrNoInformationAnswer):
+		return c.SendStatus(fiber.StatusNoContent)
+	case err == nil:
+		return c.Status(fiber.StatusOK).JSON(out)
+	default:
+		logger.Log.Error("adjustments:handler:getAll", "StatusInternalServerError", err)
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,140 @@
+package adjustments
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/adjustments/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/adjustments/mocks"
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+const testUserID = "6d1f4f0c-4a4e-4a55-9a3c-3b1c7d0e2f11"
+
+func Test_post(t *testing.T) {
+	body := `{"amount":25,"reason":"goodwill","comment":"late delivery"}`
+
+	tests := []struct {
+		name    string
+		id      string
+		body    string
+		service bool
+		err     error
+		code    int
+	}{
+		{
+			name: "negative test #1, id is not a guid",
+			id:   "1",
+			body: body,
+			code: http.StatusNotFound,
+		},
+		{
+			name: "negative test #2, bad entity",
+			id:   testUserID,
+			body: `{"amount":`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "negative test #3, unknown reason",
+			id:   testUserID,
+			body: `{"amount":25,"reason":"because","comment":"late delivery"}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "negative test #4, unknown user",
+			id:      testUserID,
+			body:    body,
+			service: true,
+			err:     apperrors.ErrNotFound,
+			code:    http.StatusNotFound,
+		},
+		{
+			name:    "negative test #5, unknown service error",
+			id:      testUserID,
+			body:    body,
+			service: true,
+			err:     errors.New("db is down"),
+			code:    http.StatusInternalServerError,
+		},
+		{
+			name:    "positive test #6, adjustment posted",
+			id:      testUserID,
+			body:    body,
+			service: true,
+			code:    http.StatusCreated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			as := mocks.NewMockadjustmentService(ctrl)
+			if tt.service {
+				as.EXPECT().Post(gomock.Any(), "admin-id", models.ModelID(testUserID), dto.AdjustmentPayload{
+					Amount:  25,
+					Reason:  "GOODWILL",
+					Comment: "late delivery",
+				}).Return(dto.AdjustmentResponse{}, tt.err)
+			}
+
+			app := fiber.New()
+			h := newHandler(as)
+			app.Post("/:id", func(c *fiber.Ctx) error {
+				c.Locals("user_id", "admin-id")
+				return c.Next()
+			}, h.post)
+
+			req := httptest.NewRequest(http.MethodPost, "/"+tt.id, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+
+func Test_getAll(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "positive test #1, adjustments listed", code: http.StatusOK},
+		{name: "positive test #2, no adjustments", err: apperrors.ErrNoInformationAnswer, code: http.StatusNoContent},
+		{name: "negative test #3, unknown service error", err: errors.New("db is down"), code: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			as := mocks.NewMockadjustmentService(ctrl)
+			as.EXPECT().GetAll(gomock.Any(), models.ModelID(testUserID)).Return(nil, tt.err)
+
+			app := fiber.New()
+			app.Get("/:id", newHandler(as).getAll)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/"+testUserID, nil), 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
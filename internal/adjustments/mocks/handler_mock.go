@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/dkmelnik/go-musthave-diploma/internal/adjustments/dto"
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockadjustmentService is a mock of adjustmentService interface.
+type MockadjustmentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockadjustmentServiceMockRecorder
+}
+
+// MockadjustmentServiceMockRecorder is the mock recorder for MockadjustmentService.
+type MockadjustmentServiceMockRecorder struct {
+	mock *MockadjustmentService
+}
+
+// NewMockadjustmentService creates a new mock instance.
+func NewMockadjustmentService(ctrl *gomock.Controller) *MockadjustmentService {
+	mock := &MockadjustmentService{ctrl: ctrl}
+	mock.recorder = &MockadjustmentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockadjustmentService) EXPECT() *MockadjustmentServiceMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockadjustmentService) GetAll(ctx context.Context, userID models.ModelID) ([]dto.AdjustmentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userID)
+	ret0, _ := ret[0].([]dto.AdjustmentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockadjustmentServiceMockRecorder) GetAll(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockadjustmentService)(nil).GetAll), ctx, userID)
+}
+
+// Post mocks base method.
+func (m *MockadjustmentService) Post(ctx context.Context, operator string, userID models.ModelID, d dto.AdjustmentPayload) (dto.AdjustmentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", ctx, operator, userID, d)
+	ret0, _ := ret[0].(dto.AdjustmentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockadjustmentServiceMockRecorder) Post(ctx, operator, userID, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockadjustmentService)(nil).Post), ctx, operator, userID, d)
+}
@@ -0,0 +1,103 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockuserRepository is a mock of userRepository interface.
+type MockuserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockuserRepositoryMockRecorder
+}
+
+// MockuserRepositoryMockRecorder is the mock recorder for MockuserRepository.
+type MockuserRepositoryMockRecorder struct {
+	mock *MockuserRepository
+}
+
+// NewMockuserRepository creates a new mock instance.
+func NewMockuserRepository(ctrl *gomock.Controller) *MockuserRepository {
+	mock := &MockuserRepository{ctrl: ctrl}
+	mock.recorder = &MockuserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockuserRepository) EXPECT() *MockuserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindOneByID mocks base method.
+func (m *MockuserRepository) FindOneByID(ctx context.Context, id models.ModelID) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOneByID", ctx, id)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOneByID indicates an expected call of FindOneByID.
+func (mr *MockuserRepositoryMockRecorder) FindOneByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOneByID", reflect.TypeOf((*MockuserRepository)(nil).FindOneByID), ctx, id)
+}
+
+// MockadjustmentRepository is a mock of adjustmentRepository interface.
+type MockadjustmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockadjustmentRepositoryMockRecorder
+}
+
+// MockadjustmentRepositoryMockRecorder is the mock recorder for MockadjustmentRepository.
+type MockadjustmentRepositoryMockRecorder struct {
+	mock *MockadjustmentRepository
+}
+
+// NewMockadjustmentRepository creates a new mock instance.
+func NewMockadjustmentRepository(ctrl *gomock.Controller) *MockadjustmentRepository {
+	mock := &MockadjustmentRepository{ctrl: ctrl}
+	mock.recorder = &MockadjustmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockadjustmentRepository) EXPECT() *MockadjustmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindByUserID mocks base method.
+func (m *MockadjustmentRepository) FindByUserID(ctx context.Context, userID models.ModelID) ([]*models.BalanceEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*models.BalanceEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockadjustmentRepositoryMockRecorder) FindByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockadjustmentRepository)(nil).FindByUserID), ctx, userID)
+}
+
+// Save mocks base method.
+func (m_2 *MockadjustmentRepository) Save(ctx context.Context, m *models.BalanceEntry) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Save", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockadjustmentRepositoryMockRecorder) Save(ctx, m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockadjustmentRepository)(nil).Save), ctx, m)
+}
@@ -0,0 +1,72 @@
+package adjustments
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+func (r *Repository) Save(ctx context.Context, m *models.BalanceEntry) error {
+	query := `
+		INSERT INTO balance_entries (user_id, kind, amount, reason_code, comment, operator)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRowContext(
+		ctx,
+		query,
+		m.UserID,
+		m.Kind,
+		m.Amount,
+		m.ReasonCode,
+		m.Comment,
+		m.Operator,
+	).Scan(&m.ID, &m.CreatedAt)
+}
+
+func (r *Repository) FindByUserID(ctx context.Context, userID models.ModelID) ([]*models.BalanceEntry, error) {
+	query := `
+		SELECT id, user_id, kind, amount, reason_code, comment, operator, created_at
+		FROM balance_entries
+		WHERE user_id = $1 AND kind = $2
+		ORDER BY created_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID, models.EntryAdjustment)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*models.BalanceEntry
+	for rows.Next() {
+		var m models.BalanceEntry
+		if err := rows.Scan(
+			&m.ID,
+			&m.UserID,
+			&m.Kind,
+			&m.Amount,
+			&m.ReasonCode,
+			&m.Comment,
+			&m.Operator,
+			&m.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,29 @@
+package adjustments
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type UserMiddleware interface {
+	Auth(c *fiber.Ctx) error
+	RequireRole(roles ...string) fiber.Handler
+}
+
+// SetupRouter serves balance adjustments under the admin group, the
+// authenticated admin is recorded as the operator.
+func SetupRouter(
+	ar fiber.Router,
+	mw UserMiddleware,
+	ur userRepository,
+	adr adjustmentRepository,
+) {
+	service := NewService(ur, adr)
+	handle := newHandler(service)
+
+	admins := mw.RequireRole(models.RoleAdmin)
+
+	ar.Post("users/:id/adjustments", mw.Auth, admins, handle.post)
+	ar.Get("users/:id/adjustments", mw.Auth, admins, handle.getAll)
+}
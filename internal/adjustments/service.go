@@ -0,0 +1,94 @@
+package adjustments
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/adjustments/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type (
+	userRepository interface {
+		FindOneByID(ctx context.Context, id models.ModelID) (*models.User, error)
+	}
+	adjustmentRepository interface {
+		Save(ctx context.Context, m *models.BalanceEntry) error
+		FindByUserID(ctx context.Context, userID models.ModelID) ([]*models.BalanceEntry, error)
+	}
+	Service struct {
+		userRepository       userRepository
+		adjustmentRepository adjustmentRepository
+	}
+)
+
+func NewService(ur userRepository, ar adjustmentRepository) *Service {
+	return &Service{ur, ar}
+}
+
+// Post records a signed adjustment of the user's balance. Adjustments are
+// never changed afterwards, mistakes are corrected by posting another one.
+func (s *Service) Post(
+	ctx context.Context,
+	operator string,
+	userID models.ModelID,
+	d dto.AdjustmentPayload,
+) (dto.AdjustmentResponse, error) {
+	if err := d.Validate(); err != nil {
+		return dto.AdjustmentResponse{}, err
+	}
+	if operator == "" {
+		return dto.AdjustmentResponse{}, apperrors.ErrNoRequiredValue
+	}
+
+	if _, err := s.userRepository.FindOneByID(ctx, userID); err != nil {
+		return dto.AdjustmentResponse{}, err
+	}
+
+	m := &models.BalanceEntry{
+		UserID:     userID,
+		Kind:       models.EntryAdjustment,
+		Amount:     d.Amount,
+		ReasonCode: sql.NullString{String: d.Reason, Valid: true},
+		Comment:    sql.NullString{String: d.Comment, Valid: true},
+		Operator:   sql.NullString{String: operator, Valid: true},
+	}
+	if err := s.adjustmentRepository.Save(ctx, m); err != nil {
+		return dto.AdjustmentResponse{}, err
+	}
+
+	logger.Log.Info("adjustments:service:post",
+		"operator", operator, "userID", userID, "amount", d.Amount, "reason", d.Reason, "id", m.ID)
+
+	return toResponse(m), nil
+}
+
+func (s *Service) GetAll(ctx context.Context, userID models.ModelID) ([]dto.AdjustmentResponse, error) {
+	entries, err := s.adjustmentRepository.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, apperrors.ErrNoInformationAnswer
+	}
+
+	out := make([]dto.AdjustmentResponse, 0, len(entries))
+	for _, v := range entries {
+		out = append(out, toResponse(v))
+	}
+
+	return out, nil
+}
+
+func toResponse(m *models.BalanceEntry) dto.AdjustmentResponse {
+	return dto.AdjustmentResponse{
+		ID:        string(m.ID),
+		Amount:    m.Amount,
+		Reason:    m.ReasonCode.String,
+		Comment:   m.Comment.String,
+		Operator:  m.Operator.String,
+		CreatedAt: m.CreatedAt,
+	}
+}
@@ -0,0 +1,123 @@
+package adjustments
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/adjustments/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/adjustments/mocks"
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+func TestService_Post(t *testing.T) {
+	valid := dto.AdjustmentPayload{Amount: -50, Reason: " goodwill ", Comment: " refund "}
+
+	tests := []struct {
+		name     string
+		operator string
+		payload  dto.AdjustmentPayload
+		findErr  error
+		find     bool
+		save     bool
+		saveErr  error
+		want     error
+	}{
+		{
+			name:     "positive test #1, debit recorded with the operator",
+			operator: "admin-id",
+			payload:  valid,
+			find:     true,
+			save:     true,
+		},
+		{
+			name:     "negative test #2, zero amount",
+			operator: "admin-id",
+			payload:  dto.AdjustmentPayload{Reason: "OTHER", Comment: "c"},
+			want:     errors.New("amount must not be zero"),
+		},
+		{
+			name:    "negative test #3, no operator",
+			payload: valid,
+			want:    apperrors.ErrNoRequiredValue,
+		},
+		{
+			name:     "negative test #4, unknown user",
+			operator: "admin-id",
+			payload:  valid,
+			find:     true,
+			findErr:  apperrors.ErrNotFound,
+			want:     apperrors.ErrNotFound,
+		},
+		{
+			name:     "negative test #5, save fails",
+			operator: "admin-id",
+			payload:  valid,
+			find:     true,
+			save:     true,
+			saveErr:  errors.New("db is down"),
+			want:     errors.New("db is down"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ur := mocks.NewMockuserRepository(ctrl)
+			ar := mocks.NewMockadjustmentRepository(ctrl)
+
+			if tt.find {
+				ur.EXPECT().FindOneByID(gomock.Any(), models.ModelID("user-id")).Return(&models.User{ID: "user-id"}, tt.findErr)
+			}
+			if tt.save {
+				ar.EXPECT().Save(gomock.Any(), &models.BalanceEntry{
+					UserID:     "user-id",
+					Kind:       models.EntryAdjustment,
+					Amount:     -50,
+					ReasonCode: sql.NullString{String: "GOODWILL", Valid: true},
+					Comment:    sql.NullString{String: "refund", Valid: true},
+					Operator:   sql.NullString{String: "admin-id", Valid: true},
+				}).Return(tt.saveErr)
+			}
+
+			out, err := NewService(ur, ar).Post(context.Background(), tt.operator, "user-id", tt.payload)
+			if tt.want != nil {
+				require.Error(t, err)
+				assert.Equal(t, tt.want.Error(), err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "admin-id", out.Operator)
+			assert.Equal(t, "GOODWILL", out.Reason)
+			assert.Equal(t, -50.0, out.Amount)
+		})
+	}
+}
+
+func TestService_GetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ar := mocks.NewMockadjustmentRepository(ctrl)
+	service := NewService(mocks.NewMockuserRepository(ctrl), ar)
+
+	ar.EXPECT().FindByUserID(gomock.Any(), models.ModelID("user-id")).Return(nil, nil)
+	_, err := service.GetAll(context.Background(), "user-id")
+	assert.ErrorIs(t, err, apperrors.ErrNoInformationAnswer)
+
+	ar.EXPECT().FindByUserID(gomock.Any(), models.ModelID("user-id")).Return([]*models.BalanceEntry{{
+		ID:       "entry-id",
+		Amount:   10,
+		Operator: sql.NullString{String: "admin-id", Valid: true},
+	}}, nil)
+	out, err := service.GetAll(context.Background(), "user-id")
+	require.NoError(t, err)
+	assert.Equal(t, []dto.AdjustmentResponse{{ID: "entry-id", Amount: 10, Operator: "admin-id"}}, out)
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type (
+	CreatePayload struct {
+		Name   string   `json:"name"`
+		Scopes []string `json:"scopes"`
+		// ExpiresAt defaults to, and is capped at, the configured key
+		// lifetime.
+		ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	}
+	APIKeyResponse struct {
+		ID         string     `json:"id"`
+		Name       string     `json:"name"`
+		Prefix     string     `json:"prefix"`
+		Scopes     []string   `json:"scopes"`
+		ExpiresAt  time.Time  `json:"expires_at"`
+		LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+		RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+		CreatedAt  time.Time  `json:"created_at"`
+	}
+	// CreatedResponse carries the key itself, it is never shown again.
+	CreatedResponse struct {
+		APIKeyResponse
+		Key string `json:"key"`
+	}
+)
+
+func (r *CreatePayload) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" || len(r.Name) > 100 {
+		return fmt.Errorf("name must be 1 to 100 characters long")
+	}
+	if len(r.Scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+	for _, s := range r.Scopes {
+		if !models.IsScope(s) {
+			return fmt.Errorf("unknown scope %q", s)
+		}
+	}
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("expires_at must be in the future")
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apikeys/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+type (
+	apiKeyService interface {
+		Create(ctx context.Context, userID models.ModelID, d dto.CreatePayload) (dto.CreatedResponse, error)
+		List(ctx context.Context, userID models.ModelID) ([]dto.APIKeyResponse, error)
+		Revoke(ctx context.Context, userID, id models.ModelID) error
+	}
+	handler struct {
+		service apiKeyService
+	}
+)
+
+func newHandler(as apiKeyService) *handler {
+	return &handler{as}
+}
+
+func (h *handler) create(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	var body dto.CreatePayload
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	}
+	if err := body.Validate(); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(err.Error())
+	}
+
+	out, err := h.service.Create(c.Context(), models.ModelID(userID), body)
+	if err != nil {
+		logger.Log.Error("apikeys:handler:create", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(out)
+}
+
+func (h *handler) list(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	out, err := h.service.List(c.Context(), models.ModelID(userID))
+	if err != nil {
+		logger.Log.Error("apikeys:handler:list", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+	if len(out) == 0 {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(out)
+}
+
+func (h *handler) revoke(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	id := c.Params("id")
+	if !utils.IsGUID(id) {
+		return c.Status(fiber.StatusNotFound).SendString(http.StatusText(fiber.StatusNotFound))
+	}
+
+	switch err := h.service.Revoke(c.Context(), models.ModelID(userID), models.ModelID(id)); {
+	case err == nil:
+		return c.SendStatus(fiber.StatusNoContent)
+	case errors.Is(err, apperrors.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).SendString(http.StatusText(fiber.StatusNotFound))
+	default:
+		logger.Log.Error("apikeys:handler:revoke", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+}
@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockapiKeyRepository is a mock of apiKeyRepository interface.
+type MockapiKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockapiKeyRepositoryMockRecorder
+}
+
+// MockapiKeyRepositoryMockRecorder is the mock recorder for MockapiKeyRepository.
+type MockapiKeyRepositoryMockRecorder struct {
+	mock *MockapiKeyRepository
+}
+
+// NewMockapiKeyRepository creates a new mock instance.
+func NewMockapiKeyRepository(ctrl *gomock.Controller) *MockapiKeyRepository {
+	mock := &MockapiKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockapiKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockapiKeyRepository) EXPECT() *MockapiKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindActive mocks base method.
+func (m *MockapiKeyRepository) FindActive(ctx context.Context, hash string) (*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindActive", ctx, hash)
+	ret0, _ := ret[0].(*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindActive indicates an expected call of FindActive.
+func (mr *MockapiKeyRepositoryMockRecorder) FindActive(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActive", reflect.TypeOf((*MockapiKeyRepository)(nil).FindActive), ctx, hash)
+}
+
+// FindByUserID mocks base method.
+func (m *MockapiKeyRepository) FindByUserID(ctx context.Context, userID models.ModelID) ([]models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", ctx, userID)
+	ret0, _ := ret[0].([]models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockapiKeyRepositoryMockRecorder) FindByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockapiKeyRepository)(nil).FindByUserID), ctx, userID)
+}
+
+// Revoke mocks base method.
+func (m *MockapiKeyRepository) Revoke(ctx context.Context, userID, id models.ModelID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockapiKeyRepositoryMockRecorder) Revoke(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockapiKeyRepository)(nil).Revoke), ctx, userID, id)
+}
+
+// Save mocks base method.
+func (m *MockapiKeyRepository) Save(ctx context.Context, k *models.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, k)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockapiKeyRepositoryMockRecorder) Save(ctx, k interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockapiKeyRepository)(nil).Save), ctx, k)
+}
+
+// Touch mocks base method.
+func (m *MockapiKeyRepository) Touch(ctx context.Context, id models.ModelID, precision time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", ctx, id, precision)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockapiKeyRepositoryMockRecorder) Touch(ctx, id, precision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockapiKeyRepository)(nil).Touch), ctx, id, precision)
+}
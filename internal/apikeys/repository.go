@@ -0,0 +1,129 @@
+package apikeys
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+func (r *Repository) Save(ctx context.Context, k *models.APIKey) error {
+	query := `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRowContext(ctx, query,
+		k.UserID, k.Name, k.Prefix, k.KeyHash, pq.Array(k.Scopes), k.ExpiresAt,
+	).Scan(&k.ID, &k.CreatedAt)
+}
+
+func (r *Repository) FindByUserID(ctx context.Context, userID models.ModelID) ([]models.APIKey, error) {
+	query := `
+		SELECT id, user_id, name, prefix, scopes, expires_at, last_used_at, revoked_at, created_at
+		FROM api_keys
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.APIKey
+	for rows.Next() {
+		var k models.APIKey
+		if err = rows.Scan(
+			&k.ID,
+			&k.UserID,
+			&k.Name,
+			&k.Prefix,
+			pq.Array(&k.Scopes),
+			&k.ExpiresAt,
+			&k.LastUsedAt,
+			&k.RevokedAt,
+			&k.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, k)
+	}
+
+	return out, rows.Err()
+}
+
+// FindActive returns the unrevoked, unexpired key with the given hash that
+// belongs to an enabled account.
+func (r *Repository) FindActive(ctx context.Context, hash string) (*models.APIKey, error) {
+	var k models.APIKey
+	query := `
+		SELECT k.id, k.user_id, k.name, k.prefix, k.scopes, k.expires_at, k.last_used_at
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id
+		WHERE k.key_hash = $1
+		  AND k.revoked_at IS NULL
+		  AND k.expires_at > NOW()
+		  AND u.disabled_at IS NULL
+	`
+	err := r.db.QueryRowContext(ctx, query, hash).Scan(
+		&k.ID,
+		&k.UserID,
+		&k.Name,
+		&k.Prefix,
+		pq.Array(&k.Scopes),
+		&k.ExpiresAt,
+		&k.LastUsedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrInvalidToken
+		}
+		return nil, err
+	}
+	return &k, nil
+}
+
+// Touch records the use of the key. Uses within precision of the last
+// recorded one are skipped to spare a write per request.
+func (r *Repository) Touch(ctx context.Context, id models.ModelID, precision time.Duration) error {
+	query := `
+		UPDATE api_keys SET last_used_at = NOW()
+		WHERE id = $1
+		  AND (last_used_at IS NULL OR last_used_at < NOW() - $2 * INTERVAL '1 second')
+	`
+	_, err := r.db.ExecContext(ctx, query, id, precision.Seconds())
+	return err
+}
+
+func (r *Repository) Revoke(ctx context.Context, userID, id models.ModelID) error {
+	query := `
+		UPDATE api_keys SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1 AND user_id = $2
+	`
+	res, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
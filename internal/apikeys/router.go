@@ -0,0 +1,23 @@
+package apikeys
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserMiddleware interface {
+	Auth(c *fiber.Ctx) error
+}
+
+// SetupRouter serves key management. It takes a login session, keys can't
+// manage keys.
+func SetupRouter(
+	r fiber.Router,
+	mw UserMiddleware,
+	as apiKeyService,
+) {
+	handle := newHandler(as)
+
+	r.Post("api-keys", mw.Auth, handle.create)
+	r.Get("api-keys", mw.Auth, handle.list)
+	r.Delete("api-keys/:id", mw.Auth, handle.revoke)
+}
@@ -0,0 +1,136 @@
+package apikeys
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apikeys/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+const (
+	// keyPrefix marks gophermart keys, e.g. for secret scanners.
+	keyPrefix = "gm_"
+	// displayLen is how much of the key is kept in clear to tell keys apart.
+	displayLen = len(keyPrefix) + 8
+	// touchPrecision bounds how often last_used_at is written.
+	touchPrecision = time.Minute
+)
+
+type (
+	apiKeyRepository interface {
+		Save(ctx context.Context, k *models.APIKey) error
+		FindByUserID(ctx context.Context, userID models.ModelID) ([]models.APIKey, error)
+		FindActive(ctx context.Context, hash string) (*models.APIKey, error)
+		Touch(ctx context.Context, id models.ModelID, precision time.Duration) error
+		Revoke(ctx context.Context, userID, id models.ModelID) error
+	}
+	Service struct {
+		ttl              time.Duration
+		apiKeyRepository apiKeyRepository
+	}
+)
+
+func NewService(ttl time.Duration, ar apiKeyRepository) *Service {
+	return &Service{ttl, ar}
+}
+
+// Create issues a key for the user. The key is only returned here, the
+// database keeps its hash. A requested expiry is capped at the configured
+// key lifetime.
+func (s *Service) Create(ctx context.Context, userID models.ModelID, d dto.CreatePayload) (dto.CreatedResponse, error) {
+	raw := keyPrefix + utils.GenerateSecret()
+
+	k := &models.APIKey{
+		UserID:    userID,
+		Name:      d.Name,
+		Prefix:    raw[:displayLen],
+		KeyHash:   utils.HashSecret(raw),
+		Scopes:    dedupe(d.Scopes),
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	if d.ExpiresAt != nil && d.ExpiresAt.Before(k.ExpiresAt) {
+		k.ExpiresAt = *d.ExpiresAt
+	}
+
+	if err := s.apiKeyRepository.Save(ctx, k); err != nil {
+		return dto.CreatedResponse{}, err
+	}
+
+	return dto.CreatedResponse{APIKeyResponse: toResponse(*k), Key: raw}, nil
+}
+
+func (s *Service) List(ctx context.Context, userID models.ModelID) ([]dto.APIKeyResponse, error) {
+	keys, err := s.apiKeyRepository.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]dto.APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, toResponse(k))
+	}
+	return out, nil
+}
+
+func (s *Service) Revoke(ctx context.Context, userID, id models.ModelID) error {
+	return s.apiKeyRepository.Revoke(ctx, userID, id)
+}
+
+// Verify returns the owner of the raw key if the key is active and granted
+// scope. Unknown, expired and revoked keys are apperrors.ErrInvalidToken.
+func (s *Service) Verify(ctx context.Context, raw, scope string) (models.ModelID, error) {
+	if !strings.HasPrefix(raw, keyPrefix) {
+		return "", apperrors.ErrInvalidToken
+	}
+
+	k, err := s.apiKeyRepository.FindActive(ctx, utils.HashSecret(raw))
+	if err != nil {
+		return "", err
+	}
+	if !k.HasScope(scope) {
+		return "", apperrors.ErrInsufficientScope
+	}
+
+	if err = s.apiKeyRepository.Touch(ctx, k.ID, touchPrecision); err != nil {
+		logger.Log.Error("apikeys:verify", "Touch", err)
+	}
+
+	return k.UserID, nil
+}
+
+func toResponse(k models.APIKey) dto.APIKeyResponse {
+	out := dto.APIKeyResponse{
+		ID:        string(k.ID),
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    k.Scopes,
+		ExpiresAt: k.ExpiresAt,
+		CreatedAt: k.CreatedAt,
+	}
+	if k.LastUsedAt.Valid {
+		lastUsedAt := k.LastUsedAt.Time
+		out.LastUsedAt = &lastUsedAt
+	}
+	if k.RevokedAt.Valid {
+		revokedAt := k.RevokedAt.Time
+		out.RevokedAt = &revokedAt
+	}
+	return out
+}
+
+func dedupe(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	out := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
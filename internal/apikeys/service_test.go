@@ -0,0 +1,159 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apikeys/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/apikeys/mocks"
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+const testTTL = 90 * 24 * time.Hour
+
+func TestService_Create(t *testing.T) {
+	soon := time.Now().Add(time.Hour)
+	late := time.Now().Add(2 * testTTL)
+
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		want      time.Time
+	}{
+		{name: "positive test #1, configured lifetime by default", want: time.Now().Add(testTTL)},
+		{name: "positive test #2, shorter expiry kept", expiresAt: &soon, want: soon},
+		{name: "positive test #3, longer expiry capped", expiresAt: &late, want: time.Now().Add(testTTL)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockapiKeyRepository(ctrl)
+			s := NewService(testTTL, repo)
+
+			var saved *models.APIKey
+			repo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, k *models.APIKey) error {
+				saved = k
+				return nil
+			})
+
+			out, err := s.Create(context.Background(), "user", dto.CreatePayload{
+				Name:      "ci",
+				Scopes:    []string{models.ScopeOrdersWrite, models.ScopeBalanceRead, models.ScopeOrdersWrite},
+				ExpiresAt: tt.expiresAt,
+			})
+			require.NoError(t, err)
+
+			assert.True(t, strings.HasPrefix(out.Key, keyPrefix))
+			assert.Equal(t, out.Key[:displayLen], out.Prefix)
+			assert.Equal(t, utils.HashSecret(out.Key), saved.KeyHash, "only the hash is stored")
+			assert.Equal(t, models.ModelID("user"), saved.UserID)
+			assert.Equal(t, []string{models.ScopeOrdersWrite, models.ScopeBalanceRead}, saved.Scopes)
+			assert.WithinDuration(t, tt.want, saved.ExpiresAt, time.Second)
+		})
+	}
+}
+
+func TestService_Verify(t *testing.T) {
+	const raw = keyPrefix + "secret"
+	key := &models.APIKey{ID: "key", UserID: "user", Scopes: []string{models.ScopeBalanceRead}}
+
+	tests := []struct {
+		name    string
+		raw     string
+		scope   string
+		prepare func(repo *mocks.MockapiKeyRepository)
+		want    error
+	}{
+		{
+			name:  "positive test #1, active key with the scope",
+			raw:   raw,
+			scope: models.ScopeBalanceRead,
+			prepare: func(repo *mocks.MockapiKeyRepository) {
+				repo.EXPECT().FindActive(gomock.Any(), utils.HashSecret(raw)).Return(key, nil)
+				repo.EXPECT().Touch(gomock.Any(), models.ModelID("key"), touchPrecision).Return(nil)
+			},
+		},
+		{
+			name:  "positive test #2, failed touch does not reject the key",
+			raw:   raw,
+			scope: models.ScopeBalanceRead,
+			prepare: func(repo *mocks.MockapiKeyRepository) {
+				repo.EXPECT().FindActive(gomock.Any(), utils.HashSecret(raw)).Return(key, nil)
+				repo.EXPECT().Touch(gomock.Any(), models.ModelID("key"), touchPrecision).Return(errors.New("db is down"))
+			},
+		},
+		{
+			name:    "negative test #3, not a gophermart key",
+			raw:     "secret",
+			scope:   models.ScopeBalanceRead,
+			prepare: func(repo *mocks.MockapiKeyRepository) {},
+			want:    apperrors.ErrInvalidToken,
+		},
+		{
+			name:  "negative test #4, unknown, expired or revoked key",
+			raw:   raw,
+			scope: models.ScopeBalanceRead,
+			prepare: func(repo *mocks.MockapiKeyRepository) {
+				repo.EXPECT().FindActive(gomock.Any(), utils.HashSecret(raw)).Return(nil, apperrors.ErrInvalidToken)
+			},
+			want: apperrors.ErrInvalidToken,
+		},
+		{
+			name:  "negative test #5, scope not granted",
+			raw:   raw,
+			scope: models.ScopeWithdraw,
+			prepare: func(repo *mocks.MockapiKeyRepository) {
+				repo.EXPECT().FindActive(gomock.Any(), utils.HashSecret(raw)).Return(key, nil)
+			},
+			want: apperrors.ErrInsufficientScope,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockapiKeyRepository(ctrl)
+			tt.prepare(repo)
+
+			userID, err := NewService(testTTL, repo).Verify(context.Background(), tt.raw, tt.scope)
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+				assert.Empty(t, userID)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, models.ModelID("user"), userID)
+		})
+	}
+}
+
+func TestService_Revoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockapiKeyRepository(ctrl)
+	s := NewService(testTTL, repo)
+	ctx := context.Background()
+
+	// the repository scopes the update to the owner, other users' keys are
+	// not found
+	gomock.InOrder(
+		repo.EXPECT().Revoke(gomock.Any(), models.ModelID("user"), models.ModelID("key")).Return(nil),
+		repo.EXPECT().Revoke(gomock.Any(), models.ModelID("other"), models.ModelID("key")).Return(apperrors.ErrNotFound),
+	)
+
+	assert.NoError(t, s.Revoke(ctx, "user", "key"))
+	assert.ErrorIs(t, s.Revoke(ctx, "other", "key"), apperrors.ErrNotFound)
+}
@@ -1,6 +1,10 @@
 package apperrors
 
-import "errors"
+import (
+	"errors"
+	"strings"
+	"time"
+)
 
 var (
 	ErrNotFound            = errors.New("not found")
@@ -11,6 +15,79 @@ var (
 	ErrUnauthorized        = errors.New("unauthorized")
 	ErrNoRequiredValue     = errors.New("no required value")
 	ErrInvalidToken        = errors.New("invalid token")
+	ErrTokenReused         = errors.New("token reused")
 	ErrInsufficientFunds   = errors.New("insufficient funds")
 	ErrNoInformationAnswer = errors.New("no information to answer")
+	ErrInvalidAmount       = errors.New("invalid amount")
+	ErrInvalidRecipient    = errors.New("invalid recipient")
+	ErrLimitExceeded       = errors.New("limit exceeded")
+	ErrInactive            = errors.New("inactive")
+	ErrInvalidReferralCode = errors.New("invalid referral code")
+	ErrTooManyAttempts     = errors.New("too many attempts")
+	ErrTwoFactorRequired   = errors.New("two-factor authentication required")
+	ErrInsufficientScope   = errors.New("insufficient scope")
+	ErrCSRF                = errors.New("cross-site request rejected")
 )
+
+// RetryError tells the client when Err stops applying, e.g. for 429
+// responses with Retry-After.
+type RetryError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// ChallengeError asks the client to complete the login with a second factor
+// for the challenge Token.
+type ChallengeError struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+func (e *ChallengeError) Error() string {
+	return ErrTwoFactorRequired.Error()
+}
+
+func (e *ChallengeError) Unwrap() error {
+	return ErrTwoFactorRequired
+}
+
+// FieldError tells why one field of a request was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists the rejected fields of a request. Handlers send it
+// as the JSON body of 422 responses.
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, f := range e.Errors {
+		msgs = append(msgs, f.Field+": "+f.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Add(field, code, message string) {
+	e.Errors = append(e.Errors, FieldError{field, code, message})
+}
+
+// OrNil returns e if any field was rejected.
+func (e *ValidationError) OrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
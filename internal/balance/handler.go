@@ -2,9 +2,11 @@ package balance
 
 import (
 	"context"
+	"errors"
 
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
 	"github.com/dkmelnik/go-musthave-diploma/internal/dto"
 	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
 	"github.com/dkmelnik/go-musthave-diploma/internal/models"
@@ -13,6 +15,7 @@ import (
 type (
 	balanceService interface {
 		GetCurrentBalance(ctx context.Context, userID models.ModelID) (dto.Balance, error)
+		GetHistory(ctx context.Context, userID models.ModelID) ([]dto.BalanceEntryResponse, error)
 	}
 	handler struct {
 		service balanceService
@@ -37,3 +40,20 @@ func (h *handler) getCurrentBalance(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(out)
 }
+
+func (h *handler) getHistory(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.SendStatus(fiber.StatusUnauthorized)
+	}
+
+	switch out, err := h.service.GetHistory(c.Context(), models.ModelID(userID)); {
+	case errors.Is(err, apperrors.ErrNoInformationAnswer):
+		return c.SendStatus(fiber.StatusNoContent)
+	case err == nil:
+		return c.Status(fiber.StatusOK).JSON(out)
+	default:
+		logger.Log.Info("getHistory", "GetHistory", err)
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+}
@@ -2,10 +2,13 @@ package balance
 
 import (
 	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
 )
 
 type userMiddleware interface {
 	Auth(c *fiber.Ctx) error
+	AuthOrKey(scope string) fiber.Handler
 }
 
 func SetupRouter(
@@ -15,5 +18,6 @@ func SetupRouter(
 ) {
 	handle := newHandler(bs)
 
-	r.Get("balance", middleware.Auth, handle.getCurrentBalance)
+	r.Get("balance", middleware.AuthOrKey(models.ScopeBalanceRead), handle.getCurrentBalance)
+	r.Get("balance/history", middleware.AuthOrKey(models.ScopeBalanceRead), handle.getHistory)
 }
@@ -3,6 +3,7 @@ package balance
 import (
 	"context"
 
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
 	"github.com/dkmelnik/go-musthave-diploma/internal/dto"
 	"github.com/dkmelnik/go-musthave-diploma/internal/models"
 )
@@ -14,14 +15,19 @@ type (
 	withdrawalRepository interface {
 		FindSumOfAmounts(ctx context.Context, userID models.ModelID) (float64, error)
 	}
+	entryRepository interface {
+		FindSumOfAmounts(ctx context.Context, userID models.ModelID) (float64, error)
+		Find(ctx context.Context, userID models.ModelID) ([]*models.BalanceEntry, error)
+	}
 	Service struct {
 		withdrawalRepository withdrawalRepository
 		orderRepository      orderRepository
+		entryRepository      entryRepository
 	}
 )
 
-func NewService(ws withdrawalRepository, or orderRepository) *Service {
-	return &Service{ws, or}
+func NewService(ws withdrawalRepository, or orderRepository, er entryRepository) *Service {
+	return &Service{ws, or, er}
 }
 
 func (s *Service) GetCurrentBalance(ctx context.Context, userID models.ModelID) (dto.Balance, error) {
@@ -34,8 +40,38 @@ func (s *Service) GetCurrentBalance(ctx context.Context, userID models.ModelID)
 	if err != nil {
 		return out, err
 	}
-	out.Current = ac - am
+	en, err := s.entryRepository.FindSumOfAmounts(ctx, userID)
+	if err != nil {
+		return out, err
+	}
+	out.Current = ac - am + en
 	out.Withdrawn = am
 
 	return out, nil
 }
+
+func (s *Service) GetHistory(ctx context.Context, userID models.ModelID) ([]dto.BalanceEntryResponse, error) {
+	entries, err := s.entryRepository.Find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, apperrors.ErrNoInformationAnswer
+	}
+
+	out := make([]dto.BalanceEntryResponse, 0, len(entries))
+
+	for _, v := range entries {
+		out = append(out, dto.BalanceEntryResponse{
+			Kind:         string(v.Kind),
+			Amount:       v.Amount,
+			Counterparty: v.CounterpartyLogin.String,
+			Reference:    v.Reference.String,
+			Reason:       v.ReasonCode.String,
+			Comment:      v.Comment.String,
+			ProcessedAT:  v.CreatedAt,
+		})
+	}
+
+	return out, nil
+}
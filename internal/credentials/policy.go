@@ -0,0 +1,171 @@
+package credentials
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+)
+
+//go:embed banned.txt
+var bannedList string
+
+// Codes of the field errors reported by Policy.
+const (
+	CodeRequired    = "required"
+	CodeTooShort    = "too_short"
+	CodeTooLong     = "too_long"
+	CodeCharset     = "invalid_characters"
+	CodeWeak        = "too_few_character_classes"
+	CodeCommon      = "too_common"
+	CodeSameAsLogin = "same_as_login"
+)
+
+type (
+	// Config of the rules for new logins and passwords. Lengths count
+	// characters, LoginCharset is the body of a regexp character class, e.g.
+	// "a-zA-Z0-9._@-". Character classes are lower case, upper case, digits
+	// and everything else. PasswordMaxBytes caps the encoded length for
+	// hashes with an input limit such as bcrypt, 0 means no cap.
+	Config struct {
+		LoginMinLength     int
+		LoginMaxLength     int
+		LoginCharset       string
+		PasswordMinLength  int
+		PasswordMaxLength  int
+		PasswordMaxBytes   int
+		PasswordMinClasses int
+		BanCommon          bool
+	}
+	// Policy checks new logins and passwords. Existing ones are never
+	// re-checked, so tightening the policy locks nobody out.
+	Policy struct {
+		conf    Config
+		login   *regexp.Regexp
+		outside *regexp.Regexp
+		banned  map[string]struct{}
+	}
+)
+
+func New(conf Config) (*Policy, error) {
+	if conf.LoginMinLength < 1 || conf.LoginMaxLength < conf.LoginMinLength {
+		return nil, fmt.Errorf("invalid login length bounds %d..%d", conf.LoginMinLength, conf.LoginMaxLength)
+	}
+	if conf.PasswordMinLength < 1 || conf.PasswordMaxLength < conf.PasswordMinLength {
+		return nil, fmt.Errorf("invalid password length bounds %d..%d", conf.PasswordMinLength, conf.PasswordMaxLength)
+	}
+	if conf.PasswordMinClasses < 0 || conf.PasswordMinClasses > 4 {
+		return nil, fmt.Errorf("password classes must be between 0 and 4, got %d", conf.PasswordMinClasses)
+	}
+
+	login, err := regexp.Compile("^[" + conf.LoginCharset + "]+$")
+	if err != nil || conf.LoginCharset == "" {
+		return nil, fmt.Errorf("invalid login charset %q", conf.LoginCharset)
+	}
+
+	p := &Policy{
+		conf:    conf,
+		login:   login,
+		outside: regexp.MustCompile("[^" + conf.LoginCharset + "]+"),
+		banned:  make(map[string]struct{}),
+	}
+	if conf.BanCommon {
+		sc := bufio.NewScanner(strings.NewReader(bannedList))
+		for sc.Scan() {
+			line := strings.TrimSpace(sc.Text())
+			if line != "" && !strings.HasPrefix(line, "#") {
+				p.banned[strings.ToLower(line)] = struct{}{}
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// LoginMaxLength is the longest login in characters.
+func (p *Policy) LoginMaxLength() int {
+	return p.conf.LoginMaxLength
+}
+
+// CheckLogin adds the violations of a new login to v.
+func (p *Policy) CheckLogin(v *apperrors.ValidationError, field, login string) {
+	n := utf8.RuneCountInString(login)
+	switch {
+	case n == 0:
+		v.Add(field, CodeRequired, "login is required")
+	case n < p.conf.LoginMinLength:
+		v.Add(field, CodeTooShort, fmt.Sprintf("login must be at least %d characters long", p.conf.LoginMinLength))
+	case n > p.conf.LoginMaxLength:
+		v.Add(field, CodeTooLong, fmt.Sprintf("login must be at most %d characters long", p.conf.LoginMaxLength))
+	case !p.login.MatchString(login):
+		v.Add(field, CodeCharset, fmt.Sprintf("login may only contain [%s]", p.conf.LoginCharset))
+	}
+}
+
+// FitLogin derives a login candidate from s, e.g. a name taken from another
+// system: runs of characters outside the charset become "-", or are dropped
+// if "-" is outside too, and the result is cut reserve characters short of
+// the maximum length to leave room for a suffix. The candidate may still be
+// too short, check it with CheckLogin.
+func (p *Policy) FitLogin(s string, reserve int) string {
+	sep := ""
+	if p.login.MatchString("-") {
+		sep = "-"
+	}
+	login := strings.Trim(p.outside.ReplaceAllString(s, sep), "-")
+
+	if limit := max(p.conf.LoginMaxLength-reserve, 0); utf8.RuneCountInString(login) > limit {
+		login = strings.TrimRight(string([]rune(login)[:limit]), "-")
+	}
+	return login
+}
+
+// CheckPassword adds the violations of a new password of login to v. The
+// login comparison is skipped when login is empty.
+func (p *Policy) CheckPassword(v *apperrors.ValidationError, field, login, password string) {
+	n := utf8.RuneCountInString(password)
+	switch {
+	case n == 0:
+		v.Add(field, CodeRequired, "password is required")
+		return
+	case n < p.conf.PasswordMinLength:
+		v.Add(field, CodeTooShort, fmt.Sprintf("password must be at least %d characters long", p.conf.PasswordMinLength))
+	case n > p.conf.PasswordMaxLength:
+		v.Add(field, CodeTooLong, fmt.Sprintf("password must be at most %d characters long", p.conf.PasswordMaxLength))
+	case p.conf.PasswordMaxBytes > 0 && len(password) > p.conf.PasswordMaxBytes:
+		v.Add(field, CodeTooLong, fmt.Sprintf("password must be at most %d bytes long", p.conf.PasswordMaxBytes))
+	}
+
+	if classes(password) < p.conf.PasswordMinClasses {
+		v.Add(field, CodeWeak, fmt.Sprintf(
+			"password must mix at least %d of lower case, upper case, digits and symbols", p.conf.PasswordMinClasses))
+	}
+	if _, ok := p.banned[strings.ToLower(password)]; ok {
+		v.Add(field, CodeCommon, "password is too common")
+	}
+	if login != "" && strings.EqualFold(password, login) {
+		v.Add(field, CodeSameAsLogin, "password must differ from the login")
+	}
+}
+
+func classes(s string) int {
+	var lower, upper, digit, other int
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+	return lower + upper + digit + other
+}
@@ -0,0 +1,134 @@
+package credentials
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+)
+
+func testPolicy(t *testing.T) *Policy {
+	p, err := New(Config{
+		LoginMinLength:     3,
+		LoginMaxLength:     16,
+		LoginCharset:       "a-z0-9._-",
+		PasswordMinLength:  8,
+		PasswordMaxLength:  32,
+		PasswordMaxBytes:   24,
+		PasswordMinClasses: 2,
+		BanCommon:          true,
+	})
+	require.NoError(t, err)
+	return p
+}
+
+func codes(v *apperrors.ValidationError) []string {
+	out := []string{}
+	for _, f := range v.Errors {
+		out = append(out, f.Code)
+	}
+	return out
+}
+
+func TestPolicy_CheckLogin(t *testing.T) {
+	p := testPolicy(t)
+	tests := []struct {
+		login string
+		want  []string
+	}{
+		{"gopher", []string{}},
+		{"", []string{CodeRequired}},
+		{"go", []string{CodeTooShort}},
+		{strings.Repeat("g", 17), []string{CodeTooLong}},
+		{"Gopher", []string{CodeCharset}},
+		{"go pher", []string{CodeCharset}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.login, func(t *testing.T) {
+			v := &apperrors.ValidationError{}
+			p.CheckLogin(v, "login", tt.login)
+			assert.Equal(t, tt.want, codes(v))
+		})
+	}
+}
+
+func TestPolicy_FitLogin(t *testing.T) {
+	p := testPolicy(t)
+	strict, err := New(Config{
+		LoginMinLength:    3,
+		LoginMaxLength:    16,
+		LoginCharset:      "a-z",
+		PasswordMinLength: 8,
+		PasswordMaxLength: 32,
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		policy  *Policy
+		s       string
+		reserve int
+		want    string
+	}{
+		{name: "fits already", policy: p, s: "gopher", want: "gopher"},
+		{name: "outside runs become dashes", policy: p, s: "John Doe!", want: "ohn-oe"},
+		{name: "dashes dropped when not allowed", policy: strict, s: "go-pher 1", want: "gopher"},
+		{name: "cut with room for a suffix", policy: p, s: strings.Repeat("g", 20), reserve: 7, want: "ggggggggg"},
+		{name: "no dash left at the cut", policy: p, s: "gopher-mart-shop", reserve: 4, want: "gopher-mart"},
+		{name: "nothing left", policy: p, s: "ЖЖЖ", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.FitLogin(tt.s, tt.reserve))
+		})
+	}
+}
+
+func TestPolicy_CheckPassword(t *testing.T) {
+	p := testPolicy(t)
+	tests := []struct {
+		name     string
+		password string
+		want     []string
+	}{
+		{"ok", "correct-horse", []string{}},
+		{"empty", "", []string{CodeRequired}},
+		{"short", "ab1", []string{CodeTooShort}},
+		{"long", strings.Repeat("ab1", 11), []string{CodeTooLong}},
+		{"too many bytes", strings.Repeat("ж1", 13), []string{CodeTooLong}},
+		{"one class", "abcdefghij", []string{CodeWeak}},
+		{"common", "Password1", []string{CodeCommon}},
+		{"same as login", "Gopher-Mart", []string{CodeSameAsLogin}},
+		{"several", "gophermart", []string{CodeWeak, CodeCommon}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &apperrors.ValidationError{}
+			p.CheckPassword(v, "password", "gopher-mart", tt.password)
+			assert.Equal(t, tt.want, codes(v))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{
+		LoginMinLength:    3,
+		LoginMaxLength:    16,
+		LoginCharset:      "z-a",
+		PasswordMinLength: 8,
+		PasswordMaxLength: 32,
+	})
+	assert.Error(t, err)
+
+	_, err = New(Config{
+		LoginMinLength:    3,
+		LoginMaxLength:    2,
+		LoginCharset:      "a-z",
+		PasswordMinLength: 8,
+		PasswordMaxLength: 32,
+	})
+	assert.Error(t, err)
+}
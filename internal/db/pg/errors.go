@@ -0,0 +1,18 @@
+package pg
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the SQLSTATE postgres reports when an insert or update
+// breaks a unique constraint.
+const uniqueViolation = "23505"
+
+// IsUniqueViolation reports whether err comes from a broken unique
+// constraint, which is how a lost race between a check and an insert shows.
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
@@ -0,0 +1,28 @@
+package pg
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsUniqueViolation(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "positive test #1, unique violation", err: &pq.Error{Code: "23505"}, want: true},
+		{name: "positive test #2, wrapped unique violation", err: fmt.Errorf("save: %w", &pq.Error{Code: "23505"}), want: true},
+		{name: "negative test #1, other postgres error", err: &pq.Error{Code: "23503"}},
+		{name: "negative test #2, not a postgres error", err: fmt.Errorf("boom")},
+		{name: "negative test #3, nil", err: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsUniqueViolation(tt.err))
+		})
+	}
+}
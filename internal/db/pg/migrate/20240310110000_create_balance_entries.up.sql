@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS balance_entries (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  kind VARCHAR(50) NOT NULL,
+  amount DECIMAL(10,2) NOT NULL,
+  counterparty_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  reference VARCHAR(255),
+  created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX ON balance_entries (user_id, kind, created_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS balance_entries_tier_bonus_idx;
+ALTER TABLE users DROP COLUMN IF EXISTS tier;
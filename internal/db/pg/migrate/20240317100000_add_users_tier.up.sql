@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tier VARCHAR(50) NOT NULL DEFAULT 'bronze';
+
+CREATE UNIQUE INDEX IF NOT EXISTS balance_entries_tier_bonus_idx
+    ON balance_entries (user_id, kind, reference)
+    WHERE kind = 'TIER_BONUS';
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS promo_codes (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  code VARCHAR(64) NOT NULL UNIQUE,
+  amount DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+  max_redemptions INTEGER NOT NULL DEFAULT 0,
+  per_user_limit INTEGER NOT NULL DEFAULT 1,
+  starts_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  ends_at TIMESTAMPTZ,
+  created_by VARCHAR(255) NOT NULL,
+  created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS promo_redemptions (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  promo_id UUID NOT NULL REFERENCES promo_codes(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX ON promo_redemptions (promo_id, user_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS referrals;
+DROP TYPE IF EXISTS referral_status;
+ALTER TABLE users DROP COLUMN IF EXISTS referral_code;
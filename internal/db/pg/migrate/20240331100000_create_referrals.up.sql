@@ -0,0 +1,19 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS referral_code VARCHAR(16) UNIQUE;
+
+UPDATE users SET referral_code = upper(substr(md5(id::text), 1, 10)) WHERE referral_code IS NULL;
+
+CREATE TYPE referral_status AS ENUM (
+    'PENDING',
+    'REWARDED'
+);
+
+CREATE TABLE IF NOT EXISTS referrals (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  referrer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  referee_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+  status referral_status NOT NULL DEFAULT 'PENDING',
+  created_at TIMESTAMP DEFAULT NOW(),
+  rewarded_at TIMESTAMP
+);
+
+CREATE INDEX ON referrals (referrer_id);
@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS balance_entries_immutable ON balance_entries;
+DROP FUNCTION IF EXISTS balance_entries_immutable();
+
+ALTER TABLE balance_entries DROP CONSTRAINT IF EXISTS balance_entries_counterparty_id_fkey;
+ALTER TABLE balance_entries
+    ADD CONSTRAINT balance_entries_counterparty_id_fkey FOREIGN KEY (counterparty_id) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE balance_entries
+    DROP COLUMN IF EXISTS operator,
+    DROP COLUMN IF EXISTS comment,
+    DROP COLUMN IF EXISTS reason_code;
@@ -0,0 +1,18 @@
+ALTER TABLE balance_entries
+    ADD COLUMN IF NOT EXISTS reason_code VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS comment TEXT,
+    ADD COLUMN IF NOT EXISTS operator VARCHAR(255);
+
+ALTER TABLE balance_entries DROP CONSTRAINT IF EXISTS balance_entries_counterparty_id_fkey;
+ALTER TABLE balance_entries
+    ADD CONSTRAINT balance_entries_counterparty_id_fkey FOREIGN KEY (counterparty_id) REFERENCES users(id);
+
+CREATE OR REPLACE FUNCTION balance_entries_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'balance entries are immutable, post a new entry instead';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER balance_entries_immutable
+    BEFORE UPDATE OR DELETE ON balance_entries
+    FOR EACH ROW EXECUTE FUNCTION balance_entries_immutable();
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS accrual_revisions (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  order_id UUID NOT NULL REFERENCES orders(id),
+  previous_accrual DECIMAL(8,2) NOT NULL,
+  new_accrual DECIMAL(8,2) NOT NULL,
+  created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX ON accrual_revisions (order_id, created_at);
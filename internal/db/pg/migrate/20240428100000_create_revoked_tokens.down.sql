@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS refresh_tokens_jti_idx;
+DROP TABLE IF EXISTS revoked_tokens;
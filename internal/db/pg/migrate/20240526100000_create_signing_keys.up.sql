@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS signing_keys (
+  generation BIGINT PRIMARY KEY,
+  alg VARCHAR(16) NOT NULL,
+  private_key TEXT NOT NULL,
+  created_at TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS users_login_lower_idx;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS disabled_at,
+  DROP COLUMN IF EXISTS role;
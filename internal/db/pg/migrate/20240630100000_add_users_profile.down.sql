@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS notification_outbox;
+DROP TABLE IF EXISTS contact_verifications;
+
+DROP INDEX IF EXISTS users_verified_phone_idx;
+DROP INDEX IF EXISTS users_verified_email_idx;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS time_zone,
+  DROP COLUMN IF EXISTS locale,
+  DROP COLUMN IF EXISTS display_name,
+  DROP COLUMN IF EXISTS phone_verified_at,
+  DROP COLUMN IF EXISTS phone,
+  DROP COLUMN IF EXISTS email_verified_at,
+  DROP COLUMN IF EXISTS email;
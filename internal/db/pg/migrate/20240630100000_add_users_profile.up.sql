@@ -0,0 +1,40 @@
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS email VARCHAR(254) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS phone VARCHAR(16) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS phone_verified_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS display_name VARCHAR(100) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS locale VARCHAR(35) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT '';
+
+-- A verified address belongs to one account only.
+CREATE UNIQUE INDEX IF NOT EXISTS users_verified_email_idx
+  ON users (LOWER(email)) WHERE email_verified_at IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS users_verified_phone_idx
+  ON users (phone) WHERE phone_verified_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS contact_verifications (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  channel VARCHAR(10) NOT NULL CHECK (channel IN ('email', 'phone')),
+  target VARCHAR(254) NOT NULL,
+  code_hash VARCHAR(64) NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  expires_at TIMESTAMPTZ NOT NULL,
+  consumed_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON contact_verifications (user_id, channel);
+
+CREATE TABLE IF NOT EXISTS notification_outbox (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  channel VARCHAR(10) NOT NULL DEFAULT '',
+  recipient VARCHAR(254) NOT NULL,
+  subject VARCHAR(255) NOT NULL,
+  body TEXT NOT NULL,
+  sent_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON notification_outbox (created_at) WHERE sent_at IS NULL;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS magic_links (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  token_hash VARCHAR(64) NOT NULL UNIQUE,
+  expires_at TIMESTAMPTZ NOT NULL,
+  requested_ip VARCHAR(45) NOT NULL DEFAULT '',
+  requested_user_agent VARCHAR(255) NOT NULL DEFAULT '',
+  used_at TIMESTAMPTZ,
+  used_ip VARCHAR(45),
+  used_user_agent VARCHAR(255),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON magic_links (user_id, created_at);
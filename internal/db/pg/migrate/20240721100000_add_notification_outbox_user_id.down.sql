@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS notification_outbox_user_id_idx;
+
+ALTER TABLE notification_outbox
+  DROP COLUMN IF EXISTS user_id;
@@ -0,0 +1,5 @@
+-- Messages carry tokens and codes, they go with the account on deletion.
+ALTER TABLE notification_outbox
+  ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS notification_outbox_user_id_idx ON notification_outbox (user_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS oidc_states_expires_at_idx;
+DROP INDEX IF EXISTS oidc_states_ip_idx;
+
+ALTER TABLE oidc_states
+  DROP COLUMN IF EXISTS ip;
@@ -0,0 +1,6 @@
+-- Pending logins are counted per client address to bound them.
+ALTER TABLE oidc_states
+  ADD COLUMN IF NOT EXISTS ip VARCHAR(45) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS oidc_states_ip_idx ON oidc_states (ip, expires_at);
+CREATE INDEX IF NOT EXISTS oidc_states_expires_at_idx ON oidc_states (expires_at);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS oidc_reauths;
+
+ALTER TABLE oidc_states
+  DROP COLUMN IF EXISTS reauth;
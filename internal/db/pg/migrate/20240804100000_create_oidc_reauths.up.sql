@@ -0,0 +1,11 @@
+-- Logins at the provider started to confirm a sensitive change rather than
+-- to log in.
+ALTER TABLE oidc_states
+  ADD COLUMN IF NOT EXISTS reauth BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Accounts without a password confirm deleting the account or setting a
+-- first password with a fresh login at the provider, good for one use.
+CREATE TABLE IF NOT EXISTS oidc_reauths (
+  user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  expires_at TIMESTAMPTZ NOT NULL
+);
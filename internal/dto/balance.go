@@ -1,6 +1,18 @@
 package dto
 
+import "time"
+
 type Balance struct {
 	Current   float64 `json:"current"`
 	Withdrawn float64 `json:"withdrawn"`
 }
+
+type BalanceEntryResponse struct {
+	Kind         string    `json:"kind"`
+	Amount       float64   `json:"amount"`
+	Counterparty string    `json:"counterparty,omitempty"`
+	Reference    string    `json:"reference,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	Comment      string    `json:"comment,omitempty"`
+	ProcessedAT  time.Time `json:"processed_at"`
+}
@@ -6,4 +6,17 @@ type Claims struct {
 	jwt.RegisteredClaims
 	SUB string
 	JTI string
+	// Role is empty in tokens issued before roles existed, read it as user.
+	Role string `json:",omitempty"`
+}
+
+// IDClaims are the claims of an OpenID Connect ID token the server relies
+// on.
+type IDClaims struct {
+	jwt.RegisteredClaims
+	AuthorizedParty   string `json:"azp"`
+	Nonce             string `json:"nonce"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	PreferredUsername string `json:"preferred_username"`
 }
@@ -0,0 +1,7 @@
+package dto
+
+// Client describes where a login comes from, it is kept with the session.
+type Client struct {
+	IP        string
+	UserAgent string
+}
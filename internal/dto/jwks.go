@@ -0,0 +1,21 @@
+package dto
+
+// JWKS is the JSON Web Key Set served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	KID string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// OKP and EC
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	// EC
+	Y string `json:"y,omitempty"`
+}
@@ -0,0 +1,166 @@
+package hasher
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgArgon2id = "argon2id"
+	AlgBcrypt   = "bcrypt"
+
+	saltLen = 16
+	keyLen  = 32
+
+	// Stored argon2id hashes may use up to paramHeadroom times the
+	// configured memory and time, or of the floors below when the configured
+	// ones are smaller, e.g. with bcrypt. Costlier hashes are rejected before
+	// any work is done, a tampered row can't pin the CPU or exhaust memory.
+	paramHeadroom = 4
+	memoryFloor   = 64 * 1024
+	timeFloor     = 3
+)
+
+// ErrMismatch is returned for wrong passwords and unusable hashes alike.
+var ErrMismatch = errors.New("password does not match")
+
+type (
+	// Config selects the algorithm for new hashes and its parameters.
+	// Memory is in KiB.
+	Config struct {
+		Algorithm  string
+		Memory     uint32
+		Time       uint32
+		Threads    uint8
+		BcryptCost int
+	}
+	// Hasher hashes passwords with the configured algorithm and verifies
+	// hashes of any supported one. Argon2id hashes are PHC strings,
+	// $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>, bcrypt
+	// hashes are stored as bcrypt produces them.
+	Hasher struct {
+		conf  Config
+		limit argon2Params
+	}
+	argon2Params struct {
+		memory  uint32
+		time    uint32
+		threads uint8
+	}
+)
+
+func New(conf Config) (*Hasher, error) {
+	switch conf.Algorithm {
+	case AlgArgon2id:
+		if conf.Memory < 8*uint32(conf.Threads) || conf.Time < 1 || conf.Threads < 1 {
+			return nil, fmt.Errorf("invalid argon2id parameters m=%d,t=%d,p=%d", conf.Memory, conf.Time, conf.Threads)
+		}
+	case AlgBcrypt:
+		if conf.BcryptCost < bcrypt.MinCost || conf.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("invalid bcrypt cost %d", conf.BcryptCost)
+		}
+	default:
+		return nil, fmt.Errorf("unknown password hash algorithm %q", conf.Algorithm)
+	}
+
+	limit := argon2Params{
+		memory: paramHeadroom * max(conf.Memory, memoryFloor),
+		time:   paramHeadroom * max(conf.Time, timeFloor),
+	}
+
+	return &Hasher{conf, limit}, nil
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.conf.Algorithm == AlgBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.conf.BcryptCost)
+		return string(hash), err
+	}
+
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	p := argon2Params{h.conf.Memory, h.conf.Time, h.conf.Threads}
+	key := argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, keyLen)
+
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		AlgArgon2id, argon2.Version, p.memory, p.time, p.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks password against hash. needsRehash reports that the hash
+// was made with another algorithm or other parameters than the configured
+// ones and should be replaced while the password is at hand.
+func (h *Hasher) Verify(hash, password string) (needsRehash bool, err error) {
+	if strings.HasPrefix(hash, "$2") {
+		if err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+			return false, ErrMismatch
+		}
+		if h.conf.Algorithm != AlgBcrypt {
+			return true, nil
+		}
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost != h.conf.BcryptCost, nil
+	}
+
+	p, salt, key, err := parseArgon2id(hash, h.limit)
+	if err != nil {
+		return false, ErrMismatch
+	}
+
+	got := argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(got, key) != 1 {
+		return false, ErrMismatch
+	}
+
+	current := argon2Params{h.conf.Memory, h.conf.Time, h.conf.Threads}
+	return h.conf.Algorithm != AlgArgon2id || p != current || len(key) != keyLen, nil
+}
+
+// parseArgon2id splits a PHC string, refusing memory and time above limit.
+func parseArgon2id(hash string, limit argon2Params) (p argon2Params, salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != AlgArgon2id {
+		return p, nil, nil, errors.New("not an argon2id PHC string")
+	}
+
+	var version int
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return p, nil, nil, err
+	}
+	if version != argon2.Version {
+		return p, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+	if _, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil {
+		return p, nil, nil, err
+	}
+	if p.time < 1 || p.threads < 1 {
+		return p, nil, nil, errors.New("invalid argon2id parameters")
+	}
+	if p.memory > limit.memory || p.time > limit.time {
+		return p, nil, nil, fmt.Errorf("argon2id parameters m=%d,t=%d,p=%d exceed the limit", p.memory, p.time, p.threads)
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return p, nil, nil, err
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return p, nil, nil, err
+	}
+	if len(key) == 0 {
+		return p, nil, nil, errors.New("empty key")
+	}
+
+	return p, salt, key, nil
+}
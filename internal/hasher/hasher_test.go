@@ -0,0 +1,121 @@
+package hasher
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// cheap parameters keep the tests fast
+var testConf = Config{Algorithm: AlgArgon2id, Memory: 64, Time: 1, Threads: 1, BcryptCost: bcrypt.MinCost}
+
+func TestHasher_Argon2id(t *testing.T) {
+	h, err := New(testConf)
+	require.NoError(t, err)
+
+	hash, err := h.Hash("secret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"), hash)
+
+	needsRehash, err := h.Verify(hash, "secret")
+	require.NoError(t, err)
+	assert.False(t, needsRehash)
+
+	_, err = h.Verify(hash, "wrong")
+	assert.ErrorIs(t, err, ErrMismatch)
+
+	other, err := h.Hash("secret")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other, "salts must differ")
+}
+
+func TestHasher_RehashOutdated(t *testing.T) {
+	h, err := New(testConf)
+	require.NoError(t, err)
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	needsRehash, err := h.Verify(string(legacy), "secret")
+	require.NoError(t, err)
+	assert.True(t, needsRehash, "bcrypt hashes are upgraded")
+
+	_, err = h.Verify(string(legacy), "wrong")
+	assert.ErrorIs(t, err, ErrMismatch)
+
+	weaker := testConf
+	weaker.Time = 2
+	h2, err := New(weaker)
+	require.NoError(t, err)
+
+	hash, err := h.Hash("secret")
+	require.NoError(t, err)
+	needsRehash, err = h2.Verify(hash, "secret")
+	require.NoError(t, err)
+	assert.True(t, needsRehash, "changed parameters trigger a rehash")
+}
+
+func TestHasher_Bcrypt(t *testing.T) {
+	conf := testConf
+	conf.Algorithm = AlgBcrypt
+	h, err := New(conf)
+	require.NoError(t, err)
+
+	hash, err := h.Hash("secret")
+	require.NoError(t, err)
+
+	needsRehash, err := h.Verify(hash, "secret")
+	require.NoError(t, err)
+	assert.False(t, needsRehash)
+}
+
+func TestHasher_Unusable(t *testing.T) {
+	h, err := New(testConf)
+	require.NoError(t, err)
+
+	for _, hash := range []string{
+		"",
+		"plain",
+		"$argon2id$v=19$m=64,t=0,p=1$c2FsdA$a2V5",
+		"$argon2id$v=18$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2i$v=19$m=64,t=1,p=1$c2FsdA$a2V5",
+		// far above the configured cost, rejected without hashing
+		"$argon2id$v=19$m=4294967295,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=4294967295,p=1$c2FsdA$a2V5",
+	} {
+		_, err = h.Verify(hash, "")
+		assert.ErrorIs(t, err, ErrMismatch, hash)
+	}
+}
+
+func TestHasher_Limit(t *testing.T) {
+	conf := testConf
+	conf.Memory = 128 * 1024
+	h, err := New(conf)
+	require.NoError(t, err)
+
+	// the configured memory and the time floor set the limits
+	maxMemory, maxTime := conf.Memory*paramHeadroom, uint32(timeFloor*paramHeadroom)
+
+	_, _, _, err = parseArgon2id(fmt.Sprintf("$argon2id$v=19$m=%d,t=%d,p=1$c2FsdA$a2V5", maxMemory, maxTime), h.limit)
+	assert.NoError(t, err)
+	_, _, _, err = parseArgon2id(fmt.Sprintf("$argon2id$v=19$m=%d,t=1,p=1$c2FsdA$a2V5", maxMemory+1), h.limit)
+	assert.Error(t, err)
+	_, _, _, err = parseArgon2id(fmt.Sprintf("$argon2id$v=19$m=64,t=%d,p=1$c2FsdA$a2V5", maxTime+1), h.limit)
+	assert.Error(t, err)
+}
+
+func TestNew_Invalid(t *testing.T) {
+	for _, conf := range []Config{
+		{Algorithm: "md5"},
+		{Algorithm: AlgArgon2id, Memory: 64, Time: 0, Threads: 1},
+		{Algorithm: AlgBcrypt, BcryptCost: 100},
+	} {
+		_, err := New(conf)
+		assert.Error(t, err, conf)
+	}
+}
@@ -0,0 +1,28 @@
+package jwt
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/dto"
+)
+
+type (
+	jwksSource interface {
+		JWKS() dto.JWKS
+	}
+	handler struct {
+		source jwksSource
+	}
+)
+
+func (h *handler) jwks(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(h.source.JWKS())
+}
+
+// SetupRouter serves the JWKS for services verifying our tokens.
+func SetupRouter(r fiber.Router, source jwksSource) {
+	handle := &handler{source}
+
+	r.Get("/.well-known/jwks.json", handle.jwks)
+}
@@ -1,38 +1,55 @@
 package jwt
 
 import (
-	"errors"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
 	"github.com/dkmelnik/go-musthave-diploma/internal/dto"
 	"github.com/dkmelnik/go-musthave-diploma/internal/models"
 )
 
-type Jwt struct {
-	secret   string
-	tokenExp time.Duration
-}
+type (
+	// KeySource hands out the key to sign with and finds keys by kid.
+	KeySource interface {
+		Signing() (*Key, error)
+		Verifying(kid string) (*Key, error)
+		JWKS() dto.JWKS
+	}
+	Jwt struct {
+		keys     KeySource
+		tokenExp time.Duration
+	}
+)
 
-func NewJwt(secret string, tokenExp time.Duration) *Jwt {
+func NewJwt(keys KeySource, tokenExp time.Duration) *Jwt {
 	return &Jwt{
-		secret,
+		keys,
 		tokenExp,
 	}
 }
 
-func (j *Jwt) BuildJWTString(userID models.ModelID, jti string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, dto.Claims{
+func (j *Jwt) BuildJWTString(userID models.ModelID, role, jti string) (string, error) {
+	key, err := j.keys.Signing()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.Method, dto.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.tokenExp)),
 		},
-		SUB: string(userID),
-		JTI: jti,
+		SUB:  string(userID),
+		JTI:  jti,
+		Role: role,
 	})
+	if key.KID != "" {
+		token.Header["kid"] = key.KID
+	}
 
-	tokenString, err := token.SignedString([]byte(j.secret))
+	tokenString, err := token.SignedString(key.Sign)
 	if err != nil {
 		return "", err
 	}
@@ -44,19 +61,29 @@ func (j *Jwt) ParseToken(tokenString string) (*dto.Claims, error) {
 	claims := &dto.Claims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims,
 		func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			kid, _ := t.Header["kid"].(string)
+			key, err := j.keys.Verifying(kid)
+			if err != nil {
+				return nil, err
+			}
+			if t.Method.Alg() != key.Method.Alg() {
 				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 			}
-			return []byte(j.secret), nil
+			return key.Verify, nil
 		})
 
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", apperrors.ErrInvalidToken, err)
 	}
 
 	if !token.Valid {
-		return nil, errors.New("token is invalid")
+		return nil, apperrors.ErrInvalidToken
 	}
 
 	return claims, nil
 }
+
+// JWKS returns the public keys verifiers need.
+func (j *Jwt) JWKS() dto.JWKS {
+	return j.keys.JWKS()
+}
@@ -0,0 +1,138 @@
+package jwt
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/sealer"
+)
+
+type memoryKeys struct {
+	keys map[int64]storedKey
+}
+
+func (m *memoryKeys) Create(_ context.Context, k storedKey) error {
+	if _, ok := m.keys[k.Generation]; !ok {
+		m.keys[k.Generation] = k
+	}
+	return nil
+}
+
+func (m *memoryKeys) FindSince(_ context.Context, generation int64) ([]storedKey, error) {
+	keys := make([]storedKey, 0)
+	for g, k := range m.keys {
+		if g >= generation {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (m *memoryKeys) DeleteBefore(_ context.Context, generation int64) error {
+	for g := range m.keys {
+		if g < generation {
+			delete(m.keys, g)
+		}
+	}
+	return nil
+}
+
+func newTestKeyring(t *testing.T, alg string) (*Keyring, *memoryKeys) {
+	s, err := sealer.Derive("test")
+	require.NoError(t, err)
+
+	repo := &memoryKeys{keys: map[int64]storedKey{}}
+	k, err := NewKeyring(alg, time.Hour, 10*time.Minute, repo, s)
+	require.NoError(t, err)
+	require.NoError(t, k.Refresh(context.Background()))
+
+	return k, repo
+}
+
+func TestJwt_roundTrip(t *testing.T) {
+	for _, alg := range []string{AlgEdDSA, AlgRS256} {
+		t.Run(alg, func(t *testing.T) {
+			k, _ := newTestKeyring(t, alg)
+			j := NewJwt(k, time.Minute)
+
+			token, err := j.BuildJWTString("user", "admin", "jti")
+			require.NoError(t, err)
+
+			claims, err := j.ParseToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, "user", claims.SUB)
+			assert.Equal(t, "jti", claims.JTI)
+			assert.Equal(t, "admin", claims.Role)
+
+			set := k.JWKS()
+			require.NotEmpty(t, set.Keys)
+			signing, err := k.Signing()
+			require.NoError(t, err)
+			found := false
+			for _, key := range set.Keys {
+				if key.KID == signing.KID {
+					found = true
+					assert.Equal(t, alg, key.Alg)
+				}
+			}
+			assert.True(t, found, "signing key is published")
+		})
+	}
+}
+
+func TestJwt_rejectsForeignKeys(t *testing.T) {
+	k1, _ := newTestKeyring(t, AlgEdDSA)
+	k2, _ := newTestKeyring(t, AlgEdDSA)
+
+	token, err := NewJwt(k1, time.Minute).BuildJWTString("user", "admin", "jti")
+	require.NoError(t, err)
+
+	_, err = NewJwt(k2, time.Minute).ParseToken(token)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+
+	// an HS256 token signed with a guessed secret must not pass either
+	hmacToken, err := NewJwt(NewHMACKeys("some_secret"), time.Minute).BuildJWTString("user", "admin", "jti")
+	require.NoError(t, err)
+	_, err = NewJwt(k1, time.Minute).ParseToken(hmacToken)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+}
+
+func TestJwt_HMAC(t *testing.T) {
+	j := NewJwt(NewHMACKeys("secret"), time.Minute)
+
+	token, err := j.BuildJWTString("user", "admin", "jti")
+	require.NoError(t, err)
+
+	claims, err := j.ParseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user", claims.SUB)
+	assert.Empty(t, j.JWKS().Keys, "symmetric keys are never published")
+
+	_, err = NewJwt(NewHMACKeys("other"), time.Minute).ParseToken(token)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+}
+
+func TestKeyring_overlap(t *testing.T) {
+	k, repo := newTestKeyring(t, AlgEdDSA)
+
+	now := time.Now()
+	current := k.generation(now)
+	assert.Contains(t, repo.keys, current)
+
+	// the next key is published only within overlap of its generation
+	nextDue := !now.Before(k.start(current + 1).Add(-k.overlap))
+	_, hasNext := repo.keys[current+1]
+	assert.Equal(t, nextDue, hasNext)
+
+	for _, key := range k.keys {
+		assert.Equal(t, key.NotBefore.Add(-k.overlap), key.PublishAt)
+		assert.Equal(t, key.NotBefore.Add(k.rotation+k.overlap), key.NotAfter)
+		assert.True(t, strings.TrimSpace(key.KID) != "")
+	}
+}
@@ -0,0 +1,238 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+)
+
+type (
+	keyRepository interface {
+		Create(ctx context.Context, k storedKey) error
+		FindSince(ctx context.Context, generation int64) ([]storedKey, error)
+		DeleteBefore(ctx context.Context, generation int64) error
+	}
+	secretSealer interface {
+		Seal(plaintext []byte) (string, error)
+		Open(sealed string) ([]byte, error)
+	}
+	// Keyring rotates asymmetric signing keys. Time is cut into generations
+	// of rotation length, each signed with its own key. A key is published
+	// overlap before its generation starts, so verifiers caching the JWKS
+	// know it in time, and verifies tokens until overlap after it ends.
+	// Keys live in Postgres, sealed, so all replicas share them.
+	Keyring struct {
+		alg        string
+		rotation   time.Duration
+		overlap    time.Duration
+		repository keyRepository
+		sealer     secretSealer
+
+		mu   sync.RWMutex
+		keys []*Key
+	}
+)
+
+func NewKeyring(
+	alg string,
+	rotation, overlap time.Duration,
+	repository keyRepository,
+	sealer secretSealer,
+) (*Keyring, error) {
+	if _, err := signingMethod(alg); err != nil {
+		return nil, err
+	}
+	if rotation <= 0 {
+		return nil, errors.New("key rotation period must be positive")
+	}
+	if overlap <= 0 || overlap >= rotation {
+		return nil, fmt.Errorf("key overlap must be positive and shorter than the rotation period %s", rotation)
+	}
+
+	return &Keyring{
+		alg:        alg,
+		rotation:   rotation,
+		overlap:    overlap,
+		repository: repository,
+		sealer:     sealer,
+	}, nil
+}
+
+// Refresh makes sure the keys of the current and, once due, the next
+// generation exist, drops retired ones and reloads the ring.
+func (k *Keyring) Refresh(ctx context.Context) error {
+	now := time.Now()
+	current := k.generation(now)
+
+	due := []int64{current}
+	if !now.Before(k.start(current + 1).Add(-k.overlap)) {
+		due = append(due, current+1)
+	}
+
+	// the previous generation verifies until overlap into the current one
+	oldest := current - 1
+	stored, err := k.repository.FindSince(ctx, oldest)
+	if err != nil {
+		return err
+	}
+
+	created := false
+	for _, generation := range due {
+		if hasGeneration(stored, generation) {
+			continue
+		}
+		if err = k.create(ctx, generation); err != nil {
+			return err
+		}
+		created = true
+	}
+	if created {
+		if stored, err = k.repository.FindSince(ctx, oldest); err != nil {
+			return err
+		}
+	}
+
+	keys := make([]*Key, 0, len(stored))
+	for _, s := range stored {
+		key, err := k.open(s)
+		if err != nil {
+			return fmt.Errorf("signing key of generation %d: %w", s.Generation, err)
+		}
+		if now.Before(key.NotAfter) {
+			keys = append(keys, key)
+		}
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.mu.Unlock()
+
+	return k.repository.DeleteBefore(ctx, oldest)
+}
+
+// Run refreshes the ring every interval until ctx is done.
+func (k *Keyring) Run(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if err := k.Refresh(ctx); err != nil {
+				logger.Log.Error("keyring:run", "Refresh", err)
+			}
+		}
+	}()
+}
+
+// Signing returns the key of the current generation.
+func (k *Keyring) Signing() (*Key, error) {
+	now := time.Now()
+	current := k.generation(now)
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for _, key := range k.keys {
+		if key.Generation == current {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no signing key for generation %d", current)
+}
+
+func (k *Keyring) Verifying(kid string) (*Key, error) {
+	now := time.Now()
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for _, key := range k.keys {
+		if key.KID == kid && !now.Before(key.PublishAt) && now.Before(key.NotAfter) {
+			return key, nil
+		}
+	}
+
+	return nil, apperrors.ErrInvalidToken
+}
+
+// JWKS lists the published keys.
+func (k *Keyring) JWKS() dto.JWKS {
+	now := time.Now()
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	set := dto.JWKS{Keys: make([]dto.JWK, 0, len(k.keys))}
+	for _, key := range k.keys {
+		if !now.Before(key.PublishAt) && now.Before(key.NotAfter) {
+			set.Keys = append(set.Keys, jwk(key))
+		}
+	}
+
+	return set
+}
+
+func (k *Keyring) create(ctx context.Context, generation int64) error {
+	der, err := generateKey(k.alg)
+	if err != nil {
+		return err
+	}
+	sealed, err := k.sealer.Seal(der)
+	if err != nil {
+		return err
+	}
+
+	return k.repository.Create(ctx, storedKey{
+		Generation: generation,
+		Alg:        k.alg,
+		PrivateKey: sealed,
+	})
+}
+
+func (k *Keyring) open(s storedKey) (*Key, error) {
+	der, err := k.sealer.Open(s.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parseKey(s.Alg, der)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Generation = s.Generation
+	key.NotBefore = k.start(s.Generation)
+	key.PublishAt = key.NotBefore.Add(-k.overlap)
+	key.NotAfter = k.start(s.Generation + 1).Add(k.overlap)
+
+	return key, nil
+}
+
+func (k *Keyring) generation(t time.Time) int64 {
+	return t.UnixNano() / int64(k.rotation)
+}
+
+func (k *Keyring) start(generation int64) time.Time {
+	return time.Unix(0, generation*int64(k.rotation))
+}
+
+func hasGeneration(stored []storedKey, generation int64) bool {
+	for _, s := range stored {
+		if s.Generation == generation {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/dto"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+type (
+	// Key signs or verifies tokens with the method of its algorithm.
+	Key struct {
+		KID    string
+		Method jwt.SigningMethod
+		Sign   interface{}
+		Verify interface{}
+		// Generation, PublishAt, NotBefore and NotAfter are set for rotated
+		// keys: published from PublishAt, signing within [NotBefore,
+		// NotAfter - overlap), verifying until NotAfter.
+		Generation int64
+		PublishAt  time.Time
+		NotBefore  time.Time
+		NotAfter   time.Time
+	}
+	// HMACKeys is the single shared secret of HS256. Its key is never
+	// published, only holders of the secret can verify tokens.
+	HMACKeys struct {
+		key *Key
+	}
+)
+
+func NewHMACKeys(secret string) *HMACKeys {
+	return &HMACKeys{&Key{
+		Method: jwt.SigningMethodHS256,
+		Sign:   []byte(secret),
+		Verify: []byte(secret),
+	}}
+}
+
+func (h *HMACKeys) Signing() (*Key, error) {
+	return h.key, nil
+}
+
+func (h *HMACKeys) Verifying(kid string) (*Key, error) {
+	if kid != "" {
+		return nil, apperrors.ErrInvalidToken
+	}
+	return h.key, nil
+}
+
+func (h *HMACKeys) JWKS() dto.JWKS {
+	return dto.JWKS{Keys: []dto.JWK{}}
+}
+
+func signingMethod(alg string) (jwt.SigningMethod, error) {
+	switch alg {
+	case AlgRS256:
+		return jwt.SigningMethodRS256, nil
+	case AlgEdDSA:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported key algorithm %q", alg)
+	}
+}
+
+// generateKey returns a new private key of alg as PKCS #8 DER.
+func generateKey(alg string) ([]byte, error) {
+	var private crypto.Signer
+	var err error
+	switch alg {
+	case AlgRS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		err = fmt.Errorf("unsupported key algorithm %q", alg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return x509.MarshalPKCS8PrivateKey(private)
+}
+
+// parseKey turns a PKCS #8 DER private key into a Key of alg. The kid is
+// derived from the public key.
+func parseKey(alg string, der []byte) (*Key, error) {
+	method, err := signingMethod(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unexpected private key type %T", parsed)
+	}
+
+	public := private.Public()
+	switch public.(type) {
+	case *rsa.PublicKey:
+		if alg != AlgRS256 {
+			return nil, fmt.Errorf("rsa key used for %s", alg)
+		}
+	case ed25519.PublicKey:
+		if alg != AlgEdDSA {
+			return nil, fmt.Errorf("ed25519 key used for %s", alg)
+		}
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(publicDER)
+
+	return &Key{
+		KID:    hex.EncodeToString(sum[:8]),
+		Method: method,
+		Sign:   private,
+		Verify: public,
+	}, nil
+}
+
+// jwk is the RFC 7517 representation of the public part of k.
+func jwk(k *Key) dto.JWK {
+	j := dto.JWK{
+		KID: k.KID,
+		Use: "sig",
+		Alg: k.Method.Alg(),
+	}
+
+	switch public := k.Verify.(type) {
+	case *rsa.PublicKey:
+		j.Kty = "RSA"
+		j.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		j.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		j.Kty = "OKP"
+		j.Crv = "Ed25519"
+		j.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+
+	return j
+}
@@ -0,0 +1,64 @@
+package jwt
+
+import (
+	"context"
+	"database/sql"
+)
+
+// storedKey is a signing_keys row, the private key sealed.
+type storedKey struct {
+	Generation int64
+	Alg        string
+	PrivateKey string
+}
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+// Create stores the key of a generation unless another replica was first.
+func (r *Repository) Create(ctx context.Context, k storedKey) error {
+	query := `
+		INSERT INTO signing_keys (generation, alg, private_key)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (generation) DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, k.Generation, k.Alg, k.PrivateKey)
+	return err
+}
+
+func (r *Repository) FindSince(ctx context.Context, generation int64) ([]storedKey, error) {
+	query := `
+		SELECT generation, alg, private_key FROM signing_keys
+		WHERE generation >= $1
+		ORDER BY generation
+	`
+	rows, err := r.db.QueryContext(ctx, query, generation)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]storedKey, 0)
+	for rows.Next() {
+		var k storedKey
+		if err = rows.Scan(&k.Generation, &k.Alg, &k.PrivateKey); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *Repository) DeleteBefore(ctx context.Context, generation int64) error {
+	query := `
+		DELETE FROM signing_keys WHERE generation < $1
+	`
+	_, err := r.db.ExecContext(ctx, query, generation)
+	return err
+}
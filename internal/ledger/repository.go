@@ -0,0 +1,81 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+func (r *Repository) FindSumOfAmounts(ctx context.Context, userID models.ModelID) (float64, error) {
+	var total float64
+
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM balance_entries
+		WHERE user_id = $1
+	`
+
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&total)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, apperrors.ErrNotFound
+		}
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (r *Repository) Find(ctx context.Context, userID models.ModelID) ([]*models.BalanceEntry, error) {
+	query := `
+		SELECT e.id, e.user_id, e.kind, e.amount, e.counterparty_id, u.login, e.reference,
+			e.reason_code, e.comment, e.operator, e.created_at
+		FROM balance_entries e
+		LEFT JOIN users u ON u.id = e.counterparty_id
+		WHERE e.user_id = $1
+		ORDER BY e.created_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*models.BalanceEntry
+	for rows.Next() {
+		var entry models.BalanceEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Kind,
+			&entry.Amount,
+			&entry.CounterpartyID,
+			&entry.CounterpartyLogin,
+			&entry.Reference,
+			&entry.ReasonCode,
+			&entry.Comment,
+			&entry.Operator,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
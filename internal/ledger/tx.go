@@ -0,0 +1,51 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+// LockAccounts takes row locks on the given users until tx ends. Debits that
+// must not overdraw an account (withdrawals and transfers) take it before
+// reading the balance, so they are serialized per account. Credits, accrual
+// revisions and admin adjustments insert their entries without it. Ids are
+// locked in a stable order to avoid deadlocks.
+func LockAccounts(ctx context.Context, tx *sql.Tx, userIDs ...models.ModelID) error {
+	ids := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		ids = append(ids, string(id))
+	}
+	sort.Strings(ids)
+
+	query := `
+		SELECT id FROM users WHERE id = $1 FOR UPDATE
+	`
+	for _, id := range ids {
+		var locked string
+		if err := tx.QueryRowContext(ctx, query, id).Scan(&locked); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CurrentBalance returns the balance as seen inside tx.
+func CurrentBalance(ctx context.Context, tx *sql.Tx, userID models.ModelID) (float64, error) {
+	var balance float64
+
+	query := `
+		SELECT
+			(SELECT COALESCE(SUM(accrual), 0) FROM orders WHERE user_id = $1)
+			- (SELECT COALESCE(SUM(amount), 0) FROM withdrawals WHERE user_id = $1)
+			+ (SELECT COALESCE(SUM(amount), 0) FROM balance_entries WHERE user_id = $1)
+	`
+	if err := tx.QueryRowContext(ctx, query, userID).Scan(&balance); err != nil {
+		return 0, err
+	}
+
+	return balance, nil
+}
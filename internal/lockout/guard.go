@@ -0,0 +1,189 @@
+package lockout
+
+import (
+	"context"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+)
+
+const (
+	loginPrefix = "login:"
+	ipPrefix    = "ip:"
+)
+
+type (
+	// Config of the guard. Attempts are counted per login and per IP within
+	// Window, successful ones are given back. Once half of the allowed
+	// failures are spent every further one delays the next attempt by
+	// BaseDelay, doubling up to MaxDelay; reaching the maximum locks the key
+	// for Lockout.
+	Config struct {
+		LoginMaxFailures int
+		IPMaxFailures    int
+		Window           time.Duration
+		BaseDelay        time.Duration
+		MaxDelay         time.Duration
+		Lockout          time.Duration
+	}
+	attemptRepository interface {
+		LockedUntil(ctx context.Context, loginKey, ipKey string) (time.Time, error)
+		Attempt(ctx context.Context, key string, maxFailures int, window, lockout time.Duration) (bool, error)
+		Delay(ctx context.Context, key string, schedule []float64) error
+		Release(ctx context.Context, key string) error
+		Delete(ctx context.Context, key string) error
+		DeleteStale(ctx context.Context, window time.Duration) (int64, error)
+	}
+	// Guard keeps its state in Postgres so every replica sees the same locks.
+	Guard struct {
+		conf       Config
+		repository attemptRepository
+	}
+)
+
+func NewGuard(conf Config, repository attemptRepository) *Guard {
+	return &Guard{conf, repository}
+}
+
+// Check counts an attempt for the login and the IP before the credentials
+// are checked, so concurrent attempts cannot overshoot the limits. It returns
+// an *apperrors.RetryError wrapping ErrTooManyAttempts while the login or the
+// IP is delayed or locked out. Every counted attempt must end in Fail or
+// Succeed.
+func (g *Guard) Check(ctx context.Context, login, ip string) error {
+	for _, k := range []struct {
+		key         string
+		maxFailures int
+	}{
+		{ipPrefix + ip, g.conf.IPMaxFailures},
+		{loginPrefix + login, g.conf.LoginMaxFailures},
+	} {
+		if k.maxFailures <= 0 {
+			continue
+		}
+
+		counted, err := g.repository.Attempt(ctx, k.key, k.maxFailures, g.conf.Window, g.conf.Lockout)
+		if err != nil {
+			return err
+		}
+		if !counted {
+			return g.retryError(ctx, login, ip)
+		}
+	}
+
+	return nil
+}
+
+// Fail turns the attempts counted by Check into failures, delaying the next
+// attempt of the login and the IP.
+func (g *Guard) Fail(ctx context.Context, login, ip string) error {
+	if err := g.delay(ctx, loginPrefix+login, g.conf.LoginMaxFailures); err != nil {
+		return err
+	}
+
+	return g.delay(ctx, ipPrefix+ip, g.conf.IPMaxFailures)
+}
+
+// Succeed clears the login's counter and gives the IP its attempt back. The
+// IP's failures are left to expire, a valid account of the attacker must not
+// reset them.
+func (g *Guard) Succeed(ctx context.Context, login, ip string) error {
+	if err := g.repository.Delete(ctx, loginPrefix+login); err != nil {
+		return err
+	}
+	if g.conf.IPMaxFailures <= 0 {
+		return nil
+	}
+
+	return g.repository.Release(ctx, ipPrefix+ip)
+}
+
+func (g *Guard) ClearLogin(ctx context.Context, login string) error {
+	return g.repository.Delete(ctx, loginPrefix+login)
+}
+
+func (g *Guard) ClearIP(ctx context.Context, ip string) error {
+	return g.repository.Delete(ctx, ipPrefix+ip)
+}
+
+func (g *Guard) retryError(ctx context.Context, login, ip string) error {
+	until, err := g.repository.LockedUntil(ctx, loginPrefix+login, ipPrefix+ip)
+	if err != nil {
+		return err
+	}
+
+	// a concurrent attempt may not have set its lock yet
+	wait := time.Until(until)
+	if wait <= 0 {
+		wait = time.Second
+	}
+
+	return &apperrors.RetryError{Err: apperrors.ErrTooManyAttempts, RetryAfter: wait}
+}
+
+func (g *Guard) delay(ctx context.Context, key string, maxFailures int) error {
+	if maxFailures <= 0 {
+		return nil
+	}
+
+	return g.repository.Delay(ctx, key, g.schedule(maxFailures))
+}
+
+// schedule lists the wait in seconds after each number of failures, the
+// last entry applies to every further failure.
+func (g *Guard) schedule(maxFailures int) []float64 {
+	out := make([]float64, maxFailures)
+	for i := range out {
+		out[i] = g.wait(i+1, maxFailures).Seconds()
+	}
+	return out
+}
+
+func (g *Guard) wait(failures, maxFailures int) time.Duration {
+	if failures >= maxFailures {
+		return g.conf.Lockout
+	}
+
+	free := maxFailures / 2
+	if failures <= free {
+		return 0
+	}
+
+	delay := g.conf.BaseDelay
+	for i := free + 1; i < failures && delay < g.conf.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > g.conf.MaxDelay {
+		delay = g.conf.MaxDelay
+	}
+
+	return delay
+}
+
+// CollectGarbage deletes stale counters every interval until ctx is done.
+func (g *Guard) CollectGarbage(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			n, err := g.repository.DeleteStale(ctx, g.conf.Window)
+			if err != nil {
+				logger.Log.Error("lockout:collectGarbage", "DeleteStale", err)
+				continue
+			}
+			logger.Log.Debug("lockout:collectGarbage", "deleted", n)
+		}
+	}()
+}
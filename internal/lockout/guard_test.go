@@ -0,0 +1,161 @@
+package lockout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/lockout/mocks"
+)
+
+func TestGuard_wait(t *testing.T) {
+	g := NewGuard(Config{
+		BaseDelay: time.Second,
+		MaxDelay:  5 * time.Second,
+		Lockout:   time.Minute,
+	}, nil)
+
+	tests := []struct {
+		name     string
+		failures int
+		want     time.Duration
+	}{
+		{name: "first failure is free", failures: 1, want: 0},
+		{name: "half of the allowance is free", failures: 5, want: 0},
+		{name: "first delayed failure", failures: 6, want: time.Second},
+		{name: "delay doubles", failures: 7, want: 2 * time.Second},
+		{name: "delay doubles again", failures: 8, want: 4 * time.Second},
+		{name: "delay is capped", failures: 9, want: 5 * time.Second},
+		{name: "maximum locks out", failures: 10, want: time.Minute},
+		{name: "past maximum stays locked out", failures: 12, want: time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, g.wait(tt.failures, 10))
+		})
+	}
+}
+
+func testGuard(ctrl *gomock.Controller) (*Guard, *mocks.MockattemptRepository) {
+	repo := mocks.NewMockattemptRepository(ctrl)
+	return NewGuard(Config{
+		LoginMaxFailures: 4,
+		IPMaxFailures:    6,
+		Window:           time.Hour,
+		BaseDelay:        time.Second,
+		MaxDelay:         5 * time.Second,
+		Lockout:          time.Minute,
+	}, repo), repo
+}
+
+func TestGuard_Check(t *testing.T) {
+	tests := []struct {
+		name        string
+		ipCounted   bool
+		login       bool
+		loginCounts bool
+		lockedFor   time.Duration
+		want        time.Duration
+	}{
+		{
+			name:        "positive test #1, attempt counted for the IP and the login",
+			ipCounted:   true,
+			login:       true,
+			loginCounts: true,
+		},
+		{
+			name:      "negative test #2, locked IP, the login is not counted",
+			lockedFor: time.Minute,
+			want:      time.Minute,
+		},
+		{
+			name:      "negative test #3, locked login",
+			ipCounted: true,
+			login:     true,
+			lockedFor: 30 * time.Second,
+			want:      30 * time.Second,
+		},
+		{
+			name:      "negative test #4, limit hit by a concurrent attempt before its lock",
+			ipCounted: true,
+			login:     true,
+			want:      time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			g, repo := testGuard(ctrl)
+
+			repo.EXPECT().Attempt(gomock.Any(), "ip:10.0.0.1", 6, time.Hour, time.Minute).Return(tt.ipCounted, nil)
+			if tt.login {
+				repo.EXPECT().Attempt(gomock.Any(), "login:alice", 4, time.Hour, time.Minute).Return(tt.loginCounts, nil)
+			}
+			if !tt.ipCounted || !tt.loginCounts {
+				var until time.Time
+				if tt.lockedFor > 0 {
+					until = time.Now().Add(tt.lockedFor)
+				}
+				repo.EXPECT().LockedUntil(gomock.Any(), "login:alice", "ip:10.0.0.1").Return(until, nil)
+			}
+
+			err := g.Check(context.Background(), "alice", "10.0.0.1")
+			if tt.want == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var retry *apperrors.RetryError
+			require.ErrorAs(t, err, &retry)
+			assert.ErrorIs(t, err, apperrors.ErrTooManyAttempts)
+			assert.InDelta(t, tt.want.Seconds(), retry.RetryAfter.Seconds(), 1)
+		})
+	}
+}
+
+func TestGuard_Fail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g, repo := testGuard(ctrl)
+
+	gomock.InOrder(
+		repo.EXPECT().Delay(gomock.Any(), "login:alice", []float64{0, 0, 1, 60}).Return(nil),
+		repo.EXPECT().Delay(gomock.Any(), "ip:10.0.0.1", []float64{0, 0, 0, 1, 2, 60}).Return(nil),
+	)
+
+	assert.NoError(t, g.Fail(context.Background(), "alice", "10.0.0.1"))
+}
+
+func TestGuard_Succeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g, repo := testGuard(ctrl)
+
+	gomock.InOrder(
+		repo.EXPECT().Delete(gomock.Any(), "login:alice").Return(nil),
+		repo.EXPECT().Release(gomock.Any(), "ip:10.0.0.1").Return(nil),
+	)
+
+	assert.NoError(t, g.Succeed(context.Background(), "alice", "10.0.0.1"))
+}
+
+func TestGuard_disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// no repository calls are expected with both limits off
+	g := NewGuard(Config{Window: time.Hour}, mocks.NewMockattemptRepository(ctrl))
+	ctx := context.Background()
+
+	assert.NoError(t, g.Check(ctx, "alice", "10.0.0.1"))
+	assert.NoError(t, g.Fail(ctx, "alice", "10.0.0.1"))
+}
@@ -0,0 +1,54 @@
+package lockout
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+)
+
+type (
+	lockoutService interface {
+		ClearLogin(ctx context.Context, login string) error
+		ClearIP(ctx context.Context, ip string) error
+	}
+	handler struct {
+		service lockoutService
+	}
+)
+
+func newHandler(ls lockoutService) *handler {
+	return &handler{ls}
+}
+
+func (h *handler) clearLogin(c *fiber.Ctx) error {
+	operator, _ := c.Locals("user_id").(string)
+	login, err := url.PathUnescape(c.Params("login"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(http.StatusText(fiber.StatusBadRequest))
+	}
+
+	if err := h.service.ClearLogin(c.Context(), login); err != nil {
+		logger.Log.Error("lockout:handler:clearLogin", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+	logger.Log.Info("lockout:handler:clearLogin", "operator", operator, "login", login)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *handler) clearIP(c *fiber.Ctx) error {
+	operator, _ := c.Locals("user_id").(string)
+	ip := c.Params("ip")
+
+	if err := h.service.ClearIP(c.Context(), ip); err != nil {
+		logger.Log.Error("lockout:handler:clearIP", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+	logger.Log.Info("lockout:handler:clearIP", "operator", operator, "ip", ip)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: guard.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockattemptRepository is a mock of attemptRepository interface.
+type MockattemptRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockattemptRepositoryMockRecorder
+}
+
+// MockattemptRepositoryMockRecorder is the mock recorder for MockattemptRepository.
+type MockattemptRepositoryMockRecorder struct {
+	mock *MockattemptRepository
+}
+
+// NewMockattemptRepository creates a new mock instance.
+func NewMockattemptRepository(ctrl *gomock.Controller) *MockattemptRepository {
+	mock := &MockattemptRepository{ctrl: ctrl}
+	mock.recorder = &MockattemptRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockattemptRepository) EXPECT() *MockattemptRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Attempt mocks base method.
+func (m *MockattemptRepository) Attempt(ctx context.Context, key string, maxFailures int, window, lockout time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Attempt", ctx, key, maxFailures, window, lockout)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Attempt indicates an expected call of Attempt.
+func (mr *MockattemptRepositoryMockRecorder) Attempt(ctx, key, maxFailures, window, lockout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attempt", reflect.TypeOf((*MockattemptRepository)(nil).Attempt), ctx, key, maxFailures, window, lockout)
+}
+
+// Delay mocks base method.
+func (m *MockattemptRepository) Delay(ctx context.Context, key string, schedule []float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delay", ctx, key, schedule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delay indicates an expected call of Delay.
+func (mr *MockattemptRepositoryMockRecorder) Delay(ctx, key, schedule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delay", reflect.TypeOf((*MockattemptRepository)(nil).Delay), ctx, key, schedule)
+}
+
+// Delete mocks base method.
+func (m *MockattemptRepository) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockattemptRepositoryMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockattemptRepository)(nil).Delete), ctx, key)
+}
+
+// DeleteStale mocks base method.
+func (m *MockattemptRepository) DeleteStale(ctx context.Context, window time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStale", ctx, window)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStale indicates an expected call of DeleteStale.
+func (mr *MockattemptRepositoryMockRecorder) DeleteStale(ctx, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStale", reflect.TypeOf((*MockattemptRepository)(nil).DeleteStale), ctx, window)
+}
+
+// LockedUntil mocks base method.
+func (m *MockattemptRepository) LockedUntil(ctx context.Context, loginKey, ipKey string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockedUntil", ctx, loginKey, ipKey)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockedUntil indicates an expected call of LockedUntil.
+func (mr *MockattemptRepositoryMockRecorder) LockedUntil(ctx, loginKey, ipKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockedUntil", reflect.TypeOf((*MockattemptRepository)(nil).LockedUntil), ctx, loginKey, ipKey)
+}
+
+// Release mocks base method.
+func (m *MockattemptRepository) Release(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockattemptRepositoryMockRecorder) Release(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockattemptRepository)(nil).Release), ctx, key)
+}
@@ -0,0 +1,112 @@
+package lockout
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+// LockedUntil returns the latest lock of the given keys, zero when none is
+// locked.
+func (r *Repository) LockedUntil(ctx context.Context, loginKey, ipKey string) (time.Time, error) {
+	var until sql.NullTime
+	query := `
+		SELECT MAX(locked_until) FROM login_attempts
+		WHERE key IN ($1, $2) AND locked_until > NOW()
+	`
+	if err := r.db.QueryRowContext(ctx, query, loginKey, ipKey).Scan(&until); err != nil {
+		return time.Time{}, err
+	}
+	return until.Time, nil
+}
+
+// Attempt counts an attempt for key unless it is locked. Failures older than
+// window start the count afresh, reaching maxFailures locks the key for
+// lockout right away. Checking, counting and locking is one statement, so
+// concurrent attempts cannot slip past the limit. It reports whether the
+// attempt was counted.
+func (r *Repository) Attempt(ctx context.Context, key string, maxFailures int, window, lockout time.Duration) (bool, error) {
+	var failures int
+	query := `
+		INSERT INTO login_attempts (key, failures, last_failure_at, locked_until)
+		VALUES ($1, 1, NOW(), CASE WHEN $2 <= 1 THEN NOW() + $4 * INTERVAL '1 second' END)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN login_attempts.last_failure_at < NOW() - $3 * INTERVAL '1 second' THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			last_failure_at = NOW(),
+			locked_until = CASE
+				WHEN login_attempts.last_failure_at >= NOW() - $3 * INTERVAL '1 second'
+					AND login_attempts.failures + 1 >= $2
+				THEN NOW() + $4 * INTERVAL '1 second'
+				ELSE login_attempts.locked_until
+			END
+		WHERE login_attempts.locked_until IS NULL OR login_attempts.locked_until <= NOW()
+		RETURNING failures
+	`
+	err := r.db.QueryRowContext(ctx, query, key, maxFailures, window.Seconds(), lockout.Seconds()).Scan(&failures)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Delay locks key for the wait schedule lists for its failures, the last
+// entry covers every further failure. A longer lock is kept.
+func (r *Repository) Delay(ctx context.Context, key string, schedule []float64) error {
+	query := `
+		UPDATE login_attempts SET locked_until = GREATEST(
+			locked_until,
+			NOW() + ($2::float8[])[LEAST(failures, cardinality($2::float8[]))] * INTERVAL '1 second'
+		)
+		WHERE key = $1 AND failures > 0
+	`
+	_, err := r.db.ExecContext(ctx, query, key, pq.Array(schedule))
+	return err
+}
+
+// Release takes back an attempt of key that turned out to be successful.
+func (r *Repository) Release(ctx context.Context, key string) error {
+	query := `
+		UPDATE login_attempts SET failures = GREATEST(failures - 1, 0) WHERE key = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, key)
+	return err
+}
+
+func (r *Repository) Delete(ctx context.Context, key string) error {
+	query := `
+		DELETE FROM login_attempts WHERE key = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, key)
+	return err
+}
+
+// DeleteStale drops counters that neither lock nor count anymore.
+func (r *Repository) DeleteStale(ctx context.Context, window time.Duration) (int64, error) {
+	query := `
+		DELETE FROM login_attempts
+		WHERE last_failure_at < NOW() - $1 * INTERVAL '1 second'
+			AND (locked_until IS NULL OR locked_until < NOW())
+	`
+	res, err := r.db.ExecContext(ctx, query, window.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,25 @@
+package lockout
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type UserMiddleware interface {
+	Auth(c *fiber.Ctx) error
+	RequireRole(roles ...string) fiber.Handler
+}
+
+func SetupRouter(
+	ar fiber.Router,
+	mw UserMiddleware,
+	ls lockoutService,
+) {
+	handle := newHandler(ls)
+
+	admins := mw.RequireRole(models.RoleAdmin)
+
+	ar.Delete("lockouts/logins/:login", mw.Auth, admins, handle.clearLogin)
+	ar.Delete("lockouts/ips/:ip", mw.Auth, admins, handle.clearIP)
+}
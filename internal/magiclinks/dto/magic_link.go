@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/credentials"
+)
+
+type RequestPayload struct {
+	Login string `json:"login"`
+}
+
+func (r *RequestPayload) Validate() error {
+	v := &apperrors.ValidationError{}
+	if strings.TrimSpace(r.Login) == "" {
+		v.Add("login", credentials.CodeRequired, "login is required")
+	}
+
+	return v.OrNil()
+}
@@ -0,0 +1,141 @@
+package magiclinks
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/magiclinks/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens"
+)
+
+type (
+	magicLinkService interface {
+		Request(ctx context.Context, d dto.RequestPayload, client appdto.Client) error
+		Consume(ctx context.Context, token string, client appdto.Client) (appdto.Tokens, error)
+	}
+	tokenTransport interface {
+		SetCookies(c *fiber.Ctx, issued appdto.Tokens)
+		CheckCSRF(c *fiber.Ctx) error
+		CSRFToken(c *fiber.Ctx) string
+	}
+	handler struct {
+		service   magicLinkService
+		transport tokenTransport
+		appURL    string
+	}
+)
+
+// confirmPage asks the user to confirm the login, so that mail scanners and
+// link previews fetching the link don't spend it. The form posts to
+// login/magic/confirm relative to the link.
+var confirmPage = template.Must(template.New("confirm").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>Log in to Gophermart</title></head>
+<body>
+<form method="post" action="magic/confirm">
+<input type="hidden" name="token" value="{{.Token}}">
+{{if .CSRF}}<input type="hidden" name="csrf_token" value="{{.CSRF}}">{{end}}
+<button type="submit">Log in to Gophermart</button>
+</form>
+</body>
+</html>
+`))
+
+func newHandler(ms magicLinkService, transport tokenTransport, appURL string) *handler {
+	return &handler{ms, transport, appURL}
+}
+
+// request answers 202 whether or not a link was sent.
+func (h *handler) request(c *fiber.Ctx) error {
+	var body dto.RequestPayload
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	}
+	if err := body.Validate(); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(err)
+	}
+
+	if err := h.service.Request(c.Context(), body, tokens.ClientOf(c)); err != nil {
+		logger.Log.Error("magiclinks:handler:request", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+
+	return c.SendStatus(fiber.StatusAccepted)
+}
+
+// confirm is the target of the link. It does not spend the link, only
+// serves the page posting it to consume.
+func (h *handler) confirm(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if token == "" {
+		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+	}
+
+	// the token is in the address of this page
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set(fiber.HeaderReferrerPolicy, "no-referrer")
+	c.Set(fiber.HeaderXFrameOptions, "DENY")
+	c.Set(fiber.HeaderContentSecurityPolicy, "default-src 'none'; form-action 'self'; frame-ancestors 'none'")
+	c.Type("html", "utf-8")
+
+	return confirmPage.Execute(c.Response().BodyWriter(), struct{ Token, CSRF string }{token, h.transport.CSRFToken(c)})
+}
+
+// consume logs the browser in like POST /api/user/login does and sends it
+// to the app. Outcomes other than a login are passed in the fragment, which
+// stays in the browser: "error" is invalid_link or inactive; users with 2FA
+// get "challenge_token" and "expires_at" to finish with
+// POST /api/user/login/2fa.
+func (h *handler) consume(c *fiber.Ctx) error {
+	if err := h.transport.CheckCSRF(c); err != nil {
+		logger.Log.Warn("magiclinks:handler:consume", "CheckCSRF", c.IP())
+		return c.Status(fiber.StatusForbidden).SendString(http.StatusText(fiber.StatusForbidden))
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	token := c.FormValue("token")
+	if token == "" {
+		return h.redirect(c, url.Values{"error": {"invalid_link"}})
+	}
+
+	issued, err := h.service.Consume(c.Context(), token, tokens.ClientOf(c))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidToken) {
+			return h.redirect(c, url.Values{"error": {"invalid_link"}})
+		}
+		if errors.Is(err, apperrors.ErrInactive) {
+			return h.redirect(c, url.Values{"error": {"inactive"}})
+		}
+		var challenge *apperrors.ChallengeError
+		if errors.As(err, &challenge) {
+			return h.redirect(c, url.Values{
+				"challenge_token": {challenge.Token},
+				"expires_at":      {challenge.ExpiresAt.Format(time.RFC3339)},
+			})
+		}
+		logger.Log.Error("magiclinks:handler:consume", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+
+	h.transport.SetCookies(c, issued)
+	return h.redirect(c, nil)
+}
+
+// redirect sends the browser to the app with fragment.
+func (h *handler) redirect(c *fiber.Ctx, fragment url.Values) error {
+	location := h.appURL
+	if len(fragment) > 0 {
+		location = strings.TrimSuffix(location, "#") + "#" + fragment.Encode()
+	}
+	return c.Redirect(location, fiber.StatusSeeOther)
+}
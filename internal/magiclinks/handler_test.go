@@ -0,0 +1,237 @@
+package magiclinks
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/magiclinks/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/magiclinks/mocks"
+)
+
+const testAppURL = "https://shop.example.com/app"
+
+func newTestApp(ms magicLinkService, transport tokenTransport) *fiber.App {
+	app := fiber.New()
+	SetupRouter(app.Group("/api/user"), transport, testAppURL, ms)
+	return app
+}
+
+func Test_request(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		service bool
+		err     error
+		code    int
+	}{
+		{
+			name: "negative test #1, bad entity",
+			body: `{"login":`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "negative test #2, no login",
+			body: `{"login":" "}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "negative test #3, unknown service error",
+			body:    `{"login":"alice"}`,
+			service: true,
+			err:     errors.New("db is down"),
+			code:    http.StatusInternalServerError,
+		},
+		{
+			name:    "positive test #4, accepted",
+			body:    `{"login":"alice"}`,
+			service: true,
+			code:    http.StatusAccepted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ms := mocks.NewMockmagicLinkService(ctrl)
+			if tt.service {
+				ms.EXPECT().Request(gomock.Any(), dto.RequestPayload{Login: "alice"}, gomock.Any()).Return(tt.err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/login/magic", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := newTestApp(ms, mocks.NewMocktokenTransport(ctrl)).Test(req, 100)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+
+func Test_confirm(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		csrf  string
+		code  int
+		want  []string
+	}{
+		{
+			name: "negative test #1, no token",
+			code: http.StatusUnauthorized,
+		},
+		{
+			name:  "positive test #2, confirmation form",
+			query: "?token=se%22cret",
+			code:  http.StatusOK,
+			want:  []string{`action="magic/confirm"`, `name="token" value="se&#34;cret"`},
+		},
+		{
+			name:  "positive test #3, confirmation form with a CSRF token",
+			query: "?token=secret",
+			csrf:  "csrf",
+			code:  http.StatusOK,
+			want:  []string{`name="token" value="secret"`, `name="csrf_token" value="csrf"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// showing the page spends nothing
+			ms := mocks.NewMockmagicLinkService(ctrl)
+			transport := mocks.NewMocktokenTransport(ctrl)
+			if tt.code == http.StatusOK {
+				transport.EXPECT().CSRFToken(gomock.Any()).Return(tt.csrf)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/user/login/magic"+tt.query, nil)
+			resp, err := newTestApp(ms, transport).Test(req, 100)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+			if tt.code != http.StatusOK {
+				return
+			}
+			assert.Equal(t, "no-store", resp.Header.Get(fiber.HeaderCacheControl))
+			assert.Equal(t, "no-referrer", resp.Header.Get(fiber.HeaderReferrerPolicy))
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			for _, w := range tt.want {
+				assert.Contains(t, string(body), w)
+			}
+			if tt.csrf == "" {
+				assert.NotContains(t, string(body), "csrf_token")
+			}
+		})
+	}
+}
+
+func Test_consume(t *testing.T) {
+	expiresAt := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		form     url.Values
+		csrf     error
+		service  bool
+		err      error
+		code     int
+		location string
+	}{
+		{
+			name: "negative test #1, CSRF check failed",
+			form: url.Values{"token": {"secret"}},
+			csrf: apperrors.ErrCSRF,
+			code: http.StatusForbidden,
+		},
+		{
+			name:     "negative test #2, no token",
+			code:     http.StatusSeeOther,
+			location: testAppURL + "#error=invalid_link",
+		},
+		{
+			name:     "negative test #3, invalid link",
+			form:     url.Values{"token": {"secret"}},
+			service:  true,
+			err:      apperrors.ErrInvalidToken,
+			code:     http.StatusSeeOther,
+			location: testAppURL + "#error=invalid_link",
+		},
+		{
+			name:     "negative test #4, disabled account",
+			form:     url.Values{"token": {"secret"}},
+			service:  true,
+			err:      apperrors.ErrInactive,
+			code:     http.StatusSeeOther,
+			location: testAppURL + "#error=inactive",
+		},
+		{
+			name:     "negative test #5, 2FA challenge",
+			form:     url.Values{"token": {"secret"}},
+			service:  true,
+			err:      &apperrors.ChallengeError{Token: "challenge", ExpiresAt: expiresAt},
+			code:     http.StatusSeeOther,
+			location: testAppURL + "#challenge_token=challenge&expires_at=2024-07-01T12%3A00%3A00Z",
+		},
+		{
+			name:    "negative test #6, unknown service error",
+			form:    url.Values{"token": {"secret"}},
+			service: true,
+			err:     errors.New("db is down"),
+			code:    http.StatusInternalServerError,
+		},
+		{
+			name:     "positive test #7, logged in",
+			form:     url.Values{"token": {"secret"}},
+			service:  true,
+			code:     http.StatusSeeOther,
+			location: testAppURL,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			issued := appdto.Tokens{AccessToken: "access"}
+			ms := mocks.NewMockmagicLinkService(ctrl)
+			transport := mocks.NewMocktokenTransport(ctrl)
+			transport.EXPECT().CheckCSRF(gomock.Any()).Return(tt.csrf)
+			if tt.service {
+				ms.EXPECT().Consume(gomock.Any(), "secret", gomock.Any()).Return(issued, tt.err)
+			}
+			if tt.service && tt.err == nil {
+				transport.EXPECT().SetCookies(gomock.Any(), issued)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/user/login/magic/confirm", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			resp, err := newTestApp(ms, transport).Test(req, 100)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+			assert.Equal(t, tt.location, resp.Header.Get(fiber.HeaderLocation))
+		})
+	}
+}
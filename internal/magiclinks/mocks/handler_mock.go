@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	dto0 "github.com/dkmelnik/go-musthave-diploma/internal/magiclinks/dto"
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockmagicLinkService is a mock of magicLinkService interface.
+type MockmagicLinkService struct {
+	ctrl     *gomock.Controller
+	recorder *MockmagicLinkServiceMockRecorder
+}
+
+// MockmagicLinkServiceMockRecorder is the mock recorder for MockmagicLinkService.
+type MockmagicLinkServiceMockRecorder struct {
+	mock *MockmagicLinkService
+}
+
+// NewMockmagicLinkService creates a new mock instance.
+func NewMockmagicLinkService(ctrl *gomock.Controller) *MockmagicLinkService {
+	mock := &MockmagicLinkService{ctrl: ctrl}
+	mock.recorder = &MockmagicLinkServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockmagicLinkService) EXPECT() *MockmagicLinkServiceMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockmagicLinkService) Consume(ctx context.Context, token string, client dto.Client) (dto.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, token, client)
+	ret0, _ := ret[0].(dto.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockmagicLinkServiceMockRecorder) Consume(ctx, token, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockmagicLinkService)(nil).Consume), ctx, token, client)
+}
+
+// Request mocks base method.
+func (m *MockmagicLinkService) Request(ctx context.Context, d dto0.RequestPayload, client dto.Client) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Request", ctx, d, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Request indicates an expected call of Request.
+func (mr *MockmagicLinkServiceMockRecorder) Request(ctx, d, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockmagicLinkService)(nil).Request), ctx, d, client)
+}
+
+// MocktokenTransport is a mock of tokenTransport interface.
+type MocktokenTransport struct {
+	ctrl     *gomock.Controller
+	recorder *MocktokenTransportMockRecorder
+}
+
+// MocktokenTransportMockRecorder is the mock recorder for MocktokenTransport.
+type MocktokenTransportMockRecorder struct {
+	mock *MocktokenTransport
+}
+
+// NewMocktokenTransport creates a new mock instance.
+func NewMocktokenTransport(ctrl *gomock.Controller) *MocktokenTransport {
+	mock := &MocktokenTransport{ctrl: ctrl}
+	mock.recorder = &MocktokenTransportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktokenTransport) EXPECT() *MocktokenTransportMockRecorder {
+	return m.recorder
+}
+
+// CSRFToken mocks base method.
+func (m *MocktokenTransport) CSRFToken(c *fiber.Ctx) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CSRFToken", c)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CSRFToken indicates an expected call of CSRFToken.
+func (mr *MocktokenTransportMockRecorder) CSRFToken(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CSRFToken", reflect.TypeOf((*MocktokenTransport)(nil).CSRFToken), c)
+}
+
+// CheckCSRF mocks base method.
+func (m *MocktokenTransport) CheckCSRF(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckCSRF", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckCSRF indicates an expected call of CheckCSRF.
+func (mr *MocktokenTransportMockRecorder) CheckCSRF(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckCSRF", reflect.TypeOf((*MocktokenTransport)(nil).CheckCSRF), c)
+}
+
+// SetCookies mocks base method.
+func (m *MocktokenTransport) SetCookies(c *fiber.Ctx, issued dto.Tokens) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetCookies", c, issued)
+}
+
+// SetCookies indicates an expected call of SetCookies.
+func (mr *MocktokenTransportMockRecorder) SetCookies(c, issued interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCookies", reflect.TypeOf((*MocktokenTransport)(nil).SetCookies), c, issued)
+}
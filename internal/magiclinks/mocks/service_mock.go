@@ -0,0 +1,157 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	dto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockmagicLinkRepository is a mock of magicLinkRepository interface.
+type MockmagicLinkRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockmagicLinkRepositoryMockRecorder
+}
+
+// MockmagicLinkRepositoryMockRecorder is the mock recorder for MockmagicLinkRepository.
+type MockmagicLinkRepositoryMockRecorder struct {
+	mock *MockmagicLinkRepository
+}
+
+// NewMockmagicLinkRepository creates a new mock instance.
+func NewMockmagicLinkRepository(ctrl *gomock.Controller) *MockmagicLinkRepository {
+	mock := &MockmagicLinkRepository{ctrl: ctrl}
+	mock.recorder = &MockmagicLinkRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockmagicLinkRepository) EXPECT() *MockmagicLinkRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockmagicLinkRepository) Consume(ctx context.Context, tokenHash, ip, userAgent string) (models.ModelID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, tokenHash, ip, userAgent)
+	ret0, _ := ret[0].(models.ModelID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockmagicLinkRepositoryMockRecorder) Consume(ctx, tokenHash, ip, userAgent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockmagicLinkRepository)(nil).Consume), ctx, tokenHash, ip, userAgent)
+}
+
+// FindActiveUser mocks base method.
+func (m *MockmagicLinkRepository) FindActiveUser(ctx context.Context, login string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindActiveUser", ctx, login)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindActiveUser indicates an expected call of FindActiveUser.
+func (mr *MockmagicLinkRepositoryMockRecorder) FindActiveUser(ctx, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActiveUser", reflect.TypeOf((*MockmagicLinkRepository)(nil).FindActiveUser), ctx, login)
+}
+
+// Save mocks base method.
+func (m_2 *MockmagicLinkRepository) Save(ctx context.Context, m *models.MagicLink, maxPerWindow int, window time.Duration) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Save", ctx, m, maxPerWindow, window)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockmagicLinkRepositoryMockRecorder) Save(ctx, m, maxPerWindow, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockmagicLinkRepository)(nil).Save), ctx, m, maxPerWindow, window)
+}
+
+// MockTokenIssuer is a mock of TokenIssuer interface.
+type MockTokenIssuer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenIssuerMockRecorder
+}
+
+// MockTokenIssuerMockRecorder is the mock recorder for MockTokenIssuer.
+type MockTokenIssuerMockRecorder struct {
+	mock *MockTokenIssuer
+}
+
+// NewMockTokenIssuer creates a new mock instance.
+func NewMockTokenIssuer(ctrl *gomock.Controller) *MockTokenIssuer {
+	mock := &MockTokenIssuer{ctrl: ctrl}
+	mock.recorder = &MockTokenIssuerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenIssuer) EXPECT() *MockTokenIssuerMockRecorder {
+	return m.recorder
+}
+
+// Issue mocks base method.
+func (m *MockTokenIssuer) Issue(ctx context.Context, userID models.ModelID, client dto.Client) (dto.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Issue", ctx, userID, client)
+	ret0, _ := ret[0].(dto.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Issue indicates an expected call of Issue.
+func (mr *MockTokenIssuerMockRecorder) Issue(ctx, userID, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issue", reflect.TypeOf((*MockTokenIssuer)(nil).Issue), ctx, userID, client)
+}
+
+// MockTwoFactor is a mock of TwoFactor interface.
+type MockTwoFactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorMockRecorder
+}
+
+// MockTwoFactorMockRecorder is the mock recorder for MockTwoFactor.
+type MockTwoFactorMockRecorder struct {
+	mock *MockTwoFactor
+}
+
+// NewMockTwoFactor creates a new mock instance.
+func NewMockTwoFactor(ctrl *gomock.Controller) *MockTwoFactor {
+	mock := &MockTwoFactor{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactor) EXPECT() *MockTwoFactorMockRecorder {
+	return m.recorder
+}
+
+// Challenge mocks base method.
+func (m *MockTwoFactor) Challenge(ctx context.Context, userID models.ModelID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Challenge", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Challenge indicates an expected call of Challenge.
+func (mr *MockTwoFactorMockRecorder) Challenge(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Challenge", reflect.TypeOf((*MockTwoFactor)(nil).Challenge), ctx, userID)
+}
@@ -0,0 +1,111 @@
+package magiclinks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/ledger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+// FindActiveUser returns the user with login unless it is disabled or
+// deleted.
+func (r *Repository) FindActiveUser(ctx context.Context, login string) (*models.User, error) {
+	var user models.User
+	query := `
+		SELECT id, login, email, email_verified_at
+		FROM users
+		WHERE login = $1 AND disabled_at IS NULL AND deleted_at IS NULL
+	`
+	err := r.db.QueryRowContext(ctx, query, login).Scan(&user.ID, &user.Login, &user.Email, &user.EmailVerifiedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Save stores a new link, the pending ones of the user stop working. A user
+// gets at most maxPerWindow links within window; the user row is locked while
+// they are counted, so concurrent requests cannot overshoot the limit. Beyond
+// it Save returns an *apperrors.RetryError wrapping ErrTooManyAttempts.
+func (r *Repository) Save(ctx context.Context, m *models.MagicLink, maxPerWindow int, window time.Duration) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = ledger.LockAccounts(ctx, tx, m.UserID); err != nil {
+		return err
+	}
+
+	var (
+		n      int
+		oldest sql.NullTime
+	)
+	countQuery := `
+		SELECT COUNT(*), MIN(created_at)
+		FROM magic_links
+		WHERE user_id = $1 AND created_at > $2
+	`
+	if err = tx.QueryRowContext(ctx, countQuery, m.UserID, time.Now().Add(-window)).Scan(&n, &oldest); err != nil {
+		return err
+	}
+	if n >= maxPerWindow {
+		return &apperrors.RetryError{Err: apperrors.ErrTooManyAttempts, RetryAfter: time.Until(oldest.Time.Add(window))}
+	}
+
+	expireQuery := `
+		UPDATE magic_links SET expires_at = NOW()
+		WHERE user_id = $1 AND used_at IS NULL AND expires_at > NOW()
+	`
+	if _, err = tx.ExecContext(ctx, expireQuery, m.UserID); err != nil {
+		return err
+	}
+
+	insertQuery := `
+		INSERT INTO magic_links (user_id, token_hash, expires_at, requested_ip, requested_user_agent)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err = tx.ExecContext(ctx, insertQuery, m.UserID, m.TokenHash, m.ExpiresAt, m.RequestedIP, m.RequestedUserAgent)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Consume spends the link with the given hash on behalf of the client at ip
+// and returns its user. The update is conditional, so of two concurrent
+// uses only one wins. Unknown, used and expired links yield
+// apperrors.ErrInvalidToken.
+func (r *Repository) Consume(ctx context.Context, tokenHash, ip, userAgent string) (models.ModelID, error) {
+	var userID models.ModelID
+	query := `
+		UPDATE magic_links SET used_at = NOW(), used_ip = $2, used_user_agent = $3
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id
+	`
+	err := r.db.QueryRowContext(ctx, query, tokenHash, ip, userAgent).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", apperrors.ErrInvalidToken
+		}
+		return "", err
+	}
+	return userID, nil
+}
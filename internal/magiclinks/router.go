@@ -0,0 +1,15 @@
+package magiclinks
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupRouter mounts the magic link endpoints; appURL is where browsers go
+// after using a link.
+func SetupRouter(r fiber.Router, transport tokenTransport, appURL string, ms magicLinkService) {
+	handle := newHandler(ms, transport, appURL)
+
+	r.Post("login/magic", handle.request)
+	r.Get("login/magic", handle.confirm)
+	r.Post("login/magic/confirm", handle.consume)
+}
@@ -0,0 +1,160 @@
+package magiclinks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/magiclinks/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/notify"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+type (
+	magicLinkRepository interface {
+		FindActiveUser(ctx context.Context, login string) (*models.User, error)
+		Save(ctx context.Context, m *models.MagicLink, maxPerWindow int, window time.Duration) error
+		Consume(ctx context.Context, tokenHash, ip, userAgent string) (models.ModelID, error)
+	}
+	TokenIssuer interface {
+		Issue(ctx context.Context, userID models.ModelID, client appdto.Client) (appdto.Tokens, error)
+	}
+	TwoFactor interface {
+		Challenge(ctx context.Context, userID models.ModelID) error
+	}
+	// Config of magic links. URL is the public address of the link endpoint
+	// without a query, the token is added as its "token" parameter. A login gets at
+	// most MaxPerWindow links within Window. Requests take at least
+	// RequestTime, which should exceed the time to store and send a link.
+	Config struct {
+		URL          string
+		TTL          time.Duration
+		MaxPerWindow int
+		Window       time.Duration
+		RequestTime  time.Duration
+	}
+	Service struct {
+		conf        Config
+		repository  magicLinkRepository
+		tokenIssuer TokenIssuer
+		twoFactor   TwoFactor
+		notifier    notify.Notifier
+	}
+)
+
+func NewService(
+	conf Config,
+	repository magicLinkRepository,
+	tokenIssuer TokenIssuer,
+	twoFactor TwoFactor,
+	notifier notify.Notifier,
+) *Service {
+	return &Service{conf, repository, tokenIssuer, twoFactor, notifier}
+}
+
+// Request sends a login link to the verified email of the user. Unknown,
+// disabled and rate limited logins, logins without a verified email and
+// failed deliveries are not reported, so the endpoint can't be used to probe
+// for accounts; they only show up in the logs. Every request takes at least
+// RequestTime for the same reason.
+func (s *Service) Request(ctx context.Context, d dto.RequestPayload, client appdto.Client) error {
+	defer pad(ctx, time.Now().Add(s.conf.RequestTime))
+
+	user, err := s.repository.FindActiveUser(ctx, d.Login)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			audit("unknown login", "", client)
+			return nil
+		}
+		return err
+	}
+	if !user.EmailVerifiedAt.Valid {
+		audit("no verified email", user.ID, client)
+		return nil
+	}
+
+	token := utils.GenerateSecret()
+	expiresAt := time.Now().Add(s.conf.TTL)
+
+	err = s.repository.Save(ctx, &models.MagicLink{
+		UserID:             user.ID,
+		TokenHash:          utils.HashSecret(token),
+		ExpiresAt:          expiresAt,
+		RequestedIP:        client.IP,
+		RequestedUserAgent: client.UserAgent,
+	}, s.conf.MaxPerWindow, s.conf.Window)
+	var retry *apperrors.RetryError
+	if errors.As(err, &retry) {
+		audit("rate limited", user.ID, client, "retry_at", time.Now().Add(retry.RetryAfter))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = s.notifier.Notify(ctx, notify.Message{
+		UserID:  user.ID,
+		Channel: notify.ChannelEmail,
+		To:      user.Email,
+		Subject: "Your login link",
+		Body: fmt.Sprintf(
+			"Open this link to log in: %s\nIt works once and expires at %s. If you did not ask for it, ignore this message.",
+			s.link(token), expiresAt.Format(time.RFC1123),
+		),
+	})
+	if err != nil {
+		// an error would tell the account exists
+		logger.Log.Error("magiclinks:service:request", "Notify", err, "userID", user.ID)
+		return nil
+	}
+
+	audit("issued", user.ID, client, "expires_at", expiresAt)
+	return nil
+}
+
+// Consume exchanges a link for tokens. A link works once, replays and
+// expired links yield apperrors.ErrInvalidToken. Users with 2FA get an
+// *apperrors.ChallengeError instead of tokens.
+func (s *Service) Consume(ctx context.Context, token string, client appdto.Client) (appdto.Tokens, error) {
+	userID, err := s.repository.Consume(ctx, utils.HashSecret(token), client.IP, client.UserAgent)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidToken) {
+			audit("rejected", "", client)
+		}
+		return appdto.Tokens{}, err
+	}
+	audit("used", userID, client)
+
+	if err = s.twoFactor.Challenge(ctx, userID); err != nil {
+		return appdto.Tokens{}, err
+	}
+
+	return s.tokenIssuer.Issue(ctx, userID, client)
+}
+
+func (s *Service) link(token string) string {
+	return s.conf.URL + "?token=" + url.QueryEscape(token)
+}
+
+// pad sleeps until deadline unless the request is cancelled.
+func pad(ctx context.Context, deadline time.Time) {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// audit records a magic link event. Tokens never reach the log.
+func audit(event string, userID models.ModelID, client appdto.Client, args ...any) {
+	args = append([]any{"event", event, "user_id", userID, "ip", client.IP, "user_agent", client.UserAgent}, args...)
+	logger.Log.Info("magiclinks:audit", args...)
+}
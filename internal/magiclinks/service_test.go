@@ -0,0 +1,347 @@
+package magiclinks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/magiclinks/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/magiclinks/mocks"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/notify"
+	notifymocks "github.com/dkmelnik/go-musthave-diploma/internal/notify/mocks"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+var (
+	testConf = Config{
+		URL:          "https://shop.example.com/api/user/login/magic",
+		TTL:          time.Minute,
+		MaxPerWindow: 2,
+		Window:       time.Hour,
+		RequestTime:  20 * time.Millisecond,
+	}
+	testClient = appdto.Client{IP: "10.0.0.1", UserAgent: "curl"}
+	linkRe     = regexp.MustCompile(`https://shop\.example\.com/api/user/login/magic\?token=\S+`)
+)
+
+type serviceMocks struct {
+	links     *mocks.MockmagicLinkRepository
+	issuer    *mocks.MockTokenIssuer
+	twoFactor *mocks.MockTwoFactor
+	notifier  *notifymocks.MockNotifier
+}
+
+func newTestService(ctrl *gomock.Controller, conf Config) (*Service, serviceMocks) {
+	m := serviceMocks{
+		links:     mocks.NewMockmagicLinkRepository(ctrl),
+		issuer:    mocks.NewMockTokenIssuer(ctrl),
+		twoFactor: mocks.NewMockTwoFactor(ctrl),
+		notifier:  notifymocks.NewMockNotifier(ctrl),
+	}
+	return NewService(conf, m.links, m.issuer, m.twoFactor, m.notifier), m
+}
+
+func verifiedUser() *models.User {
+	return &models.User{
+		ID:              "user",
+		Login:           "alice",
+		Email:           "alice@example.com",
+		EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+}
+
+func tokenOf(t *testing.T, m notify.Message) string {
+	link := linkRe.FindString(m.Body)
+	require.NotEmpty(t, link, m.Body)
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	return u.Query().Get("token")
+}
+
+func TestService_Request(t *testing.T) {
+	tests := []struct {
+		name    string
+		login   string
+		prepare func(t *testing.T, m serviceMocks)
+		want    error
+	}{
+		{
+			name:  "positive test #1, link sent to the verified email",
+			login: "alice",
+			prepare: func(t *testing.T, m serviceMocks) {
+				var saved *models.MagicLink
+				gomock.InOrder(
+					m.links.EXPECT().FindActiveUser(gomock.Any(), "alice").Return(verifiedUser(), nil),
+					m.links.EXPECT().Save(gomock.Any(), gomock.Any(), 2, time.Hour).DoAndReturn(func(_ context.Context, l *models.MagicLink, _ int, _ time.Duration) error {
+						saved = l
+						return nil
+					}),
+					m.notifier.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, msg notify.Message) error {
+						assert.Equal(t, models.ModelID("user"), msg.UserID)
+						assert.Equal(t, notify.ChannelEmail, msg.Channel)
+						assert.Equal(t, "alice@example.com", msg.To)
+						assert.Equal(t, utils.HashSecret(tokenOf(t, msg)), saved.TokenHash, "only the hash is stored")
+						assert.WithinDuration(t, time.Now().Add(testConf.TTL), saved.ExpiresAt, time.Second)
+						assert.Equal(t, testClient.IP, saved.RequestedIP)
+						return nil
+					}),
+				)
+			},
+		},
+		{
+			name:  "positive test #2, unknown login not reported",
+			login: "bob",
+			prepare: func(t *testing.T, m serviceMocks) {
+				m.links.EXPECT().FindActiveUser(gomock.Any(), "bob").Return(nil, apperrors.ErrNotFound)
+			},
+		},
+		{
+			name:  "positive test #3, no link without a verified email",
+			login: "alice",
+			prepare: func(t *testing.T, m serviceMocks) {
+				user := verifiedUser()
+				user.EmailVerifiedAt = sql.NullTime{}
+				m.links.EXPECT().FindActiveUser(gomock.Any(), "alice").Return(user, nil)
+			},
+		},
+		{
+			name:  "positive test #4, rate limited login not reported",
+			login: "alice",
+			prepare: func(t *testing.T, m serviceMocks) {
+				m.links.EXPECT().FindActiveUser(gomock.Any(), "alice").Return(verifiedUser(), nil)
+				m.links.EXPECT().Save(gomock.Any(), gomock.Any(), 2, time.Hour).
+					Return(&apperrors.RetryError{Err: apperrors.ErrTooManyAttempts, RetryAfter: time.Minute})
+			},
+		},
+		{
+			name:  "positive test #5, failed delivery not reported",
+			login: "alice",
+			prepare: func(t *testing.T, m serviceMocks) {
+				m.links.EXPECT().FindActiveUser(gomock.Any(), "alice").Return(verifiedUser(), nil)
+				m.links.EXPECT().Save(gomock.Any(), gomock.Any(), 2, time.Hour).Return(nil)
+				m.notifier.EXPECT().Notify(gomock.Any(), gomock.Any()).Return(errors.New("smtp is down"))
+			},
+		},
+		{
+			name:  "negative test #6, storage error",
+			login: "alice",
+			prepare: func(t *testing.T, m serviceMocks) {
+				m.links.EXPECT().FindActiveUser(gomock.Any(), "alice").Return(nil, errors.New("db is down"))
+			},
+			want: errors.New("db is down"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newTestService(ctrl, testConf)
+			tt.prepare(t, m)
+
+			start := time.Now()
+			err := s.Request(context.Background(), dto.RequestPayload{Login: tt.login}, testClient)
+			assert.GreaterOrEqual(t, time.Since(start), testConf.RequestTime, "every request takes the same time")
+
+			if tt.want != nil {
+				assert.EqualError(t, err, tt.want.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestService_Consume(t *testing.T) {
+	issued := appdto.Tokens{AccessToken: "access"}
+
+	tests := []struct {
+		name    string
+		prepare func(m serviceMocks)
+		want    error
+	}{
+		{
+			name: "positive test #1, tokens issued",
+			prepare: func(m serviceMocks) {
+				gomock.InOrder(
+					m.links.EXPECT().Consume(gomock.Any(), utils.HashSecret("secret"), testClient.IP, testClient.UserAgent).Return(models.ModelID("user"), nil),
+					m.twoFactor.EXPECT().Challenge(gomock.Any(), models.ModelID("user")).Return(nil),
+					m.issuer.EXPECT().Issue(gomock.Any(), models.ModelID("user"), testClient).Return(issued, nil),
+				)
+			},
+		},
+		{
+			name: "negative test #2, unknown, used or expired link",
+			prepare: func(m serviceMocks) {
+				m.links.EXPECT().Consume(gomock.Any(), utils.HashSecret("secret"), testClient.IP, testClient.UserAgent).Return(models.ModelID(""), apperrors.ErrInvalidToken)
+			},
+			want: apperrors.ErrInvalidToken,
+		},
+		{
+			name: "negative test #3, 2FA challenge instead of tokens",
+			prepare: func(m serviceMocks) {
+				m.links.EXPECT().Consume(gomock.Any(), utils.HashSecret("secret"), testClient.IP, testClient.UserAgent).Return(models.ModelID("user"), nil)
+				m.twoFactor.EXPECT().Challenge(gomock.Any(), models.ModelID("user")).Return(&apperrors.ChallengeError{Token: "challenge"})
+			},
+			want: &apperrors.ChallengeError{Token: "challenge"},
+		},
+		{
+			name: "negative test #4, account disabled after the link was sent",
+			prepare: func(m serviceMocks) {
+				m.links.EXPECT().Consume(gomock.Any(), utils.HashSecret("secret"), testClient.IP, testClient.UserAgent).Return(models.ModelID("user"), nil)
+				m.twoFactor.EXPECT().Challenge(gomock.Any(), models.ModelID("user")).Return(nil)
+				m.issuer.EXPECT().Issue(gomock.Any(), models.ModelID("user"), testClient).Return(appdto.Tokens{}, apperrors.ErrInactive)
+			},
+			want: apperrors.ErrInactive,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newTestService(ctrl, testConf)
+			tt.prepare(m)
+
+			got, err := s.Consume(context.Background(), "secret", testClient)
+			if tt.want != nil {
+				assert.EqualError(t, err, tt.want.Error())
+				assert.Empty(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, issued, got)
+		})
+	}
+}
+
+// storedLinks plays the magic_links table by the contract of Repository:
+// Save refuses links beyond the limit of the window and expires the pending
+// links of the user, Consume spends a link that is neither used nor expired.
+type storedLinks []models.MagicLink
+
+func (l *storedLinks) expect(m serviceMocks) {
+	m.links.EXPECT().FindActiveUser(gomock.Any(), "alice").Return(verifiedUser(), nil).AnyTimes()
+	m.links.EXPECT().Save(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, link *models.MagicLink, maxPerWindow int, window time.Duration) error {
+		now := time.Now()
+		n, oldest := 0, now
+		for _, v := range *l {
+			if v.UserID == link.UserID && v.CreatedAt.After(now.Add(-window)) {
+				n++
+				if v.CreatedAt.Before(oldest) {
+					oldest = v.CreatedAt
+				}
+			}
+		}
+		if n >= maxPerWindow {
+			return &apperrors.RetryError{Err: apperrors.ErrTooManyAttempts, RetryAfter: time.Until(oldest.Add(window))}
+		}
+
+		link.CreatedAt = now
+		for i := range *l {
+			if (*l)[i].UserID == link.UserID && !(*l)[i].UsedAt.Valid && (*l)[i].ExpiresAt.After(now) {
+				(*l)[i].ExpiresAt = now
+			}
+		}
+		*l = append(*l, *link)
+		return nil
+	}).AnyTimes()
+	m.links.EXPECT().Consume(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tokenHash, _, _ string) (models.ModelID, error) {
+		for i, link := range *l {
+			if link.TokenHash == tokenHash && !link.UsedAt.Valid && link.ExpiresAt.After(time.Now()) {
+				(*l)[i].UsedAt = sql.NullTime{Time: time.Now(), Valid: true}
+				return link.UserID, nil
+			}
+		}
+		return "", apperrors.ErrInvalidToken
+	}).AnyTimes()
+	m.twoFactor.EXPECT().Challenge(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	m.issuer.EXPECT().Issue(gomock.Any(), gomock.Any(), gomock.Any()).Return(appdto.Tokens{AccessToken: "access"}, nil).AnyTimes()
+}
+
+func TestService_links(t *testing.T) {
+	conf := testConf
+	conf.TTL = 50 * time.Millisecond
+	conf.RequestTime = 0
+
+	send := func(t *testing.T, s *Service, m serviceMocks) string {
+		var token string
+		m.notifier.EXPECT().Notify(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, msg notify.Message) error {
+			token = tokenOf(t, msg)
+			return nil
+		})
+		require.NoError(t, s.Request(context.Background(), dto.RequestPayload{Login: "alice"}, testClient))
+		return token
+	}
+
+	t.Run("positive test #1, a link works once", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s, m := newTestService(ctrl, conf)
+		(&storedLinks{}).expect(m)
+		token := send(t, s, m)
+
+		_, err := s.Consume(context.Background(), token, testClient)
+		require.NoError(t, err)
+		_, err = s.Consume(context.Background(), token, testClient)
+		assert.ErrorIs(t, err, apperrors.ErrInvalidToken, "replay")
+	})
+
+	t.Run("negative test #2, a new link invalidates the pending ones", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s, m := newTestService(ctrl, conf)
+		(&storedLinks{}).expect(m)
+		first := send(t, s, m)
+		second := send(t, s, m)
+
+		_, err := s.Consume(context.Background(), first, testClient)
+		assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+		_, err = s.Consume(context.Background(), second, testClient)
+		assert.NoError(t, err)
+	})
+
+	t.Run("negative test #3, expired link", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s, m := newTestService(ctrl, conf)
+		(&storedLinks{}).expect(m)
+		token := send(t, s, m)
+
+		time.Sleep(conf.TTL)
+		_, err := s.Consume(context.Background(), token, testClient)
+		assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+	})
+
+	t.Run("negative test #4, no link beyond the limit of the window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s, m := newTestService(ctrl, conf)
+		links := &storedLinks{}
+		links.expect(m)
+		send(t, s, m)
+		last := send(t, s, m)
+
+		// the notifier expects no third message
+		require.NoError(t, s.Request(context.Background(), dto.RequestPayload{Login: "alice"}, testClient))
+		assert.Len(t, *links, conf.MaxPerWindow)
+
+		_, err := s.Consume(context.Background(), last, testClient)
+		assert.NoError(t, err, "the refused request leaves the pending link alone")
+	})
+}
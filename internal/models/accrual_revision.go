@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// AccrualRevision records a change of an order's accrual after it was
+// PROCESSED. The difference is posted to the ledger, the order keeps its
+// original accrual.
+type AccrualRevision struct {
+	ID              ModelID   `db:"id"`
+	OrderID         ModelID   `db:"order_id"`
+	PreviousAccrual float64   `db:"previous_accrual"`
+	NewAccrual      float64   `db:"new_accrual"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+func (m *AccrualRevision) Delta() float64 {
+	return m.NewAccrual - m.PreviousAccrual
+}
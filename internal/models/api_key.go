@@ -0,0 +1,46 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Scopes an API key can be granted.
+const (
+	ScopeOrdersWrite = "orders:write"
+	ScopeBalanceRead = "balance:read"
+	ScopeWithdraw    = "withdraw"
+)
+
+// IsScope reports whether scope is one of the known scopes.
+func IsScope(scope string) bool {
+	switch scope {
+	case ScopeOrdersWrite, ScopeBalanceRead, ScopeWithdraw:
+		return true
+	}
+	return false
+}
+
+// APIKey is stored hashed, Prefix is kept to tell keys apart in listings.
+type APIKey struct {
+	ID         ModelID      `db:"id"`
+	UserID     ModelID      `db:"user_id"`
+	Name       string       `db:"name"`
+	Prefix     string       `db:"prefix"`
+	KeyHash    string       `db:"key_hash"`
+	Scopes     []string     `db:"scopes"`
+	ExpiresAt  time.Time    `db:"expires_at"`
+	LastUsedAt sql.NullTime `db:"last_used_at"`
+	RevokedAt  sql.NullTime `db:"revoked_at"`
+	CreatedAt  time.Time    `db:"created_at"`
+}
+
+// HasScope reports whether the key was granted scope.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type EntryKind string
+
+var (
+	EntryTransferIn  EntryKind = "TRANSFER_IN"
+	EntryTransferOut EntryKind = "TRANSFER_OUT"
+	EntryTierBonus   EntryKind = "TIER_BONUS"
+	EntryPromo       EntryKind = "PROMO"
+	EntryReferral    EntryKind = "REFERRAL_BONUS"
+	EntryAdjustment  EntryKind = "ADJUSTMENT"
+	EntryRevision    EntryKind = "ACCRUAL_REVISION"
+	// EntryTierBonusRevision follows an accrual revision with the matching
+	// change of the order's tier bonus.
+	EntryTierBonusRevision EntryKind = "TIER_BONUS_REVISION"
+)
+
+// BalanceEntry is a signed movement of points that is neither an order
+// accrual nor a withdrawal: credits are positive, debits are negative.
+type BalanceEntry struct {
+	ID             ModelID        `db:"id"`
+	UserID         ModelID        `db:"user_id"`
+	Kind           EntryKind      `db:"kind"`
+	Amount         float64        `db:"amount"`
+	CounterpartyID sql.NullString `db:"counterparty_id"`
+	// CounterpartyLogin is filled only by queries joining users.
+	CounterpartyLogin sql.NullString `db:"counterparty_login"`
+	Reference         sql.NullString `db:"reference"`
+	// ReasonCode, Comment and Operator are set on manual adjustments.
+	ReasonCode sql.NullString `db:"reason_code"`
+	Comment    sql.NullString `db:"comment"`
+	Operator   sql.NullString `db:"operator"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
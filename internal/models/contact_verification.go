@@ -0,0 +1,25 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Contact channels that can be verified.
+const (
+	ChannelEmail = "email"
+	ChannelPhone = "phone"
+)
+
+// ContactVerification is a single-use code sent to Target, stored hashed.
+type ContactVerification struct {
+	ID         ModelID      `db:"id"`
+	UserID     ModelID      `db:"user_id"`
+	Channel    string       `db:"channel"`
+	Target     string       `db:"target"`
+	CodeHash   string       `db:"code_hash"`
+	Attempts   int          `db:"attempts"`
+	ExpiresAt  time.Time    `db:"expires_at"`
+	ConsumedAt sql.NullTime `db:"consumed_at"`
+	CreatedAt  time.Time    `db:"created_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type (
+	// Identity links a user to the subject of an external OpenID provider.
+	Identity struct {
+		ID          ModelID      `db:"id"`
+		UserID      ModelID      `db:"user_id"`
+		Issuer      string       `db:"issuer"`
+		Subject     string       `db:"subject"`
+		Email       string       `db:"email"`
+		LastLoginAt sql.NullTime `db:"last_login_at"`
+		CreatedAt   time.Time    `db:"created_at"`
+	}
+	// OIDCState is a pending authorization request, stored under the hash
+	// of its state parameter. LinkUserID is set when a logged in user links
+	// the provider to the account, or confirms a sensitive change of it
+	// with Reauth. IP is the address that started it.
+	OIDCState struct {
+		StateHash    string         `db:"state_hash"`
+		Nonce        string         `db:"nonce"`
+		CodeVerifier string         `db:"code_verifier"`
+		LinkUserID   sql.NullString `db:"link_user_id"`
+		Reauth       bool           `db:"reauth"`
+		IP           string         `db:"ip"`
+		ExpiresAt    time.Time      `db:"expires_at"`
+		CreatedAt    time.Time      `db:"created_at"`
+	}
+)
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// MagicLink is a single-use login link, stored hashed. The requesting and
+// the using client are kept for the audit trail.
+type MagicLink struct {
+	ID                 ModelID        `db:"id"`
+	UserID             ModelID        `db:"user_id"`
+	TokenHash          string         `db:"token_hash"`
+	ExpiresAt          time.Time      `db:"expires_at"`
+	RequestedIP        string         `db:"requested_ip"`
+	RequestedUserAgent string         `db:"requested_user_agent"`
+	UsedAt             sql.NullTime   `db:"used_at"`
+	UsedIP             sql.NullString `db:"used_ip"`
+	UsedUserAgent      sql.NullString `db:"used_user_agent"`
+	CreatedAt          time.Time      `db:"created_at"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type PromoCode struct {
+	ID     ModelID `db:"id"`
+	Code   string  `db:"code"`
+	Amount float64 `db:"amount"`
+	// MaxRedemptions caps redemptions across all users, zero means no cap.
+	MaxRedemptions int          `db:"max_redemptions"`
+	PerUserLimit   int          `db:"per_user_limit"`
+	StartsAt       time.Time    `db:"starts_at"`
+	EndsAt         sql.NullTime `db:"ends_at"`
+	CreatedBy      string       `db:"created_by"`
+	CreatedAt      time.Time    `db:"created_at"`
+	// Active and Redemptions are computed by queries.
+	Active      bool `db:"-"`
+	Redemptions int  `db:"-"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ReferralStatus string
+
+var (
+	ReferralPending  ReferralStatus = "PENDING"
+	ReferralRewarded ReferralStatus = "REWARDED"
+)
+
+type Referral struct {
+	ID         ModelID        `db:"id"`
+	ReferrerID ModelID        `db:"referrer_id"`
+	RefereeID  ModelID        `db:"referee_id"`
+	Status     ReferralStatus `db:"status"`
+	CreatedAt  time.Time      `db:"created_at"`
+	RewardedAt sql.NullTime   `db:"rewarded_at"`
+	// RefereeLogin is filled only by queries joining users.
+	RefereeLogin string `db:"referee_login"`
+}
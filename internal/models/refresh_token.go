@@ -0,0 +1,20 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// RefreshToken is stored hashed. Tokens rotated from one login share the
+// family id and the jti of the access tokens they issue.
+type RefreshToken struct {
+	ID        ModelID      `db:"id"`
+	UserID    ModelID      `db:"user_id"`
+	FamilyID  ModelID      `db:"family_id"`
+	JTI       string       `db:"jti"`
+	TokenHash string       `db:"token_hash"`
+	ExpiresAt time.Time    `db:"expires_at"`
+	RotatedAt sql.NullTime `db:"rotated_at"`
+	RevokedAt sql.NullTime `db:"revoked_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
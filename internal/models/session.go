@@ -0,0 +1,20 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Session is a login on one device. It shares the jti of its refresh token
+// family and lives as long as the family does.
+type Session struct {
+	ID         ModelID      `db:"id"`
+	UserID     ModelID      `db:"user_id"`
+	JTI        string       `db:"jti"`
+	IP         string       `db:"ip"`
+	UserAgent  string       `db:"user_agent"`
+	ExpiresAt  time.Time    `db:"expires_at"`
+	LastSeenAt time.Time    `db:"last_seen_at"`
+	RevokedAt  sql.NullTime `db:"revoked_at"`
+	CreatedAt  time.Time    `db:"created_at"`
+}
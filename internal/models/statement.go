@@ -0,0 +1,24 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type StatementRowType string
+
+var (
+	StatementOrder      StatementRowType = "ORDER"
+	StatementAccrual    StatementRowType = "ACCRUAL"
+	StatementWithdrawal StatementRowType = "WITHDRAWAL"
+)
+
+// StatementRow is one line of a user's statement. Ledger entries use their
+// entry kind as the type. Amount keeps the exact decimal text of the column.
+type StatementRow struct {
+	OccurredAt time.Time
+	Type       StatementRowType
+	Reference  string
+	Status     string
+	Amount     sql.NullString
+}
@@ -0,0 +1,8 @@
+package models
+
+type Transfer struct {
+	ID          ModelID
+	SenderID    ModelID
+	RecipientID ModelID
+	Amount      float64
+}
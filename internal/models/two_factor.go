@@ -0,0 +1,13 @@
+package models
+
+import (
+	"database/sql"
+)
+
+// TwoFactorState is the 2FA part of a users row. Secret is sealed, 2FA is
+// pending until EnabledAt is set.
+type TwoFactorState struct {
+	Login     string         `db:"login"`
+	Secret    sql.NullString `db:"totp_secret"`
+	EnabledAt sql.NullTime   `db:"totp_enabled_at"`
+}
@@ -1,12 +1,43 @@
 package models
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 type ModelID string
 
+const (
+	RoleUser    = "user"
+	RoleSupport = "support"
+	RoleAdmin   = "admin"
+)
+
+// IsRole reports whether role is one of the known roles.
+func IsRole(role string) bool {
+	switch role {
+	case RoleUser, RoleSupport, RoleAdmin:
+		return true
+	}
+	return false
+}
+
 type User struct {
-	ID        ModelID   `db:"id"`
-	Login     string    `db:"login"`
-	Password  string    `db:"password"`
-	CreatedAt time.Time `db:"created_at"`
+	ID       ModelID `db:"id"`
+	Login    string  `db:"login"`
+	Password string  `db:"password"`
+	// ReferralCode is the code the user shares to invite others.
+	ReferralCode string       `db:"referral_code"`
+	Role         string       `db:"role"`
+	DisabledAt   sql.NullTime `db:"disabled_at"`
+	CreatedAt    time.Time    `db:"created_at"`
+
+	// Profile, contacts are only used once verified.
+	Email           string       `db:"email"`
+	EmailVerifiedAt sql.NullTime `db:"email_verified_at"`
+	Phone           string       `db:"phone"`
+	PhoneVerifiedAt sql.NullTime `db:"phone_verified_at"`
+	DisplayName     string       `db:"display_name"`
+	Locale          string       `db:"locale"`
+	TimeZone        string       `db:"time_zone"`
 }
@@ -11,3 +11,13 @@ type Withdrawal struct {
 	Amount      float64   `db:"amount"`
 	CreatedAt   time.Time `db:"created_at"`
 }
+
+// WithdrawalUsage summarizes what a user has already withdrawn.
+type WithdrawalUsage struct {
+	Day   float64
+	Month float64
+	// SinceLastLarge is the time passed since the last withdrawal at or above
+	// the large amount threshold; HasLarge is false when there was none.
+	SinceLastLarge time.Duration
+	HasLarge       bool
+}
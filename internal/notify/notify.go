@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+const (
+	KindStdout = "stdout"
+	KindFile   = "file"
+	KindOutbox = "outbox"
+)
+
+// Channels of a message, an empty channel addresses the user's login.
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+)
+
+type (
+	// Message is addressed To a contact on Channel, or to a login when the
+	// channel is empty. UserID is the recipient's account, stored messages
+	// are deleted with it.
+	Message struct {
+		UserID  models.ModelID
+		Channel string
+		To      string
+		Subject string
+		Body    string
+	}
+	// Notifier delivers messages to users. Real transports (mail, SMS) plug
+	// in behind it; the writer based one is meant for local use.
+	Notifier interface {
+		Notify(ctx context.Context, m Message) error
+	}
+	Writer struct {
+		mu sync.Mutex
+		w  io.Writer
+	}
+)
+
+// New builds the notifier of the given kind, path is used by KindFile and db
+// by KindOutbox.
+func New(kind, path string, db *sql.DB) (Notifier, error) {
+	switch kind {
+	case "", KindStdout:
+		return NewWriter(os.Stdout), nil
+	case KindFile:
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		return NewWriter(f), nil
+	case KindOutbox:
+		return NewOutbox(db), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier %q", kind)
+	}
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (n *Writer) Notify(_ context.Context, m Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	to := m.To
+	if m.Channel != "" {
+		to = m.Channel + ":" + m.To
+	}
+
+	_, err := fmt.Fprintf(n.w, "--- %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC3339), to, m.Subject, m.Body)
+	return err
+}
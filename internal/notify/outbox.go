@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Outbox queues messages in the notification_outbox table. A delivery
+// worker, or a developer with psql, picks them up from there.
+type Outbox struct {
+	db *sql.DB
+}
+
+func NewOutbox(db *sql.DB) *Outbox {
+	return &Outbox{db}
+}
+
+func (o *Outbox) Notify(ctx context.Context, m Message) error {
+	query := `
+		INSERT INTO notification_outbox (user_id, channel, recipient, subject, body)
+		VALUES (NULLIF($1, '')::uuid, $2, $3, $4, $5)
+	`
+	_, err := o.db.ExecContext(ctx, query, m.UserID, m.Channel, m.To, m.Subject, m.Body)
+	return err
+}
@@ -0,0 +1,5 @@
+package dto
+
+type AuthorizationResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
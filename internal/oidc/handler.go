@@ -0,0 +1,188 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/oidc/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens"
+	usersdto "github.com/dkmelnik/go-musthave-diploma/internal/users/dto"
+)
+
+type (
+	oidcService interface {
+		Begin(ctx context.Context, linkUserID models.ModelID, ip string) (authURL, state string, err error)
+		BeginReauth(ctx context.Context, userID models.ModelID, ip string) (authURL, state string, err error)
+		Complete(ctx context.Context, code, state string, client appdto.Client) (appdto.Tokens, error)
+		Unlink(ctx context.Context, userID models.ModelID) error
+	}
+	tokenTransport interface {
+		Write(c *fiber.Ctx, issued appdto.Tokens, asJSON bool) error
+	}
+	handler struct {
+		service   oidcService
+		transport tokenTransport
+	}
+)
+
+// stateCookie ties the callback to the browser that started the login, so
+// nobody can log a victim into their own account with a callback URL.
+const (
+	stateCookie     = "oidc_state"
+	stateCookiePath = "/api/user/oidc"
+)
+
+func newHandler(service oidcService, transport tokenTransport) *handler {
+	return &handler{service, transport}
+}
+
+// login sends the browser to the provider.
+func (h *handler) login(c *fiber.Ctx) error {
+	authURL, state, err := h.service.Begin(c.Context(), "", c.IP())
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTooManyAttempts) {
+			return tooManyAttempts(c, err)
+		}
+		logger.Log.Error("oidc:handler:login", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+	setState(c, state)
+
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// link answers with the provider URL the client opens to link its account.
+func (h *handler) link(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+	}
+
+	authURL, state, err := h.service.Begin(c.Context(), models.ModelID(userID), c.IP())
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTooManyAttempts) {
+			return tooManyAttempts(c, err)
+		}
+		logger.Log.Error("oidc:handler:link", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+	setState(c, state)
+
+	return c.Status(fiber.StatusOK).JSON(dto.AuthorizationResponse{AuthorizationURL: authURL})
+}
+
+// reauth answers with the provider URL the client opens to confirm deleting
+// the account or setting a first password, see Service.BeginReauth.
+func (h *handler) reauth(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+	}
+
+	authURL, state, err := h.service.BeginReauth(c.Context(), models.ModelID(userID), c.IP())
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTooManyAttempts) {
+			return tooManyAttempts(c, err)
+		}
+		logger.Log.Error("oidc:handler:reauth", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+	setState(c, state)
+
+	return c.Status(fiber.StatusOK).JSON(dto.AuthorizationResponse{AuthorizationURL: authURL})
+}
+
+func (h *handler) unlink(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+	}
+
+	switch err := h.service.Unlink(c.Context(), models.ModelID(userID)); {
+	case err == nil:
+		return c.SendStatus(fiber.StatusNoContent)
+	case errors.Is(err, apperrors.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).SendString(http.StatusText(fiber.StatusNotFound))
+	case errors.Is(err, apperrors.ErrNoRequiredValue):
+		// the account has no password to fall back to yet, PUT /api/user/password
+		// sets one after POST /api/user/oidc/reauth
+		return c.Status(fiber.StatusConflict).SendString(http.StatusText(fiber.StatusConflict))
+	default:
+		logger.Log.Error("oidc:handler:unlink", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+}
+
+// callback is where the provider sends the browser back, it logs the user
+// in like POST /api/user/login does. A confirmed re-authentication answers
+// 204 without new tokens.
+func (h *handler) callback(c *fiber.Ctx) error {
+	code, state := c.Query("code"), c.Query("state")
+	if c.Query("error") != "" || code == "" || state == "" || c.Cookies(stateCookie) != state {
+		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+	}
+	c.Cookie(&fiber.Cookie{Name: stateCookie, Path: stateCookiePath, Expires: time.Unix(0, 0), HTTPOnly: true})
+
+	issued, err := h.service.Complete(c.Context(), code, state, tokens.ClientOf(c))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidToken) {
+			logger.Log.Warn("oidc:handler:callback", "rejected", err.Error(), "ip", c.IP())
+			return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+		}
+		if errors.Is(err, apperrors.ErrIsExist) {
+			return c.Status(fiber.StatusConflict).SendString(http.StatusText(fiber.StatusConflict))
+		}
+		if errors.Is(err, apperrors.ErrInvalidCredentials) {
+			// re-authenticated as somebody else
+			return c.Status(fiber.StatusForbidden).SendString(http.StatusText(fiber.StatusForbidden))
+		}
+		if errors.Is(err, apperrors.ErrInactive) {
+			return c.Status(fiber.StatusForbidden).SendString(http.StatusText(fiber.StatusForbidden))
+		}
+		var challenge *apperrors.ChallengeError
+		if errors.As(err, &challenge) {
+			return c.Status(fiber.StatusAccepted).JSON(usersdto.ChallengeResponse{
+				ChallengeToken: challenge.Token,
+				ExpiresAt:      challenge.ExpiresAt,
+			})
+		}
+		logger.Log.Error("oidc:handler:callback", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+
+	if issued.AccessToken == "" {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	return h.transport.Write(c, issued, tokens.WantsJSON(c))
+}
+
+func tooManyAttempts(c *fiber.Ctx, err error) error {
+	var retry *apperrors.RetryError
+	if errors.As(err, &retry) {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retry.RetryAfter.Seconds()))))
+	}
+	return c.Status(fiber.StatusTooManyRequests).SendString(http.StatusText(fiber.StatusTooManyRequests))
+}
+
+func setState(c *fiber.Ctx, state string) {
+	c.Cookie(&fiber.Cookie{
+		Name:     stateCookie,
+		Value:    state,
+		Path:     stateCookiePath,
+		HTTPOnly: true,
+		Secure:   c.Protocol() == "https",
+		// the provider redirects back with a top-level GET
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
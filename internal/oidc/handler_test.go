@@ -0,0 +1,187 @@
+package oidc
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/oidc/mocks"
+)
+
+func Test_login(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		code       int
+		location   string
+		retryAfter string
+	}{
+		{
+			name:     "positive test #1, sent to the provider",
+			code:     http.StatusFound,
+			location: testIssuer + "/authorize",
+		},
+		{
+			name:       "negative test #2, too many pending logins",
+			err:        &apperrors.RetryError{Err: apperrors.ErrTooManyAttempts, RetryAfter: 90 * time.Second},
+			code:       http.StatusTooManyRequests,
+			retryAfter: "90",
+		},
+		{
+			name: "negative test #3, unknown service error",
+			err:  errors.New("db is down"),
+			code: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mocks.NewMockoidcService(ctrl)
+			service.EXPECT().Begin(gomock.Any(), models.ModelID(""), "0.0.0.0").Return(tt.location, "state", tt.err)
+
+			app := fiber.New()
+			h := newHandler(service, mocks.NewMocktokenTransport(ctrl))
+			app.Get("/oidc/login", h.login)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/oidc/login", nil), 100)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+			assert.Equal(t, tt.retryAfter, resp.Header.Get(fiber.HeaderRetryAfter))
+			if tt.err == nil {
+				assert.Equal(t, tt.location, resp.Header.Get(fiber.HeaderLocation))
+				require.Len(t, resp.Cookies(), 1)
+				assert.Equal(t, stateCookie, resp.Cookies()[0].Name)
+				assert.Equal(t, "state", resp.Cookies()[0].Value)
+			}
+		})
+	}
+}
+
+func Test_reauth(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		code       int
+		retryAfter string
+	}{
+		{
+			name: "positive test #1, provider URL answered",
+			code: http.StatusOK,
+		},
+		{
+			name:       "negative test #2, too many pending logins",
+			err:        &apperrors.RetryError{Err: apperrors.ErrTooManyAttempts, RetryAfter: 90 * time.Second},
+			code:       http.StatusTooManyRequests,
+			retryAfter: "90",
+		},
+		{
+			name: "negative test #3, unknown service error",
+			err:  errors.New("db is down"),
+			code: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mocks.NewMockoidcService(ctrl)
+			service.EXPECT().BeginReauth(gomock.Any(), models.ModelID("user"), "0.0.0.0").Return(testIssuer+"/authorize", "state", tt.err)
+
+			app := fiber.New()
+			h := newHandler(service, mocks.NewMocktokenTransport(ctrl))
+			app.Post("/oidc/reauth", func(c *fiber.Ctx) error {
+				c.Locals("user_id", "user")
+				return c.Next()
+			}, h.reauth)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/oidc/reauth", nil), 100)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+			assert.Equal(t, tt.retryAfter, resp.Header.Get(fiber.HeaderRetryAfter))
+			if tt.err == nil {
+				require.Len(t, resp.Cookies(), 1)
+				assert.Equal(t, stateCookie, resp.Cookies()[0].Name)
+				assert.Equal(t, "state", resp.Cookies()[0].Value)
+			}
+		})
+	}
+}
+
+func Test_callback(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie string
+		issued appdto.Tokens
+		err    error
+		code   int
+	}{
+		{
+			name: "negative test #1, state cookie missing",
+			code: http.StatusUnauthorized,
+		},
+		{
+			name:   "positive test #2, re-authenticated",
+			cookie: "state",
+			code:   http.StatusNoContent,
+		},
+		{
+			name:   "negative test #3, re-authenticated as somebody else",
+			cookie: "state",
+			err:    apperrors.ErrInvalidCredentials,
+			code:   http.StatusForbidden,
+		},
+		{
+			name:   "positive test #4, logged in",
+			cookie: "state",
+			issued: appdto.Tokens{AccessToken: "access"},
+			code:   http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mocks.NewMockoidcService(ctrl)
+			transport := mocks.NewMocktokenTransport(ctrl)
+			if tt.cookie != "" {
+				service.EXPECT().Complete(gomock.Any(), "code", "state", gomock.Any()).Return(tt.issued, tt.err)
+			}
+			if tt.issued.AccessToken != "" {
+				transport.EXPECT().Write(gomock.Any(), tt.issued, false).DoAndReturn(func(c *fiber.Ctx, _ appdto.Tokens, _ bool) error {
+					return c.SendStatus(fiber.StatusOK)
+				})
+			}
+
+			app := fiber.New()
+			app.Get("/oidc/callback", newHandler(service, transport).callback)
+
+			req := httptest.NewRequest(http.MethodGet, "/oidc/callback?code=code&state=state", nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: stateCookie, Value: tt.cookie})
+			}
+			resp, err := app.Test(req, 100)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockoidcService is a mock of oidcService interface.
+type MockoidcService struct {
+	ctrl     *gomock.Controller
+	recorder *MockoidcServiceMockRecorder
+}
+
+// MockoidcServiceMockRecorder is the mock recorder for MockoidcService.
+type MockoidcServiceMockRecorder struct {
+	mock *MockoidcService
+}
+
+// NewMockoidcService creates a new mock instance.
+func NewMockoidcService(ctrl *gomock.Controller) *MockoidcService {
+	mock := &MockoidcService{ctrl: ctrl}
+	mock.recorder = &MockoidcServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockoidcService) EXPECT() *MockoidcServiceMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockoidcService) Begin(ctx context.Context, linkUserID models.ModelID, ip string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx, linkUserID, ip)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockoidcServiceMockRecorder) Begin(ctx, linkUserID, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockoidcService)(nil).Begin), ctx, linkUserID, ip)
+}
+
+// BeginReauth mocks base method.
+func (m *MockoidcService) BeginReauth(ctx context.Context, userID models.ModelID, ip string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginReauth", ctx, userID, ip)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BeginReauth indicates an expected call of BeginReauth.
+func (mr *MockoidcServiceMockRecorder) BeginReauth(ctx, userID, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginReauth", reflect.TypeOf((*MockoidcService)(nil).BeginReauth), ctx, userID, ip)
+}
+
+// Complete mocks base method.
+func (m *MockoidcService) Complete(ctx context.Context, code, state string, client dto.Client) (dto.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, code, state, client)
+	ret0, _ := ret[0].(dto.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockoidcServiceMockRecorder) Complete(ctx, code, state, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockoidcService)(nil).Complete), ctx, code, state, client)
+}
+
+// Unlink mocks base method.
+func (m *MockoidcService) Unlink(ctx context.Context, userID models.ModelID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlink", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlink indicates an expected call of Unlink.
+func (mr *MockoidcServiceMockRecorder) Unlink(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlink", reflect.TypeOf((*MockoidcService)(nil).Unlink), ctx, userID)
+}
+
+// MocktokenTransport is a mock of tokenTransport interface.
+type MocktokenTransport struct {
+	ctrl     *gomock.Controller
+	recorder *MocktokenTransportMockRecorder
+}
+
+// MocktokenTransportMockRecorder is the mock recorder for MocktokenTransport.
+type MocktokenTransportMockRecorder struct {
+	mock *MocktokenTransport
+}
+
+// NewMocktokenTransport creates a new mock instance.
+func NewMocktokenTransport(ctrl *gomock.Controller) *MocktokenTransport {
+	mock := &MocktokenTransport{ctrl: ctrl}
+	mock.recorder = &MocktokenTransportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktokenTransport) EXPECT() *MocktokenTransportMockRecorder {
+	return m.recorder
+}
+
+// Write mocks base method.
+func (m *MocktokenTransport) Write(c *fiber.Ctx, issued dto.Tokens, asJSON bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Write", c, issued, asJSON)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Write indicates an expected call of Write.
+func (mr *MocktokenTransportMockRecorder) Write(c, issued, asJSON interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MocktokenTransport)(nil).Write), c, issued, asJSON)
+}
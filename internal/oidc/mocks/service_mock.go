@@ -0,0 +1,342 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	dto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIdentityProvider is a mock of IdentityProvider interface.
+type MockIdentityProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityProviderMockRecorder
+}
+
+// MockIdentityProviderMockRecorder is the mock recorder for MockIdentityProvider.
+type MockIdentityProviderMockRecorder struct {
+	mock *MockIdentityProvider
+}
+
+// NewMockIdentityProvider creates a new mock instance.
+func NewMockIdentityProvider(ctrl *gomock.Controller) *MockIdentityProvider {
+	mock := &MockIdentityProvider{ctrl: ctrl}
+	mock.recorder = &MockIdentityProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityProvider) EXPECT() *MockIdentityProviderMockRecorder {
+	return m.recorder
+}
+
+// AuthURL mocks base method.
+func (m *MockIdentityProvider) AuthURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthURL", ctx, state, nonce, verifier)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthURL indicates an expected call of AuthURL.
+func (mr *MockIdentityProviderMockRecorder) AuthURL(ctx, state, nonce, verifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthURL", reflect.TypeOf((*MockIdentityProvider)(nil).AuthURL), ctx, state, nonce, verifier)
+}
+
+// Exchange mocks base method.
+func (m *MockIdentityProvider) Exchange(ctx context.Context, code, verifier string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, code, verifier)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockIdentityProviderMockRecorder) Exchange(ctx, code, verifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockIdentityProvider)(nil).Exchange), ctx, code, verifier)
+}
+
+// Issuer mocks base method.
+func (m *MockIdentityProvider) Issuer() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Issuer")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Issuer indicates an expected call of Issuer.
+func (mr *MockIdentityProviderMockRecorder) Issuer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issuer", reflect.TypeOf((*MockIdentityProvider)(nil).Issuer))
+}
+
+// Verify mocks base method.
+func (m *MockIdentityProvider) Verify(ctx context.Context, raw, nonce string) (*dto.IDClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, raw, nonce)
+	ret0, _ := ret[0].(*dto.IDClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockIdentityProviderMockRecorder) Verify(ctx, raw, nonce interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockIdentityProvider)(nil).Verify), ctx, raw, nonce)
+}
+
+// MockoidcRepository is a mock of oidcRepository interface.
+type MockoidcRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockoidcRepositoryMockRecorder
+}
+
+// MockoidcRepositoryMockRecorder is the mock recorder for MockoidcRepository.
+type MockoidcRepositoryMockRecorder struct {
+	mock *MockoidcRepository
+}
+
+// NewMockoidcRepository creates a new mock instance.
+func NewMockoidcRepository(ctrl *gomock.Controller) *MockoidcRepository {
+	mock := &MockoidcRepository{ctrl: ctrl}
+	mock.recorder = &MockoidcRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockoidcRepository) EXPECT() *MockoidcRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConsumeState mocks base method.
+func (m *MockoidcRepository) ConsumeState(ctx context.Context, stateHash string) (*models.OIDCState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeState", ctx, stateHash)
+	ret0, _ := ret[0].(*models.OIDCState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeState indicates an expected call of ConsumeState.
+func (mr *MockoidcRepositoryMockRecorder) ConsumeState(ctx, stateHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeState", reflect.TypeOf((*MockoidcRepository)(nil).ConsumeState), ctx, stateHash)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockoidcRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockoidcRepositoryMockRecorder) DeleteExpired(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockoidcRepository)(nil).DeleteExpired), ctx)
+}
+
+// FindUserBySubject mocks base method.
+func (m *MockoidcRepository) FindUserBySubject(ctx context.Context, issuer, subject string) (models.ModelID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserBySubject", ctx, issuer, subject)
+	ret0, _ := ret[0].(models.ModelID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserBySubject indicates an expected call of FindUserBySubject.
+func (mr *MockoidcRepositoryMockRecorder) FindUserBySubject(ctx, issuer, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserBySubject", reflect.TypeOf((*MockoidcRepository)(nil).FindUserBySubject), ctx, issuer, subject)
+}
+
+// IsLoginTaken mocks base method.
+func (m *MockoidcRepository) IsLoginTaken(ctx context.Context, login string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsLoginTaken", ctx, login)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsLoginTaken indicates an expected call of IsLoginTaken.
+func (mr *MockoidcRepositoryMockRecorder) IsLoginTaken(ctx, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLoginTaken", reflect.TypeOf((*MockoidcRepository)(nil).IsLoginTaken), ctx, login)
+}
+
+// Link mocks base method.
+func (m *MockoidcRepository) Link(ctx context.Context, identity *models.Identity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Link", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Link indicates an expected call of Link.
+func (mr *MockoidcRepositoryMockRecorder) Link(ctx, identity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Link", reflect.TypeOf((*MockoidcRepository)(nil).Link), ctx, identity)
+}
+
+// PendingStates mocks base method.
+func (m *MockoidcRepository) PendingStates(ctx context.Context, ip string) (int, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingStates", ctx, ip)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PendingStates indicates an expected call of PendingStates.
+func (mr *MockoidcRepositoryMockRecorder) PendingStates(ctx, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingStates", reflect.TypeOf((*MockoidcRepository)(nil).PendingStates), ctx, ip)
+}
+
+// Provision mocks base method.
+func (m *MockoidcRepository) Provision(ctx context.Context, user *models.User, identity *models.Identity) (models.ModelID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Provision", ctx, user, identity)
+	ret0, _ := ret[0].(models.ModelID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Provision indicates an expected call of Provision.
+func (mr *MockoidcRepositoryMockRecorder) Provision(ctx, user, identity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Provision", reflect.TypeOf((*MockoidcRepository)(nil).Provision), ctx, user, identity)
+}
+
+// SaveReauth mocks base method.
+func (m *MockoidcRepository) SaveReauth(ctx context.Context, userID models.ModelID, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveReauth", ctx, userID, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveReauth indicates an expected call of SaveReauth.
+func (mr *MockoidcRepositoryMockRecorder) SaveReauth(ctx, userID, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveReauth", reflect.TypeOf((*MockoidcRepository)(nil).SaveReauth), ctx, userID, expiresAt)
+}
+
+// SaveState mocks base method.
+func (m_2 *MockoidcRepository) SaveState(ctx context.Context, m *models.OIDCState) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SaveState", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveState indicates an expected call of SaveState.
+func (mr *MockoidcRepositoryMockRecorder) SaveState(ctx, m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveState", reflect.TypeOf((*MockoidcRepository)(nil).SaveState), ctx, m)
+}
+
+// Unlink mocks base method.
+func (m *MockoidcRepository) Unlink(ctx context.Context, userID models.ModelID, issuer string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlink", ctx, userID, issuer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlink indicates an expected call of Unlink.
+func (mr *MockoidcRepositoryMockRecorder) Unlink(ctx, userID, issuer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlink", reflect.TypeOf((*MockoidcRepository)(nil).Unlink), ctx, userID, issuer)
+}
+
+// MockTokenIssuer is a mock of TokenIssuer interface.
+type MockTokenIssuer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenIssuerMockRecorder
+}
+
+// MockTokenIssuerMockRecorder is the mock recorder for MockTokenIssuer.
+type MockTokenIssuerMockRecorder struct {
+	mock *MockTokenIssuer
+}
+
+// NewMockTokenIssuer creates a new mock instance.
+func NewMockTokenIssuer(ctrl *gomock.Controller) *MockTokenIssuer {
+	mock := &MockTokenIssuer{ctrl: ctrl}
+	mock.recorder = &MockTokenIssuerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenIssuer) EXPECT() *MockTokenIssuerMockRecorder {
+	return m.recorder
+}
+
+// Issue mocks base method.
+func (m *MockTokenIssuer) Issue(ctx context.Context, userID models.ModelID, client dto.Client) (dto.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Issue", ctx, userID, client)
+	ret0, _ := ret[0].(dto.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Issue indicates an expected call of Issue.
+func (mr *MockTokenIssuerMockRecorder) Issue(ctx, userID, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issue", reflect.TypeOf((*MockTokenIssuer)(nil).Issue), ctx, userID, client)
+}
+
+// MockTwoFactor is a mock of TwoFactor interface.
+type MockTwoFactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorMockRecorder
+}
+
+// MockTwoFactorMockRecorder is the mock recorder for MockTwoFactor.
+type MockTwoFactorMockRecorder struct {
+	mock *MockTwoFactor
+}
+
+// NewMockTwoFactor creates a new mock instance.
+func NewMockTwoFactor(ctrl *gomock.Controller) *MockTwoFactor {
+	mock := &MockTwoFactor{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactor) EXPECT() *MockTwoFactorMockRecorder {
+	return m.recorder
+}
+
+// Challenge mocks base method.
+func (m *MockTwoFactor) Challenge(ctx context.Context, userID models.ModelID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Challenge", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Challenge indicates an expected call of Challenge.
+func (mr *MockTwoFactorMockRecorder) Challenge(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Challenge", reflect.TypeOf((*MockTwoFactor)(nil).Challenge), ctx, userID)
+}
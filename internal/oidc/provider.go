@@ -0,0 +1,319 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+)
+
+// jwksRefetch bounds how often unknown key ids make the provider refetch
+// its key set.
+const jwksRefetch = time.Minute
+
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+type (
+	// ProviderConfig registers the server as a confidential client of the
+	// provider at Issuer. RedirectURL must point to GET /api/user/oidc/callback.
+	ProviderConfig struct {
+		Issuer       string
+		ClientID     string
+		ClientSecret string
+		RedirectURL  string
+		Scopes       []string
+	}
+	// Provider talks to an OpenID provider as a relying party using the
+	// authorization code flow with PKCE. The discovery document and the key
+	// set are fetched on first use.
+	Provider struct {
+		conf   ProviderConfig
+		client *resty.Client
+
+		mu          sync.Mutex
+		metadata    *metadata
+		keys        map[string]any
+		keysFetched time.Time
+	}
+	metadata struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}
+	tokenResponse struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+)
+
+func NewProvider(conf ProviderConfig) *Provider {
+	if len(conf.Scopes) == 0 {
+		conf.Scopes = []string{"openid"}
+	}
+
+	return &Provider{
+		conf:   conf,
+		client: resty.New().SetTimeout(10 * time.Second),
+	}
+}
+
+func (p *Provider) Issuer() string {
+	return p.conf.Issuer
+}
+
+// AuthURL is where the browser starts the login for state. The provider
+// binds the code to the S256 challenge of verifier and the ID token to nonce.
+func (p *Provider) AuthURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(md.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.conf.ClientID)
+	q.Set("redirect_uri", p.conf.RedirectURL)
+	q.Set("scope", strings.Join(p.conf.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", challengeS256(verifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// Exchange redeems an authorization code and returns the raw ID token.
+// Rejected codes yield apperrors.ErrInvalidToken.
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (string, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var body tokenResponse
+	resp, err := p.request(ctx).
+		SetBasicAuth(url.QueryEscape(p.conf.ClientID), url.QueryEscape(p.conf.ClientSecret)).
+		SetFormData(map[string]string{
+			"grant_type":    "authorization_code",
+			"code":          code,
+			"redirect_uri":  p.conf.RedirectURL,
+			"code_verifier": verifier,
+		}).
+		SetResult(&body).
+		SetError(&body).
+		Post(md.TokenEndpoint)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case body.Error == "invalid_grant":
+		return "", apperrors.ErrInvalidToken
+	case resp.IsError():
+		return "", fmt.Errorf("token endpoint answered %d: %s %s", resp.StatusCode(), body.Error, body.ErrorDescription)
+	case body.IDToken == "":
+		return "", errors.New("token endpoint returned no id_token")
+	}
+
+	return body.IDToken, nil
+}
+
+// Verify checks the signature and the claims of an ID token issued for the
+// request with nonce. Invalid tokens yield apperrors.ErrInvalidToken.
+func (p *Provider) Verify(ctx context.Context, raw, nonce string) (*appdto.IDClaims, error) {
+	var claims appdto.IDClaims
+	_, err := jwt.ParseWithClaims(raw, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, kid)
+	}, jwt.WithValidMethods(signingMethods))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", apperrors.ErrInvalidToken, err)
+	}
+
+	now := time.Now()
+	switch {
+	case !claims.VerifyIssuer(p.conf.Issuer, true):
+		return nil, fmt.Errorf("%w: issuer %q", apperrors.ErrInvalidToken, claims.Issuer)
+	case !claims.VerifyAudience(p.conf.ClientID, true):
+		return nil, fmt.Errorf("%w: audience %v", apperrors.ErrInvalidToken, claims.Audience)
+	case len(claims.Audience) > 1 && claims.AuthorizedParty != p.conf.ClientID:
+		return nil, fmt.Errorf("%w: authorized party %q", apperrors.ErrInvalidToken, claims.AuthorizedParty)
+	case !claims.VerifyExpiresAt(now, true):
+		return nil, fmt.Errorf("%w: expired", apperrors.ErrInvalidToken)
+	case subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1:
+		return nil, fmt.Errorf("%w: nonce mismatch", apperrors.ErrInvalidToken)
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w: no subject", apperrors.ErrInvalidToken)
+	}
+
+	return &claims, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	var md metadata
+	resp, err := p.request(ctx).
+		SetResult(&md).
+		// the issuer is compared as given, only the discovery path is joined
+		Get(strings.TrimSuffix(p.conf.Issuer, "/") + "/.well-known/openid-configuration")
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("discovery answered %d", resp.StatusCode())
+	}
+	if md.Issuer != p.conf.Issuer {
+		return nil, fmt.Errorf("discovery names issuer %q, want %q", md.Issuer, p.conf.Issuer)
+	}
+	if md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JWKSURI == "" {
+		return nil, errors.New("discovery document lacks endpoints")
+	}
+
+	p.metadata = &md
+	return p.metadata, nil
+}
+
+// key returns the verification key kid, refetching the key set when the
+// provider rotated its keys.
+func (p *Provider) key(ctx context.Context, kid string) (any, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if k, ok := p.lookup(kid); ok {
+		return k, nil
+	}
+	if time.Since(p.keysFetched) < jwksRefetch {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	var set appdto.JWKS
+	resp, err := p.request(ctx).SetResult(&set).Get(md.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("jwks answered %d", resp.StatusCode())
+	}
+
+	p.keys = make(map[string]any, len(set.Keys))
+	p.keysFetched = time.Now()
+	for _, j := range set.Keys {
+		if j.Use != "" && j.Use != "sig" {
+			continue
+		}
+		if k, err := publicKey(j); err == nil {
+			p.keys[j.KID] = k
+		}
+	}
+
+	if k, ok := p.lookup(kid); ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+// lookup tolerates tokens without a kid from providers with a single key.
+func (p *Provider) lookup(kid string) (any, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, k := range p.keys {
+			return k, true
+		}
+	}
+	k, ok := p.keys[kid]
+	return k, ok
+}
+
+func publicKey(j appdto.JWK) (any, error) {
+	dec := base64.RawURLEncoding.DecodeString
+
+	switch j.Kty {
+	case "RSA":
+		n, err := dec(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := dec(j.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", j.Crv)
+		}
+		x, err := dec(j.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := dec(j.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", j.Crv)
+		}
+		x, err := dec(j.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", j.Kty)
+}
+
+// request parses JSON answers whatever their content type.
+func (p *Provider) request(ctx context.Context) *resty.Request {
+	return p.client.R().SetContext(ctx).ForceContentType("application/json")
+}
+
+// challengeS256 is the RFC 7636 code challenge of verifier.
+func challengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,273 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/credentials"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+)
+
+// mockProvider is a minimal OpenID provider: it hands out codes through
+// authorize, checks client credentials and PKCE on redeem and signs ID
+// tokens with an RSA key.
+type mockProvider struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+
+	mu     sync.Mutex
+	codes  map[string]pendingCode
+	claims jwt.MapClaims
+	// issuer is the identifier the provider announces, its URL by default
+	issuer string
+}
+
+type pendingCode struct {
+	challenge, nonce string
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	m := &mockProvider{t: t, key: key, kid: "k1", codes: map[string]pendingCode{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 m.issuer,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(appdto.JWKS{Keys: []appdto.JWK{{
+			Kty: "RSA",
+			KID: m.kid,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", m.token)
+	m.server = httptest.NewServer(mux)
+	m.issuer = m.server.URL
+	t.Cleanup(m.server.Close)
+
+	return m
+}
+
+// authorize stands in for the browser round trip to the provider.
+func (m *mockProvider) authorize(authURL string) (code, state string) {
+	u, err := url.Parse(authURL)
+	require.NoError(m.t, err)
+	q := u.Query()
+	require.Equal(m.t, "S256", q.Get("code_challenge_method"))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	code = "code-" + q.Get("state")
+	m.codes[code] = pendingCode{q.Get("code_challenge"), q.Get("nonce")}
+	return code, q.Get("state")
+}
+
+func (m *mockProvider) token(w http.ResponseWriter, r *http.Request) {
+	id, secret, _ := r.BasicAuth()
+	if id != "client" || secret != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pending, ok := m.codes[r.PostFormValue("code")]
+	delete(m.codes, r.PostFormValue("code"))
+	if !ok || challengeS256(r.PostFormValue("code_verifier")) != pending.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   m.issuer,
+		"sub":   "subject-1",
+		"aud":   "client",
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": pending.nonce,
+		"email": "alice@example.com",
+	}
+	for k, v := range m.claims {
+		claims[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = m.kid
+	signed, err := token.SignedString(m.key)
+	require.NoError(m.t, err)
+
+	_ = json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "token_type": "Bearer"})
+}
+
+func (m *mockProvider) newProvider() *Provider {
+	return m.newProviderOf(m.server.URL)
+}
+
+func (m *mockProvider) newProviderOf(issuer string) *Provider {
+	return NewProvider(ProviderConfig{
+		Issuer:       issuer,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/api/user/oidc/callback",
+		Scopes:       []string{"openid", "email"},
+	})
+}
+
+func TestProvider_codeFlow(t *testing.T) {
+	m := newMockProvider(t)
+	p := m.newProvider()
+	ctx := context.Background()
+
+	authURL, err := p.AuthURL(ctx, "state", "nonce", "verifier")
+	require.NoError(t, err)
+	code, state := m.authorize(authURL)
+	assert.Equal(t, "state", state)
+
+	raw, err := p.Exchange(ctx, code, "verifier")
+	require.NoError(t, err)
+
+	claims, err := p.Verify(ctx, raw, "nonce")
+	require.NoError(t, err)
+	assert.Equal(t, "subject-1", claims.Subject)
+	assert.Equal(t, "alice@example.com", claims.Email)
+
+	_, err = p.Exchange(ctx, code, "verifier")
+	assert.ErrorIs(t, err, apperrors.ErrInvalidToken, "codes work once")
+}
+
+func TestProvider_issuer(t *testing.T) {
+	tests := []struct {
+		name      string
+		announced string
+		issuer    string
+		ok        bool
+	}{
+		{name: "positive test #1, same issuer", announced: "", issuer: "", ok: true},
+		{name: "positive test #2, trailing slash on both sides", announced: "/", issuer: "/", ok: true},
+		{name: "negative test #3, slash configured, none announced", announced: "", issuer: "/"},
+		{name: "negative test #4, slash announced, none configured", announced: "/", issuer: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMockProvider(t)
+			m.issuer = m.server.URL + tt.announced
+			p := m.newProviderOf(m.server.URL + tt.issuer)
+
+			assert.Equal(t, m.server.URL+tt.issuer, p.Issuer(), "the issuer is kept as configured")
+
+			_, err := p.AuthURL(context.Background(), "state", "nonce", "verifier")
+			if !tt.ok {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProvider_rejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   jwt.MapClaims
+		verifier string
+		nonce    string
+	}{
+		{name: "wrong verifier", verifier: "other"},
+		{name: "wrong nonce", nonce: "other"},
+		{name: "foreign audience", claims: jwt.MapClaims{"aud": "someone-else"}},
+		{name: "foreign issuer", claims: jwt.MapClaims{"iss": "https://evil.example.com"}},
+		{name: "expired", claims: jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}},
+		{name: "no expiry", claims: jwt.MapClaims{"exp": nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMockProvider(t)
+			m.claims = tt.claims
+			p := m.newProvider()
+			ctx := context.Background()
+
+			authURL, err := p.AuthURL(ctx, "state", "nonce", "verifier")
+			require.NoError(t, err)
+			code, _ := m.authorize(authURL)
+
+			verifier := "verifier"
+			if tt.verifier != "" {
+				verifier = tt.verifier
+			}
+			raw, err := p.Exchange(ctx, code, verifier)
+			if err == nil {
+				nonce := "nonce"
+				if tt.nonce != "" {
+					nonce = tt.nonce
+				}
+				_, err = p.Verify(ctx, raw, nonce)
+			}
+			assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+		})
+	}
+}
+
+func testPolicy(t *testing.T) *credentials.Policy {
+	p, err := credentials.New(credentials.Config{
+		LoginMinLength:    3,
+		LoginMaxLength:    20,
+		LoginCharset:      "a-z0-9._-",
+		PasswordMinLength: 8,
+		PasswordMaxLength: 64,
+	})
+	require.NoError(t, err)
+	return p
+}
+
+func TestWantedLogin(t *testing.T) {
+	p := testPolicy(t)
+	tests := []struct {
+		name   string
+		claims *appdto.IDClaims
+		want   string
+	}{
+		{name: "preferred username", claims: &appdto.IDClaims{PreferredUsername: "alice", Email: "a@example.com"}, want: "alice"},
+		{name: "email outside the charset", claims: &appdto.IDClaims{Email: "a@example.com"}, want: "a-example.com"},
+		{name: "unsafe characters replaced", claims: &appdto.IDClaims{PreferredUsername: "john doe!"}, want: "john-doe"},
+		{name: "too short username skipped", claims: &appdto.IDClaims{PreferredUsername: "Jo", Email: "jo@example.io"}, want: "jo-example.io"},
+		{name: "cut with room for a suffix", claims: &appdto.IDClaims{PreferredUsername: "a-very-long-user-name"}, want: "a-very-long-u"},
+		{name: "subject", claims: &appdto.IDClaims{RegisteredClaims: jwt.RegisteredClaims{Subject: "123"}}, want: "oidc-123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wantedLogin(p, tt.claims)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, validLogin(p, got))
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package oidc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/db/pg"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+func (r *Repository) SaveState(ctx context.Context, m *models.OIDCState) error {
+	query := `
+		INSERT INTO oidc_states (state_hash, nonce, code_verifier, link_user_id, reauth, ip, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.ExecContext(ctx, query, m.StateHash, m.Nonce, m.CodeVerifier, m.LinkUserID, m.Reauth, m.IP, m.ExpiresAt)
+	return err
+}
+
+// PendingStates returns how many unexpired requests ip started and when the
+// first of them expires.
+func (r *Repository) PendingStates(ctx context.Context, ip string) (int, time.Time, error) {
+	var (
+		n     int
+		first sql.NullTime
+	)
+	query := `
+		SELECT COUNT(*), MIN(expires_at)
+		FROM oidc_states
+		WHERE ip = $1 AND expires_at > NOW()
+	`
+	if err := r.db.QueryRowContext(ctx, query, ip).Scan(&n, &first); err != nil {
+		return 0, time.Time{}, err
+	}
+	return n, first.Time, nil
+}
+
+// DeleteExpired deletes the requests nobody completed in time and the
+// unused re-authentications.
+func (r *Repository) DeleteExpired(ctx context.Context) (int64, error) {
+	var total int64
+	for _, query := range []string{
+		`DELETE FROM oidc_states WHERE expires_at <= NOW()`,
+		`DELETE FROM oidc_reauths WHERE expires_at <= NOW()`,
+	} {
+		res, err := r.db.ExecContext(ctx, query)
+		if err != nil {
+			return total, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// ConsumeState deletes the pending request with the given state hash and
+// returns it, so a state works once. Unknown and expired states yield
+// apperrors.ErrInvalidToken.
+func (r *Repository) ConsumeState(ctx context.Context, stateHash string) (*models.OIDCState, error) {
+	var st models.OIDCState
+	query := `
+		DELETE FROM oidc_states WHERE state_hash = $1 AND expires_at > NOW()
+		RETURNING state_hash, nonce, code_verifier, link_user_id, reauth, ip, expires_at, created_at
+	`
+	err := r.db.QueryRowContext(ctx, query, stateHash).Scan(
+		&st.StateHash,
+		&st.Nonce,
+		&st.CodeVerifier,
+		&st.LinkUserID,
+		&st.Reauth,
+		&st.IP,
+		&st.ExpiresAt,
+		&st.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrInvalidToken
+		}
+		return nil, err
+	}
+	return &st, nil
+}
+
+// FindUserBySubject returns the user linked to the subject of issuer and
+// records the login.
+func (r *Repository) FindUserBySubject(ctx context.Context, issuer, subject string) (models.ModelID, error) {
+	var userID models.ModelID
+	query := `
+		UPDATE user_identities SET last_login_at = NOW()
+		WHERE issuer = $1 AND subject = $2
+		RETURNING user_id
+	`
+	err := r.db.QueryRowContext(ctx, query, issuer, subject).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", apperrors.ErrNotFound
+		}
+		return "", err
+	}
+	return userID, nil
+}
+
+func (r *Repository) IsLoginTaken(ctx context.Context, login string) (bool, error) {
+	var taken bool
+	query := `
+		SELECT EXISTS (SELECT 1 FROM users WHERE LOWER(login) = LOWER($1))
+	`
+	err := r.db.QueryRowContext(ctx, query, login).Scan(&taken)
+	return taken, err
+}
+
+// Provision creates a user without a password, who can only log in through
+// the provider, together with its identity. A login or subject taken
+// meanwhile yields apperrors.ErrIsExist.
+func (r *Repository) Provision(ctx context.Context, user *models.User, identity *models.Identity) (models.ModelID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var userID models.ModelID
+	userQuery := `
+		INSERT INTO users (login, password, referral_code)
+		VALUES ($1, '', $2)
+		RETURNING id
+	`
+	if err = tx.QueryRowContext(ctx, userQuery, user.Login, user.ReferralCode).Scan(&userID); err != nil {
+		if pg.IsUniqueViolation(err) {
+			return "", apperrors.ErrIsExist
+		}
+		return "", err
+	}
+
+	identityQuery := `
+		INSERT INTO user_identities (user_id, issuer, subject, email, last_login_at)
+		VALUES ($1, $2, $3, $4, NOW())
+	`
+	_, err = tx.ExecContext(ctx, identityQuery, userID, identity.Issuer, identity.Subject, identity.Email)
+	if err != nil {
+		if pg.IsUniqueViolation(err) {
+			return "", apperrors.ErrIsExist
+		}
+		return "", err
+	}
+
+	return userID, tx.Commit()
+}
+
+// Link attaches the identity to its user. A subject already linked to
+// another user yields apperrors.ErrIsExist, linking it again to the same
+// user only records the login.
+func (r *Repository) Link(ctx context.Context, identity *models.Identity) error {
+	query := `
+		INSERT INTO user_identities (user_id, issuer, subject, email, last_login_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (issuer, subject) DO UPDATE SET last_login_at = NOW()
+		WHERE user_identities.user_id = EXCLUDED.user_id
+	`
+	res, err := r.db.ExecContext(ctx, query, identity.UserID, identity.Issuer, identity.Subject, identity.Email)
+	if err != nil {
+		if pg.IsUniqueViolation(err) {
+			return apperrors.ErrIsExist
+		}
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperrors.ErrIsExist
+	}
+	return nil
+}
+
+// Unlink removes the identities of issuer from the user unless they are its
+// only way to log in, which yields apperrors.ErrNoRequiredValue.
+func (r *Repository) Unlink(ctx context.Context, userID models.ModelID, issuer string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var password string
+	if err = tx.QueryRowContext(ctx, `SELECT password FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&password); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.ErrNotFound
+		}
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM user_identities WHERE user_id = $1 AND issuer = $2`, userID, issuer)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperrors.ErrNotFound
+	}
+	if password == "" {
+		return apperrors.ErrNoRequiredValue
+	}
+
+	return tx.Commit()
+}
+
+// SaveReauth records that the user just logged in at the provider again,
+// replacing an unused earlier record.
+func (r *Repository) SaveReauth(ctx context.Context, userID models.ModelID, expiresAt time.Time) error {
+	query := `
+		INSERT INTO oidc_reauths (user_id, expires_at)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET expires_at = EXCLUDED.expires_at
+	`
+	_, err := r.db.ExecContext(ctx, query, userID, expiresAt)
+	return err
+}
+
+// ConsumeReauth spends the unexpired re-authentication of the user. Without
+// one it yields apperrors.ErrInvalidCredentials.
+func (r *Repository) ConsumeReauth(ctx context.Context, userID models.ModelID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM oidc_reauths WHERE user_id = $1 AND expires_at > NOW()`, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperrors.ErrInvalidCredentials
+	}
+	return nil
+}
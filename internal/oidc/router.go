@@ -0,0 +1,19 @@
+package oidc
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserMiddleware interface {
+	Auth(c *fiber.Ctx) error
+}
+
+func SetupRouter(r fiber.Router, mw UserMiddleware, transport tokenTransport, service oidcService) {
+	handle := newHandler(service, transport)
+
+	r.Get("oidc/login", handle.login)
+	r.Get("oidc/callback", handle.callback)
+	r.Post("oidc/link", mw.Auth, handle.link)
+	r.Post("oidc/reauth", mw.Auth, handle.reauth)
+	r.Delete("oidc/link", mw.Auth, handle.unlink)
+}
@@ -0,0 +1,297 @@
+package oidc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/credentials"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+const (
+	// loginAttempts bounds the suffixes tried when the wanted login is taken.
+	loginAttempts = 5
+	// suffixLen is the length of the "-xxxxxx" suffix of taken logins.
+	suffixLen = 7
+	// provisionAttempts bounds the retries of first logins losing a race.
+	provisionAttempts = 3
+)
+
+type (
+	IdentityProvider interface {
+		Issuer() string
+		AuthURL(ctx context.Context, state, nonce, verifier string) (string, error)
+		Exchange(ctx context.Context, code, verifier string) (string, error)
+		Verify(ctx context.Context, raw, nonce string) (*appdto.IDClaims, error)
+	}
+	oidcRepository interface {
+		SaveState(ctx context.Context, m *models.OIDCState) error
+		PendingStates(ctx context.Context, ip string) (int, time.Time, error)
+		DeleteExpired(ctx context.Context) (int64, error)
+		ConsumeState(ctx context.Context, stateHash string) (*models.OIDCState, error)
+		FindUserBySubject(ctx context.Context, issuer, subject string) (models.ModelID, error)
+		IsLoginTaken(ctx context.Context, login string) (bool, error)
+		Provision(ctx context.Context, user *models.User, identity *models.Identity) (models.ModelID, error)
+		Link(ctx context.Context, identity *models.Identity) error
+		Unlink(ctx context.Context, userID models.ModelID, issuer string) error
+		SaveReauth(ctx context.Context, userID models.ModelID, expiresAt time.Time) error
+	}
+	TokenIssuer interface {
+		Issue(ctx context.Context, userID models.ModelID, client appdto.Client) (appdto.Tokens, error)
+	}
+	TwoFactor interface {
+		Challenge(ctx context.Context, userID models.ModelID) error
+	}
+	// Config of the login: it must be completed within StateTTL, and one
+	// client address may have at most StatesPerIP logins pending. A fresh
+	// login confirming a sensitive change is good for ReauthTTL.
+	Config struct {
+		StateTTL    time.Duration
+		StatesPerIP int
+		ReauthTTL   time.Duration
+	}
+	Service struct {
+		conf        Config
+		provider    IdentityProvider
+		repository  oidcRepository
+		policy      *credentials.Policy
+		tokenIssuer TokenIssuer
+		twoFactor   TwoFactor
+	}
+)
+
+func NewService(
+	conf Config,
+	provider IdentityProvider,
+	repository oidcRepository,
+	policy *credentials.Policy,
+	tokenIssuer TokenIssuer,
+	twoFactor TwoFactor,
+) *Service {
+	return &Service{conf, provider, repository, policy, tokenIssuer, twoFactor}
+}
+
+// Begin starts an authorization request of the client at ip and returns the
+// provider URL to send the browser to along with its state. With linkUserID
+// set, the callback links the provider subject to that user instead of
+// logging in by it. Past StatesPerIP pending requests of ip it yields an
+// *apperrors.RetryError.
+func (s *Service) Begin(ctx context.Context, linkUserID models.ModelID, ip string) (authURL, state string, err error) {
+	return s.begin(ctx, &models.OIDCState{
+		LinkUserID: sql.NullString{String: string(linkUserID), Valid: linkUserID != ""},
+		IP:         ip,
+	})
+}
+
+// BeginReauth starts an authorization request like Begin by which the user,
+// logged in already, confirms deleting the account or setting a first
+// password. Accounts provisioned by the provider have no password to
+// confirm them with.
+func (s *Service) BeginReauth(ctx context.Context, userID models.ModelID, ip string) (authURL, state string, err error) {
+	return s.begin(ctx, &models.OIDCState{
+		LinkUserID: sql.NullString{String: string(userID), Valid: true},
+		Reauth:     true,
+		IP:         ip,
+	})
+}
+
+func (s *Service) begin(ctx context.Context, st *models.OIDCState) (authURL, state string, err error) {
+	n, firstExpiry, err := s.repository.PendingStates(ctx, st.IP)
+	if err != nil {
+		return "", "", err
+	}
+	if n >= s.conf.StatesPerIP {
+		logger.Log.Warn("oidc:begin", "too many pending logins", "ip", st.IP)
+		return "", "", &apperrors.RetryError{Err: apperrors.ErrTooManyAttempts, RetryAfter: time.Until(firstExpiry)}
+	}
+
+	state, nonce, verifier := utils.GenerateSecret(), utils.GenerateSecret(), utils.GenerateSecret()
+	st.StateHash = utils.HashSecret(state)
+	st.Nonce = nonce
+	st.CodeVerifier = verifier
+	st.ExpiresAt = time.Now().Add(s.conf.StateTTL)
+
+	if err = s.repository.SaveState(ctx, st); err != nil {
+		return "", "", err
+	}
+
+	authURL, err = s.provider.AuthURL(ctx, state, nonce, verifier)
+	return authURL, state, err
+}
+
+// Complete redeems the code of the callback for state and logs the user in.
+// Subjects seen for the first time get a new user. Unknown, replayed and
+// expired states as well as rejected codes and ID tokens yield
+// apperrors.ErrInvalidToken; users with 2FA get an *apperrors.ChallengeError.
+// Requests started by BeginReauth yield no tokens, only record the fresh
+// login; a subject of another user yields apperrors.ErrInvalidCredentials.
+func (s *Service) Complete(ctx context.Context, code, state string, client appdto.Client) (appdto.Tokens, error) {
+	st, err := s.repository.ConsumeState(ctx, utils.HashSecret(state))
+	if err != nil {
+		return appdto.Tokens{}, err
+	}
+
+	raw, err := s.provider.Exchange(ctx, code, st.CodeVerifier)
+	if err != nil {
+		return appdto.Tokens{}, err
+	}
+
+	claims, err := s.provider.Verify(ctx, raw, st.Nonce)
+	if err != nil {
+		return appdto.Tokens{}, err
+	}
+
+	identity := &models.Identity{
+		Issuer:  s.provider.Issuer(),
+		Subject: claims.Subject,
+		Email:   claims.Email,
+	}
+
+	if st.Reauth {
+		return appdto.Tokens{}, s.reauthenticate(ctx, models.ModelID(st.LinkUserID.String), identity)
+	}
+
+	var userID models.ModelID
+	if st.LinkUserID.Valid {
+		identity.UserID = models.ModelID(st.LinkUserID.String)
+		if err = s.repository.Link(ctx, identity); err != nil {
+			return appdto.Tokens{}, err
+		}
+		userID = identity.UserID
+	} else if userID, err = s.findOrProvision(ctx, claims, identity); err != nil {
+		return appdto.Tokens{}, err
+	}
+
+	if err = s.twoFactor.Challenge(ctx, userID); err != nil {
+		return appdto.Tokens{}, err
+	}
+
+	return s.tokenIssuer.Issue(ctx, userID, client)
+}
+
+// Unlink detaches the provider from the user, see Repository.Unlink.
+func (s *Service) Unlink(ctx context.Context, userID models.ModelID) error {
+	return s.repository.Unlink(ctx, userID, s.provider.Issuer())
+}
+
+func (s *Service) reauthenticate(ctx context.Context, userID models.ModelID, identity *models.Identity) error {
+	linked, err := s.repository.FindUserBySubject(ctx, identity.Issuer, identity.Subject)
+	if errors.Is(err, apperrors.ErrNotFound) || (err == nil && linked != userID) {
+		logger.Log.Warn("oidc:reauthenticate", "foreign subject", "user_id", userID, "subject", identity.Subject)
+		return apperrors.ErrInvalidCredentials
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.repository.SaveReauth(ctx, userID, time.Now().Add(s.conf.ReauthTTL))
+}
+
+// CollectGarbage deletes expired login requests and re-authentications every
+// interval until ctx is done.
+func (s *Service) CollectGarbage(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			n, err := s.repository.DeleteExpired(ctx)
+			if err != nil {
+				logger.Log.Error("oidc:collectGarbage", "DeleteExpired", err)
+				continue
+			}
+			logger.Log.Debug("oidc:collectGarbage", "deleted", n)
+		}
+	}()
+}
+
+// findOrProvision returns the user of the identity, provisioning one on the
+// first login of the subject. Provisioning fails with apperrors.ErrIsExist
+// when a concurrent first login of the subject, or another user, takes the
+// identity or the login first; the lookup is then retried.
+func (s *Service) findOrProvision(ctx context.Context, claims *appdto.IDClaims, identity *models.Identity) (models.ModelID, error) {
+	for i := 0; i < provisionAttempts; i++ {
+		userID, err := s.repository.FindUserBySubject(ctx, identity.Issuer, identity.Subject)
+		if !errors.Is(err, apperrors.ErrNotFound) {
+			return userID, err
+		}
+
+		userID, err = s.provision(ctx, claims, identity)
+		if !errors.Is(err, apperrors.ErrIsExist) {
+			return userID, err
+		}
+	}
+	return "", apperrors.ErrIsExist
+}
+
+func (s *Service) provision(ctx context.Context, claims *appdto.IDClaims, identity *models.Identity) (models.ModelID, error) {
+	login, err := s.freeLogin(ctx, wantedLogin(s.policy, claims))
+	if err != nil {
+		return "", err
+	}
+
+	userID, err := s.repository.Provision(ctx, &models.User{
+		Login:        login,
+		ReferralCode: utils.GenerateCode(10),
+	}, identity)
+	if err != nil {
+		return "", err
+	}
+
+	logger.Log.Info("oidc:provision", "user_id", userID, "issuer", identity.Issuer, "subject", identity.Subject)
+	return userID, nil
+}
+
+// freeLogin returns login or, when it is taken, login with a random suffix.
+// Existing accounts are never taken over by name, they link explicitly.
+// Candidates the login policy rejects count as taken.
+func (s *Service) freeLogin(ctx context.Context, login string) (string, error) {
+	candidate := login
+	for i := 0; i < loginAttempts; i++ {
+		if validLogin(s.policy, candidate) {
+			taken, err := s.repository.IsLoginTaken(ctx, candidate)
+			if err != nil {
+				return "", err
+			}
+			if !taken {
+				return candidate, nil
+			}
+		}
+		candidate = s.policy.FitLogin(login+"-"+strings.ToLower(utils.GenerateCode(suffixLen-1)), 0)
+	}
+	return "", apperrors.ErrIsExist
+}
+
+// wantedLogin derives a login the policy accepts from the claims: the
+// preferred username, the email or, lacking both, the subject.
+func wantedLogin(policy *credentials.Policy, claims *appdto.IDClaims) string {
+	for _, v := range []string{claims.PreferredUsername, claims.Email, "oidc-" + claims.Subject} {
+		if login := policy.FitLogin(v, suffixLen); validLogin(policy, login) {
+			return login
+		}
+	}
+	return policy.FitLogin("oidc-"+strings.ToLower(utils.GenerateCode(8)), suffixLen)
+}
+
+func validLogin(policy *credentials.Policy, login string) bool {
+	v := &apperrors.ValidationError{}
+	policy.CheckLogin(v, "login", login)
+	return v.OrNil() == nil
+}
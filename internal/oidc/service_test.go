@@ -0,0 +1,365 @@
+package oidc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/oidc/mocks"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+const testIssuer = "https://id.example.com"
+
+var testConf = Config{StateTTL: time.Minute, StatesPerIP: 3, ReauthTTL: 5 * time.Minute}
+
+type serviceMocks struct {
+	provider  *mocks.MockIdentityProvider
+	repo      *mocks.MockoidcRepository
+	issuer    *mocks.MockTokenIssuer
+	twoFactor *mocks.MockTwoFactor
+}
+
+func newTestService(t *testing.T, ctrl *gomock.Controller) (*Service, serviceMocks) {
+	m := serviceMocks{
+		provider:  mocks.NewMockIdentityProvider(ctrl),
+		repo:      mocks.NewMockoidcRepository(ctrl),
+		issuer:    mocks.NewMockTokenIssuer(ctrl),
+		twoFactor: mocks.NewMockTwoFactor(ctrl),
+	}
+	m.provider.EXPECT().Issuer().Return(testIssuer).AnyTimes()
+	return NewService(testConf, m.provider, m.repo, testPolicy(t), m.issuer, m.twoFactor), m
+}
+
+// callback expects the state to be redeemed for the ID token of subject.
+func (m serviceMocks) callback(st *models.OIDCState, claims *appdto.IDClaims) {
+	gomock.InOrder(
+		m.repo.EXPECT().ConsumeState(gomock.Any(), utils.HashSecret("state")).Return(st, nil),
+		m.provider.EXPECT().Exchange(gomock.Any(), "code", "verifier").Return("raw", nil),
+		m.provider.EXPECT().Verify(gomock.Any(), "raw", "nonce").Return(claims, nil),
+	)
+}
+
+func subjectClaims(username string) *appdto.IDClaims {
+	return &appdto.IDClaims{
+		RegisteredClaims:  jwt.RegisteredClaims{Subject: "subject-1"},
+		Email:             "alice@example.com",
+		PreferredUsername: username,
+	}
+}
+
+func TestService_Begin(t *testing.T) {
+	tests := []struct {
+		name       string
+		linkUserID models.ModelID
+		reauth     bool
+	}{
+		{name: "positive test #1, login"},
+		{name: "positive test #2, link", linkUserID: "user"},
+		{name: "positive test #3, re-authentication", linkUserID: "user", reauth: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newTestService(t, ctrl)
+
+			var saved *models.OIDCState
+			m.repo.EXPECT().PendingStates(gomock.Any(), "10.0.0.1").Return(testConf.StatesPerIP-1, time.Now(), nil)
+			m.repo.EXPECT().SaveState(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, st *models.OIDCState) error {
+				saved = st
+				return nil
+			})
+			m.provider.EXPECT().AuthURL(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, state, nonce, verifier string) (string, error) {
+					assert.Equal(t, utils.HashSecret(state), saved.StateHash, "only the hash of the state is stored")
+					assert.Equal(t, nonce, saved.Nonce)
+					assert.Equal(t, verifier, saved.CodeVerifier)
+					return testIssuer + "/authorize?state=" + state, nil
+				})
+
+			begin := s.Begin
+			if tt.reauth {
+				begin = s.BeginReauth
+			}
+			authURL, state, err := begin(context.Background(), tt.linkUserID, "10.0.0.1")
+			require.NoError(t, err)
+
+			assert.Equal(t, testIssuer+"/authorize?state="+state, authURL)
+			assert.Equal(t, sql.NullString{String: string(tt.linkUserID), Valid: tt.linkUserID != ""}, saved.LinkUserID)
+			assert.Equal(t, tt.reauth, saved.Reauth)
+			assert.Equal(t, "10.0.0.1", saved.IP)
+			assert.WithinDuration(t, time.Now().Add(testConf.StateTTL), saved.ExpiresAt, time.Second)
+		})
+	}
+}
+
+func TestService_BeginLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, m := newTestService(t, ctrl)
+	m.repo.EXPECT().PendingStates(gomock.Any(), "10.0.0.1").Return(testConf.StatesPerIP, time.Now().Add(30*time.Second), nil)
+
+	_, _, err := s.Begin(context.Background(), "", "10.0.0.1")
+
+	var retry *apperrors.RetryError
+	require.ErrorAs(t, err, &retry)
+	assert.ErrorIs(t, err, apperrors.ErrTooManyAttempts)
+	assert.InDelta(t, 30*time.Second, retry.RetryAfter, float64(time.Second))
+}
+
+func TestService_Complete(t *testing.T) {
+	login := &models.OIDCState{Nonce: "nonce", CodeVerifier: "verifier"}
+	link := &models.OIDCState{Nonce: "nonce", CodeVerifier: "verifier", LinkUserID: sql.NullString{String: "user", Valid: true}}
+	issued := appdto.Tokens{AccessToken: "access"}
+	errDB := errors.New("db is down")
+
+	tests := []struct {
+		name    string
+		prepare func(m serviceMocks)
+		want    error
+	}{
+		{
+			name: "positive test #1, linked subject logs in",
+			prepare: func(m serviceMocks) {
+				m.callback(login, subjectClaims("alice"))
+				gomock.InOrder(
+					m.repo.EXPECT().FindUserBySubject(gomock.Any(), testIssuer, "subject-1").Return(models.ModelID("user"), nil),
+					m.twoFactor.EXPECT().Challenge(gomock.Any(), models.ModelID("user")).Return(nil),
+					m.issuer.EXPECT().Issue(gomock.Any(), models.ModelID("user"), gomock.Any()).Return(issued, nil),
+				)
+			},
+		},
+		{
+			name: "positive test #2, first login provisions a user",
+			prepare: func(m serviceMocks) {
+				m.callback(login, subjectClaims("alice"))
+				gomock.InOrder(
+					m.repo.EXPECT().FindUserBySubject(gomock.Any(), testIssuer, "subject-1").Return(models.ModelID(""), apperrors.ErrNotFound),
+					m.repo.EXPECT().IsLoginTaken(gomock.Any(), "alice").Return(false, nil),
+					m.repo.EXPECT().Provision(gomock.Any(), gomock.Any(), &models.Identity{
+						Issuer:  testIssuer,
+						Subject: "subject-1",
+						Email:   "alice@example.com",
+					}).DoAndReturn(func(_ context.Context, user *models.User, _ *models.Identity) (models.ModelID, error) {
+						assert.Equal(t, "alice", user.Login)
+						assert.Empty(t, user.Password)
+						return "user", nil
+					}),
+					m.twoFactor.EXPECT().Challenge(gomock.Any(), models.ModelID("user")).Return(nil),
+					m.issuer.EXPECT().Issue(gomock.Any(), models.ModelID("user"), gomock.Any()).Return(issued, nil),
+				)
+			},
+		},
+		{
+			name: "positive test #3, an existing login is not taken over",
+			prepare: func(m serviceMocks) {
+				m.callback(login, subjectClaims("alice"))
+				m.repo.EXPECT().FindUserBySubject(gomock.Any(), testIssuer, "subject-1").Return(models.ModelID(""), apperrors.ErrNotFound)
+				gomock.InOrder(
+					m.repo.EXPECT().IsLoginTaken(gomock.Any(), "alice").Return(true, nil),
+					m.repo.EXPECT().IsLoginTaken(gomock.Any(), gomock.Any()).Return(false, nil),
+				)
+				m.repo.EXPECT().Provision(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, user *models.User, _ *models.Identity) (models.ModelID, error) {
+						assert.Regexp(t, regexp.MustCompile(`^alice-[a-z0-9]{6}$`), user.Login)
+						return "user", nil
+					})
+				m.twoFactor.EXPECT().Challenge(gomock.Any(), models.ModelID("user")).Return(nil)
+				m.issuer.EXPECT().Issue(gomock.Any(), models.ModelID("user"), gomock.Any()).Return(issued, nil)
+			},
+		},
+		{
+			name: "positive test #4, link to the logged in user",
+			prepare: func(m serviceMocks) {
+				m.callback(link, subjectClaims("alice"))
+				gomock.InOrder(
+					m.repo.EXPECT().Link(gomock.Any(), &models.Identity{
+						UserID:  "user",
+						Issuer:  testIssuer,
+						Subject: "subject-1",
+						Email:   "alice@example.com",
+					}).Return(nil),
+					m.twoFactor.EXPECT().Challenge(gomock.Any(), models.ModelID("user")).Return(nil),
+					m.issuer.EXPECT().Issue(gomock.Any(), models.ModelID("user"), gomock.Any()).Return(issued, nil),
+				)
+			},
+		},
+		{
+			name: "negative test #5, subject linked to another user",
+			prepare: func(m serviceMocks) {
+				m.callback(link, subjectClaims("alice"))
+				m.repo.EXPECT().Link(gomock.Any(), gomock.Any()).Return(apperrors.ErrIsExist)
+			},
+			want: apperrors.ErrIsExist,
+		},
+		{
+			name: "negative test #6, unknown, replayed or expired state",
+			prepare: func(m serviceMocks) {
+				m.repo.EXPECT().ConsumeState(gomock.Any(), utils.HashSecret("state")).Return(nil, apperrors.ErrInvalidToken)
+			},
+			want: apperrors.ErrInvalidToken,
+		},
+		{
+			name: "negative test #7, rejected ID token",
+			prepare: func(m serviceMocks) {
+				m.repo.EXPECT().ConsumeState(gomock.Any(), utils.HashSecret("state")).Return(login, nil)
+				m.provider.EXPECT().Exchange(gomock.Any(), "code", "verifier").Return("raw", nil)
+				m.provider.EXPECT().Verify(gomock.Any(), "raw", "nonce").Return(nil, fmt.Errorf("%w: nonce", apperrors.ErrInvalidToken))
+			},
+			want: apperrors.ErrInvalidToken,
+		},
+		{
+			name: "negative test #8, 2FA challenge instead of tokens",
+			prepare: func(m serviceMocks) {
+				m.callback(login, subjectClaims("alice"))
+				m.repo.EXPECT().FindUserBySubject(gomock.Any(), testIssuer, "subject-1").Return(models.ModelID("user"), nil)
+				m.twoFactor.EXPECT().Challenge(gomock.Any(), models.ModelID("user")).Return(&apperrors.ChallengeError{Token: "challenge"})
+			},
+			want: apperrors.ErrTwoFactorRequired,
+		},
+		{
+			name: "negative test #9, storage error",
+			prepare: func(m serviceMocks) {
+				m.callback(login, subjectClaims("alice"))
+				m.repo.EXPECT().FindUserBySubject(gomock.Any(), testIssuer, "subject-1").Return(models.ModelID(""), errDB)
+			},
+			want: errDB,
+		},
+		{
+			name: "positive test #10, concurrent first login of the subject won",
+			prepare: func(m serviceMocks) {
+				m.callback(login, subjectClaims("alice"))
+				gomock.InOrder(
+					m.repo.EXPECT().FindUserBySubject(gomock.Any(), testIssuer, "subject-1").Return(models.ModelID(""), apperrors.ErrNotFound),
+					m.repo.EXPECT().IsLoginTaken(gomock.Any(), "alice").Return(false, nil),
+					m.repo.EXPECT().Provision(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.ModelID(""), apperrors.ErrIsExist),
+					m.repo.EXPECT().FindUserBySubject(gomock.Any(), testIssuer, "subject-1").Return(models.ModelID("user"), nil),
+					m.twoFactor.EXPECT().Challenge(gomock.Any(), models.ModelID("user")).Return(nil),
+					m.issuer.EXPECT().Issue(gomock.Any(), models.ModelID("user"), gomock.Any()).Return(issued, nil),
+				)
+			},
+		},
+		{
+			name: "positive test #11, login taken meanwhile",
+			prepare: func(m serviceMocks) {
+				m.callback(login, subjectClaims("alice"))
+				gomock.InOrder(
+					m.repo.EXPECT().FindUserBySubject(gomock.Any(), testIssuer, "subject-1").Return(models.ModelID(""), apperrors.ErrNotFound),
+					m.repo.EXPECT().IsLoginTaken(gomock.Any(), "alice").Return(false, nil),
+					m.repo.EXPECT().Provision(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.ModelID(""), apperrors.ErrIsExist),
+					m.repo.EXPECT().FindUserBySubject(gomock.Any(), testIssuer, "subject-1").Return(models.ModelID(""), apperrors.ErrNotFound),
+					m.repo.EXPECT().IsLoginTaken(gomock.Any(), "alice").Return(true, nil),
+					m.repo.EXPECT().IsLoginTaken(gomock.Any(), gomock.Any()).Return(false, nil),
+					m.repo.EXPECT().Provision(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.ModelID("user"), nil),
+					m.twoFactor.EXPECT().Challenge(gomock.Any(), models.ModelID("user")).Return(nil),
+					m.issuer.EXPECT().Issue(gomock.Any(), models.ModelID("user"), gomock.Any()).Return(issued, nil),
+				)
+			},
+		},
+		{
+			name: "negative test #12, every provisioning attempt lost",
+			prepare: func(m serviceMocks) {
+				m.callback(login, subjectClaims("alice"))
+				m.repo.EXPECT().FindUserBySubject(gomock.Any(), testIssuer, "subject-1").Return(models.ModelID(""), apperrors.ErrNotFound).Times(provisionAttempts)
+				m.repo.EXPECT().IsLoginTaken(gomock.Any(), gomock.Any()).Return(false, nil).Times(provisionAttempts)
+				m.repo.EXPECT().Provision(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.ModelID(""), apperrors.ErrIsExist).Times(provisionAttempts)
+			},
+			want: apperrors.ErrIsExist,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newTestService(t, ctrl)
+			tt.prepare(m)
+
+			got, err := s.Complete(context.Background(), "code", "state", appdto.Client{})
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+				assert.Empty(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, issued, got)
+		})
+	}
+}
+
+func TestService_CompleteReauth(t *testing.T) {
+	reauth := &models.OIDCState{Nonce: "nonce", CodeVerifier: "verifier", LinkUserID: sql.NullString{String: "user", Valid: true}, Reauth: true}
+
+	tests := []struct {
+		name    string
+		prepare func(m serviceMocks)
+		want    error
+	}{
+		{
+			name: "positive test #1, fresh login recorded, no tokens issued",
+			prepare: func(m serviceMocks) {
+				m.callback(reauth, subjectClaims("alice"))
+				m.repo.EXPECT().FindUserBySubject(gomock.Any(), testIssuer, "subject-1").Return(models.ModelID("user"), nil)
+				m.repo.EXPECT().SaveReauth(gomock.Any(), models.ModelID("user"), gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ models.ModelID, expiresAt time.Time) error {
+						assert.WithinDuration(t, time.Now().Add(testConf.ReauthTTL), expiresAt, time.Second)
+						return nil
+					})
+			},
+		},
+		{
+			name: "negative test #2, subject of another user",
+			prepare: func(m serviceMocks) {
+				m.callback(reauth, subjectClaims("alice"))
+				m.repo.EXPECT().FindUserBySubject(gomock.Any(), testIssuer, "subject-1").Return(models.ModelID("other"), nil)
+			},
+			want: apperrors.ErrInvalidCredentials,
+		},
+		{
+			name: "negative test #3, subject not linked, nobody is provisioned",
+			prepare: func(m serviceMocks) {
+				m.callback(reauth, subjectClaims("alice"))
+				m.repo.EXPECT().FindUserBySubject(gomock.Any(), testIssuer, "subject-1").Return(models.ModelID(""), apperrors.ErrNotFound)
+			},
+			want: apperrors.ErrInvalidCredentials,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newTestService(t, ctrl)
+			tt.prepare(m)
+
+			got, err := s.Complete(context.Background(), "code", "state", appdto.Client{})
+			assert.ErrorIs(t, err, tt.want)
+			assert.Empty(t, got)
+		})
+	}
+}
+
+func TestService_Unlink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, m := newTestService(t, ctrl)
+	m.repo.EXPECT().Unlink(gomock.Any(), models.ModelID("user"), testIssuer).Return(apperrors.ErrNoRequiredValue)
+
+	assert.ErrorIs(t, s.Unlink(context.Background(), "user"), apperrors.ErrNoRequiredValue)
+}
@@ -0,0 +1,160 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockorderRepository is a mock of orderRepository interface.
+type MockorderRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockorderRepositoryMockRecorder
+}
+
+// MockorderRepositoryMockRecorder is the mock recorder for MockorderRepository.
+type MockorderRepositoryMockRecorder struct {
+	mock *MockorderRepository
+}
+
+// NewMockorderRepository creates a new mock instance.
+func NewMockorderRepository(ctrl *gomock.Controller) *MockorderRepository {
+	mock := &MockorderRepository{ctrl: ctrl}
+	mock.recorder = &MockorderRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockorderRepository) EXPECT() *MockorderRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindByUserID mocks base method.
+func (m *MockorderRepository) FindByUserID(ctx context.Context, userID models.ModelID) ([]*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockorderRepositoryMockRecorder) FindByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockorderRepository)(nil).FindByUserID), ctx, userID)
+}
+
+// FindOneByNumber mocks base method.
+func (m *MockorderRepository) FindOneByNumber(ctx context.Context, number string) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOneByNumber", ctx, number)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOneByNumber indicates an expected call of FindOneByNumber.
+func (mr *MockorderRepositoryMockRecorder) FindOneByNumber(ctx, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOneByNumber", reflect.TypeOf((*MockorderRepository)(nil).FindOneByNumber), ctx, number)
+}
+
+// FindProcessedSince mocks base method.
+func (m *MockorderRepository) FindProcessedSince(ctx context.Context, window time.Duration) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindProcessedSince", ctx, window)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindProcessedSince indicates an expected call of FindProcessedSince.
+func (mr *MockorderRepositoryMockRecorder) FindProcessedSince(ctx, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindProcessedSince", reflect.TypeOf((*MockorderRepository)(nil).FindProcessedSince), ctx, window)
+}
+
+// ReviseAccrual mocks base method.
+func (m *MockorderRepository) ReviseAccrual(ctx context.Context, order *models.Order, accrual float64) (*models.AccrualRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReviseAccrual", ctx, order, accrual)
+	ret0, _ := ret[0].(*models.AccrualRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReviseAccrual indicates an expected call of ReviseAccrual.
+func (mr *MockorderRepositoryMockRecorder) ReviseAccrual(ctx, order, accrual interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReviseAccrual", reflect.TypeOf((*MockorderRepository)(nil).ReviseAccrual), ctx, order, accrual)
+}
+
+// Save mocks base method.
+func (m_2 *MockorderRepository) Save(ctx context.Context, m *models.Order) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Save", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockorderRepositoryMockRecorder) Save(ctx, m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockorderRepository)(nil).Save), ctx, m)
+}
+
+// UpdateByNumber mocks base method.
+func (m *MockorderRepository) UpdateByNumber(ctx context.Context, order *models.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateByNumber", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateByNumber indicates an expected call of UpdateByNumber.
+func (mr *MockorderRepositoryMockRecorder) UpdateByNumber(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateByNumber", reflect.TypeOf((*MockorderRepository)(nil).UpdateByNumber), ctx, order)
+}
+
+// MockworkerService is a mock of workerService interface.
+type MockworkerService struct {
+	ctrl     *gomock.Controller
+	recorder *MockworkerServiceMockRecorder
+}
+
+// MockworkerServiceMockRecorder is the mock recorder for MockworkerService.
+type MockworkerServiceMockRecorder struct {
+	mock *MockworkerService
+}
+
+// NewMockworkerService creates a new mock instance.
+func NewMockworkerService(ctrl *gomock.Controller) *MockworkerService {
+	mock := &MockworkerService{ctrl: ctrl}
+	mock.recorder = &MockworkerServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockworkerService) EXPECT() *MockworkerServiceMockRecorder {
+	return m.recorder
+}
+
+// CalculateAccrual mocks base method.
+func (m *MockworkerService) CalculateAccrual(number string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CalculateAccrual", number)
+}
+
+// CalculateAccrual indicates an expected call of CalculateAccrual.
+func (mr *MockworkerServiceMockRecorder) CalculateAccrual(number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalculateAccrual", reflect.TypeOf((*MockworkerService)(nil).CalculateAccrual), number)
+}
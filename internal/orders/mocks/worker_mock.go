@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: worker.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAccrualListener is a mock of AccrualListener interface.
+type MockAccrualListener struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccrualListenerMockRecorder
+}
+
+// MockAccrualListenerMockRecorder is the mock recorder for MockAccrualListener.
+type MockAccrualListenerMockRecorder struct {
+	mock *MockAccrualListener
+}
+
+// NewMockAccrualListener creates a new mock instance.
+func NewMockAccrualListener(ctrl *gomock.Controller) *MockAccrualListener {
+	mock := &MockAccrualListener{ctrl: ctrl}
+	mock.recorder = &MockAccrualListenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccrualListener) EXPECT() *MockAccrualListenerMockRecorder {
+	return m.recorder
+}
+
+// OnOrderProcessed mocks base method.
+func (m *MockAccrualListener) OnOrderProcessed(ctx context.Context, order *models.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnOrderProcessed", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OnOrderProcessed indicates an expected call of OnOrderProcessed.
+func (mr *MockAccrualListenerMockRecorder) OnOrderProcessed(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnOrderProcessed", reflect.TypeOf((*MockAccrualListener)(nil).OnOrderProcessed), ctx, order)
+}
+
+// MockRevisionListener is a mock of RevisionListener interface.
+type MockRevisionListener struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevisionListenerMockRecorder
+}
+
+// MockRevisionListenerMockRecorder is the mock recorder for MockRevisionListener.
+type MockRevisionListenerMockRecorder struct {
+	mock *MockRevisionListener
+}
+
+// NewMockRevisionListener creates a new mock instance.
+func NewMockRevisionListener(ctrl *gomock.Controller) *MockRevisionListener {
+	mock := &MockRevisionListener{ctrl: ctrl}
+	mock.recorder = &MockRevisionListenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevisionListener) EXPECT() *MockRevisionListenerMockRecorder {
+	return m.recorder
+}
+
+// OnAccrualRevised mocks base method.
+func (m *MockRevisionListener) OnAccrualRevised(ctx context.Context, order *models.Order, rev *models.AccrualRevision) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnAccrualRevised", ctx, order, rev)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OnAccrualRevised indicates an expected call of OnAccrualRevised.
+func (mr *MockRevisionListenerMockRecorder) OnAccrualRevised(ctx, order, rev interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnAccrualRevised", reflect.TypeOf((*MockRevisionListener)(nil).OnAccrualRevised), ctx, order, rev)
+}
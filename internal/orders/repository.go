@@ -3,6 +3,9 @@ package orders
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"math"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -42,7 +45,7 @@ func (r *Repository) Save(ctx context.Context, order *models.Order) error {
 func (r *Repository) UpdateByNumber(ctx context.Context, order *models.Order) error {
 	query := `
 		UPDATE orders
-		SET status = $1, accrual = $2
+		SET status = $1, accrual = $2, updated_at = NOW()
 		WHERE number = $3
 	`
 	_, err := r.db.ExecContext(ctx, query, order.Status, order.Accrual, order.Number)
@@ -55,7 +58,7 @@ func (r *Repository) UpdateByNumber(ctx context.Context, order *models.Order) er
 
 func (r *Repository) FindOneByNumber(ctx context.Context, number string) (*models.Order, error) {
 	query := `
-		SELECT id, user_id, number, status, created_at, updated_at
+		SELECT id, user_id, number, accrual, status, created_at, updated_at
 		FROM orders
 		WHERE number = $1
 		LIMIT 1
@@ -66,6 +69,7 @@ func (r *Repository) FindOneByNumber(ctx context.Context, number string) (*model
 		&order.ID,
 		&order.UserID,
 		&order.Number,
+		&order.Accrual,
 		&order.Status,
 		&order.CreatedAt,
 		&order.UpdatedAt,
@@ -82,7 +86,14 @@ func (r *Repository) FindOneByNumber(ctx context.Context, number string) (*model
 
 func (r *Repository) FindByUserID(ctx context.Context, userID models.ModelID) ([]*models.Order, error) {
 	query := `
-			SELECT id, user_id, number, accrual, status, created_at, updated_at 
+			SELECT id, user_id, number,
+				COALESCE((
+					SELECT new_accrual FROM accrual_revisions ar
+					WHERE ar.order_id = orders.id
+					ORDER BY ar.created_at DESC
+					LIMIT 1
+				), accrual),
+				status, created_at, updated_at 
 			FROM orders
 			WHERE user_id = $1
 			ORDER BY created_at 
@@ -128,3 +139,97 @@ func (r *Repository) FindSumOfAccruals(ctx context.Context, userID models.ModelI
 
 	return totalAccrual, nil
 }
+
+func (r *Repository) FindProcessedSince(ctx context.Context, window time.Duration) ([]string, error) {
+	query := `
+		SELECT number
+		FROM orders
+		WHERE status = $1 AND updated_at >= NOW() - $2 * INTERVAL '1 second'
+	`
+	rows, err := r.db.QueryContext(ctx, query, models.OrderProcessed, window.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var numbers []string
+	for rows.Next() {
+		var number string
+		if err := rows.Scan(&number); err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, number)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return numbers, nil
+}
+
+// ReviseAccrual posts the difference between the current and the new accrual
+// of a PROCESSED order to the ledger. The order row is locked so concurrent
+// pollers cannot post one revision twice. It returns nil when the accrual
+// has not changed.
+func (r *Repository) ReviseAccrual(ctx context.Context, order *models.Order, accrual float64) (*models.AccrualRevision, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rev := &models.AccrualRevision{
+		OrderID:    order.ID,
+		NewAccrual: accrual,
+	}
+
+	currentQuery := `
+		SELECT COALESCE((
+			SELECT new_accrual FROM accrual_revisions
+			WHERE order_id = o.id
+			ORDER BY created_at DESC
+			LIMIT 1
+		), o.accrual, 0)
+		FROM orders o
+		WHERE o.id = $1
+		FOR UPDATE
+	`
+	if err = tx.QueryRowContext(ctx, currentQuery, order.ID).Scan(&rev.PreviousAccrual); err != nil {
+		return nil, err
+	}
+
+	if math.Abs(rev.Delta()) < 0.005 {
+		return nil, nil
+	}
+
+	revisionQuery := `
+		INSERT INTO accrual_revisions (order_id, previous_accrual, new_accrual)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRowContext(ctx, revisionQuery, rev.OrderID, rev.PreviousAccrual, rev.NewAccrual).
+		Scan(&rev.ID, &rev.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	entryQuery := `
+		INSERT INTO balance_entries (user_id, kind, amount, reference, comment)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err = tx.ExecContext(
+		ctx,
+		entryQuery,
+		order.UserID,
+		models.EntryRevision,
+		rev.Delta(),
+		order.Number,
+		fmt.Sprintf("accrual revised from %.2f to %.2f", rev.PreviousAccrual, rev.NewAccrual),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return rev, tx.Commit()
+}
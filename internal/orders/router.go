@@ -2,10 +2,13 @@ package orders
 
 import (
 	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
 )
 
 type UserMiddleware interface {
 	Auth(c *fiber.Ctx) error
+	AuthOrKey(scope string) fiber.Handler
 }
 
 func SetupRouter(
@@ -13,14 +16,15 @@ func SetupRouter(
 	accrualAddr string,
 	middleware UserMiddleware,
 	orderRepository orderRepository,
+	listeners ...AccrualListener,
 ) {
 	group := r.Group("/orders")
 
-	wr := newWorker(accrualAddr, orderRepository)
+	wr := newWorker(accrualAddr, orderRepository, listeners)
 	service := NewService(wr, orderRepository)
 	handle := newHandler(service)
 
-	group.Post("/", middleware.Auth, handle.create)
+	group.Post("/", middleware.AuthOrKey(models.ScopeOrdersWrite), handle.create)
 	group.Get("/", middleware.Auth, handle.getAllOrders)
 
 }
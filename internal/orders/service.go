@@ -3,6 +3,7 @@ package orders
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
 	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
@@ -16,6 +17,8 @@ type (
 		FindOneByNumber(ctx context.Context, number string) (*models.Order, error)
 		FindByUserID(ctx context.Context, userID models.ModelID) ([]*models.Order, error)
 		UpdateByNumber(ctx context.Context, order *models.Order) error
+		FindProcessedSince(ctx context.Context, window time.Duration) ([]string, error)
+		ReviseAccrual(ctx context.Context, order *models.Order, accrual float64) (*models.AccrualRevision, error)
 	}
 
 	workerService interface {
@@ -2,30 +2,50 @@ package orders
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-resty/resty/v2"
 
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
 	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
 	"github.com/dkmelnik/go-musthave-diploma/internal/models"
 	"github.com/dkmelnik/go-musthave-diploma/internal/orders/dto"
 )
 
-type worker struct {
-	interval        time.Duration
-	attempts        int
-	accrualAddr     string
-	orderRepository orderRepository
-}
+// defaultRetryAfter is the pause after a 429 of the accrual system without a
+// usable Retry-After.
+const defaultRetryAfter = time.Minute
 
-func newWorker(accrualAddr string, or orderRepository) *worker {
+type (
+	// AccrualListener is notified once an order becomes PROCESSED.
+	AccrualListener interface {
+		OnOrderProcessed(ctx context.Context, order *models.Order) error
+	}
+	// RevisionListener is an AccrualListener that also follows accrual
+	// revisions of PROCESSED orders.
+	RevisionListener interface {
+		OnAccrualRevised(ctx context.Context, order *models.Order, rev *models.AccrualRevision) error
+	}
+	worker struct {
+		interval        time.Duration
+		attempts        int
+		accrualAddr     string
+		orderRepository orderRepository
+		listeners       []AccrualListener
+	}
+)
+
+func newWorker(accrualAddr string, or orderRepository, listeners []AccrualListener) *worker {
 	return &worker{
 		interval:        300 * time.Millisecond,
 		attempts:        15,
 		accrualAddr:     accrualAddr,
 		orderRepository: or,
+		listeners:       listeners,
 	}
 }
 
@@ -56,9 +76,22 @@ func (s *worker) CalculateAccrual(number string) {
 					ctx.Done()
 					return
 				}
-				if err := s.processAccrualRequest(ctx, client, number); err != nil {
+				done, err := s.processAccrualRequest(ctx, client, number)
+				var retry *apperrors.RetryError
+				if errors.As(err, &retry) {
+					// throttled polls are not attempts
+					logger.Log.Warn("calculateAccrualForOrder", "number", number, "retry after", retry.RetryAfter)
+					if !sleep(ctx, retry.RetryAfter) {
+						return
+					}
+					continue
+				}
+				if err != nil {
 					logger.Log.Info("calculateAccrualForOrder", "error", err)
 				}
+				if done {
+					return
+				}
 				currentAttempts++
 			case <-ctx.Done():
 				logger.Log.Info("calculateAccrualForOrder", "context", "cancelled")
@@ -68,7 +101,65 @@ func (s *worker) CalculateAccrual(number string) {
 	}()
 }
 
-func (s *worker) processAccrualRequest(ctx context.Context, client *resty.Client, number string) error {
+// WatchRevisions re-polls orders processed within window every interval, so
+// accruals re-calculated by the accrual system are picked up as revisions.
+// A 429 of the accrual system pauses it for Retry-After. It runs in the
+// background until ctx is done.
+func WatchRevisions(
+	ctx context.Context,
+	accrualAddr string,
+	window, interval time.Duration,
+	or orderRepository,
+	listeners ...AccrualListener,
+) {
+	if window <= 0 || interval <= 0 {
+		return
+	}
+
+	go newWorker(accrualAddr, or, listeners).watchRevisions(ctx, window, interval)
+}
+
+func (s *worker) watchRevisions(ctx context.Context, window, interval time.Duration) {
+	client := resty.New()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Log.Info("watchRevisions", "context", "cancelled")
+			return
+		case <-ticker.C:
+		}
+
+		numbers, err := s.orderRepository.FindProcessedSince(ctx, window)
+		if err != nil {
+			logger.Log.Error("watchRevisions", "FindProcessedSince", err)
+			continue
+		}
+		for _, number := range numbers {
+			_, err := s.processAccrualRequest(ctx, client, number)
+			var retry *apperrors.RetryError
+			if errors.As(err, &retry) {
+				logger.Log.Warn("watchRevisions", "number", number, "retry after", retry.RetryAfter)
+				if !sleep(ctx, retry.RetryAfter) {
+					logger.Log.Info("watchRevisions", "context", "cancelled")
+					return
+				}
+				continue
+			}
+			if err != nil {
+				logger.Log.Info("watchRevisions", "number", number, "error", err)
+			}
+		}
+	}
+}
+
+// processAccrualRequest polls the accrual system once and reports whether
+// the order has reached a final status. A 429 yields an
+// *apperrors.RetryError with the delay the accrual system asked for.
+func (s *worker) processAccrualRequest(ctx context.Context, client *resty.Client, number string) (bool, error) {
 	accrualRes := dto.Accrual{}
 	resp, err := client.R().
 		SetContext(ctx).
@@ -78,17 +169,39 @@ func (s *worker) processAccrualRequest(ctx context.Context, client *resty.Client
 	logger.Log.Info("-------------processAccrualRequest", "accrualRes", accrualRes)
 
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	switch resp.StatusCode() {
 	case http.StatusOK:
 		if accrualRes.Status == string(models.OrderRegistered) {
-			return nil
+			return false, nil
+		}
+
+		existing, err := s.orderRepository.FindOneByNumber(ctx, number)
+		if err != nil {
+			return false, err
+		}
+		if existing.Status == models.OrderProcessed {
+			if accrualRes.Status == string(models.OrderProcessed) && accrualRes.Accrual != nil {
+				rev, err := s.orderRepository.ReviseAccrual(ctx, existing, *accrualRes.Accrual)
+				if err != nil {
+					return true, err
+				}
+				if rev != nil {
+					logger.Log.Info("processAccrualRequest", "number", number,
+						"revised from", rev.PreviousAccrual, "revised to", rev.NewAccrual)
+					s.notifyRevised(ctx, existing, rev)
+				}
+			}
+			return true, nil
 		}
 
 		order := &models.Order{
-			Number: number,
+			ID:        existing.ID,
+			UserID:    existing.UserID,
+			Number:    number,
+			CreatedAt: existing.CreatedAt,
 		}
 		if accrualRes.Status == string(models.OrderProcessed) {
 			order.SetAccrual(accrualRes.Accrual)
@@ -98,16 +211,72 @@ func (s *worker) processAccrualRequest(ctx context.Context, client *resty.Client
 
 		if err := s.orderRepository.UpdateByNumber(ctx, order); err != nil {
 			logger.Log.Info("processAccrualRequest", "order", order)
-			return err
+			return false, err
+		}
+
+		if order.Status == models.OrderProcessed {
+			s.notifyProcessed(ctx, order)
 		}
+
+		return order.Status == models.OrderProcessed || order.Status == models.OrderInvalid, nil
 	case http.StatusNoContent:
 		logger.Log.Warn("calculateAccrualForOrder", "http.Status", http.StatusNoContent, "resp", resp.String())
-		ctx.Done()
-		return nil
+		return false, nil
+	case http.StatusTooManyRequests:
+		return false, &apperrors.RetryError{
+			Err:        apperrors.ErrTooManyAttempts,
+			RetryAfter: retryAfter(resp.Header().Get("Retry-After")),
+		}
 	case http.StatusInternalServerError:
 		logger.Log.Warn("calculateAccrualForOrder", "http.Status", http.StatusInternalServerError, "resp", resp.String())
-		ctx.Done()
-		return nil
+		return false, nil
+	}
+	return false, nil
+}
+
+// retryAfter reads a Retry-After header given in seconds or as a date.
+func retryAfter(header string) time.Duration {
+	if secs, err := strconv.Atoi(header); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return defaultRetryAfter
+}
+
+// sleep waits for d and reports false when ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (s *worker) notifyProcessed(ctx context.Context, order *models.Order) {
+	for _, l := range s.listeners {
+		if err := l.OnOrderProcessed(ctx, order); err != nil {
+			logger.Log.Error("notifyProcessed", "number", order.Number, "error", err)
+		}
+	}
+}
+
+func (s *worker) notifyRevised(ctx context.Context, order *models.Order, rev *models.AccrualRevision) {
+	for _, l := range s.listeners {
+		rl, ok := l.(RevisionListener)
+		if !ok {
+			continue
+		}
+		if err := rl.OnAccrualRevised(ctx, order, rev); err != nil {
+			logger.Log.Error("notifyRevised", "number", order.Number, "error", err)
+		}
 	}
-	return nil
 }
@@ -0,0 +1,176 @@
+package orders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/orders/mocks"
+)
+
+// listener follows both processed orders and revisions, like tiers.Service.
+type listener struct {
+	*mocks.MockAccrualListener
+	*mocks.MockRevisionListener
+}
+
+func accrualServer(t *testing.T, body string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestWorker_revision(t *testing.T) {
+	processed := &models.Order{ID: "order", UserID: "user", Number: "12345678903", Status: models.OrderProcessed}
+
+	tests := []struct {
+		name   string
+		body   string
+		rev    *models.AccrualRevision
+		revise bool
+	}{
+		{
+			name:   "positive test #1, changed accrual is revised and announced",
+			body:   `{"order":"12345678903","status":"PROCESSED","accrual":60}`,
+			rev:    &models.AccrualRevision{OrderID: "order", PreviousAccrual: 100, NewAccrual: 60},
+			revise: true,
+		},
+		{
+			name:   "positive test #2, unchanged accrual is not announced",
+			body:   `{"order":"12345678903","status":"PROCESSED","accrual":100}`,
+			revise: true,
+		},
+		{
+			name: "positive test #3, accrual system has no accrual",
+			body: `{"order":"12345678903","status":"INVALID"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			or := mocks.NewMockorderRepository(ctrl)
+			or.EXPECT().FindOneByNumber(gomock.Any(), "12345678903").Return(processed, nil)
+			if tt.revise {
+				or.EXPECT().ReviseAccrual(gomock.Any(), processed, gomock.Any()).Return(tt.rev, nil)
+			}
+
+			l := listener{mocks.NewMockAccrualListener(ctrl), mocks.NewMockRevisionListener(ctrl)}
+			if tt.rev != nil {
+				l.MockRevisionListener.EXPECT().OnAccrualRevised(gomock.Any(), processed, tt.rev).Return(nil)
+			}
+
+			w := newWorker(accrualServer(t, tt.body).URL, or, []AccrualListener{l})
+			done, err := w.processAccrualRequest(context.Background(), resty.New(), "12345678903")
+
+			assert.NoError(t, err)
+			assert.True(t, done)
+		})
+	}
+}
+
+func TestWorker_watchRevisionsStops(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	or := mocks.NewMockorderRepository(ctrl)
+	or.EXPECT().FindProcessedSince(gomock.Any(), time.Hour).Return(nil, nil).AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		newWorker("", or, nil).watchRevisions(ctx, time.Hour, time.Millisecond)
+		close(stopped)
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("watchRevisions did not stop after the context was cancelled")
+	}
+}
+
+func TestWorker_throttled(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		want       time.Duration
+	}{
+		{name: "positive test #1, delay in seconds", retryAfter: "2", want: 2 * time.Second},
+		{name: "positive test #2, no delay given", want: defaultRetryAfter},
+		{name: "positive test #3, delay as a past date", retryAfter: "Mon, 01 Jan 2024 00:00:00 GMT", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(http.StatusTooManyRequests)
+			}))
+			defer srv.Close()
+
+			w := newWorker(srv.URL, mocks.NewMockorderRepository(ctrl), nil)
+			done, err := w.processAccrualRequest(context.Background(), resty.New(), "12345678903")
+
+			var retry *apperrors.RetryError
+			require.ErrorAs(t, err, &retry)
+			assert.ErrorIs(t, err, apperrors.ErrTooManyAttempts)
+			assert.Equal(t, tt.want, retry.RetryAfter)
+			assert.False(t, done)
+		})
+	}
+}
+
+func TestWorker_watchRevisionsThrottled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	or := mocks.NewMockorderRepository(ctrl)
+	or.EXPECT().FindProcessedSince(gomock.Any(), time.Hour).Return([]string{"12345678903", "79927398713"}, nil).AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		newWorker(srv.URL, or, nil).watchRevisions(ctx, time.Hour, time.Millisecond)
+		close(stopped)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(1), requests.Load(), "polling pauses for Retry-After")
+	cancel()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("watchRevisions did not stop while paused")
+	}
+}
@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/credentials"
+)
+
+type (
+	ChangePayload struct {
+		OldPassword string `json:"old_password"`
+		NewPassword string `json:"new_password"`
+	}
+	ResetRequestPayload struct {
+		Login string `json:"login"`
+	}
+	ResetConfirmPayload struct {
+		Token       string `json:"token"`
+		NewPassword string `json:"new_password"`
+	}
+)
+
+// Validate only requires new_password, the service checks it against the
+// policy once it knows the login. old_password is checked by the service too,
+// accounts without a password leave it empty.
+func (r *ChangePayload) Validate() error {
+	v := &apperrors.ValidationError{}
+	if r.NewPassword == "" {
+		v.Add("new_password", credentials.CodeRequired, "new_password is required")
+	}
+
+	return v.OrNil()
+}
+
+func (r *ResetRequestPayload) Validate() error {
+	v := &apperrors.ValidationError{}
+	if strings.TrimSpace(r.Login) == "" {
+		v.Add("login", credentials.CodeRequired, "login is required")
+	}
+
+	return v.OrNil()
+}
+
+func (r *ResetConfirmPayload) Validate() error {
+	v := &apperrors.ValidationError{}
+	if strings.TrimSpace(r.Token) == "" {
+		v.Add("token", credentials.CodeRequired, "token is required")
+	}
+	if r.NewPassword == "" {
+		v.Add("new_password", credentials.CodeRequired, "new_password is required")
+	}
+
+	return v.OrNil()
+}
@@ -0,0 +1,99 @@
+package passwords
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/passwords/dto"
+)
+
+type (
+	passwordService interface {
+		Change(ctx context.Context, userID models.ModelID, currentJTI string, d dto.ChangePayload) error
+		RequestReset(ctx context.Context, d dto.ResetRequestPayload) error
+		ConfirmReset(ctx context.Context, d dto.ResetConfirmPayload) error
+	}
+	handler struct {
+		service passwordService
+	}
+)
+
+func newHandler(ps passwordService) *handler {
+	return &handler{ps}
+}
+
+func (h *handler) change(c *fiber.Ctx) error {
+	claims, ok := c.Locals("token").(*appdto.Claims)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+	}
+
+	var body dto.ChangePayload
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	}
+	if err := body.Validate(); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(err)
+	}
+
+	var verr *apperrors.ValidationError
+	switch err := h.service.Change(c.Context(), models.ModelID(claims.SUB), claims.JTI, body); {
+	case err == nil:
+		return c.SendStatus(fiber.StatusOK)
+	case errors.Is(err, apperrors.ErrInvalidCredentials):
+		return c.Status(fiber.StatusForbidden).SendString(http.StatusText(fiber.StatusForbidden))
+	case errors.As(err, &verr):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(verr)
+	default:
+		logger.Log.Error("passwords:handler:change", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+}
+
+// requestReset answers 202 whether or not the login exists.
+func (h *handler) requestReset(c *fiber.Ctx) error {
+	var body dto.ResetRequestPayload
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	}
+	if err := body.Validate(); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(err)
+	}
+
+	if err := h.service.RequestReset(c.Context(), body); err != nil {
+		logger.Log.Error("passwords:handler:requestReset", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+
+	return c.SendStatus(fiber.StatusAccepted)
+}
+
+func (h *handler) confirmReset(c *fiber.Ctx) error {
+	var body dto.ResetConfirmPayload
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	}
+	if err := body.Validate(); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(err)
+	}
+
+	var verr *apperrors.ValidationError
+	switch err := h.service.ConfirmReset(c.Context(), body); {
+	case err == nil:
+		return c.SendStatus(fiber.StatusOK)
+	case errors.Is(err, apperrors.ErrInvalidToken):
+		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+	case errors.As(err, &verr):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(verr)
+	default:
+		logger.Log.Error("passwords:handler:confirmReset", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+}
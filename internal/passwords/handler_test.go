@@ -0,0 +1,142 @@
+package passwords
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/passwords/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/passwords/mocks"
+)
+
+func Test_requestReset(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		service bool
+		err     error
+		code    int
+	}{
+		{
+			name:    "positive test #1, reset requested",
+			body:    `{"login":"alice"}`,
+			service: true,
+			code:    http.StatusAccepted,
+		},
+		{
+			name: "negative test #2, no login",
+			body: `{"login":" "}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "negative test #3, unknown service error",
+			body:    `{"login":"alice"}`,
+			service: true,
+			err:     errors.New("db is down"),
+			code:    http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ps := mocks.NewMockpasswordService(ctrl)
+			if tt.service {
+				ps.EXPECT().RequestReset(gomock.Any(), dto.ResetRequestPayload{Login: "alice"}).Return(tt.err)
+			}
+
+			app := fiber.New()
+			app.Post("/", newHandler(ps).requestReset)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+
+func Test_confirmReset(t *testing.T) {
+	body := `{"token":"token","new_password":"correct horse 1"}`
+
+	tests := []struct {
+		name    string
+		body    string
+		service bool
+		err     error
+		code    int
+	}{
+		{
+			name:    "positive test #1, password reset",
+			body:    body,
+			service: true,
+			code:    http.StatusOK,
+		},
+		{
+			name: "negative test #2, no token",
+			body: `{"new_password":"correct horse 1"}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "negative test #3, invalid token",
+			body:    body,
+			service: true,
+			err:     apperrors.ErrInvalidToken,
+			code:    http.StatusUnauthorized,
+		},
+		{
+			name:    "negative test #4, password breaks the policy",
+			body:    body,
+			service: true,
+			err:     &apperrors.ValidationError{Errors: []apperrors.FieldError{{Field: "new_password"}}},
+			code:    http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "negative test #5, unknown service error",
+			body:    body,
+			service: true,
+			err:     errors.New("db is down"),
+			code:    http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ps := mocks.NewMockpasswordService(ctrl)
+			if tt.service {
+				ps.EXPECT().ConfirmReset(gomock.Any(), dto.ResetConfirmPayload{Token: "token", NewPassword: "correct horse 1"}).
+					Return(tt.err)
+			}
+
+			app := fiber.New()
+			app.Post("/", newHandler(ps).confirmReset)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	dto "github.com/dkmelnik/go-musthave-diploma/internal/passwords/dto"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockpasswordService is a mock of passwordService interface.
+type MockpasswordService struct {
+	ctrl     *gomock.Controller
+	recorder *MockpasswordServiceMockRecorder
+}
+
+// MockpasswordServiceMockRecorder is the mock recorder for MockpasswordService.
+type MockpasswordServiceMockRecorder struct {
+	mock *MockpasswordService
+}
+
+// NewMockpasswordService creates a new mock instance.
+func NewMockpasswordService(ctrl *gomock.Controller) *MockpasswordService {
+	mock := &MockpasswordService{ctrl: ctrl}
+	mock.recorder = &MockpasswordServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpasswordService) EXPECT() *MockpasswordServiceMockRecorder {
+	return m.recorder
+}
+
+// Change mocks base method.
+func (m *MockpasswordService) Change(ctx context.Context, userID models.ModelID, currentJTI string, d dto.ChangePayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Change", ctx, userID, currentJTI, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Change indicates an expected call of Change.
+func (mr *MockpasswordServiceMockRecorder) Change(ctx, userID, currentJTI, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Change", reflect.TypeOf((*MockpasswordService)(nil).Change), ctx, userID, currentJTI, d)
+}
+
+// ConfirmReset mocks base method.
+func (m *MockpasswordService) ConfirmReset(ctx context.Context, d dto.ResetConfirmPayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmReset", ctx, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmReset indicates an expected call of ConfirmReset.
+func (mr *MockpasswordServiceMockRecorder) ConfirmReset(ctx, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmReset", reflect.TypeOf((*MockpasswordService)(nil).ConfirmReset), ctx, d)
+}
+
+// RequestReset mocks base method.
+func (m *MockpasswordService) RequestReset(ctx context.Context, d dto.ResetRequestPayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestReset", ctx, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestReset indicates an expected call of RequestReset.
+func (mr *MockpasswordServiceMockRecorder) RequestReset(ctx, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestReset", reflect.TypeOf((*MockpasswordService)(nil).RequestReset), ctx, d)
+}
@@ -0,0 +1,276 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUserRepository is a mock of UserRepository interface.
+type MockUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepositoryMockRecorder
+}
+
+// MockUserRepositoryMockRecorder is the mock recorder for MockUserRepository.
+type MockUserRepositoryMockRecorder struct {
+	mock *MockUserRepository
+}
+
+// NewMockUserRepository creates a new mock instance.
+func NewMockUserRepository(ctrl *gomock.Controller) *MockUserRepository {
+	mock := &MockUserRepository{ctrl: ctrl}
+	mock.recorder = &MockUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindOneByLogin mocks base method.
+func (m *MockUserRepository) FindOneByLogin(ctx context.Context, login string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOneByLogin", ctx, login)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOneByLogin indicates an expected call of FindOneByLogin.
+func (mr *MockUserRepositoryMockRecorder) FindOneByLogin(ctx, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOneByLogin", reflect.TypeOf((*MockUserRepository)(nil).FindOneByLogin), ctx, login)
+}
+
+// MockpasswordRepository is a mock of passwordRepository interface.
+type MockpasswordRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockpasswordRepositoryMockRecorder
+}
+
+// MockpasswordRepositoryMockRecorder is the mock recorder for MockpasswordRepository.
+type MockpasswordRepositoryMockRecorder struct {
+	mock *MockpasswordRepository
+}
+
+// NewMockpasswordRepository creates a new mock instance.
+func NewMockpasswordRepository(ctrl *gomock.Controller) *MockpasswordRepository {
+	mock := &MockpasswordRepository{ctrl: ctrl}
+	mock.recorder = &MockpasswordRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpasswordRepository) EXPECT() *MockpasswordRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConsumeReset mocks base method.
+func (m *MockpasswordRepository) ConsumeReset(ctx context.Context, tokenHash, passwordHash string) (models.ModelID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeReset", ctx, tokenHash, passwordHash)
+	ret0, _ := ret[0].(models.ModelID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeReset indicates an expected call of ConsumeReset.
+func (mr *MockpasswordRepositoryMockRecorder) ConsumeReset(ctx, tokenHash, passwordHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeReset", reflect.TypeOf((*MockpasswordRepository)(nil).ConsumeReset), ctx, tokenHash, passwordHash)
+}
+
+// FindCredentials mocks base method.
+func (m *MockpasswordRepository) FindCredentials(ctx context.Context, userID models.ModelID) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCredentials", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindCredentials indicates an expected call of FindCredentials.
+func (mr *MockpasswordRepositoryMockRecorder) FindCredentials(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCredentials", reflect.TypeOf((*MockpasswordRepository)(nil).FindCredentials), ctx, userID)
+}
+
+// FindResetLogin mocks base method.
+func (m *MockpasswordRepository) FindResetLogin(ctx context.Context, tokenHash string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindResetLogin", ctx, tokenHash)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindResetLogin indicates an expected call of FindResetLogin.
+func (mr *MockpasswordRepositoryMockRecorder) FindResetLogin(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindResetLogin", reflect.TypeOf((*MockpasswordRepository)(nil).FindResetLogin), ctx, tokenHash)
+}
+
+// SaveReset mocks base method.
+func (m_2 *MockpasswordRepository) SaveReset(ctx context.Context, m *models.PasswordReset, maxPerWindow int, window time.Duration) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SaveReset", ctx, m, maxPerWindow, window)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveReset indicates an expected call of SaveReset.
+func (mr *MockpasswordRepositoryMockRecorder) SaveReset(ctx, m, maxPerWindow, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveReset", reflect.TypeOf((*MockpasswordRepository)(nil).SaveReset), ctx, m, maxPerWindow, window)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockpasswordRepository) UpdatePassword(ctx context.Context, userID models.ModelID, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, userID, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockpasswordRepositoryMockRecorder) UpdatePassword(ctx, userID, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockpasswordRepository)(nil).UpdatePassword), ctx, userID, hash)
+}
+
+// MockPasswordHasher is a mock of PasswordHasher interface.
+type MockPasswordHasher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordHasherMockRecorder
+}
+
+// MockPasswordHasherMockRecorder is the mock recorder for MockPasswordHasher.
+type MockPasswordHasherMockRecorder struct {
+	mock *MockPasswordHasher
+}
+
+// NewMockPasswordHasher creates a new mock instance.
+func NewMockPasswordHasher(ctrl *gomock.Controller) *MockPasswordHasher {
+	mock := &MockPasswordHasher{ctrl: ctrl}
+	mock.recorder = &MockPasswordHasherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordHasher) EXPECT() *MockPasswordHasherMockRecorder {
+	return m.recorder
+}
+
+// Hash mocks base method.
+func (m *MockPasswordHasher) Hash(password string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Hash", password)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Hash indicates an expected call of Hash.
+func (mr *MockPasswordHasherMockRecorder) Hash(password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Hash", reflect.TypeOf((*MockPasswordHasher)(nil).Hash), password)
+}
+
+// Verify mocks base method.
+func (m *MockPasswordHasher) Verify(hash, password string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", hash, password)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockPasswordHasherMockRecorder) Verify(hash, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockPasswordHasher)(nil).Verify), hash, password)
+}
+
+// MockSessionRevoker is a mock of SessionRevoker interface.
+type MockSessionRevoker struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionRevokerMockRecorder
+}
+
+// MockSessionRevokerMockRecorder is the mock recorder for MockSessionRevoker.
+type MockSessionRevokerMockRecorder struct {
+	mock *MockSessionRevoker
+}
+
+// NewMockSessionRevoker creates a new mock instance.
+func NewMockSessionRevoker(ctrl *gomock.Controller) *MockSessionRevoker {
+	mock := &MockSessionRevoker{ctrl: ctrl}
+	mock.recorder = &MockSessionRevokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionRevoker) EXPECT() *MockSessionRevokerMockRecorder {
+	return m.recorder
+}
+
+// RevokeSessions mocks base method.
+func (m *MockSessionRevoker) RevokeSessions(ctx context.Context, userID models.ModelID, exceptJTI string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessions", ctx, userID, exceptJTI)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSessions indicates an expected call of RevokeSessions.
+func (mr *MockSessionRevokerMockRecorder) RevokeSessions(ctx, userID, exceptJTI interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessions", reflect.TypeOf((*MockSessionRevoker)(nil).RevokeSessions), ctx, userID, exceptJTI)
+}
+
+// MockReauthenticator is a mock of Reauthenticator interface.
+type MockReauthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockReauthenticatorMockRecorder
+}
+
+// MockReauthenticatorMockRecorder is the mock recorder for MockReauthenticator.
+type MockReauthenticatorMockRecorder struct {
+	mock *MockReauthenticator
+}
+
+// NewMockReauthenticator creates a new mock instance.
+func NewMockReauthenticator(ctrl *gomock.Controller) *MockReauthenticator {
+	mock := &MockReauthenticator{ctrl: ctrl}
+	mock.recorder = &MockReauthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReauthenticator) EXPECT() *MockReauthenticatorMockRecorder {
+	return m.recorder
+}
+
+// ConsumeReauth mocks base method.
+func (m *MockReauthenticator) ConsumeReauth(ctx context.Context, userID models.ModelID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeReauth", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConsumeReauth indicates an expected call of ConsumeReauth.
+func (mr *MockReauthenticatorMockRecorder) ConsumeReauth(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeReauth", reflect.TypeOf((*MockReauthenticator)(nil).ConsumeReauth), ctx, userID)
+}
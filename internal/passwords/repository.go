@@ -0,0 +1,166 @@
+package passwords
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/ledger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+func (r *Repository) FindCredentials(ctx context.Context, userID models.ModelID) (login, hash string, err error) {
+	query := `
+		SELECT login, password FROM users WHERE id = $1
+	`
+	err = r.db.QueryRowContext(ctx, query, userID).Scan(&login, &hash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", apperrors.ErrNotFound
+		}
+		return "", "", err
+	}
+	return login, hash, nil
+}
+
+func (r *Repository) UpdatePassword(ctx context.Context, userID models.ModelID, hash string) error {
+	query := `
+		UPDATE users SET password = $2 WHERE id = $1
+	`
+	res, err := r.db.ExecContext(ctx, query, userID, hash)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// SaveReset stores a new reset token, the pending ones of the user stop
+// working. A user gets at most maxPerWindow tokens within window; the user
+// row is locked while they are counted, so concurrent requests cannot
+// overshoot the limit. Beyond it SaveReset returns
+// apperrors.ErrTooManyAttempts.
+func (r *Repository) SaveReset(ctx context.Context, m *models.PasswordReset, maxPerWindow int, window time.Duration) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = ledger.LockAccounts(ctx, tx, m.UserID); err != nil {
+		return err
+	}
+
+	var n int
+	countQuery := `
+		SELECT COUNT(*)
+		FROM password_resets
+		WHERE user_id = $1 AND created_at > NOW() - $2 * INTERVAL '1 second'
+	`
+	if err = tx.QueryRowContext(ctx, countQuery, m.UserID, window.Seconds()).Scan(&n); err != nil {
+		return err
+	}
+	if n >= maxPerWindow {
+		return apperrors.ErrTooManyAttempts
+	}
+
+	expireQuery := `
+		UPDATE password_resets SET used_at = NOW()
+		WHERE user_id = $1 AND used_at IS NULL
+	`
+	if _, err = tx.ExecContext(ctx, expireQuery, m.UserID); err != nil {
+		return err
+	}
+
+	insertQuery := `
+		INSERT INTO password_resets (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+	`
+	if _, err = tx.ExecContext(ctx, insertQuery, m.UserID, m.TokenHash, m.ExpiresAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// FindResetLogin returns the login of the user a pending reset token with
+// the given hash belongs to. Unknown, used and expired tokens yield
+// apperrors.ErrInvalidToken.
+func (r *Repository) FindResetLogin(ctx context.Context, tokenHash string) (string, error) {
+	var login string
+	query := `
+		SELECT u.login
+		FROM password_resets pr
+		JOIN users u ON u.id = pr.user_id
+		WHERE pr.token_hash = $1 AND pr.used_at IS NULL AND pr.expires_at > NOW()
+	`
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(&login)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", apperrors.ErrInvalidToken
+		}
+		return "", err
+	}
+	return login, nil
+}
+
+// ConsumeReset spends the reset token with the given hash and sets the
+// password of its user in one transaction. Unknown, used and expired tokens
+// yield apperrors.ErrInvalidToken.
+func (r *Repository) ConsumeReset(ctx context.Context, tokenHash, passwordHash string) (models.ModelID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var reset models.PasswordReset
+	query := `
+		SELECT id, user_id, expires_at, used_at
+		FROM password_resets
+		WHERE token_hash = $1
+		FOR UPDATE
+	`
+	err = tx.QueryRowContext(ctx, query, tokenHash).Scan(&reset.ID, &reset.UserID, &reset.ExpiresAt, &reset.UsedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", apperrors.ErrInvalidToken
+		}
+		return "", err
+	}
+	if reset.UsedAt.Valid || !reset.ExpiresAt.After(time.Now()) {
+		return "", apperrors.ErrInvalidToken
+	}
+
+	useQuery := `
+		UPDATE password_resets SET used_at = NOW() WHERE id = $1
+	`
+	if _, err = tx.ExecContext(ctx, useQuery, reset.ID); err != nil {
+		return "", err
+	}
+
+	passwordQuery := `
+		UPDATE users SET password = $2 WHERE id = $1
+	`
+	if _, err = tx.ExecContext(ctx, passwordQuery, reset.UserID, passwordHash); err != nil {
+		return "", err
+	}
+
+	return reset.UserID, tx.Commit()
+}
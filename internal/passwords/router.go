@@ -0,0 +1,32 @@
+package passwords
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/credentials"
+	"github.com/dkmelnik/go-musthave-diploma/internal/notify"
+)
+
+type UserMiddleware interface {
+	Auth(c *fiber.Ctx) error
+}
+
+func SetupRouter(
+	r fiber.Router,
+	policy *credentials.Policy,
+	hasher PasswordHasher,
+	resetConf ResetConfig,
+	mw UserMiddleware,
+	userRepository UserRepository,
+	passwordRepository passwordRepository,
+	sessionRevoker SessionRevoker,
+	reauth Reauthenticator,
+	notifier notify.Notifier,
+) {
+	ps := NewService(policy, hasher, resetConf, userRepository, passwordRepository, sessionRevoker, reauth, notifier)
+	handle := newHandler(ps)
+
+	r.Put("password", mw.Auth, handle.change)
+	r.Post("password/reset", handle.requestReset)
+	r.Post("password/reset/confirm", handle.confirmReset)
+}
@@ -0,0 +1,191 @@
+package passwords
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/credentials"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/notify"
+	"github.com/dkmelnik/go-musthave-diploma/internal/passwords/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+type (
+	UserRepository interface {
+		FindOneByLogin(ctx context.Context, login string) (*models.User, error)
+	}
+	passwordRepository interface {
+		FindCredentials(ctx context.Context, userID models.ModelID) (login, hash string, err error)
+		FindResetLogin(ctx context.Context, tokenHash string) (string, error)
+		UpdatePassword(ctx context.Context, userID models.ModelID, hash string) error
+		SaveReset(ctx context.Context, m *models.PasswordReset, maxPerWindow int, window time.Duration) error
+		ConsumeReset(ctx context.Context, tokenHash, passwordHash string) (models.ModelID, error)
+	}
+	PasswordHasher interface {
+		Hash(password string) (string, error)
+		Verify(hash, password string) (needsRehash bool, err error)
+	}
+	SessionRevoker interface {
+		RevokeSessions(ctx context.Context, userID models.ModelID, exceptJTI string) error
+	}
+	// Reauthenticator spends a fresh login of the user at the OpenID
+	// provider, see oidc.Service.BeginReauth.
+	Reauthenticator interface {
+		ConsumeReauth(ctx context.Context, userID models.ModelID) error
+	}
+	// ResetConfig of password resets. Tokens expire after TTL, a login gets
+	// at most MaxPerWindow of them within Window.
+	ResetConfig struct {
+		TTL          time.Duration
+		MaxPerWindow int
+		Window       time.Duration
+	}
+	Service struct {
+		policy             *credentials.Policy
+		hasher             PasswordHasher
+		resetConf          ResetConfig
+		userRepository     UserRepository
+		passwordRepository passwordRepository
+		sessionRevoker     SessionRevoker
+		reauth             Reauthenticator
+		notifier           notify.Notifier
+	}
+)
+
+func NewService(
+	policy *credentials.Policy,
+	hasher PasswordHasher,
+	resetConf ResetConfig,
+	ur UserRepository,
+	pr passwordRepository,
+	sr SessionRevoker,
+	reauth Reauthenticator,
+	notifier notify.Notifier,
+) *Service {
+	return &Service{policy, hasher, resetConf, ur, pr, sr, reauth, notifier}
+}
+
+// Change sets a new password after checking the old one and revokes every
+// session but the current one. Accounts provisioned by the OpenID provider
+// have no password yet; they set the first one after a fresh login there,
+// which is only spent once the new password passes the policy.
+func (s *Service) Change(ctx context.Context, userID models.ModelID, currentJTI string, d dto.ChangePayload) error {
+	login, hash, err := s.passwordRepository.FindCredentials(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if hash != "" {
+		if _, err = s.hasher.Verify(hash, d.OldPassword); err != nil {
+			return apperrors.ErrInvalidCredentials
+		}
+	}
+
+	if err = s.checkPassword(login, d.NewPassword); err != nil {
+		return err
+	}
+
+	newHash, err := s.hasher.Hash(d.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	if hash == "" {
+		if err = s.reauth.ConsumeReauth(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	if err = s.passwordRepository.UpdatePassword(ctx, userID, newHash); err != nil {
+		return err
+	}
+
+	return s.sessionRevoker.RevokeSessions(ctx, userID, currentJTI)
+}
+
+// RequestReset sends a reset token to the user. Unknown and rate limited
+// logins and failed deliveries are not reported, so the endpoint can't be
+// used to probe for accounts; they only show up in the log.
+func (s *Service) RequestReset(ctx context.Context, d dto.ResetRequestPayload) error {
+	user, err := s.userRepository.FindOneByLogin(ctx, d.Login)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token := utils.GenerateSecret()
+	expiresAt := time.Now().Add(s.resetConf.TTL)
+
+	// a verified email beats the login, which may not be an address at all
+	channel, to := "", user.Login
+	if user.EmailVerifiedAt.Valid {
+		channel, to = notify.ChannelEmail, user.Email
+	}
+
+	err = s.passwordRepository.SaveReset(ctx, &models.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: utils.HashSecret(token),
+		ExpiresAt: expiresAt,
+	}, s.resetConf.MaxPerWindow, s.resetConf.Window)
+	if errors.Is(err, apperrors.ErrTooManyAttempts) {
+		logger.Log.Info("passwords:service:requestReset", "rate limited", user.ID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = s.notifier.Notify(ctx, notify.Message{
+		UserID:  user.ID,
+		Channel: channel,
+		To:      to,
+		Subject: "Password reset",
+		Body: fmt.Sprintf(
+			"Use this token to reset your password: %s\nIt expires at %s. If you did not ask for a reset, ignore this message.",
+			token, expiresAt.Format(time.RFC1123),
+		),
+	})
+	if err != nil {
+		logger.Log.Error("passwords:service:requestReset", "Notify", err, "userID", user.ID)
+	}
+
+	return nil
+}
+
+// ConfirmReset sets the new password with a reset token and revokes every
+// session of the user.
+func (s *Service) ConfirmReset(ctx context.Context, d dto.ResetConfirmPayload) error {
+	login, err := s.passwordRepository.FindResetLogin(ctx, utils.HashSecret(d.Token))
+	if err != nil {
+		return err
+	}
+
+	if err = s.checkPassword(login, d.NewPassword); err != nil {
+		return err
+	}
+
+	newHash, err := s.hasher.Hash(d.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	userID, err := s.passwordRepository.ConsumeReset(ctx, utils.HashSecret(d.Token), newHash)
+	if err != nil {
+		return err
+	}
+
+	return s.sessionRevoker.RevokeSessions(ctx, userID, "")
+}
+
+func (s *Service) checkPassword(login, password string) error {
+	v := &apperrors.ValidationError{}
+	s.policy.CheckPassword(v, "new_password", login, password)
+	return v.OrNil()
+}
@@ -0,0 +1,304 @@
+package passwords
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/credentials"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/notify"
+	notifymocks "github.com/dkmelnik/go-musthave-diploma/internal/notify/mocks"
+	"github.com/dkmelnik/go-musthave-diploma/internal/passwords/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/passwords/mocks"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+type serviceMocks struct {
+	users     *mocks.MockUserRepository
+	passwords *mocks.MockpasswordRepository
+	hasher    *mocks.MockPasswordHasher
+	revoker   *mocks.MockSessionRevoker
+	reauth    *mocks.MockReauthenticator
+	notifier  *notifymocks.MockNotifier
+}
+
+func newTestService(t *testing.T, ctrl *gomock.Controller) (*Service, serviceMocks) {
+	policy, err := credentials.New(credentials.Config{
+		LoginMinLength:     3,
+		LoginMaxLength:     64,
+		LoginCharset:       "a-z0-9._@-",
+		PasswordMinLength:  8,
+		PasswordMaxLength:  128,
+		PasswordMinClasses: 2,
+	})
+	require.NoError(t, err)
+
+	m := serviceMocks{
+		users:     mocks.NewMockUserRepository(ctrl),
+		passwords: mocks.NewMockpasswordRepository(ctrl),
+		hasher:    mocks.NewMockPasswordHasher(ctrl),
+		revoker:   mocks.NewMockSessionRevoker(ctrl),
+		reauth:    mocks.NewMockReauthenticator(ctrl),
+		notifier:  notifymocks.NewMockNotifier(ctrl),
+	}
+	conf := ResetConfig{TTL: 30 * time.Minute, MaxPerWindow: 3, Window: time.Hour}
+	return NewService(policy, m.hasher, conf, m.users, m.passwords, m.revoker, m.reauth, m.notifier), m
+}
+
+func TestService_RequestReset(t *testing.T) {
+	user := &models.User{ID: "user", Login: "alice"}
+	verified := &models.User{
+		ID:              "user",
+		Login:           "alice",
+		Email:           "alice@example.com",
+		EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+
+	tests := []struct {
+		name      string
+		user      *models.User
+		findErr   error
+		sent      int
+		notify    bool
+		notifyErr error
+		to        string
+		channel   string
+		want      error
+	}{
+		{
+			name:   "positive test #1, token sent to the login",
+			user:   user,
+			notify: true,
+			to:     "alice",
+		},
+		{
+			name:    "positive test #2, verified email beats the login",
+			user:    verified,
+			notify:  true,
+			to:      "alice@example.com",
+			channel: notify.ChannelEmail,
+		},
+		{
+			name:    "positive test #3, unknown login looks the same",
+			findErr: apperrors.ErrNotFound,
+		},
+		{
+			name: "positive test #4, rate limited login looks the same",
+			user: user,
+			sent: 3,
+		},
+		{
+			name:      "positive test #5, failed delivery looks the same",
+			user:      user,
+			notify:    true,
+			notifyErr: errors.New("smtp is down"),
+			to:        "alice",
+		},
+		{
+			name:    "negative test #6, database error",
+			findErr: errors.New("db is down"),
+			want:    errors.New("db is down"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestService(t, ctrl)
+
+			m.users.EXPECT().FindOneByLogin(gomock.Any(), "alice").Return(tt.user, tt.findErr)
+
+			var saved *models.PasswordReset
+			if tt.user != nil {
+				m.passwords.EXPECT().SaveReset(gomock.Any(), gomock.Any(), 3, time.Hour).
+					DoAndReturn(func(_ context.Context, r *models.PasswordReset, maxPerWindow int, _ time.Duration) error {
+						if tt.sent >= maxPerWindow {
+							return apperrors.ErrTooManyAttempts
+						}
+						saved = r
+						return nil
+					})
+			}
+			if tt.notify {
+				m.notifier.EXPECT().Notify(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, msg notify.Message) error {
+						assert.Equal(t, models.ModelID("user"), msg.UserID)
+						assert.Equal(t, tt.to, msg.To)
+						assert.Equal(t, tt.channel, msg.Channel)
+
+						// only the hash of the mailed token is stored
+						token := strings.TrimPrefix(strings.SplitN(msg.Body, "\n", 2)[0], "Use this token to reset your password: ")
+						assert.Equal(t, utils.HashSecret(token), saved.TokenHash)
+						return tt.notifyErr
+					})
+			}
+
+			err := service.RequestReset(context.Background(), dto.ResetRequestPayload{Login: "alice"})
+			if tt.want != nil {
+				require.Error(t, err)
+				assert.Equal(t, tt.want.Error(), err.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestService_ConfirmReset(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		findErr  error
+		consume  error
+		want     error
+	}{
+		{
+			name:     "positive test #1, password reset and sessions revoked",
+			password: "correct horse 1",
+		},
+		{
+			name:     "negative test #2, unknown or used token",
+			password: "correct horse 1",
+			findErr:  apperrors.ErrInvalidToken,
+			want:     apperrors.ErrInvalidToken,
+		},
+		{
+			name:     "negative test #3, token used concurrently",
+			password: "correct horse 1",
+			consume:  apperrors.ErrInvalidToken,
+			want:     apperrors.ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestService(t, ctrl)
+			hash := utils.HashSecret("token")
+
+			m.passwords.EXPECT().FindResetLogin(gomock.Any(), hash).Return("alice", tt.findErr)
+			if tt.findErr == nil {
+				m.hasher.EXPECT().Hash(tt.password).Return("new-hash", nil)
+				m.passwords.EXPECT().ConsumeReset(gomock.Any(), hash, "new-hash").Return(models.ModelID("user"), tt.consume)
+			}
+			if tt.want == nil {
+				m.revoker.EXPECT().RevokeSessions(gomock.Any(), models.ModelID("user"), "").Return(nil)
+			}
+
+			err := service.ConfirmReset(context.Background(), dto.ResetConfirmPayload{Token: "token", NewPassword: tt.password})
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestService_ConfirmResetPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestService(t, ctrl)
+	m.passwords.EXPECT().FindResetLogin(gomock.Any(), gomock.Any()).Return("alice", nil)
+
+	err := service.ConfirmReset(context.Background(), dto.ResetConfirmPayload{Token: "token", NewPassword: "short"})
+
+	var verr *apperrors.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "new_password", verr.Errors[0].Field)
+}
+
+func TestService_Change(t *testing.T) {
+	tests := []struct {
+		name      string
+		verifyErr error
+		want      error
+	}{
+		{name: "positive test #1, password changed, other sessions revoked"},
+		{name: "negative test #2, wrong old password", verifyErr: errors.New("mismatch"), want: apperrors.ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestService(t, ctrl)
+
+			m.passwords.EXPECT().FindCredentials(gomock.Any(), models.ModelID("user")).Return("alice", "old-hash", nil)
+			m.hasher.EXPECT().Verify("old-hash", "old password").Return(false, tt.verifyErr)
+			if tt.want == nil {
+				m.hasher.EXPECT().Hash("correct horse 1").Return("new-hash", nil)
+				m.passwords.EXPECT().UpdatePassword(gomock.Any(), models.ModelID("user"), "new-hash").Return(nil)
+				m.revoker.EXPECT().RevokeSessions(gomock.Any(), models.ModelID("user"), "jti").Return(nil)
+			}
+
+			err := service.Change(context.Background(), "user", "jti", dto.ChangePayload{
+				OldPassword: "old password",
+				NewPassword: "correct horse 1",
+			})
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestService_ChangeFirstPassword(t *testing.T) {
+	tests := []struct {
+		name      string
+		password  string
+		weak      bool
+		reauthErr error
+		want      error
+	}{
+		{
+			name:     "positive test #1, first password set after a fresh provider login",
+			password: "correct horse 1",
+		},
+		{
+			name:      "negative test #2, no fresh provider login",
+			password:  "correct horse 1",
+			reauthErr: apperrors.ErrInvalidCredentials,
+			want:      apperrors.ErrInvalidCredentials,
+		},
+		{
+			name:     "negative test #3, weak password, the login is not spent",
+			password: "short",
+			weak:     true,
+			want:     &apperrors.ValidationError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, m := newTestService(t, ctrl)
+
+			m.passwords.EXPECT().FindCredentials(gomock.Any(), models.ModelID("user")).Return("alice", "", nil)
+			if !tt.weak {
+				m.hasher.EXPECT().Hash(tt.password).Return("new-hash", nil)
+				m.reauth.EXPECT().ConsumeReauth(gomock.Any(), models.ModelID("user")).Return(tt.reauthErr)
+			}
+			if tt.want == nil {
+				m.passwords.EXPECT().UpdatePassword(gomock.Any(), models.ModelID("user"), "new-hash").Return(nil)
+				m.revoker.EXPECT().RevokeSessions(gomock.Any(), models.ModelID("user"), "jti").Return(nil)
+			}
+
+			err := service.Change(context.Background(), "user", "jti", dto.ChangePayload{NewPassword: tt.password})
+
+			var verr *apperrors.ValidationError
+			if errors.As(tt.want, &verr) {
+				assert.ErrorAs(t, err, &verr)
+				return
+			}
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
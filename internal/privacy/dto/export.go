@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	ordersdto "github.com/dkmelnik/go-musthave-diploma/internal/orders/dto"
+	tokensdto "github.com/dkmelnik/go-musthave-diploma/internal/tokens/dto"
+	withdrawalsdto "github.com/dkmelnik/go-musthave-diploma/internal/withdrawals/dto"
+)
+
+type (
+	ProfileExport struct {
+		ID           string    `json:"id"`
+		Login        string    `json:"login"`
+		Role         string    `json:"role"`
+		ReferralCode string    `json:"referral_code,omitempty"`
+		Email        string    `json:"email,omitempty"`
+		Phone        string    `json:"phone,omitempty"`
+		DisplayName  string    `json:"display_name,omitempty"`
+		Locale       string    `json:"locale,omitempty"`
+		TimeZone     string    `json:"time_zone,omitempty"`
+		CreatedAt    time.Time `json:"created_at"`
+	}
+	// Export is the archive of the personal data kept about the user.
+	Export struct {
+		ExportedAt  time.Time                           `json:"exported_at"`
+		Profile     ProfileExport                       `json:"profile"`
+		Orders      []ordersdto.OrderResponse           `json:"orders"`
+		Withdrawals []withdrawalsdto.WithdrawalResponse `json:"withdrawals"`
+		Sessions    []tokensdto.SessionResponse         `json:"sessions"`
+	}
+	// DeletePayload confirms deleting the account with the password. It is
+	// left empty by accounts without one, see privacy.Service.Delete.
+	DeletePayload struct {
+		Password string `json:"password"`
+	}
+)
@@ -0,0 +1,83 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/privacy/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens"
+)
+
+type (
+	privacyService interface {
+		Export(ctx context.Context, userID models.ModelID) (dto.Export, error)
+		Delete(ctx context.Context, userID models.ModelID, d dto.DeletePayload, client appdto.Client) error
+	}
+	handler struct {
+		service   privacyService
+		transport *tokens.Transport
+	}
+)
+
+func newHandler(ps privacyService, transport *tokens.Transport) *handler {
+	return &handler{ps, transport}
+}
+
+// export answers with the archive as a file download.
+func (h *handler) export(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	out, err := h.service.Export(c.Context(), models.ModelID(userID))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+		}
+		logger.Log.Error("privacy:handler:export", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+
+	c.Attachment("gophermart-export.json")
+	return c.Status(fiber.StatusOK).JSON(out)
+}
+
+func (h *handler) delete(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	var body dto.DeletePayload
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	}
+
+	switch err := h.service.Delete(c.Context(), models.ModelID(userID), body, tokens.ClientOf(c)); {
+	case err == nil:
+		h.transport.Clear(c)
+		return c.SendStatus(fiber.StatusNoContent)
+	case errors.Is(err, apperrors.ErrInvalidCredentials):
+		return c.Status(fiber.StatusForbidden).SendString(http.StatusText(fiber.StatusForbidden))
+	case errors.Is(err, apperrors.ErrNotFound):
+		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+	case errors.Is(err, apperrors.ErrTooManyAttempts):
+		return tooManyAttempts(c, err)
+	default:
+		logger.Log.Error("privacy:handler:delete", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+}
+
+// tooManyAttempts answers a locked out login, with Retry-After when known.
+func tooManyAttempts(c *fiber.Ctx, err error) error {
+	var retry *apperrors.RetryError
+	if errors.As(err, &retry) {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retry.RetryAfter.Seconds()))))
+	}
+	return c.Status(fiber.StatusTooManyRequests).SendString(http.StatusText(fiber.StatusTooManyRequests))
+}
@@ -0,0 +1,177 @@
+package privacy
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/privacy/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/privacy/mocks"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens"
+)
+
+func newTestApp(t *testing.T, ps privacyService) *fiber.App {
+	transport, err := tokens.NewTransport(tokens.CookieConfig{})
+	require.NoError(t, err)
+
+	h := newHandler(ps, transport)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "user")
+		return c.Next()
+	})
+	app.Get("/export", h.export)
+	app.Delete("/", h.delete)
+	return app
+}
+
+func Test_export(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{
+			name: "negative test #1, deleted account",
+			err:  apperrors.ErrNotFound,
+			code: http.StatusUnauthorized,
+		},
+		{
+			name: "negative test #2, unknown service error",
+			err:  errors.New("db is down"),
+			code: http.StatusInternalServerError,
+		},
+		{
+			name: "positive test #3, archive downloaded",
+			code: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ps := mocks.NewMockprivacyService(ctrl)
+			ps.EXPECT().Export(gomock.Any(), models.ModelID("user")).Return(dto.Export{}, tt.err)
+
+			resp, err := newTestApp(t, ps).Test(httptest.NewRequest(http.MethodGet, "/export", nil), 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+			if tt.code == http.StatusOK {
+				assert.Contains(t, resp.Header.Get(fiber.HeaderContentDisposition), "attachment")
+			}
+		})
+	}
+}
+
+func Test_delete(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		service  bool
+		password string
+		err      error
+		code     int
+		cleared  bool
+	}{
+		{
+			name: "negative test #1, bad entity",
+			body: `{"password":`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "negative test #2, no password and no fresh provider login",
+			body:    `{}`,
+			service: true,
+			err:     apperrors.ErrInvalidCredentials,
+			code:    http.StatusForbidden,
+		},
+		{
+			name:     "negative test #3, wrong password",
+			body:     `{"password":"secret"}`,
+			service:  true,
+			password: "secret",
+			err:      apperrors.ErrInvalidCredentials,
+			code:     http.StatusForbidden,
+		},
+		{
+			name:     "negative test #4, deleted already",
+			body:     `{"password":"secret"}`,
+			service:  true,
+			password: "secret",
+			err:      apperrors.ErrNotFound,
+			code:     http.StatusUnauthorized,
+		},
+		{
+			name:     "negative test #5, unknown service error",
+			body:     `{"password":"secret"}`,
+			service:  true,
+			password: "secret",
+			err:      errors.New("db is down"),
+			code:     http.StatusInternalServerError,
+		},
+		{
+			name:     "positive test #6, deleted and logged out",
+			body:     `{"password":"secret"}`,
+			service:  true,
+			password: "secret",
+			code:     http.StatusNoContent,
+			cleared:  true,
+		},
+		{
+			name:     "negative test #7, locked out",
+			body:     `{"password":"secret"}`,
+			service:  true,
+			password: "secret",
+			err:      &apperrors.RetryError{Err: apperrors.ErrTooManyAttempts, RetryAfter: 30 * time.Second},
+			code:     http.StatusTooManyRequests,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ps := mocks.NewMockprivacyService(ctrl)
+			if tt.service {
+				ps.EXPECT().Delete(gomock.Any(), models.ModelID("user"), dto.DeletePayload{Password: tt.password}, gomock.Any()).Return(tt.err)
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := newTestApp(t, ps).Test(req, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+			if tt.code == http.StatusTooManyRequests {
+				assert.Equal(t, "30", resp.Header.Get(fiber.HeaderRetryAfter))
+			}
+
+			var cleared bool
+			for _, c := range resp.Cookies() {
+				if c.Name == tokens.RefreshCookie && c.Value == "" {
+					cleared = true
+				}
+			}
+			assert.Equal(t, tt.cleared, cleared)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	dto0 "github.com/dkmelnik/go-musthave-diploma/internal/privacy/dto"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockprivacyService is a mock of privacyService interface.
+type MockprivacyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockprivacyServiceMockRecorder
+}
+
+// MockprivacyServiceMockRecorder is the mock recorder for MockprivacyService.
+type MockprivacyServiceMockRecorder struct {
+	mock *MockprivacyService
+}
+
+// NewMockprivacyService creates a new mock instance.
+func NewMockprivacyService(ctrl *gomock.Controller) *MockprivacyService {
+	mock := &MockprivacyService{ctrl: ctrl}
+	mock.recorder = &MockprivacyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockprivacyService) EXPECT() *MockprivacyServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockprivacyService) Delete(ctx context.Context, userID models.ModelID, d dto0.DeletePayload, client dto.Client) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, d, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockprivacyServiceMockRecorder) Delete(ctx, userID, d, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockprivacyService)(nil).Delete), ctx, userID, d, client)
+}
+
+// Export mocks base method.
+func (m *MockprivacyService) Export(ctx context.Context, userID models.ModelID) (dto0.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, userID)
+	ret0, _ := ret[0].(dto0.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockprivacyServiceMockRecorder) Export(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockprivacyService)(nil).Export), ctx, userID)
+}
@@ -0,0 +1,113 @@
+package privacy
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+// FindProfile returns the account of a user that was not deleted.
+func (r *Repository) FindProfile(ctx context.Context, id models.ModelID) (*models.User, error) {
+	user := models.User{ID: id}
+	query := `
+		SELECT login, password, COALESCE(referral_code, ''), role,
+			email, phone, display_name, locale, time_zone, created_at
+		FROM users
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&user.Login,
+		&user.Password,
+		&user.ReferralCode,
+		&user.Role,
+		&user.Email,
+		&user.Phone,
+		&user.DisplayName,
+		&user.Locale,
+		&user.TimeZone,
+		&user.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Anonymize replaces the login with a placeholder, drops the credentials,
+// second factors and profile and deletes data only kept for the user's
+// convenience. Orders, withdrawals and balance entries stay linked to the
+// now anonymous id for accounting.
+func (r *Repository) Anonymize(ctx context.Context, id models.ModelID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// the old login is read in the update, so its lockout counter can go too
+	var login string
+	query := `
+		UPDATE users u SET
+			login = 'deleted-' || u.id::text,
+			password = '',
+			referral_code = NULL,
+			email = '',
+			email_verified_at = NULL,
+			phone = '',
+			phone_verified_at = NULL,
+			display_name = '',
+			locale = '',
+			time_zone = '',
+			totp_secret = NULL,
+			totp_enabled_at = NULL,
+			totp_last_step = NULL,
+			disabled_at = COALESCE(u.disabled_at, NOW()),
+			deleted_at = NOW()
+		FROM (SELECT id, login FROM users WHERE id = $1 FOR UPDATE) old
+		WHERE u.id = old.id AND u.deleted_at IS NULL
+		RETURNING old.login
+	`
+	if err = tx.QueryRowContext(ctx, query, id).Scan(&login); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.ErrNotFound
+		}
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM login_attempts WHERE key = $1`, "login:"+login); err != nil {
+		return err
+	}
+
+	for _, query := range []string{
+		`DELETE FROM sessions WHERE user_id = $1`,
+		`DELETE FROM api_keys WHERE user_id = $1`,
+		`DELETE FROM password_resets WHERE user_id = $1`,
+		`DELETE FROM magic_links WHERE user_id = $1`,
+		`DELETE FROM user_identities WHERE user_id = $1`,
+		`DELETE FROM oidc_states WHERE link_user_id = $1`,
+		`DELETE FROM totp_recovery_codes WHERE user_id = $1`,
+		`DELETE FROM two_factor_challenges WHERE user_id = $1`,
+		`DELETE FROM contact_verifications WHERE user_id = $1`,
+		`DELETE FROM notification_outbox WHERE user_id = $1`,
+	} {
+		if _, err = tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
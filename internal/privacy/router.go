@@ -0,0 +1,23 @@
+package privacy
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens"
+)
+
+type UserMiddleware interface {
+	Auth(c *fiber.Ctx) error
+}
+
+func SetupRouter(
+	r fiber.Router,
+	mw UserMiddleware,
+	transport *tokens.Transport,
+	ps privacyService,
+) {
+	handle := newHandler(ps, transport)
+
+	r.Get("export", mw.Auth, handle.export)
+	r.Delete("", mw.Auth, handle.delete)
+}
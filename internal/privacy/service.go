@@ -0,0 +1,190 @@
+package privacy
+
+import (
+	"context"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	ordersdto "github.com/dkmelnik/go-musthave-diploma/internal/orders/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/privacy/dto"
+	tokensdto "github.com/dkmelnik/go-musthave-diploma/internal/tokens/dto"
+	withdrawalsdto "github.com/dkmelnik/go-musthave-diploma/internal/withdrawals/dto"
+)
+
+type (
+	profileRepository interface {
+		FindProfile(ctx context.Context, id models.ModelID) (*models.User, error)
+		Anonymize(ctx context.Context, id models.ModelID) error
+	}
+	OrderRepository interface {
+		FindByUserID(ctx context.Context, userID models.ModelID) ([]*models.Order, error)
+	}
+	WithdrawalRepository interface {
+		Find(ctx context.Context, userID models.ModelID) ([]*models.Withdrawal, error)
+	}
+	PasswordHasher interface {
+		Verify(hash, password string) (needsRehash bool, err error)
+	}
+	SessionRepository interface {
+		FindSessions(ctx context.Context, userID models.ModelID) ([]models.Session, error)
+	}
+	SessionRevoker interface {
+		RevokeSessions(ctx context.Context, userID models.ModelID, exceptJTI string) error
+	}
+	// Reauthenticator spends a fresh login of the user at the OpenID
+	// provider, see oidc.Service.BeginReauth.
+	Reauthenticator interface {
+		ConsumeReauth(ctx context.Context, userID models.ModelID) error
+	}
+	// LoginGuard throttles password guesses the same way as on login.
+	LoginGuard interface {
+		Check(ctx context.Context, login, ip string) error
+		Fail(ctx context.Context, login, ip string) error
+		Succeed(ctx context.Context, login, ip string) error
+	}
+	Service struct {
+		hasher               PasswordHasher
+		profileRepository    profileRepository
+		orderRepository      OrderRepository
+		withdrawalRepository WithdrawalRepository
+		sessionRepository    SessionRepository
+		sessionRevoker       SessionRevoker
+		reauth               Reauthenticator
+		loginGuard           LoginGuard
+	}
+)
+
+func NewService(
+	hasher PasswordHasher,
+	pr profileRepository,
+	or OrderRepository,
+	wr WithdrawalRepository,
+	sr SessionRepository,
+	revoker SessionRevoker,
+	reauth Reauthenticator,
+	loginGuard LoginGuard,
+) *Service {
+	return &Service{hasher, pr, or, wr, sr, revoker, reauth, loginGuard}
+}
+
+// Export collects the personal data kept about the user.
+func (s *Service) Export(ctx context.Context, userID models.ModelID) (dto.Export, error) {
+	user, err := s.profileRepository.FindProfile(ctx, userID)
+	if err != nil {
+		return dto.Export{}, err
+	}
+
+	out := dto.Export{
+		ExportedAt: time.Now(),
+		Profile: dto.ProfileExport{
+			ID:           string(user.ID),
+			Login:        user.Login,
+			Role:         user.Role,
+			ReferralCode: user.ReferralCode,
+			Email:        user.Email,
+			Phone:        user.Phone,
+			DisplayName:  user.DisplayName,
+			Locale:       user.Locale,
+			TimeZone:     user.TimeZone,
+			CreatedAt:    user.CreatedAt,
+		},
+		Orders:      make([]ordersdto.OrderResponse, 0),
+		Withdrawals: make([]withdrawalsdto.WithdrawalResponse, 0),
+		Sessions:    make([]tokensdto.SessionResponse, 0),
+	}
+
+	orders, err := s.orderRepository.FindByUserID(ctx, userID)
+	if err != nil {
+		return dto.Export{}, err
+	}
+	for _, v := range orders {
+		d := ordersdto.OrderResponse{
+			Number:     v.Number,
+			Status:     string(v.Status),
+			UploadedAt: v.CreatedAt,
+		}
+		if v.Accrual.Valid {
+			d.SetAccrual(v.Accrual.Float64)
+		}
+		out.Orders = append(out.Orders, d)
+	}
+
+	withdrawals, err := s.withdrawalRepository.Find(ctx, userID)
+	if err != nil {
+		return dto.Export{}, err
+	}
+	for _, v := range withdrawals {
+		out.Withdrawals = append(out.Withdrawals, withdrawalsdto.WithdrawalResponse{
+			Order:       v.OrderNumber,
+			Sum:         v.Amount,
+			ProcessedAT: v.CreatedAt,
+		})
+	}
+
+	sessions, err := s.sessionRepository.FindSessions(ctx, userID)
+	if err != nil {
+		return dto.Export{}, err
+	}
+	for _, v := range sessions {
+		out.Sessions = append(out.Sessions, tokensdto.SessionResponse{
+			ID:         string(v.ID),
+			IP:         v.IP,
+			UserAgent:  v.UserAgent,
+			CreatedAt:  v.CreatedAt,
+			LastSeenAt: v.LastSeenAt,
+			ExpiresAt:  v.ExpiresAt,
+		})
+	}
+
+	return out, nil
+}
+
+// Delete anonymizes the account once the password is confirmed; accounts
+// without one, provisioned by the OpenID provider, confirm with a fresh login
+// there instead. Password guesses count against the login guard of the
+// user's login and the client's IP. Every token of the user is revoked first,
+// so no session outlives the account.
+func (s *Service) Delete(ctx context.Context, userID models.ModelID, d dto.DeletePayload, client appdto.Client) error {
+	user, err := s.profileRepository.FindProfile(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Password == "" {
+		if err = s.reauth.ConsumeReauth(ctx, userID); err != nil {
+			return err
+		}
+	} else if err = s.guardedVerify(ctx, user, d.Password, client); err != nil {
+		return err
+	}
+
+	if err = s.sessionRevoker.RevokeSessions(ctx, userID, ""); err != nil {
+		return err
+	}
+
+	if err = s.profileRepository.Anonymize(ctx, userID); err != nil {
+		return err
+	}
+	logger.Log.Info("privacy:delete", "user", userID)
+
+	return nil
+}
+
+// guardedVerify checks the password of the user behind the login guard.
+func (s *Service) guardedVerify(ctx context.Context, user *models.User, password string, client appdto.Client) error {
+	if err := s.loginGuard.Check(ctx, user.Login, client.IP); err != nil {
+		return err
+	}
+
+	if _, err := s.hasher.Verify(user.Password, password); err != nil {
+		if ferr := s.loginGuard.Fail(ctx, user.Login, client.IP); ferr != nil {
+			return ferr
+		}
+		return apperrors.ErrInvalidCredentials
+	}
+
+	return s.loginGuard.Succeed(ctx, user.Login, client.IP)
+}
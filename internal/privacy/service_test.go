@@ -0,0 +1,192 @@
+package privacy
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/privacy/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/privacy/mocks"
+)
+
+type serviceMocks struct {
+	hasher      *mocks.MockPasswordHasher
+	profiles    *mocks.MockprofileRepository
+	orders      *mocks.MockOrderRepository
+	withdrawals *mocks.MockWithdrawalRepository
+	sessions    *mocks.MockSessionRepository
+	revoker     *mocks.MockSessionRevoker
+	reauth      *mocks.MockReauthenticator
+	guard       *mocks.MockLoginGuard
+}
+
+func newTestService(ctrl *gomock.Controller) (*Service, serviceMocks) {
+	m := serviceMocks{
+		hasher:      mocks.NewMockPasswordHasher(ctrl),
+		profiles:    mocks.NewMockprofileRepository(ctrl),
+		orders:      mocks.NewMockOrderRepository(ctrl),
+		withdrawals: mocks.NewMockWithdrawalRepository(ctrl),
+		sessions:    mocks.NewMockSessionRepository(ctrl),
+		revoker:     mocks.NewMockSessionRevoker(ctrl),
+		reauth:      mocks.NewMockReauthenticator(ctrl),
+		guard:       mocks.NewMockLoginGuard(ctrl),
+	}
+	return NewService(m.hasher, m.profiles, m.orders, m.withdrawals, m.sessions, m.revoker, m.reauth, m.guard), m
+}
+
+func TestService_Export(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, m := newTestService(ctrl)
+	user := &models.User{ID: "user", Login: "alice", Password: "hash", Email: "alice@example.com", Role: models.RoleUser}
+
+	m.profiles.EXPECT().FindProfile(gomock.Any(), models.ModelID("user")).Return(user, nil)
+	m.orders.EXPECT().FindByUserID(gomock.Any(), models.ModelID("user")).Return([]*models.Order{
+		{Number: "12345678903", Status: models.OrderProcessed, Accrual: sql.NullFloat64{Float64: 500, Valid: true}},
+	}, nil)
+	m.withdrawals.EXPECT().Find(gomock.Any(), models.ModelID("user")).Return([]*models.Withdrawal{
+		{OrderNumber: "2377225624", Amount: 100},
+	}, nil)
+	m.sessions.EXPECT().FindSessions(gomock.Any(), models.ModelID("user")).Return([]models.Session{
+		{ID: "phone", IP: "10.0.0.1", UserAgent: "curl"},
+	}, nil)
+
+	out, err := s.Export(context.Background(), "user")
+	require.NoError(t, err)
+
+	assert.WithinDuration(t, time.Now(), out.ExportedAt, time.Second)
+	assert.Equal(t, "alice", out.Profile.Login)
+	assert.Equal(t, "alice@example.com", out.Profile.Email)
+	require.Len(t, out.Orders, 1)
+	assert.Equal(t, "12345678903", out.Orders[0].Number)
+	require.Len(t, out.Withdrawals, 1)
+	assert.Equal(t, "2377225624", out.Withdrawals[0].Order)
+	require.Len(t, out.Sessions, 1)
+	assert.Equal(t, "10.0.0.1", out.Sessions[0].IP)
+}
+
+func TestService_ExportEmpty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, m := newTestService(ctrl)
+
+	m.profiles.EXPECT().FindProfile(gomock.Any(), models.ModelID("user")).Return(&models.User{ID: "user"}, nil)
+	m.orders.EXPECT().FindByUserID(gomock.Any(), models.ModelID("user")).Return(nil, nil)
+	m.withdrawals.EXPECT().Find(gomock.Any(), models.ModelID("user")).Return(nil, nil)
+	m.sessions.EXPECT().FindSessions(gomock.Any(), models.ModelID("user")).Return(nil, nil)
+
+	out, err := s.Export(context.Background(), "user")
+	require.NoError(t, err)
+
+	// empty lists are exported as [] rather than null
+	assert.NotNil(t, out.Orders)
+	assert.NotNil(t, out.Withdrawals)
+	assert.NotNil(t, out.Sessions)
+}
+
+func TestService_Delete(t *testing.T) {
+	user := &models.User{ID: "user", Login: "alice", Password: "hash"}
+	provisioned := &models.User{ID: "user", Login: "alice"}
+
+	tests := []struct {
+		name    string
+		prepare func(m serviceMocks)
+		want    error
+	}{
+		{
+			name: "positive test #1, sessions revoked before anonymizing",
+			prepare: func(m serviceMocks) {
+				gomock.InOrder(
+					m.profiles.EXPECT().FindProfile(gomock.Any(), models.ModelID("user")).Return(user, nil),
+					m.guard.EXPECT().Check(gomock.Any(), "alice", "10.0.0.1").Return(nil),
+					m.hasher.EXPECT().Verify("hash", "secret").Return(false, nil),
+					m.guard.EXPECT().Succeed(gomock.Any(), "alice", "10.0.0.1").Return(nil),
+					m.revoker.EXPECT().RevokeSessions(gomock.Any(), models.ModelID("user"), "").Return(nil),
+					m.profiles.EXPECT().Anonymize(gomock.Any(), models.ModelID("user")).Return(nil),
+				)
+			},
+		},
+		{
+			name: "negative test #2, wrong password",
+			prepare: func(m serviceMocks) {
+				m.profiles.EXPECT().FindProfile(gomock.Any(), models.ModelID("user")).Return(user, nil)
+				m.guard.EXPECT().Check(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+				m.hasher.EXPECT().Verify("hash", "secret").Return(false, apperrors.ErrInvalidCredentials)
+				m.guard.EXPECT().Fail(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+			},
+			want: apperrors.ErrInvalidCredentials,
+		},
+		{
+			name: "negative test #3, deleted already",
+			prepare: func(m serviceMocks) {
+				m.profiles.EXPECT().FindProfile(gomock.Any(), models.ModelID("user")).Return(nil, apperrors.ErrNotFound)
+			},
+			want: apperrors.ErrNotFound,
+		},
+		{
+			name: "negative test #4, revoking failed, the account is kept",
+			prepare: func(m serviceMocks) {
+				m.profiles.EXPECT().FindProfile(gomock.Any(), models.ModelID("user")).Return(user, nil)
+				m.guard.EXPECT().Check(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+				m.hasher.EXPECT().Verify("hash", "secret").Return(false, nil)
+				m.guard.EXPECT().Succeed(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+				m.revoker.EXPECT().RevokeSessions(gomock.Any(), models.ModelID("user"), "").Return(errors.New("db is down"))
+			},
+			want: errors.New("db is down"),
+		},
+		{
+			name: "positive test #5, no password, confirmed by a fresh provider login",
+			prepare: func(m serviceMocks) {
+				gomock.InOrder(
+					m.profiles.EXPECT().FindProfile(gomock.Any(), models.ModelID("user")).Return(provisioned, nil),
+					m.reauth.EXPECT().ConsumeReauth(gomock.Any(), models.ModelID("user")).Return(nil),
+					m.revoker.EXPECT().RevokeSessions(gomock.Any(), models.ModelID("user"), "").Return(nil),
+					m.profiles.EXPECT().Anonymize(gomock.Any(), models.ModelID("user")).Return(nil),
+				)
+			},
+		},
+		{
+			name: "negative test #6, no password and no fresh provider login",
+			prepare: func(m serviceMocks) {
+				m.profiles.EXPECT().FindProfile(gomock.Any(), models.ModelID("user")).Return(provisioned, nil)
+				m.reauth.EXPECT().ConsumeReauth(gomock.Any(), models.ModelID("user")).Return(apperrors.ErrInvalidCredentials)
+			},
+			want: apperrors.ErrInvalidCredentials,
+		},
+		{
+			name: "negative test #7, locked out, the password is not checked",
+			prepare: func(m serviceMocks) {
+				m.profiles.EXPECT().FindProfile(gomock.Any(), models.ModelID("user")).Return(user, nil)
+				m.guard.EXPECT().Check(gomock.Any(), "alice", "10.0.0.1").Return(apperrors.ErrTooManyAttempts)
+			},
+			want: apperrors.ErrTooManyAttempts,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newTestService(ctrl)
+			tt.prepare(m)
+
+			err := s.Delete(context.Background(), "user", dto.DeletePayload{Password: "secret"}, appdto.Client{IP: "10.0.0.1"})
+			if tt.want != nil {
+				assert.EqualError(t, err, tt.want.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type (
+	ProfileResponse struct {
+		Login         string `json:"login"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Phone         string `json:"phone"`
+		PhoneVerified bool   `json:"phone_verified"`
+		DisplayName   string `json:"display_name"`
+		Locale        string `json:"locale"`
+		TimeZone      string `json:"time_zone"`
+	}
+	// ProfilePatch changes the fields that are present, an empty string
+	// clears a field. Changing a contact drops its verification.
+	ProfilePatch struct {
+		Email       *string `json:"email"`
+		Phone       *string `json:"phone"`
+		DisplayName *string `json:"display_name"`
+		Locale      *string `json:"locale"`
+		TimeZone    *string `json:"time_zone"`
+	}
+	VerifyPayload struct {
+		Channel string `json:"channel"`
+	}
+	ConfirmPayload struct {
+		Channel string `json:"channel"`
+		Code    string `json:"code"`
+	}
+)
+
+var (
+	// phonePattern is E.164: a plus, a country code and up to 15 digits.
+	phonePattern  = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+	localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+)
+
+func (r *ProfilePatch) Validate() error {
+	if r.Email != nil {
+		email := strings.ToLower(strings.TrimSpace(*r.Email))
+		if email != "" {
+			addr, err := mail.ParseAddress(email)
+			if err != nil || addr.Address != email || len(email) > 254 {
+				return fmt.Errorf("email is not a valid address")
+			}
+		}
+		r.Email = &email
+	}
+	if r.Phone != nil {
+		phone := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(*r.Phone)
+		if phone != "" && !phonePattern.MatchString(phone) {
+			return fmt.Errorf("phone must be in international format, e.g. +15551234567")
+		}
+		r.Phone = &phone
+	}
+	if r.DisplayName != nil {
+		name := strings.TrimSpace(*r.DisplayName)
+		if len([]rune(name)) > 100 {
+			return fmt.Errorf("display_name must be at most 100 characters long")
+		}
+		r.DisplayName = &name
+	}
+	if r.Locale != nil && *r.Locale != "" {
+		if len(*r.Locale) > 35 || !localePattern.MatchString(*r.Locale) {
+			return fmt.Errorf("locale must be a language tag, e.g. en-US")
+		}
+	}
+	if r.TimeZone != nil && *r.TimeZone != "" {
+		// "Local" would mean the zone of the server
+		if _, err := time.LoadLocation(*r.TimeZone); err != nil || *r.TimeZone == "Local" || len(*r.TimeZone) > 64 {
+			return fmt.Errorf("time_zone must be an IANA time zone, e.g. Europe/Berlin")
+		}
+	}
+
+	return nil
+}
+
+func (r *VerifyPayload) Validate() error {
+	return validateChannel(r.Channel)
+}
+
+func (r *ConfirmPayload) Validate() error {
+	if strings.TrimSpace(r.Code) == "" {
+		return fmt.Errorf("code is required")
+	}
+
+	return validateChannel(r.Channel)
+}
+
+func validateChannel(channel string) error {
+	if channel != models.ChannelEmail && channel != models.ChannelPhone {
+		return fmt.Errorf("channel must be %q or %q", models.ChannelEmail, models.ChannelPhone)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestProfilePatch_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   ProfilePatch
+		wantErr bool
+	}{
+		{name: "empty patch", patch: ProfilePatch{}},
+		{name: "clear fields", patch: ProfilePatch{Email: ptr(""), Phone: ptr(""), TimeZone: ptr("")}},
+		{name: "valid fields", patch: ProfilePatch{
+			Email:    ptr("alice@example.com"),
+			Phone:    ptr("+1 (555) 123-4567"),
+			Locale:   ptr("en-US"),
+			TimeZone: ptr("Europe/Berlin"),
+		}},
+		{name: "email with display name", patch: ProfilePatch{Email: ptr("Alice <alice@example.com>")}, wantErr: true},
+		{name: "email without domain", patch: ProfilePatch{Email: ptr("alice")}, wantErr: true},
+		{name: "local phone", patch: ProfilePatch{Phone: ptr("5551234567")}, wantErr: true},
+		{name: "bad locale", patch: ProfilePatch{Locale: ptr("english please")}, wantErr: true},
+		{name: "unknown time zone", patch: ProfilePatch{TimeZone: ptr("Mars/Olympus")}, wantErr: true},
+		{name: "server time zone", patch: ProfilePatch{TimeZone: ptr("Local")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.patch.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProfilePatch_ValidateNormalizes(t *testing.T) {
+	patch := ProfilePatch{Email: ptr("  Alice@Example.COM "), Phone: ptr("+1 555-123-4567")}
+	require.NoError(t, patch.Validate())
+
+	assert.Equal(t, "alice@example.com", *patch.Email)
+	assert.Equal(t, "+15551234567", *patch.Phone)
+}
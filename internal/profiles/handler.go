@@ -0,0 +1,118 @@
+package profiles
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/profiles/dto"
+)
+
+type (
+	profileService interface {
+		Get(ctx context.Context, userID models.ModelID) (dto.ProfileResponse, error)
+		Update(ctx context.Context, userID models.ModelID, d dto.ProfilePatch) (dto.ProfileResponse, error)
+		RequestVerification(ctx context.Context, userID models.ModelID, d dto.VerifyPayload) error
+		ConfirmVerification(ctx context.Context, userID models.ModelID, d dto.ConfirmPayload) error
+	}
+	handler struct {
+		service profileService
+	}
+)
+
+func newHandler(ps profileService) *handler {
+	return &handler{ps}
+}
+
+func (h *handler) get(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	out, err := h.service.Get(c.Context(), models.ModelID(userID))
+	if err != nil {
+		return h.fail(c, "profiles:handler:get", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(out)
+}
+
+func (h *handler) update(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	var body dto.ProfilePatch
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	}
+	if err := body.Validate(); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(err.Error())
+	}
+
+	out, err := h.service.Update(c.Context(), models.ModelID(userID), body)
+	if err != nil {
+		return h.fail(c, "profiles:handler:update", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(out)
+}
+
+func (h *handler) requestVerification(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	var body dto.VerifyPayload
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	}
+	if err := body.Validate(); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(err.Error())
+	}
+
+	if err := h.service.RequestVerification(c.Context(), models.ModelID(userID), body); err != nil {
+		return h.fail(c, "profiles:handler:requestVerification", err)
+	}
+
+	return c.SendStatus(fiber.StatusAccepted)
+}
+
+func (h *handler) confirmVerification(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	var body dto.ConfirmPayload
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	}
+	if err := body.Validate(); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(err.Error())
+	}
+
+	if err := h.service.ConfirmVerification(c.Context(), models.ModelID(userID), body); err != nil {
+		return h.fail(c, "profiles:handler:confirmVerification", err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *handler) fail(c *fiber.Ctx, op string, err error) error {
+	var retry *apperrors.RetryError
+	switch {
+	case errors.As(err, &retry):
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retry.RetryAfter.Seconds()))))
+		return c.Status(fiber.StatusTooManyRequests).SendString(http.StatusText(fiber.StatusTooManyRequests))
+	case errors.Is(err, apperrors.ErrNotFound):
+		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+	case errors.Is(err, apperrors.ErrInvalidToken):
+		return c.Status(fiber.StatusUnprocessableEntity).SendString("invalid or expired code")
+	case errors.Is(err, apperrors.ErrNoRequiredValue):
+		return c.Status(fiber.StatusUnprocessableEntity).SendString("set the contact in the profile first")
+	case errors.Is(err, apperrors.ErrIsExist):
+		return c.Status(fiber.StatusConflict).SendString("contact is already verified")
+	default:
+		logger.Log.Error(op, "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+}
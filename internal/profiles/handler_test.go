@@ -0,0 +1,211 @@
+package profiles
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/profiles/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/profiles/mocks"
+)
+
+func newTestApp(ps profileService) *fiber.App {
+	h := newHandler(ps)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "user")
+		return c.Next()
+	})
+	app.Patch("/profile", h.update)
+	app.Post("/profile/verify", h.requestVerification)
+	app.Post("/profile/verify/confirm", h.confirmVerification)
+	return app
+}
+
+type handlerCase struct {
+	name    string
+	body    string
+	prepare func(ps *mocks.MockprofileService)
+	code    int
+}
+
+func runHandlerCases(t *testing.T, path string, method string, tests []handlerCase, check func(t *testing.T, tt handlerCase, resp *http.Response)) {
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ps := mocks.NewMockprofileService(ctrl)
+			if tt.prepare != nil {
+				tt.prepare(ps)
+			}
+
+			req := httptest.NewRequest(method, path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := newTestApp(ps).Test(req, 100)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+			if check != nil {
+				check(t, tt, resp)
+			}
+		})
+	}
+}
+
+func Test_update(t *testing.T) {
+	runHandlerCases(t, "/profile", http.MethodPatch, []handlerCase{
+		{
+			name: "negative test #1, bad entity",
+			body: `{"email":`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "negative test #2, invalid email",
+			body: `{"email":"not an address"}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "negative test #3, deleted account",
+			body: `{"display_name":"Alice"}`,
+			prepare: func(ps *mocks.MockprofileService) {
+				ps.EXPECT().Update(gomock.Any(), models.ModelID("user"), gomock.Any()).Return(dto.ProfileResponse{}, apperrors.ErrNotFound)
+			},
+			code: http.StatusUnauthorized,
+		},
+		{
+			name: "negative test #4, unknown service error",
+			body: `{"display_name":"Alice"}`,
+			prepare: func(ps *mocks.MockprofileService) {
+				ps.EXPECT().Update(gomock.Any(), models.ModelID("user"), gomock.Any()).Return(dto.ProfileResponse{}, errors.New("db is down"))
+			},
+			code: http.StatusInternalServerError,
+		},
+		{
+			name: "positive test #5, contact normalized and profile returned",
+			body: `{"email":" Alice@Example.com ","phone":"+1 (555) 123-4567"}`,
+			prepare: func(ps *mocks.MockprofileService) {
+				ps.EXPECT().Update(gomock.Any(), models.ModelID("user"), gomock.Any()).DoAndReturn(
+					func(_ interface{}, _ models.ModelID, d dto.ProfilePatch) (dto.ProfileResponse, error) {
+						assert.Equal(t, "alice@example.com", *d.Email)
+						assert.Equal(t, "+15551234567", *d.Phone)
+						assert.Nil(t, d.DisplayName)
+						return dto.ProfileResponse{Login: "alice", Email: *d.Email, Phone: *d.Phone}, nil
+					})
+			},
+			code: http.StatusOK,
+		},
+	}, func(t *testing.T, tt handlerCase, resp *http.Response) {
+		if tt.code != http.StatusOK {
+			return
+		}
+		var out dto.ProfileResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		assert.Equal(t, "alice@example.com", out.Email)
+		assert.False(t, out.EmailVerified)
+	})
+}
+
+func Test_requestVerification(t *testing.T) {
+	runHandlerCases(t, "/profile/verify", http.MethodPost, []handlerCase{
+		{
+			name: "negative test #1, unknown channel",
+			body: `{"channel":"fax"}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "negative test #2, no contact to verify",
+			body: `{"channel":"email"}`,
+			prepare: func(ps *mocks.MockprofileService) {
+				ps.EXPECT().RequestVerification(gomock.Any(), models.ModelID("user"), dto.VerifyPayload{Channel: "email"}).Return(apperrors.ErrNoRequiredValue)
+			},
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "negative test #3, verified already",
+			body: `{"channel":"email"}`,
+			prepare: func(ps *mocks.MockprofileService) {
+				ps.EXPECT().RequestVerification(gomock.Any(), models.ModelID("user"), dto.VerifyPayload{Channel: "email"}).Return(apperrors.ErrIsExist)
+			},
+			code: http.StatusConflict,
+		},
+		{
+			name: "negative test #4, resent too soon",
+			body: `{"channel":"phone"}`,
+			prepare: func(ps *mocks.MockprofileService) {
+				ps.EXPECT().RequestVerification(gomock.Any(), models.ModelID("user"), dto.VerifyPayload{Channel: "phone"}).
+					Return(&apperrors.RetryError{Err: apperrors.ErrTooManyAttempts, RetryAfter: 41500 * time.Millisecond})
+			},
+			code: http.StatusTooManyRequests,
+		},
+		{
+			name: "positive test #5, code sent",
+			body: `{"channel":"email"}`,
+			prepare: func(ps *mocks.MockprofileService) {
+				ps.EXPECT().RequestVerification(gomock.Any(), models.ModelID("user"), dto.VerifyPayload{Channel: "email"}).Return(nil)
+			},
+			code: http.StatusAccepted,
+		},
+	}, func(t *testing.T, tt handlerCase, resp *http.Response) {
+		if tt.code == http.StatusTooManyRequests {
+			assert.Equal(t, "42", resp.Header.Get(fiber.HeaderRetryAfter))
+		}
+	})
+}
+
+func Test_confirmVerification(t *testing.T) {
+	confirm := dto.ConfirmPayload{Channel: "email", Code: "123456"}
+
+	runHandlerCases(t, "/profile/verify/confirm", http.MethodPost, []handlerCase{
+		{
+			name: "negative test #1, bad entity",
+			body: `{"channel":`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "negative test #2, wrong, spent or expired code",
+			body: `{"channel":"email","code":"123456"}`,
+			prepare: func(ps *mocks.MockprofileService) {
+				ps.EXPECT().ConfirmVerification(gomock.Any(), models.ModelID("user"), confirm).Return(apperrors.ErrInvalidToken)
+			},
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "negative test #3, contact verified by another account",
+			body: `{"channel":"email","code":"123456"}`,
+			prepare: func(ps *mocks.MockprofileService) {
+				ps.EXPECT().ConfirmVerification(gomock.Any(), models.ModelID("user"), confirm).Return(apperrors.ErrIsExist)
+			},
+			code: http.StatusConflict,
+		},
+		{
+			name: "negative test #4, unknown service error",
+			body: `{"channel":"email","code":"123456"}`,
+			prepare: func(ps *mocks.MockprofileService) {
+				ps.EXPECT().ConfirmVerification(gomock.Any(), models.ModelID("user"), confirm).Return(errors.New("db is down"))
+			},
+			code: http.StatusInternalServerError,
+		},
+		{
+			name: "positive test #5, contact verified",
+			body: `{"channel":"email","code":"123456"}`,
+			prepare: func(ps *mocks.MockprofileService) {
+				ps.EXPECT().ConfirmVerification(gomock.Any(), models.ModelID("user"), confirm).Return(nil)
+			},
+			code: http.StatusOK,
+		},
+	}, nil)
+}
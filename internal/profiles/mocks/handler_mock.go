@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	dto "github.com/dkmelnik/go-musthave-diploma/internal/profiles/dto"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockprofileService is a mock of profileService interface.
+type MockprofileService struct {
+	ctrl     *gomock.Controller
+	recorder *MockprofileServiceMockRecorder
+}
+
+// MockprofileServiceMockRecorder is the mock recorder for MockprofileService.
+type MockprofileServiceMockRecorder struct {
+	mock *MockprofileService
+}
+
+// NewMockprofileService creates a new mock instance.
+func NewMockprofileService(ctrl *gomock.Controller) *MockprofileService {
+	mock := &MockprofileService{ctrl: ctrl}
+	mock.recorder = &MockprofileServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockprofileService) EXPECT() *MockprofileServiceMockRecorder {
+	return m.recorder
+}
+
+// ConfirmVerification mocks base method.
+func (m *MockprofileService) ConfirmVerification(ctx context.Context, userID models.ModelID, d dto.ConfirmPayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmVerification", ctx, userID, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmVerification indicates an expected call of ConfirmVerification.
+func (mr *MockprofileServiceMockRecorder) ConfirmVerification(ctx, userID, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmVerification", reflect.TypeOf((*MockprofileService)(nil).ConfirmVerification), ctx, userID, d)
+}
+
+// Get mocks base method.
+func (m *MockprofileService) Get(ctx context.Context, userID models.ModelID) (dto.ProfileResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID)
+	ret0, _ := ret[0].(dto.ProfileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockprofileServiceMockRecorder) Get(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockprofileService)(nil).Get), ctx, userID)
+}
+
+// RequestVerification mocks base method.
+func (m *MockprofileService) RequestVerification(ctx context.Context, userID models.ModelID, d dto.VerifyPayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestVerification", ctx, userID, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestVerification indicates an expected call of RequestVerification.
+func (mr *MockprofileServiceMockRecorder) RequestVerification(ctx, userID, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestVerification", reflect.TypeOf((*MockprofileService)(nil).RequestVerification), ctx, userID, d)
+}
+
+// Update mocks base method.
+func (m *MockprofileService) Update(ctx context.Context, userID models.ModelID, d dto.ProfilePatch) (dto.ProfileResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userID, d)
+	ret0, _ := ret[0].(dto.ProfileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockprofileServiceMockRecorder) Update(ctx, userID, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockprofileService)(nil).Update), ctx, userID, d)
+}
@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockprofileRepository is a mock of profileRepository interface.
+type MockprofileRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockprofileRepositoryMockRecorder
+}
+
+// MockprofileRepositoryMockRecorder is the mock recorder for MockprofileRepository.
+type MockprofileRepositoryMockRecorder struct {
+	mock *MockprofileRepository
+}
+
+// NewMockprofileRepository creates a new mock instance.
+func NewMockprofileRepository(ctrl *gomock.Controller) *MockprofileRepository {
+	mock := &MockprofileRepository{ctrl: ctrl}
+	mock.recorder = &MockprofileRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockprofileRepository) EXPECT() *MockprofileRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConfirmVerification mocks base method.
+func (m *MockprofileRepository) ConfirmVerification(ctx context.Context, userID models.ModelID, channel, codeHash string, maxAttempts int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmVerification", ctx, userID, channel, codeHash, maxAttempts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmVerification indicates an expected call of ConfirmVerification.
+func (mr *MockprofileRepositoryMockRecorder) ConfirmVerification(ctx, userID, channel, codeHash, maxAttempts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmVerification", reflect.TypeOf((*MockprofileRepository)(nil).ConfirmVerification), ctx, userID, channel, codeHash, maxAttempts)
+}
+
+// FindProfile mocks base method.
+func (m *MockprofileRepository) FindProfile(ctx context.Context, id models.ModelID) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindProfile", ctx, id)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindProfile indicates an expected call of FindProfile.
+func (mr *MockprofileRepositoryMockRecorder) FindProfile(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindProfile", reflect.TypeOf((*MockprofileRepository)(nil).FindProfile), ctx, id)
+}
+
+// LastVerificationAt mocks base method.
+func (m *MockprofileRepository) LastVerificationAt(ctx context.Context, userID models.ModelID, channel string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastVerificationAt", ctx, userID, channel)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastVerificationAt indicates an expected call of LastVerificationAt.
+func (mr *MockprofileRepositoryMockRecorder) LastVerificationAt(ctx, userID, channel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastVerificationAt", reflect.TypeOf((*MockprofileRepository)(nil).LastVerificationAt), ctx, userID, channel)
+}
+
+// SaveVerification mocks base method.
+func (m *MockprofileRepository) SaveVerification(ctx context.Context, v *models.ContactVerification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveVerification", ctx, v)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveVerification indicates an expected call of SaveVerification.
+func (mr *MockprofileRepositoryMockRecorder) SaveVerification(ctx, v interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveVerification", reflect.TypeOf((*MockprofileRepository)(nil).SaveVerification), ctx, v)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockprofileRepository) UpdateProfile(ctx context.Context, u *models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, u)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockprofileRepositoryMockRecorder) UpdateProfile(ctx, u interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockprofileRepository)(nil).UpdateProfile), ctx, u)
+}
@@ -0,0 +1,219 @@
+package profiles
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/db/pg"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+func (r *Repository) FindProfile(ctx context.Context, id models.ModelID) (*models.User, error) {
+	user := models.User{ID: id}
+	query := `
+		SELECT login, email, email_verified_at, phone, phone_verified_at, display_name, locale, time_zone
+		FROM users
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&user.Login,
+		&user.Email,
+		&user.EmailVerifiedAt,
+		&user.Phone,
+		&user.PhoneVerifiedAt,
+		&user.DisplayName,
+		&user.Locale,
+		&user.TimeZone,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateProfile stores the profile fields of u. A changed email or phone
+// loses its verification.
+func (r *Repository) UpdateProfile(ctx context.Context, u *models.User) error {
+	query := `
+		UPDATE users SET
+			email_verified_at = CASE WHEN email = $2 THEN email_verified_at END,
+			phone_verified_at = CASE WHEN phone = $3 THEN phone_verified_at END,
+			email = $2,
+			phone = $3,
+			display_name = $4,
+			locale = $5,
+			time_zone = $6
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	res, err := r.db.ExecContext(ctx, query, u.ID, u.Email, u.Phone, u.DisplayName, u.Locale, u.TimeZone)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// SaveVerification stores a new code, the pending ones of the user for the
+// same channel stop working.
+func (r *Repository) SaveVerification(ctx context.Context, v *models.ContactVerification) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	expireQuery := `
+		UPDATE contact_verifications SET consumed_at = NOW()
+		WHERE user_id = $1 AND channel = $2 AND consumed_at IS NULL
+	`
+	if _, err = tx.ExecContext(ctx, expireQuery, v.UserID, v.Channel); err != nil {
+		return err
+	}
+
+	insertQuery := `
+		INSERT INTO contact_verifications (user_id, channel, target, code_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err = tx.ExecContext(ctx, insertQuery, v.UserID, v.Channel, v.Target, v.CodeHash, v.ExpiresAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// LastVerificationAt returns when the last code for the channel was sent,
+// the zero time if none was.
+func (r *Repository) LastVerificationAt(ctx context.Context, userID models.ModelID, channel string) (time.Time, error) {
+	var last sql.NullTime
+	query := `
+		SELECT MAX(created_at) FROM contact_verifications WHERE user_id = $1 AND channel = $2
+	`
+	if err := r.db.QueryRowContext(ctx, query, userID, channel).Scan(&last); err != nil {
+		return time.Time{}, err
+	}
+	return last.Time, nil
+}
+
+// ConfirmVerification checks the code with the given hash against the
+// pending code of the channel and marks the contact verified. Every wrong
+// guess counts, a code is dead after maxAttempts of them. Missing, spent
+// and expired codes and contacts changed since the code was sent yield
+// apperrors.ErrInvalidToken; a contact verified by another account yields
+// apperrors.ErrIsExist.
+func (r *Repository) ConfirmVerification(
+	ctx context.Context,
+	userID models.ModelID,
+	channel, codeHash string,
+	maxAttempts int,
+) error {
+	column, ok := contactColumns[channel]
+	if !ok {
+		return fmt.Errorf("unknown channel %q", channel)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var v models.ContactVerification
+	query := `
+		SELECT id, target, code_hash
+		FROM contact_verifications
+		WHERE user_id = $1 AND channel = $2 AND consumed_at IS NULL
+		  AND expires_at > NOW() AND attempts < $3
+		ORDER BY created_at DESC
+		LIMIT 1
+		FOR UPDATE
+	`
+	err = tx.QueryRowContext(ctx, query, userID, channel, maxAttempts).Scan(&v.ID, &v.Target, &v.CodeHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.ErrInvalidToken
+		}
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(v.CodeHash), []byte(codeHash)) != 1 {
+		failQuery := `
+			UPDATE contact_verifications SET attempts = attempts + 1 WHERE id = $1
+		`
+		if _, err = tx.ExecContext(ctx, failQuery, v.ID); err != nil {
+			return err
+		}
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+		return apperrors.ErrInvalidToken
+	}
+
+	consumeQuery := `
+		UPDATE contact_verifications SET consumed_at = NOW() WHERE id = $1
+	`
+	if _, err = tx.ExecContext(ctx, consumeQuery, v.ID); err != nil {
+		return err
+	}
+
+	var taken bool
+	takenQuery := fmt.Sprintf(`
+		SELECT EXISTS (
+			SELECT 1 FROM users WHERE id <> $1 AND %[1]s = $2 AND %[1]s_verified_at IS NOT NULL
+		)
+	`, column)
+	if err = tx.QueryRowContext(ctx, takenQuery, userID, v.Target).Scan(&taken); err != nil {
+		return err
+	}
+	if taken {
+		return apperrors.ErrIsExist
+	}
+
+	verifyQuery := fmt.Sprintf(`
+		UPDATE users SET %[1]s_verified_at = NOW() WHERE id = $1 AND %[1]s = $2
+	`, column)
+	res, err := tx.ExecContext(ctx, verifyQuery, userID, v.Target)
+	if err != nil {
+		// another account verified the contact since the check above
+		if pg.IsUniqueViolation(err) {
+			return apperrors.ErrIsExist
+		}
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperrors.ErrInvalidToken
+	}
+
+	return tx.Commit()
+}
+
+// contactColumns maps channels to the users columns they verify.
+var contactColumns = map[string]string{
+	models.ChannelEmail: "email",
+	models.ChannelPhone: "phone",
+}
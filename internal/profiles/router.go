@@ -0,0 +1,22 @@
+package profiles
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserMiddleware interface {
+	Auth(c *fiber.Ctx) error
+}
+
+func SetupRouter(
+	r fiber.Router,
+	mw UserMiddleware,
+	ps profileService,
+) {
+	handle := newHandler(ps)
+
+	r.Get("profile", mw.Auth, handle.get)
+	r.Patch("profile", mw.Auth, handle.update)
+	r.Post("profile/verify", mw.Auth, handle.requestVerification)
+	r.Post("profile/verify/confirm", mw.Auth, handle.confirmVerification)
+}
@@ -0,0 +1,166 @@
+package profiles
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/notify"
+	"github.com/dkmelnik/go-musthave-diploma/internal/profiles/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+const (
+	codeDigits = 6
+	// resendCooldown spaces out codes sent to the same channel.
+	resendCooldown = time.Minute
+)
+
+type (
+	profileRepository interface {
+		FindProfile(ctx context.Context, id models.ModelID) (*models.User, error)
+		UpdateProfile(ctx context.Context, u *models.User) error
+		SaveVerification(ctx context.Context, v *models.ContactVerification) error
+		LastVerificationAt(ctx context.Context, userID models.ModelID, channel string) (time.Time, error)
+		ConfirmVerification(ctx context.Context, userID models.ModelID, channel, codeHash string, maxAttempts int) error
+	}
+	Service struct {
+		codeTTL           time.Duration
+		maxAttempts       int
+		profileRepository profileRepository
+		notifier          notify.Notifier
+	}
+)
+
+func NewService(codeTTL time.Duration, maxAttempts int, pr profileRepository, notifier notify.Notifier) *Service {
+	return &Service{codeTTL, maxAttempts, pr, notifier}
+}
+
+func (s *Service) Get(ctx context.Context, userID models.ModelID) (dto.ProfileResponse, error) {
+	u, err := s.profileRepository.FindProfile(ctx, userID)
+	if err != nil {
+		return dto.ProfileResponse{}, err
+	}
+
+	return profile(u), nil
+}
+
+func (s *Service) Update(ctx context.Context, userID models.ModelID, d dto.ProfilePatch) (dto.ProfileResponse, error) {
+	u, err := s.profileRepository.FindProfile(ctx, userID)
+	if err != nil {
+		return dto.ProfileResponse{}, err
+	}
+
+	if d.Email != nil && *d.Email != u.Email {
+		u.Email, u.EmailVerifiedAt.Valid = *d.Email, false
+	}
+	if d.Phone != nil && *d.Phone != u.Phone {
+		u.Phone, u.PhoneVerifiedAt.Valid = *d.Phone, false
+	}
+	if d.DisplayName != nil {
+		u.DisplayName = *d.DisplayName
+	}
+	if d.Locale != nil {
+		u.Locale = *d.Locale
+	}
+	if d.TimeZone != nil {
+		u.TimeZone = *d.TimeZone
+	}
+
+	if err = s.profileRepository.UpdateProfile(ctx, u); err != nil {
+		return dto.ProfileResponse{}, err
+	}
+
+	return profile(u), nil
+}
+
+// RequestVerification sends a code to the email or phone of the profile.
+// Contacts that are missing yield apperrors.ErrNoRequiredValue, verified
+// ones apperrors.ErrIsExist.
+func (s *Service) RequestVerification(ctx context.Context, userID models.ModelID, d dto.VerifyPayload) error {
+	u, err := s.profileRepository.FindProfile(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	target, verified, channel := u.Email, u.EmailVerifiedAt.Valid, notify.ChannelEmail
+	if d.Channel == models.ChannelPhone {
+		target, verified, channel = u.Phone, u.PhoneVerifiedAt.Valid, notify.ChannelSMS
+	}
+	if target == "" {
+		return apperrors.ErrNoRequiredValue
+	}
+	if verified {
+		return apperrors.ErrIsExist
+	}
+
+	last, err := s.profileRepository.LastVerificationAt(ctx, userID, d.Channel)
+	if err != nil {
+		return err
+	}
+	if wait := time.Until(last.Add(resendCooldown)); wait > 0 {
+		return &apperrors.RetryError{Err: apperrors.ErrTooManyAttempts, RetryAfter: wait}
+	}
+
+	code, err := newCode()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(s.codeTTL)
+
+	err = s.profileRepository.SaveVerification(ctx, &models.ContactVerification{
+		UserID:    userID,
+		Channel:   d.Channel,
+		Target:    target,
+		CodeHash:  utils.HashSecret(code),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.notifier.Notify(ctx, notify.Message{
+		UserID:  userID,
+		Channel: channel,
+		To:      target,
+		Subject: "Verification code",
+		Body: fmt.Sprintf(
+			"Your verification code is %s. It expires at %s.",
+			code, expiresAt.Format(time.RFC1123),
+		),
+	})
+}
+
+func (s *Service) ConfirmVerification(ctx context.Context, userID models.ModelID, d dto.ConfirmPayload) error {
+	return s.profileRepository.ConfirmVerification(ctx, userID, d.Channel, utils.HashSecret(d.Code), s.maxAttempts)
+}
+
+func newCode() (string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < codeDigits; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", codeDigits, n), nil
+}
+
+func profile(u *models.User) dto.ProfileResponse {
+	return dto.ProfileResponse{
+		Login:         u.Login,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerifiedAt.Valid,
+		Phone:         u.Phone,
+		PhoneVerified: u.PhoneVerifiedAt.Valid,
+		DisplayName:   u.DisplayName,
+		Locale:        u.Locale,
+		TimeZone:      u.TimeZone,
+	}
+}
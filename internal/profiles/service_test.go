@@ -0,0 +1,324 @@
+package profiles
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/notify"
+	"github.com/dkmelnik/go-musthave-diploma/internal/profiles/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/profiles/mocks"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+// storedProfile plays the users and contact_verifications tables by the
+// contract of Repository: the newest live code of a channel is checked, a
+// wrong guess counts and the contact must still be the one the code was
+// sent to.
+type storedProfile struct {
+	user          models.User
+	verifications []models.ContactVerification
+}
+
+func (p *storedProfile) expect(repo *mocks.MockprofileRepository) {
+	repo.EXPECT().FindProfile(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ models.ModelID) (*models.User, error) {
+			u := p.user
+			return &u, nil
+		}).AnyTimes()
+	repo.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, u *models.User) error {
+			p.user = *u
+			return nil
+		}).AnyTimes()
+	repo.EXPECT().SaveVerification(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, v *models.ContactVerification) error {
+			v.CreatedAt = time.Now()
+			p.verifications = append(p.verifications, *v)
+			return nil
+		}).AnyTimes()
+	repo.EXPECT().LastVerificationAt(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ models.ModelID, channel string) (time.Time, error) {
+			var last time.Time
+			for _, v := range p.verifications {
+				if v.Channel == channel && v.CreatedAt.After(last) {
+					last = v.CreatedAt
+				}
+			}
+			return last, nil
+		}).AnyTimes()
+	repo.EXPECT().ConfirmVerification(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ models.ModelID, channel, codeHash string, maxAttempts int) error {
+			var v *models.ContactVerification
+			for i := range p.verifications {
+				c := &p.verifications[i]
+				if c.Channel == channel && !c.ConsumedAt.Valid && c.ExpiresAt.After(time.Now()) && c.Attempts < maxAttempts {
+					v = c
+				}
+			}
+			if v == nil {
+				return apperrors.ErrInvalidToken
+			}
+			if v.CodeHash != codeHash {
+				v.Attempts++
+				return apperrors.ErrInvalidToken
+			}
+			v.ConsumedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+			contact, verifiedAt := &p.user.Email, &p.user.EmailVerifiedAt
+			if channel == models.ChannelPhone {
+				contact, verifiedAt = &p.user.Phone, &p.user.PhoneVerifiedAt
+			}
+			if *contact != v.Target {
+				return apperrors.ErrInvalidToken
+			}
+			*verifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			return nil
+		}).AnyTimes()
+}
+
+// sentCodes keeps the messages handed to the notifier.
+type sentCodes struct {
+	messages []notify.Message
+}
+
+func (n *sentCodes) Notify(_ context.Context, m notify.Message) error {
+	n.messages = append(n.messages, m)
+	return nil
+}
+
+var codePattern = regexp.MustCompile(`\b[0-9]{6}\b`)
+
+// last returns the code of the last message sent.
+func (n *sentCodes) last(t *testing.T) string {
+	require.NotEmpty(t, n.messages)
+	code := codePattern.FindString(n.messages[len(n.messages)-1].Body)
+	require.NotEmpty(t, code)
+	return code
+}
+
+func newStoredService(t *testing.T, user models.User) (*Service, *storedProfile, *sentCodes) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	stored := &storedProfile{user: user}
+	repo := mocks.NewMockprofileRepository(ctrl)
+	stored.expect(repo)
+	sent := &sentCodes{}
+
+	return NewService(10*time.Minute, 3, repo, sent), stored, sent
+}
+
+// wrongCode differs from code in every digit.
+func wrongCode(code string) string {
+	b := []byte(code)
+	for i := range b {
+		b[i] = '0' + (b[i]-'0'+1)%10
+	}
+	return string(b)
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestService_Update(t *testing.T) {
+	verified := sql.NullTime{Time: time.Now(), Valid: true}
+	s, stored, _ := newStoredService(t, models.User{
+		ID:              "user",
+		Login:           "alice",
+		Email:           "alice@example.com",
+		EmailVerifiedAt: verified,
+		Phone:           "+15551234567",
+		PhoneVerifiedAt: verified,
+		DisplayName:     "Alice",
+	})
+
+	out, err := s.Update(context.Background(), "user", dto.ProfilePatch{Email: ptr("alice@example.com"), Locale: ptr("en-US")})
+	require.NoError(t, err)
+	assert.True(t, out.EmailVerified, "the same email keeps its verification")
+	assert.Equal(t, "Alice", out.DisplayName, "absent fields are kept")
+	assert.Equal(t, "en-US", out.Locale)
+
+	out, err = s.Update(context.Background(), "user", dto.ProfilePatch{Email: ptr("alice@example.org"), DisplayName: ptr("")})
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.org", out.Email)
+	assert.False(t, out.EmailVerified, "a new email drops the verification")
+	assert.True(t, out.PhoneVerified)
+	assert.Empty(t, out.DisplayName)
+	assert.Equal(t, "alice@example.org", stored.user.Email)
+	assert.False(t, stored.user.EmailVerifiedAt.Valid)
+}
+
+func TestService_UpdateError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockprofileRepository(ctrl)
+	repo.EXPECT().FindProfile(gomock.Any(), models.ModelID("user")).Return(&models.User{ID: "user"}, nil)
+	repo.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).Return(errors.New("db is down"))
+
+	_, err := NewService(time.Minute, 3, repo, &sentCodes{}).Update(context.Background(), "user", dto.ProfilePatch{Locale: ptr("en")})
+	assert.Error(t, err)
+}
+
+func TestService_RequestVerification(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    models.User
+		channel string
+		want    error
+	}{
+		{
+			name:    "positive test #1, email code sent",
+			user:    models.User{ID: "user", Email: "alice@example.com"},
+			channel: models.ChannelEmail,
+		},
+		{
+			name:    "positive test #2, phone code sent",
+			user:    models.User{ID: "user", Phone: "+15551234567"},
+			channel: models.ChannelPhone,
+		},
+		{
+			name:    "negative test #3, no email",
+			user:    models.User{ID: "user"},
+			channel: models.ChannelEmail,
+			want:    apperrors.ErrNoRequiredValue,
+		},
+		{
+			name:    "negative test #4, verified already",
+			user:    models.User{ID: "user", Email: "alice@example.com", EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true}},
+			channel: models.ChannelEmail,
+			want:    apperrors.ErrIsExist,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, stored, sent := newStoredService(t, tt.user)
+
+			err := s.RequestVerification(context.Background(), "user", dto.VerifyPayload{Channel: tt.channel})
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+				assert.Empty(t, sent.messages)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Len(t, stored.verifications, 1)
+			require.Len(t, sent.messages, 1)
+			assert.Equal(t, stored.verifications[0].Target, sent.messages[0].To)
+			assert.Equal(t, utils.HashSecret(sent.last(t)), stored.verifications[0].CodeHash, "only the hash is stored")
+		})
+	}
+}
+
+func TestService_RequestVerificationThrottled(t *testing.T) {
+	s, stored, sent := newStoredService(t, models.User{ID: "user", Email: "alice@example.com", Phone: "+15551234567"})
+
+	require.NoError(t, s.RequestVerification(context.Background(), "user", dto.VerifyPayload{Channel: models.ChannelEmail}))
+
+	err := s.RequestVerification(context.Background(), "user", dto.VerifyPayload{Channel: models.ChannelEmail})
+	assert.ErrorIs(t, err, apperrors.ErrTooManyAttempts)
+	var retry *apperrors.RetryError
+	require.ErrorAs(t, err, &retry)
+	assert.InDelta(t, resendCooldown.Seconds(), retry.RetryAfter.Seconds(), 5)
+
+	require.NoError(t, s.RequestVerification(context.Background(), "user", dto.VerifyPayload{Channel: models.ChannelPhone}),
+		"the cooldown is per channel")
+	assert.Len(t, sent.messages, 2)
+
+	// once the cooldown has passed another code may be sent
+	stored.verifications[0].CreatedAt = time.Now().Add(-resendCooldown)
+	require.NoError(t, s.RequestVerification(context.Background(), "user", dto.VerifyPayload{Channel: models.ChannelEmail}))
+	assert.Len(t, sent.messages, 3)
+}
+
+func TestService_ConfirmVerification(t *testing.T) {
+	ctx := context.Background()
+	email := dto.VerifyPayload{Channel: models.ChannelEmail}
+
+	t.Run("positive test #1, right code verifies the contact", func(t *testing.T) {
+		s, stored, sent := newStoredService(t, models.User{ID: "user", Email: "alice@example.com"})
+		require.NoError(t, s.RequestVerification(ctx, "user", email))
+
+		require.NoError(t, s.ConfirmVerification(ctx, "user", dto.ConfirmPayload{Channel: models.ChannelEmail, Code: sent.last(t)}))
+		assert.True(t, stored.user.EmailVerifiedAt.Valid)
+
+		err := s.ConfirmVerification(ctx, "user", dto.ConfirmPayload{Channel: models.ChannelEmail, Code: sent.last(t)})
+		assert.ErrorIs(t, err, apperrors.ErrInvalidToken, "a code is spent once")
+	})
+
+	t.Run("negative test #2, wrong code", func(t *testing.T) {
+		s, stored, sent := newStoredService(t, models.User{ID: "user", Email: "alice@example.com"})
+		require.NoError(t, s.RequestVerification(ctx, "user", email))
+
+		err := s.ConfirmVerification(ctx, "user", dto.ConfirmPayload{Channel: models.ChannelEmail, Code: wrongCode(sent.last(t))})
+		assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+		assert.False(t, stored.user.EmailVerifiedAt.Valid)
+		assert.Equal(t, 1, stored.verifications[0].Attempts)
+
+		require.NoError(t, s.ConfirmVerification(ctx, "user", dto.ConfirmPayload{Channel: models.ChannelEmail, Code: sent.last(t)}),
+			"a wrong guess leaves the code usable")
+	})
+
+	t.Run("negative test #3, attempts exhausted", func(t *testing.T) {
+		s, stored, sent := newStoredService(t, models.User{ID: "user", Email: "alice@example.com"})
+		require.NoError(t, s.RequestVerification(ctx, "user", email))
+		code := sent.last(t)
+
+		for i := 0; i < 3; i++ {
+			err := s.ConfirmVerification(ctx, "user", dto.ConfirmPayload{Channel: models.ChannelEmail, Code: wrongCode(code)})
+			require.ErrorIs(t, err, apperrors.ErrInvalidToken)
+		}
+
+		err := s.ConfirmVerification(ctx, "user", dto.ConfirmPayload{Channel: models.ChannelEmail, Code: code})
+		assert.ErrorIs(t, err, apperrors.ErrInvalidToken, "the right code is dead after maxAttempts guesses")
+		assert.False(t, stored.user.EmailVerifiedAt.Valid)
+	})
+
+	t.Run("negative test #4, expired code", func(t *testing.T) {
+		s, stored, sent := newStoredService(t, models.User{ID: "user", Email: "alice@example.com"})
+		require.NoError(t, s.RequestVerification(ctx, "user", email))
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), stored.verifications[0].ExpiresAt, 5*time.Second)
+		stored.verifications[0].ExpiresAt = time.Now().Add(-time.Second)
+
+		err := s.ConfirmVerification(ctx, "user", dto.ConfirmPayload{Channel: models.ChannelEmail, Code: sent.last(t)})
+		assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+		assert.False(t, stored.user.EmailVerifiedAt.Valid)
+	})
+
+	t.Run("negative test #5, contact changed since sending", func(t *testing.T) {
+		s, stored, sent := newStoredService(t, models.User{ID: "user", Email: "alice@example.com"})
+		require.NoError(t, s.RequestVerification(ctx, "user", email))
+		code := sent.last(t)
+
+		_, err := s.Update(ctx, "user", dto.ProfilePatch{Email: ptr("mallory@example.com")})
+		require.NoError(t, err)
+
+		err = s.ConfirmVerification(ctx, "user", dto.ConfirmPayload{Channel: models.ChannelEmail, Code: code})
+		assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+		assert.False(t, stored.user.EmailVerifiedAt.Valid, "the new address is not verified by the old code")
+	})
+
+	t.Run("negative test #6, contact verified by another account", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockprofileRepository(ctrl)
+		repo.EXPECT().ConfirmVerification(gomock.Any(), models.ModelID("user"), models.ChannelEmail, gomock.Any(), 3).
+			Return(apperrors.ErrIsExist)
+
+		err := NewService(time.Minute, 3, repo, &sentCodes{}).
+			ConfirmVerification(ctx, "user", dto.ConfirmPayload{Channel: models.ChannelEmail, Code: "123456"})
+		assert.ErrorIs(t, err, apperrors.ErrIsExist)
+	})
+}
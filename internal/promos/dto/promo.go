@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var codePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,64}$`)
+
+type (
+	CreatePromoPayload struct {
+		Code           string     `json:"code"`
+		Amount         float64    `json:"amount"`
+		MaxRedemptions int        `json:"max_redemptions"`
+		PerUserLimit   int        `json:"per_user_limit"`
+		StartsAt       *time.Time `json:"starts_at"`
+		EndsAt         *time.Time `json:"ends_at"`
+	}
+	RedeemPayload struct {
+		Code string `json:"code"`
+	}
+	PromoResponse struct {
+		Code           string     `json:"code"`
+		Amount         float64    `json:"amount"`
+		MaxRedemptions int        `json:"max_redemptions"`
+		PerUserLimit   int        `json:"per_user_limit"`
+		Redemptions    int        `json:"redemptions"`
+		StartsAt       time.Time  `json:"starts_at"`
+		EndsAt         *time.Time `json:"ends_at,omitempty"`
+		CreatedBy      string     `json:"created_by"`
+	}
+)
+
+// NormalizeCode makes codes case-insensitive.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (p *CreatePromoPayload) Validate() error {
+	p.Code = NormalizeCode(p.Code)
+
+	if !codePattern.MatchString(p.Code) {
+		return fmt.Errorf("code must be 3-64 characters of A-Z, 0-9, '_' or '-'")
+	}
+
+	if p.Amount <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+
+	if p.MaxRedemptions < 0 {
+		return fmt.Errorf("max_redemptions must not be negative")
+	}
+
+	if p.PerUserLimit == 0 {
+		p.PerUserLimit = 1
+	}
+	if p.PerUserLimit < 0 {
+		return fmt.Errorf("per_user_limit must be positive")
+	}
+
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return fmt.Errorf("ends_at must be after starts_at")
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePromoPayload_Validate(t *testing.T) {
+	starts := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	ends := starts.Add(24 * time.Hour)
+
+	tests := []struct {
+		name         string
+		payload      CreatePromoPayload
+		wantErr      bool
+		wantCode     string
+		wantPerLimit int
+	}{
+		{
+			name:         "positive test #1, code normalized, per user limit defaults to one",
+			payload:      CreatePromoPayload{Code: " summer-24 ", Amount: 100},
+			wantCode:     "SUMMER-24",
+			wantPerLimit: 1,
+		},
+		{
+			name:         "positive test #2, validity window and limits",
+			payload:      CreatePromoPayload{Code: "WELCOME", Amount: 50, MaxRedemptions: 10, PerUserLimit: 2, StartsAt: &starts, EndsAt: &ends},
+			wantCode:     "WELCOME",
+			wantPerLimit: 2,
+		},
+		{
+			name:    "negative test #3, code too short",
+			payload: CreatePromoPayload{Code: "AB", Amount: 100},
+			wantErr: true,
+		},
+		{
+			name:    "negative test #4, code with spaces inside",
+			payload: CreatePromoPayload{Code: "SUMMER 24", Amount: 100},
+			wantErr: true,
+		},
+		{
+			name:    "negative test #5, amount not positive",
+			payload: CreatePromoPayload{Code: "SUMMER", Amount: 0},
+			wantErr: true,
+		},
+		{
+			name:    "negative test #6, negative max redemptions",
+			payload: CreatePromoPayload{Code: "SUMMER", Amount: 100, MaxRedemptions: -1},
+			wantErr: true,
+		},
+		{
+			name:    "negative test #7, negative per user limit",
+			payload: CreatePromoPayload{Code: "SUMMER", Amount: 100, PerUserLimit: -1},
+			wantErr: true,
+		},
+		{
+			name:    "negative test #8, window ends before it starts",
+			payload: CreatePromoPayload{Code: "SUMMER", Amount: 100, StartsAt: &ends, EndsAt: &starts},
+			wantErr: true,
+		},
+		{
+			name:    "negative test #9, empty window",
+			payload: CreatePromoPayload{Code: "SUMMER", Amount: 100, StartsAt: &starts, EndsAt: &starts},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.payload.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCode, tt.payload.Code)
+			assert.Equal(t, tt.wantPerLimit, tt.payload.PerUserLimit)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package promos
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/promos/dto"
+)
+
+type (
+	promoService interface {
+		Create(ctx context.Context, operator string, d dto.CreatePromoPayload) error
+		GetAll(ctx context.Context) ([]dto.PromoResponse, error)
+		Redeem(ctx context.Context, userID models.ModelID, d dto.RedeemPayload) error
+	}
+	handler struct {
+		service promoService
+	}
+)
+
+func newHandler(ps promoService) *handler {
+	return &handler{ps}
+}
+
+func (h *handler) redeem(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.SendStatus(fiber.StatusUnauthorized)
+	}
+
+	var body dto.RedeemPayload
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	}
+
+	switch err := h.service.Redeem(c.Context(), models.ModelID(userID), body); {
+	case err == nil:
+		return c.SendStatus(fiber.StatusOK)
+	case errors.Is(err, apperrors.ErrNotFound):
+		return c.SendStatus(fiber.StatusNotFound)
+	case errors.Is(err, apperrors.ErrInactive):
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(err.Error())
+	case errors.Is(err, apperrors.ErrLimitExceeded):
+		return c.Status(fiber.StatusConflict).SendString(err.Error())
+	default:
+		logger.Log.Error("promos:handler:redeem", "StatusInternalServerError", err)
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+}
+
+func (h *handler) create(c *fiber.Ctx) error {
+	operator, _ := c.Locals("user_id").(string)
+
+	var body dto.CreatePromoPayload
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	}
+
+	if err := body.Validate(); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(err.Error())
+	}
+
+	switch err := h.service.Create(c.Context(), operator, body); {
+	case err == nil:
+		logger.Log.Info("promos:handler:create", "operator", operator, "code", body.Code)
+		return c.SendStatus(fiber.StatusCreated)
+	case errors.Is(err, apperrors.ErrIsExist):
+		return c.SendStatus(fiber.StatusConflict)
+	default:
+		logger.Log.Error("promos:handler:create", "StatusInternalServerError", err)
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+}
+
+func (h *handler) getAll(c *fiber.Ctx) error {
+	switch out, err := h.service.GetAll(c.Context()); {
+	case errors.Is(err, apperrors.ErrNoInformationAnswer):
+		return c.SendStatus(fiber.StatusNoContent)
+	case err == nil:
+		return c.Status(fiber.StatusOK).JSON(out)
+	default:
+		logger.Log.Error("promos:handler:getAll", "StatusInternalServerError", err)
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+}
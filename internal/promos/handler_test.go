@@ -0,0 +1,226 @@
+package promos
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/promos/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/promos/mocks"
+)
+
+func Test_redeem(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		userID  string
+		service bool
+		err     error
+		code    int
+	}{
+		{
+			name: "negative test #1, unauthenticated",
+			body: `{"code":"SUMMER"}`,
+			code: http.StatusUnauthorized,
+		},
+		{
+			name:   "negative test #2, bad entity",
+			body:   `{"code":`,
+			userID: "user",
+			code:   http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "negative test #3, unknown code",
+			body:    `{"code":"SUMMER"}`,
+			userID:  "user",
+			service: true,
+			err:     apperrors.ErrNotFound,
+			code:    http.StatusNotFound,
+		},
+		{
+			name:    "negative test #4, inactive code",
+			body:    `{"code":"SUMMER"}`,
+			userID:  "user",
+			service: true,
+			err:     apperrors.ErrInactive,
+			code:    http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "negative test #5, limit reached",
+			body:    `{"code":"SUMMER"}`,
+			userID:  "user",
+			service: true,
+			err:     apperrors.ErrLimitExceeded,
+			code:    http.StatusConflict,
+		},
+		{
+			name:    "negative test #6, unknown service error",
+			body:    `{"code":"SUMMER"}`,
+			userID:  "user",
+			service: true,
+			err:     errors.New("db is down"),
+			code:    http.StatusInternalServerError,
+		},
+		{
+			name:    "positive test #7, redeemed",
+			body:    `{"code":"SUMMER"}`,
+			userID:  "user",
+			service: true,
+			code:    http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ps := mocks.NewMockpromoService(ctrl)
+			if tt.service {
+				ps.EXPECT().Redeem(gomock.Any(), models.ModelID("user"), dto.RedeemPayload{Code: "SUMMER"}).Return(tt.err)
+			}
+
+			app := fiber.New()
+			h := newHandler(ps)
+			app.Post("/", func(c *fiber.Ctx) error {
+				if tt.userID != "" {
+					c.Locals("user_id", tt.userID)
+				}
+				return c.Next()
+			}, h.redeem)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+
+func Test_create(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		service bool
+		err     error
+		code    int
+	}{
+		{
+			name: "negative test #1, bad entity",
+			body: `{"code":`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "negative test #2, invalid payload",
+			body: `{"code":"SUMMER","amount":0}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "negative test #3, duplicate code",
+			body:    `{"code":"summer","amount":100}`,
+			service: true,
+			err:     apperrors.ErrIsExist,
+			code:    http.StatusConflict,
+		},
+		{
+			name:    "negative test #4, unknown service error",
+			body:    `{"code":"summer","amount":100}`,
+			service: true,
+			err:     errors.New("db is down"),
+			code:    http.StatusInternalServerError,
+		},
+		{
+			name:    "positive test #5, created",
+			body:    `{"code":"summer","amount":100}`,
+			service: true,
+			code:    http.StatusCreated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ps := mocks.NewMockpromoService(ctrl)
+			if tt.service {
+				ps.EXPECT().Create(gomock.Any(), "admin", dto.CreatePromoPayload{Code: "SUMMER", Amount: 100, PerUserLimit: 1}).Return(tt.err)
+			}
+
+			app := fiber.New()
+			h := newHandler(ps)
+			app.Post("/", func(c *fiber.Ctx) error {
+				c.Locals("user_id", "admin")
+				return c.Next()
+			}, h.create)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+
+func Test_getAll(t *testing.T) {
+	tests := []struct {
+		name string
+		out  []dto.PromoResponse
+		err  error
+		code int
+	}{
+		{
+			name: "positive test #1, promos listed",
+			out:  []dto.PromoResponse{{Code: "SUMMER", Amount: 100}},
+			code: http.StatusOK,
+		},
+		{
+			name: "positive test #2, no promos",
+			err:  apperrors.ErrNoInformationAnswer,
+			code: http.StatusNoContent,
+		},
+		{
+			name: "negative test #3, unknown service error",
+			err:  errors.New("db is down"),
+			code: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ps := mocks.NewMockpromoService(ctrl)
+			ps.EXPECT().GetAll(gomock.Any()).Return(tt.out, tt.err)
+
+			app := fiber.New()
+			app.Get("/", newHandler(ps).getAll)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil), 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
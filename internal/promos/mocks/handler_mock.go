@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	dto "github.com/dkmelnik/go-musthave-diploma/internal/promos/dto"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockpromoService is a mock of promoService interface.
+type MockpromoService struct {
+	ctrl     *gomock.Controller
+	recorder *MockpromoServiceMockRecorder
+}
+
+// MockpromoServiceMockRecorder is the mock recorder for MockpromoService.
+type MockpromoServiceMockRecorder struct {
+	mock *MockpromoService
+}
+
+// NewMockpromoService creates a new mock instance.
+func NewMockpromoService(ctrl *gomock.Controller) *MockpromoService {
+	mock := &MockpromoService{ctrl: ctrl}
+	mock.recorder = &MockpromoServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpromoService) EXPECT() *MockpromoServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockpromoService) Create(ctx context.Context, operator string, d dto.CreatePromoPayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, operator, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockpromoServiceMockRecorder) Create(ctx, operator, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockpromoService)(nil).Create), ctx, operator, d)
+}
+
+// GetAll mocks base method.
+func (m *MockpromoService) GetAll(ctx context.Context) ([]dto.PromoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]dto.PromoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockpromoServiceMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockpromoService)(nil).GetAll), ctx)
+}
+
+// Redeem mocks base method.
+func (m *MockpromoService) Redeem(ctx context.Context, userID models.ModelID, d dto.RedeemPayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeem", ctx, userID, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Redeem indicates an expected call of Redeem.
+func (mr *MockpromoServiceMockRecorder) Redeem(ctx, userID, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeem", reflect.TypeOf((*MockpromoService)(nil).Redeem), ctx, userID, d)
+}
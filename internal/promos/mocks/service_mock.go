@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockpromoRepository is a mock of promoRepository interface.
+type MockpromoRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockpromoRepositoryMockRecorder
+}
+
+// MockpromoRepositoryMockRecorder is the mock recorder for MockpromoRepository.
+type MockpromoRepositoryMockRecorder struct {
+	mock *MockpromoRepository
+}
+
+// NewMockpromoRepository creates a new mock instance.
+func NewMockpromoRepository(ctrl *gomock.Controller) *MockpromoRepository {
+	mock := &MockpromoRepository{ctrl: ctrl}
+	mock.recorder = &MockpromoRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpromoRepository) EXPECT() *MockpromoRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Find mocks base method.
+func (m *MockpromoRepository) Find(ctx context.Context) ([]*models.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx)
+	ret0, _ := ret[0].([]*models.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockpromoRepositoryMockRecorder) Find(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockpromoRepository)(nil).Find), ctx)
+}
+
+// IsEntryByCode mocks base method.
+func (m *MockpromoRepository) IsEntryByCode(ctx context.Context, code string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEntryByCode", ctx, code)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsEntryByCode indicates an expected call of IsEntryByCode.
+func (mr *MockpromoRepositoryMockRecorder) IsEntryByCode(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEntryByCode", reflect.TypeOf((*MockpromoRepository)(nil).IsEntryByCode), ctx, code)
+}
+
+// Redeem mocks base method.
+func (m *MockpromoRepository) Redeem(ctx context.Context, code string, userID models.ModelID, check func(*models.PromoCode, int) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeem", ctx, code, userID, check)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Redeem indicates an expected call of Redeem.
+func (mr *MockpromoRepositoryMockRecorder) Redeem(ctx, code, userID, check interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeem", reflect.TypeOf((*MockpromoRepository)(nil).Redeem), ctx, code, userID, check)
+}
+
+// Save mocks base method.
+func (m *MockpromoRepository) Save(ctx context.Context, p *models.PromoCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockpromoRepositoryMockRecorder) Save(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockpromoRepository)(nil).Save), ctx, p)
+}
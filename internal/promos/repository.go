@@ -0,0 +1,171 @@
+package promos
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/db/pg"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+// Save stores p. A code taken by a concurrent Save yields apperrors.ErrIsExist.
+func (r *Repository) Save(ctx context.Context, p *models.PromoCode) error {
+	query := `
+		INSERT INTO promo_codes (code, amount, max_redemptions, per_user_limit, starts_at, ends_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		p.Code,
+		p.Amount,
+		p.MaxRedemptions,
+		p.PerUserLimit,
+		p.StartsAt,
+		p.EndsAt,
+		p.CreatedBy,
+	)
+	if pg.IsUniqueViolation(err) {
+		return apperrors.ErrIsExist
+	}
+	return err
+}
+
+func (r *Repository) IsEntryByCode(ctx context.Context, code string) (bool, error) {
+	var id string
+	query := `
+		SELECT id FROM promo_codes WHERE code = $1 LIMIT 1
+	`
+	err := r.db.QueryRowContext(ctx, query, code).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *Repository) Find(ctx context.Context) ([]*models.PromoCode, error) {
+	query := `
+		SELECT p.id, p.code, p.amount, p.max_redemptions, p.per_user_limit, p.starts_at, p.ends_at,
+			p.created_by, p.created_at, COUNT(pr.id)
+		FROM promo_codes p
+		LEFT JOIN promo_redemptions pr ON pr.promo_id = p.id
+		GROUP BY p.id
+		ORDER BY p.created_at
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promos []*models.PromoCode
+	for rows.Next() {
+		var p models.PromoCode
+		if err := rows.Scan(
+			&p.ID,
+			&p.Code,
+			&p.Amount,
+			&p.MaxRedemptions,
+			&p.PerUserLimit,
+			&p.StartsAt,
+			&p.EndsAt,
+			&p.CreatedBy,
+			&p.CreatedAt,
+			&p.Redemptions,
+		); err != nil {
+			return nil, err
+		}
+		promos = append(promos, &p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return promos, nil
+}
+
+// Redeem credits the promo amount to the user. The promo row stays locked
+// while check runs, so concurrent redemptions of one code are serialized and
+// see each other's counts.
+func (r *Repository) Redeem(
+	ctx context.Context,
+	code string,
+	userID models.ModelID,
+	check func(p *models.PromoCode, byUser int) error,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var p models.PromoCode
+	promoQuery := `
+		SELECT id, code, amount, max_redemptions, per_user_limit, starts_at, ends_at,
+			starts_at <= NOW() AND (ends_at IS NULL OR ends_at > NOW())
+		FROM promo_codes
+		WHERE code = $1
+		FOR UPDATE
+	`
+	err = tx.QueryRowContext(ctx, promoQuery, code).Scan(
+		&p.ID,
+		&p.Code,
+		&p.Amount,
+		&p.MaxRedemptions,
+		&p.PerUserLimit,
+		&p.StartsAt,
+		&p.EndsAt,
+		&p.Active,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.ErrNotFound
+		}
+		return err
+	}
+
+	var byUser int
+	countQuery := `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE user_id = $2)
+		FROM promo_redemptions
+		WHERE promo_id = $1
+	`
+	if err = tx.QueryRowContext(ctx, countQuery, p.ID, userID).Scan(&p.Redemptions, &byUser); err != nil {
+		return err
+	}
+
+	if err = check(&p, byUser); err != nil {
+		return err
+	}
+
+	redemptionQuery := `
+		INSERT INTO promo_redemptions (promo_id, user_id) VALUES ($1, $2)
+	`
+	if _, err = tx.ExecContext(ctx, redemptionQuery, p.ID, userID); err != nil {
+		return err
+	}
+
+	entryQuery := `
+		INSERT INTO balance_entries (user_id, kind, amount, reference)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err = tx.ExecContext(ctx, entryQuery, userID, models.EntryPromo, p.Amount, p.Code); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,29 @@
+package promos
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type UserMiddleware interface {
+	Auth(c *fiber.Ctx) error
+	RequireRole(roles ...string) fiber.Handler
+}
+
+func SetupRouter(
+	r fiber.Router,
+	ar fiber.Router,
+	mw UserMiddleware,
+	pr promoRepository,
+) {
+	service := NewService(pr)
+	handle := newHandler(service)
+
+	admins := mw.RequireRole(models.RoleAdmin)
+
+	r.Post("promo", mw.Auth, handle.redeem)
+
+	ar.Post("promos", mw.Auth, admins, handle.create)
+	ar.Get("promos", mw.Auth, admins, handle.getAll)
+}
@@ -0,0 +1,109 @@
+package promos
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/promos/dto"
+)
+
+type (
+	promoRepository interface {
+		Save(ctx context.Context, p *models.PromoCode) error
+		IsEntryByCode(ctx context.Context, code string) (bool, error)
+		Find(ctx context.Context) ([]*models.PromoCode, error)
+		Redeem(
+			ctx context.Context,
+			code string,
+			userID models.ModelID,
+			check func(p *models.PromoCode, byUser int) error,
+		) error
+	}
+	Service struct {
+		promoRepository promoRepository
+	}
+)
+
+func NewService(pr promoRepository) *Service {
+	return &Service{pr}
+}
+
+func (s *Service) Create(ctx context.Context, operator string, d dto.CreatePromoPayload) error {
+	exist, err := s.promoRepository.IsEntryByCode(ctx, d.Code)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return apperrors.ErrIsExist
+	}
+
+	p := &models.PromoCode{
+		Code:           d.Code,
+		Amount:         d.Amount,
+		MaxRedemptions: d.MaxRedemptions,
+		PerUserLimit:   d.PerUserLimit,
+		StartsAt:       time.Now(),
+		CreatedBy:      operator,
+	}
+	if d.StartsAt != nil {
+		p.StartsAt = *d.StartsAt
+	}
+	if d.EndsAt != nil {
+		p.EndsAt = sql.NullTime{Time: *d.EndsAt, Valid: true}
+	}
+
+	return s.promoRepository.Save(ctx, p)
+}
+
+func (s *Service) GetAll(ctx context.Context) ([]dto.PromoResponse, error) {
+	promos, err := s.promoRepository.Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(promos) == 0 {
+		return nil, apperrors.ErrNoInformationAnswer
+	}
+
+	out := make([]dto.PromoResponse, 0, len(promos))
+
+	for _, v := range promos {
+		d := dto.PromoResponse{
+			Code:           v.Code,
+			Amount:         v.Amount,
+			MaxRedemptions: v.MaxRedemptions,
+			PerUserLimit:   v.PerUserLimit,
+			Redemptions:    v.Redemptions,
+			StartsAt:       v.StartsAt,
+			CreatedBy:      v.CreatedBy,
+		}
+		if v.EndsAt.Valid {
+			d.EndsAt = &v.EndsAt.Time
+		}
+		out = append(out, d)
+	}
+
+	return out, nil
+}
+
+func (s *Service) Redeem(ctx context.Context, userID models.ModelID, d dto.RedeemPayload) error {
+	code := dto.NormalizeCode(d.Code)
+	if code == "" {
+		return apperrors.ErrNotFound
+	}
+
+	return s.promoRepository.Redeem(ctx, code, userID, func(p *models.PromoCode, byUser int) error {
+		if !p.Active {
+			return apperrors.ErrInactive
+		}
+		if p.MaxRedemptions > 0 && p.Redemptions >= p.MaxRedemptions {
+			return apperrors.ErrLimitExceeded
+		}
+		if byUser >= p.PerUserLimit {
+			return apperrors.ErrLimitExceeded
+		}
+		return nil
+	})
+}
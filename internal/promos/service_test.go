@@ -0,0 +1,192 @@
+package promos
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/promos/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/promos/mocks"
+)
+
+func TestService_Redeem(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		code   string
+		promo  models.PromoCode
+		byUser int
+		want   error
+	}{
+		{
+			name:  "positive test #1, redeemed",
+			code:  " summer ",
+			promo: models.PromoCode{Active: true, PerUserLimit: 1},
+		},
+		{
+			name:   "positive test #2, redeemed again within the per user limit",
+			code:   "SUMMER",
+			promo:  models.PromoCode{Active: true, MaxRedemptions: 10, Redemptions: 5, PerUserLimit: 2},
+			byUser: 1,
+		},
+		{
+			name: "negative test #3, not started yet",
+			code: "SUMMER",
+			promo: models.PromoCode{
+				Active:       false,
+				StartsAt:     now.Add(time.Hour),
+				PerUserLimit: 1,
+			},
+			want: apperrors.ErrInactive,
+		},
+		{
+			name: "negative test #4, ended",
+			code: "SUMMER",
+			promo: models.PromoCode{
+				Active:       false,
+				StartsAt:     now.Add(-48 * time.Hour),
+				EndsAt:       sql.NullTime{Time: now.Add(-time.Hour), Valid: true},
+				PerUserLimit: 1,
+			},
+			want: apperrors.ErrInactive,
+		},
+		{
+			name:  "negative test #5, max redemptions reached",
+			code:  "SUMMER",
+			promo: models.PromoCode{Active: true, MaxRedemptions: 10, Redemptions: 10, PerUserLimit: 1},
+			want:  apperrors.ErrLimitExceeded,
+		},
+		{
+			name:   "negative test #6, per user limit reached",
+			code:   "SUMMER",
+			promo:  models.PromoCode{Active: true, Redemptions: 3, PerUserLimit: 2},
+			byUser: 2,
+			want:   apperrors.ErrLimitExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockpromoRepository(ctrl)
+			repo.EXPECT().Redeem(gomock.Any(), "SUMMER", models.ModelID("user"), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ string, _ models.ModelID, check func(*models.PromoCode, int) error) error {
+					return check(&tt.promo, tt.byUser)
+				})
+
+			err := NewService(repo).Redeem(context.Background(), "user", dto.RedeemPayload{Code: tt.code})
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestService_RedeemUnknown(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		repoErr error
+	}{
+		{name: "negative test #1, blank code"},
+		{name: "negative test #2, unknown code", code: "NOPE", repoErr: apperrors.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockpromoRepository(ctrl)
+			if tt.code != "" {
+				repo.EXPECT().Redeem(gomock.Any(), tt.code, models.ModelID("user"), gomock.Any()).Return(tt.repoErr)
+			}
+
+			err := NewService(repo).Redeem(context.Background(), "user", dto.RedeemPayload{Code: tt.code})
+			assert.ErrorIs(t, err, apperrors.ErrNotFound)
+		})
+	}
+}
+
+func TestService_Create(t *testing.T) {
+	starts := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	ends := starts.Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		exists  bool
+		saveErr error
+		want    error
+	}{
+		{name: "positive test #1, created"},
+		{name: "negative test #2, code taken", exists: true, want: apperrors.ErrIsExist},
+		{name: "negative test #3, code taken concurrently", saveErr: apperrors.ErrIsExist, want: apperrors.ErrIsExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockpromoRepository(ctrl)
+			repo.EXPECT().IsEntryByCode(gomock.Any(), "SUMMER").Return(tt.exists, nil)
+			if !tt.exists {
+				repo.EXPECT().Save(gomock.Any(), &models.PromoCode{
+					Code:           "SUMMER",
+					Amount:         100,
+					MaxRedemptions: 10,
+					PerUserLimit:   1,
+					StartsAt:       starts,
+					EndsAt:         sql.NullTime{Time: ends, Valid: true},
+					CreatedBy:      "admin",
+				}).Return(tt.saveErr)
+			}
+
+			err := NewService(repo).Create(context.Background(), "admin", dto.CreatePromoPayload{
+				Code:           "SUMMER",
+				Amount:         100,
+				MaxRedemptions: 10,
+				PerUserLimit:   1,
+				StartsAt:       &starts,
+				EndsAt:         &ends,
+			})
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestService_GetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ends := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	repo := mocks.NewMockpromoRepository(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().Find(gomock.Any()).Return(nil, nil),
+		repo.EXPECT().Find(gomock.Any()).Return([]*models.PromoCode{
+			{Code: "SUMMER", Amount: 100, Redemptions: 3, EndsAt: sql.NullTime{Time: ends, Valid: true}},
+			{Code: "WELCOME", Amount: 50},
+		}, nil),
+		repo.EXPECT().Find(gomock.Any()).Return(nil, errors.New("db is down")),
+	)
+	s := NewService(repo)
+
+	_, err := s.GetAll(context.Background())
+	assert.ErrorIs(t, err, apperrors.ErrNoInformationAnswer)
+
+	out, err := s.GetAll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	assert.Equal(t, 3, out[0].Redemptions)
+	assert.Equal(t, &ends, out[0].EndsAt)
+	assert.Nil(t, out[1].EndsAt)
+
+	_, err = s.GetAll(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+type (
+	ReferralResponse struct {
+		Login        string     `json:"login"`
+		Status       string     `json:"status"`
+		RegisteredAt time.Time  `json:"registered_at"`
+		RewardedAt   *time.Time `json:"rewarded_at,omitempty"`
+	}
+	ReferralsResponse struct {
+		Code          string             `json:"code"`
+		ReferrerBonus float64            `json:"referrer_bonus"`
+		RefereeBonus  float64            `json:"referee_bonus"`
+		Referrals     []ReferralResponse `json:"referrals"`
+	}
+)
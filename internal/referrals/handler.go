@@ -0,0 +1,39 @@
+package referrals
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/referrals/dto"
+)
+
+type (
+	referralService interface {
+		GetReferrals(ctx context.Context, userID models.ModelID) (dto.ReferralsResponse, error)
+	}
+	handler struct {
+		service referralService
+	}
+)
+
+func newHandler(rs referralService) *handler {
+	return &handler{rs}
+}
+
+func (h *handler) getReferrals(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.SendStatus(fiber.StatusUnauthorized)
+	}
+
+	out, err := h.service.GetReferrals(c.Context(), models.ModelID(userID))
+	if err != nil {
+		logger.Log.Error("referrals:handler:getReferrals", "StatusInternalServerError", err)
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(out)
+}
@@ -0,0 +1,83 @@
+package referrals
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/referrals/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/referrals/mocks"
+)
+
+func Test_getReferrals(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  string
+		service bool
+		out     dto.ReferralsResponse
+		err     error
+		code    int
+	}{
+		{
+			name: "negative test #1, unauthenticated",
+			code: http.StatusUnauthorized,
+		},
+		{
+			name:    "negative test #2, unknown service error",
+			userID:  "alice",
+			service: true,
+			err:     errors.New("db is down"),
+			code:    http.StatusInternalServerError,
+		},
+		{
+			name:    "positive test #3, referrals listed",
+			userID:  "alice",
+			service: true,
+			out: dto.ReferralsResponse{
+				Code:      "ALICE1",
+				Referrals: []dto.ReferralResponse{{Login: "bob", Status: "PENDING"}},
+			},
+			code: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rs := mocks.NewMockreferralService(ctrl)
+			if tt.service {
+				rs.EXPECT().GetReferrals(gomock.Any(), models.ModelID("alice")).Return(tt.out, tt.err)
+			}
+
+			app := fiber.New()
+			h := newHandler(rs)
+			app.Get("/", func(c *fiber.Ctx) error {
+				if tt.userID != "" {
+					c.Locals("user_id", tt.userID)
+				}
+				return c.Next()
+			}, h.getReferrals)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil), 100)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+			if tt.code == http.StatusOK {
+				var out dto.ReferralsResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+				assert.Equal(t, "ALICE1", out.Code)
+				assert.Equal(t, "bob", out.Referrals[0].Login)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	dto "github.com/dkmelnik/go-musthave-diploma/internal/referrals/dto"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockreferralService is a mock of referralService interface.
+type MockreferralService struct {
+	ctrl     *gomock.Controller
+	recorder *MockreferralServiceMockRecorder
+}
+
+// MockreferralServiceMockRecorder is the mock recorder for MockreferralService.
+type MockreferralServiceMockRecorder struct {
+	mock *MockreferralService
+}
+
+// NewMockreferralService creates a new mock instance.
+func NewMockreferralService(ctrl *gomock.Controller) *MockreferralService {
+	mock := &MockreferralService{ctrl: ctrl}
+	mock.recorder = &MockreferralServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockreferralService) EXPECT() *MockreferralServiceMockRecorder {
+	return m.recorder
+}
+
+// GetReferrals mocks base method.
+func (m *MockreferralService) GetReferrals(ctx context.Context, userID models.ModelID) (dto.ReferralsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReferrals", ctx, userID)
+	ret0, _ := ret[0].(dto.ReferralsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReferrals indicates an expected call of GetReferrals.
+func (mr *MockreferralServiceMockRecorder) GetReferrals(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReferrals", reflect.TypeOf((*MockreferralService)(nil).GetReferrals), ctx, userID)
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockreferralRepository is a mock of referralRepository interface.
+type MockreferralRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockreferralRepositoryMockRecorder
+}
+
+// MockreferralRepositoryMockRecorder is the mock recorder for MockreferralRepository.
+type MockreferralRepositoryMockRecorder struct {
+	mock *MockreferralRepository
+}
+
+// NewMockreferralRepository creates a new mock instance.
+func NewMockreferralRepository(ctrl *gomock.Controller) *MockreferralRepository {
+	mock := &MockreferralRepository{ctrl: ctrl}
+	mock.recorder = &MockreferralRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockreferralRepository) EXPECT() *MockreferralRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindByReferrerID mocks base method.
+func (m *MockreferralRepository) FindByReferrerID(ctx context.Context, referrerID models.ModelID) ([]*models.Referral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByReferrerID", ctx, referrerID)
+	ret0, _ := ret[0].([]*models.Referral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByReferrerID indicates an expected call of FindByReferrerID.
+func (mr *MockreferralRepositoryMockRecorder) FindByReferrerID(ctx, referrerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByReferrerID", reflect.TypeOf((*MockreferralRepository)(nil).FindByReferrerID), ctx, referrerID)
+}
+
+// FindCodeByUserID mocks base method.
+func (m *MockreferralRepository) FindCodeByUserID(ctx context.Context, userID models.ModelID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCodeByUserID", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCodeByUserID indicates an expected call of FindCodeByUserID.
+func (mr *MockreferralRepositoryMockRecorder) FindCodeByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCodeByUserID", reflect.TypeOf((*MockreferralRepository)(nil).FindCodeByUserID), ctx, userID)
+}
+
+// Reward mocks base method.
+func (m *MockreferralRepository) Reward(ctx context.Context, refereeID models.ModelID, referrerBonus, refereeBonus float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reward", ctx, refereeID, referrerBonus, refereeBonus)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reward indicates an expected call of Reward.
+func (mr *MockreferralRepositoryMockRecorder) Reward(ctx, refereeID, referrerBonus, refereeBonus interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reward", reflect.TypeOf((*MockreferralRepository)(nil).Reward), ctx, refereeID, referrerBonus, refereeBonus)
+}
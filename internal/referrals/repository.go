@@ -0,0 +1,121 @@
+package referrals
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+func (r *Repository) FindCodeByUserID(ctx context.Context, userID models.ModelID) (string, error) {
+	var code sql.NullString
+	query := `
+		SELECT referral_code FROM users WHERE id = $1
+	`
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", apperrors.ErrNotFound
+		}
+		return "", err
+	}
+	return code.String, nil
+}
+
+func (r *Repository) FindByReferrerID(ctx context.Context, referrerID models.ModelID) ([]*models.Referral, error) {
+	query := `
+		SELECT rf.id, rf.referrer_id, rf.referee_id, u.login, rf.status, rf.created_at, rf.rewarded_at
+		FROM referrals rf
+		JOIN users u ON u.id = rf.referee_id
+		WHERE rf.referrer_id = $1
+		ORDER BY rf.created_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, referrerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var referrals []*models.Referral
+	for rows.Next() {
+		var m models.Referral
+		if err := rows.Scan(
+			&m.ID,
+			&m.ReferrerID,
+			&m.RefereeID,
+			&m.RefereeLogin,
+			&m.Status,
+			&m.CreatedAt,
+			&m.RewardedAt,
+		); err != nil {
+			return nil, err
+		}
+		referrals = append(referrals, &m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return referrals, nil
+}
+
+// Reward marks the referee's pending referral as rewarded and credits both
+// sides. It does nothing when there is no pending referral, so it is safe to
+// call on every processed order.
+func (r *Repository) Reward(ctx context.Context, refereeID models.ModelID, referrerBonus, refereeBonus float64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var referralID, referrerID models.ModelID
+	rewardQuery := `
+		UPDATE referrals
+		SET status = $2, rewarded_at = NOW()
+		WHERE referee_id = $1 AND status = $3
+		RETURNING id, referrer_id
+	`
+	err = tx.QueryRowContext(ctx, rewardQuery, refereeID, models.ReferralRewarded, models.ReferralPending).
+		Scan(&referralID, &referrerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	entryQuery := `
+		INSERT INTO balance_entries (user_id, kind, amount, counterparty_id, reference)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	credits := []struct {
+		userID, counterpartyID models.ModelID
+		amount                 float64
+	}{
+		{referrerID, refereeID, referrerBonus},
+		{refereeID, referrerID, refereeBonus},
+	}
+	for _, c := range credits {
+		if c.amount <= 0 {
+			continue
+		}
+		_, err = tx.ExecContext(ctx, entryQuery, c.userID, models.EntryReferral, c.amount, c.counterpartyID, referralID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,19 @@
+package referrals
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserMiddleware interface {
+	Auth(c *fiber.Ctx) error
+}
+
+func SetupRouter(
+	r fiber.Router,
+	mw UserMiddleware,
+	rs referralService,
+) {
+	handle := newHandler(rs)
+
+	r.Get("referrals", mw.Auth, handle.getReferrals)
+}
@@ -0,0 +1,69 @@
+package referrals
+
+import (
+	"context"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/referrals/dto"
+)
+
+type (
+	referralRepository interface {
+		FindCodeByUserID(ctx context.Context, userID models.ModelID) (string, error)
+		FindByReferrerID(ctx context.Context, referrerID models.ModelID) ([]*models.Referral, error)
+		Reward(ctx context.Context, refereeID models.ModelID, referrerBonus, refereeBonus float64) error
+	}
+	Service struct {
+		referrerBonus      float64
+		refereeBonus       float64
+		referralRepository referralRepository
+	}
+)
+
+func NewService(referrerBonus, refereeBonus float64, rr referralRepository) *Service {
+	return &Service{referrerBonus, refereeBonus, rr}
+}
+
+// OnOrderProcessed rewards the referral of the order's owner. Rewards are
+// deferred until the referee's first processed order to make fake sign-ups
+// worthless.
+func (s *Service) OnOrderProcessed(ctx context.Context, order *models.Order) error {
+	if order.Status != models.OrderProcessed {
+		return nil
+	}
+
+	return s.referralRepository.Reward(ctx, order.UserID, s.referrerBonus, s.refereeBonus)
+}
+
+func (s *Service) GetReferrals(ctx context.Context, userID models.ModelID) (dto.ReferralsResponse, error) {
+	code, err := s.referralRepository.FindCodeByUserID(ctx, userID)
+	if err != nil {
+		return dto.ReferralsResponse{}, err
+	}
+
+	referrals, err := s.referralRepository.FindByReferrerID(ctx, userID)
+	if err != nil {
+		return dto.ReferralsResponse{}, err
+	}
+
+	out := dto.ReferralsResponse{
+		Code:          code,
+		ReferrerBonus: s.referrerBonus,
+		RefereeBonus:  s.refereeBonus,
+		Referrals:     make([]dto.ReferralResponse, 0, len(referrals)),
+	}
+
+	for _, v := range referrals {
+		d := dto.ReferralResponse{
+			Login:        v.RefereeLogin,
+			Status:       string(v.Status),
+			RegisteredAt: v.CreatedAt,
+		}
+		if v.RewardedAt.Valid {
+			d.RewardedAt = &v.RewardedAt.Time
+		}
+		out.Referrals = append(out.Referrals, d)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,152 @@
+package referrals
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/referrals/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/referrals/mocks"
+)
+
+// storedReferrals plays the referrals table by the contract of
+// Repository.Reward: a pending referral of the referee turns REWARDED and
+// both sides are credited, anything else is left alone.
+type storedReferrals struct {
+	referrals []models.Referral
+	credits   map[models.ModelID]float64
+}
+
+func (r *storedReferrals) expect(repo *mocks.MockreferralRepository) {
+	repo.EXPECT().Reward(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, refereeID models.ModelID, referrerBonus, refereeBonus float64) error {
+			for i, v := range r.referrals {
+				if v.RefereeID != refereeID || v.Status != models.ReferralPending {
+					continue
+				}
+				r.referrals[i].Status = models.ReferralRewarded
+				r.referrals[i].RewardedAt = sql.NullTime{Time: time.Now(), Valid: true}
+				r.credits[v.ReferrerID] += referrerBonus
+				r.credits[v.RefereeID] += refereeBonus
+			}
+			return nil
+		}).AnyTimes()
+}
+
+func TestService_OnOrderProcessed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stored := &storedReferrals{
+		referrals: []models.Referral{{ReferrerID: "alice", RefereeID: "bob", Status: models.ReferralPending}},
+		credits:   map[models.ModelID]float64{},
+	}
+	repo := mocks.NewMockreferralRepository(ctrl)
+	stored.expect(repo)
+	s := NewService(100, 50, repo)
+
+	for _, status := range []models.OrderStatus{models.OrderNew, models.OrderProcessing, models.OrderInvalid} {
+		require.NoError(t, s.OnOrderProcessed(context.Background(), &models.Order{UserID: "bob", Status: status}))
+	}
+	assert.Equal(t, models.ReferralPending, stored.referrals[0].Status, "no reward before the first processed order")
+	assert.Empty(t, stored.credits)
+
+	require.NoError(t, s.OnOrderProcessed(context.Background(), &models.Order{UserID: "bob", Status: models.OrderProcessed}))
+	assert.Equal(t, models.ReferralRewarded, stored.referrals[0].Status)
+	assert.Equal(t, map[models.ModelID]float64{"alice": 100, "bob": 50}, stored.credits)
+
+	require.NoError(t, s.OnOrderProcessed(context.Background(), &models.Order{UserID: "bob", Status: models.OrderProcessed}))
+	assert.Equal(t, map[models.ModelID]float64{"alice": 100, "bob": 50}, stored.credits, "rewarded once")
+
+	require.NoError(t, s.OnOrderProcessed(context.Background(), &models.Order{UserID: "carol", Status: models.OrderProcessed}))
+	assert.Equal(t, map[models.ModelID]float64{"alice": 100, "bob": 50}, stored.credits, "users without a referrer earn nothing")
+}
+
+func TestService_OnOrderProcessedError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockreferralRepository(ctrl)
+	repo.EXPECT().Reward(gomock.Any(), models.ModelID("bob"), 100.0, 50.0).Return(errors.New("db is down"))
+
+	err := NewService(100, 50, repo).OnOrderProcessed(context.Background(), &models.Order{UserID: "bob", Status: models.OrderProcessed})
+	assert.Error(t, err)
+}
+
+func TestService_GetReferrals(t *testing.T) {
+	registered := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	rewarded := registered.Add(24 * time.Hour)
+	errDB := errors.New("db is down")
+
+	tests := []struct {
+		name      string
+		codeErr   error
+		referrals []*models.Referral
+		findErr   error
+		want      dto.ReferralsResponse
+		wantErr   error
+	}{
+		{
+			name: "positive test #1, pending and rewarded referrals",
+			referrals: []*models.Referral{
+				{RefereeLogin: "bob", Status: models.ReferralRewarded, CreatedAt: registered, RewardedAt: sql.NullTime{Time: rewarded, Valid: true}},
+				{RefereeLogin: "carol", Status: models.ReferralPending, CreatedAt: registered},
+			},
+			want: dto.ReferralsResponse{
+				Code:          "ALICE1",
+				ReferrerBonus: 100,
+				RefereeBonus:  50,
+				Referrals: []dto.ReferralResponse{
+					{Login: "bob", Status: "REWARDED", RegisteredAt: registered, RewardedAt: &rewarded},
+					{Login: "carol", Status: "PENDING", RegisteredAt: registered},
+				},
+			},
+		},
+		{
+			name: "positive test #2, nobody referred yet",
+			want: dto.ReferralsResponse{
+				Code:          "ALICE1",
+				ReferrerBonus: 100,
+				RefereeBonus:  50,
+				Referrals:     []dto.ReferralResponse{},
+			},
+		},
+		{
+			name:    "negative test #3, code lookup failed",
+			codeErr: errDB,
+			wantErr: errDB,
+		},
+		{
+			name:    "negative test #4, referral lookup failed",
+			findErr: errDB,
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockreferralRepository(ctrl)
+			repo.EXPECT().FindCodeByUserID(gomock.Any(), models.ModelID("alice")).Return("ALICE1", tt.codeErr)
+			if tt.codeErr == nil {
+				repo.EXPECT().FindByReferrerID(gomock.Any(), models.ModelID("alice")).Return(tt.referrals, tt.findErr)
+			}
+
+			out, err := NewService(100, 50, repo).GetReferrals(context.Background(), "alice")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package sealer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// Sealer encrypts small secrets for storage with AES-256-GCM. Sealed values
+// are base64 of nonce||ciphertext.
+type Sealer struct {
+	aead cipher.AEAD
+}
+
+// New takes a base64 encoded 32 byte key.
+func New(key string) (*Sealer, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("decode key: %w", err)
+	}
+	if len(raw) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(raw))
+	}
+
+	return newSealer(raw)
+}
+
+// Derive builds a Sealer keyed by the sha256 of passphrase, for development
+// setups without a dedicated key.
+func Derive(passphrase string) (*Sealer, error) {
+	sum := sha256.Sum256([]byte("sealer:" + passphrase))
+	return newSealer(sum[:])
+}
+
+func newSealer(key []byte) (*Sealer, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sealer{aead}, nil
+}
+
+func (s *Sealer) Seal(plaintext []byte) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(s.aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+func (s *Sealer) Open(sealed string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) < s.aead.NonceSize() {
+		return nil, errors.New("sealed value too short")
+	}
+
+	nonce, ciphertext := raw[:s.aead.NonceSize()], raw[s.aead.NonceSize():]
+	return s.aead.Open(nil, nonce, ciphertext, nil)
+}
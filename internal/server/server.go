@@ -13,8 +13,17 @@ type Server struct {
 	addr string
 }
 
-func NewServer(addr string) *Server {
-	return &Server{fiber.New(), addr}
+// NewServer creates a server listening on addr. Client IPs are read from
+// proxyHeader only when the request comes from one of trustedProxies.
+func NewServer(addr, proxyHeader string, trustedProxies []string) *Server {
+	conf := fiber.Config{}
+	if len(trustedProxies) > 0 {
+		conf.ProxyHeader = proxyHeader
+		conf.EnableTrustedProxyCheck = true
+		conf.TrustedProxies = trustedProxies
+		conf.EnableIPValidation = true
+	}
+	return &Server{fiber.New(conf), addr}
 }
 
 func (s *Server) Run() error {
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewServer_clientIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies []string
+		want    string
+	}{
+		{
+			name: "positive test #1, header ignored without trusted proxies",
+			want: "0.0.0.0",
+		},
+		{
+			name:    "positive test #2, header read from a trusted proxy",
+			proxies: []string{"0.0.0.0"},
+			want:    "203.0.113.7",
+		},
+		{
+			name:    "negative test #3, header ignored from other addresses",
+			proxies: []string{"10.0.0.0/8"},
+			want:    "0.0.0.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := NewServer(":0", "X-Real-IP", tt.proxies).GetApp()
+			app.Get("/", func(c *fiber.Ctx) error {
+				return c.SendString(c.IP())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-Real-IP", "203.0.113.7")
+
+			resp, err := app.Test(req, 100)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(body))
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package statements
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+	FormatXLSX  Format = "xlsx"
+)
+
+// columns is the column order of every format.
+var columns = []string{"occurred_at", "type", "reference", "status", "amount"}
+
+// incomplete ends a statement whose rows could not all be written, so a
+// truncated download cannot be mistaken for a whole one.
+const incomplete = "statement is incomplete"
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case "":
+		return FormatCSV, nil
+	case FormatCSV, FormatJSONL, FormatXLSX:
+		return f, nil
+	default:
+		return "", apperrors.ErrTypeNotCorrect
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatJSONL:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+type rowWriter interface {
+	Write(row *models.StatementRow) error
+	// Abort writes the incomplete marker in place of the remaining rows.
+	// Close must still be called afterwards.
+	Abort() error
+	// Close flushes buffered data, it does not close the underlying writer.
+	Close() error
+}
+
+func newRowWriter(f Format, w io.Writer) (rowWriter, error) {
+	switch f {
+	case FormatJSONL:
+		return &jsonlWriter{json.NewEncoder(w)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return nil, err
+		}
+		return &csvWriter{cw}, nil
+	}
+}
+
+func record(row *models.StatementRow) []string {
+	return []string{
+		row.OccurredAt.Format(time.RFC3339),
+		string(row.Type),
+		row.Reference,
+		row.Status,
+		row.Amount.String,
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Write(row *models.StatementRow) error {
+	return c.w.Write(record(row))
+}
+
+func (c *csvWriter) Abort() error {
+	return c.w.Write([]string{"error", incomplete})
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+// jsonlRow keeps the field order of columns; the amount is written as a
+// JSON number with the exact decimal text from the database.
+type jsonlRow struct {
+	OccurredAt string          `json:"occurred_at"`
+	Type       string          `json:"type"`
+	Reference  string          `json:"reference"`
+	Status     string          `json:"status"`
+	Amount     json.RawMessage `json:"amount"`
+}
+
+func (j *jsonlWriter) Write(row *models.StatementRow) error {
+	amount := json.RawMessage("null")
+	if row.Amount.Valid {
+		amount = json.RawMessage(row.Amount.String)
+	}
+	return j.enc.Encode(jsonlRow{
+		OccurredAt: row.OccurredAt.Format(time.RFC3339),
+		Type:       string(row.Type),
+		Reference:  row.Reference,
+		Status:     row.Status,
+		Amount:     amount,
+	})
+}
+
+func (j *jsonlWriter) Abort() error {
+	return j.enc.Encode(struct {
+		Error string `json:"error"`
+	}{incomplete})
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil
+}
+
+// xlsxWriter streams a single-sheet workbook. The sheet is the last zip
+// entry, so rows go straight to the output as they are written.
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	row   int
+}
+
+var xlsxParts = []struct {
+	name string
+	body string
+}{
+	{
+		"[Content_Types].xml",
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`,
+	},
+	{
+		"_rels/.rels",
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		"xl/workbook.xml",
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Statement" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`,
+	},
+	{
+		"xl/_rels/workbook.xml.rels",
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`,
+	},
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	zw := zip.NewWriter(w)
+
+	for _, part := range xlsxParts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.WriteString(f, xml.Header+part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.WriteString(sheet, xml.Header+
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+
+	x := &xlsxWriter{zw: zw, sheet: sheet}
+	if err = x.writeRow(columns, -1); err != nil {
+		return nil, err
+	}
+
+	return x, nil
+}
+
+func (x *xlsxWriter) Write(row *models.StatementRow) error {
+	numeric := -1
+	if row.Amount.Valid {
+		numeric = len(columns) - 1
+	}
+	return x.writeRow(record(row), numeric)
+}
+
+// writeRow writes cells as inline strings except the cell at index numeric.
+func (x *xlsxWriter) writeRow(cells []string, numeric int) error {
+	x.row++
+	if _, err := fmt.Fprintf(x.sheet, `<row r="%d">`, x.row); err != nil {
+		return err
+	}
+	for i, v := range cells {
+		if i == numeric {
+			if _, err := fmt.Fprintf(x.sheet, `<c><v>%s</v></c>`, v); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := io.WriteString(x.sheet, `<c t="inlineStr"><is><t>`); err != nil {
+			return err
+		}
+		if err := xml.EscapeText(x.sheet, []byte(v)); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(x.sheet, `</t></is></c>`); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(x.sheet, `</row>`)
+	return err
+}
+
+func (x *xlsxWriter) Abort() error {
+	return x.writeRow([]string{"error", incomplete}, -1)
+}
+
+func (x *xlsxWriter) Close() error {
+	if _, err := io.WriteString(x.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
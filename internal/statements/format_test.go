@@ -0,0 +1,87 @@
+package statements
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+func testRows() []*models.StatementRow {
+	at := time.Date(2024, 4, 1, 10, 30, 0, 0, time.FixedZone("MSK", 3*60*60))
+	return []*models.StatementRow{
+		{OccurredAt: at, Type: models.StatementOrder, Reference: "12345678903", Status: "NEW"},
+		{
+			OccurredAt: at.Add(time.Minute),
+			Type:       models.StatementWithdrawal,
+			Reference:  "2377225624",
+			Amount:     sql.NullString{String: "-751.10", Valid: true},
+		},
+	}
+}
+
+func writeAll(t *testing.T, f Format) []byte {
+	var buf bytes.Buffer
+	w, err := newRowWriter(f, &buf)
+	require.NoError(t, err)
+	for _, row := range testRows() {
+		require.NoError(t, w.Write(row))
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestRowWriter_csv(t *testing.T) {
+	want := "occurred_at,type,reference,status,amount\n" +
+		"2024-04-01T10:30:00+03:00,ORDER,12345678903,NEW,\n" +
+		"2024-04-01T10:31:00+03:00,WITHDRAWAL,2377225624,,-751.10\n"
+	assert.Equal(t, want, string(writeAll(t, FormatCSV)))
+}
+
+func TestRowWriter_jsonl(t *testing.T) {
+	want := `{"occurred_at":"2024-04-01T10:30:00+03:00","type":"ORDER","reference":"12345678903","status":"NEW","amount":null}` + "\n" +
+		`{"occurred_at":"2024-04-01T10:31:00+03:00","type":"WITHDRAWAL","reference":"2377225624","status":"","amount":-751.10}` + "\n"
+	assert.Equal(t, want, string(writeAll(t, FormatJSONL)))
+}
+
+func TestRowWriter_xlsx(t *testing.T) {
+	b := writeAll(t, FormatXLSX)
+
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	require.NoError(t, err)
+
+	var sheet []byte
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			sheet, err = io.ReadAll(rc)
+			require.NoError(t, err)
+			rc.Close()
+		}
+	}
+
+	assert.Len(t, zr.File, 5)
+	assert.Contains(t, string(sheet), `<row r="3">`)
+	assert.Contains(t, string(sheet), `<c><v>-751.10</v></c>`)
+	assert.Contains(t, string(sheet), `</sheetData></worksheet>`)
+}
+
+func TestParsePeriod(t *testing.T) {
+	from, to, err := parsePeriod("2024-04-01", "2024-04-30")
+	require.NoError(t, err)
+	assert.Equal(t, 30*24*time.Hour, to.Sub(from))
+
+	_, _, err = parsePeriod("2024-05-01T00:00:00Z", "2024-04-01T00:00:00Z")
+	assert.Error(t, err)
+
+	_, _, err = parsePeriod("yesterday", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package statements
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+const dateLayout = "2006-01-02"
+
+type (
+	statementService interface {
+		Export(ctx context.Context, userID models.ModelID, from, to time.Time, f Format, w io.Writer) error
+	}
+	handler struct {
+		service statementService
+	}
+)
+
+func newHandler(ss statementService) *handler {
+	return &handler{ss}
+}
+
+func (h *handler) export(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.SendStatus(fiber.StatusUnauthorized)
+	}
+
+	format, err := ParseFormat(c.Query("format"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("format must be one of csv, jsonl, xlsx")
+	}
+
+	from, to, err := parsePeriod(c.Query("from"), c.Query("to"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="statement.%s"`, format))
+
+	// the body is produced after the handler returns; the fasthttp request
+	// context stays valid until the response is written, unlike c
+	ctx := c.Context()
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.service.Export(ctx, models.ModelID(userID), from, to, format, w); err != nil {
+			logger.Log.Error("statements:handler:export", "userID", userID, "error", err)
+		}
+		if err := w.Flush(); err != nil {
+			logger.Log.Warn("statements:handler:export", "flush", err)
+		}
+	})
+
+	return nil
+}
+
+// parsePeriod accepts RFC3339 timestamps or dates. A date in "to" includes
+// the whole day. Missing bounds mean the beginning of history and now.
+func parsePeriod(fromStr, toStr string) (time.Time, time.Time, error) {
+	from, to := time.Time{}, time.Now()
+
+	if fromStr != "" {
+		t, err := parseBound(fromStr)
+		if err != nil {
+			return from, to, fmt.Errorf("from: %w", err)
+		}
+		from = t
+	}
+
+	if toStr != "" {
+		t, err := parseBound(toStr)
+		if err != nil {
+			return from, to, fmt.Errorf("to: %w", err)
+		}
+		if len(toStr) == len(dateLayout) {
+			t = t.AddDate(0, 0, 1)
+		}
+		to = t
+	}
+
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("from must be before to")
+	}
+
+	return from, to, nil
+}
+
+func parseBound(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(dateLayout, s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, apperrors.ErrParse
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockstatementRepository is a mock of statementRepository interface.
+type MockstatementRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockstatementRepositoryMockRecorder
+}
+
+// MockstatementRepositoryMockRecorder is the mock recorder for MockstatementRepository.
+type MockstatementRepositoryMockRecorder struct {
+	mock *MockstatementRepository
+}
+
+// NewMockstatementRepository creates a new mock instance.
+func NewMockstatementRepository(ctrl *gomock.Controller) *MockstatementRepository {
+	mock := &MockstatementRepository{ctrl: ctrl}
+	mock.recorder = &MockstatementRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockstatementRepository) EXPECT() *MockstatementRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Iterate mocks base method.
+func (m *MockstatementRepository) Iterate(ctx context.Context, userID models.ModelID, from, to time.Time, fn func(*models.StatementRow) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Iterate", ctx, userID, from, to, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Iterate indicates an expected call of Iterate.
+func (mr *MockstatementRepositoryMockRecorder) Iterate(ctx, userID, from, to, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Iterate", reflect.TypeOf((*MockstatementRepository)(nil).Iterate), ctx, userID, from, to, fn)
+}
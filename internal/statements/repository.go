@@ -0,0 +1,78 @@
+package statements
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+// Iterate calls fn for every statement row of the user in [from, to) in
+// chronological order. Rows are read from the cursor one by one and never
+// collected in memory.
+func (r *Repository) Iterate(
+	ctx context.Context,
+	userID models.ModelID,
+	from, to time.Time,
+	fn func(row *models.StatementRow) error,
+) error {
+	query := `
+		SELECT occurred_at, type, reference, status, amount
+		FROM (
+			SELECT created_at::timestamptz AS occurred_at, $4::text AS type, number AS reference,
+				status::text AS status, NULL::text AS amount
+			FROM orders
+			WHERE user_id = $1
+			UNION ALL
+			SELECT updated_at::timestamptz, $5::text, number, status::text, accrual::text
+			FROM orders
+			WHERE user_id = $1 AND status = $7 AND accrual IS NOT NULL
+			UNION ALL
+			SELECT created_at::timestamptz, $6::text, order_number, '', (-amount)::text
+			FROM withdrawals
+			WHERE user_id = $1
+			UNION ALL
+			SELECT created_at::timestamptz, kind::text, COALESCE(reference, ''), '', amount::text
+			FROM balance_entries
+			WHERE user_id = $1
+		) s
+		WHERE occurred_at >= $2 AND occurred_at < $3
+		ORDER BY occurred_at, type, reference
+	`
+	rows, err := r.db.QueryContext(
+		ctx,
+		query,
+		userID,
+		from,
+		to,
+		models.StatementOrder,
+		models.StatementAccrual,
+		models.StatementWithdrawal,
+		models.OrderProcessed,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var row models.StatementRow
+	for rows.Next() {
+		if err := rows.Scan(&row.OccurredAt, &row.Type, &row.Reference, &row.Status, &row.Amount); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
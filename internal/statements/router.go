@@ -0,0 +1,20 @@
+package statements
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserMiddleware interface {
+	Auth(c *fiber.Ctx) error
+}
+
+func SetupRouter(
+	r fiber.Router,
+	mw UserMiddleware,
+	sr statementRepository,
+) {
+	service := NewService(sr)
+	handle := newHandler(service)
+
+	r.Get("statement", mw.Auth, handle.export)
+}
@@ -0,0 +1,46 @@
+package statements
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type (
+	statementRepository interface {
+		Iterate(
+			ctx context.Context,
+			userID models.ModelID,
+			from, to time.Time,
+			fn func(row *models.StatementRow) error,
+		) error
+	}
+	Service struct {
+		statementRepository statementRepository
+	}
+)
+
+func NewService(sr statementRepository) *Service {
+	return &Service{sr}
+}
+
+// Export writes the user's statement for [from, to) to w in format f. When
+// the rows stop coming halfway the statement ends with an error marker and
+// the error is returned.
+func (s *Service) Export(ctx context.Context, userID models.ModelID, from, to time.Time, f Format, w io.Writer) error {
+	rw, err := newRowWriter(f, w)
+	if err != nil {
+		return err
+	}
+
+	if err = s.statementRepository.Iterate(ctx, userID, from, to, rw.Write); err != nil {
+		if abortErr := rw.Abort(); abortErr == nil {
+			rw.Close()
+		}
+		return err
+	}
+
+	return rw.Close()
+}
@@ -0,0 +1,78 @@
+package statements
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/statements/mocks"
+)
+
+func TestService_Export(t *testing.T) {
+	errRows := errors.New("connection reset")
+
+	tests := []struct {
+		name    string
+		format  Format
+		fail    bool
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "positive test #1, whole statement",
+			format: FormatCSV,
+			want: "occurred_at,type,reference,status,amount\n" +
+				"2024-04-01T10:30:00+03:00,ORDER,12345678903,NEW,\n" +
+				"2024-04-01T10:31:00+03:00,WITHDRAWAL,2377225624,,-751.10\n",
+		},
+		{
+			name:   "negative test #1, csv cut off after the first row",
+			format: FormatCSV,
+			fail:   true,
+			want: "occurred_at,type,reference,status,amount\n" +
+				"2024-04-01T10:30:00+03:00,ORDER,12345678903,NEW,\n" +
+				"error,statement is incomplete\n",
+			wantErr: errRows,
+		},
+		{
+			name:   "negative test #2, jsonl cut off after the first row",
+			format: FormatJSONL,
+			fail:   true,
+			want: `{"occurred_at":"2024-04-01T10:30:00+03:00","type":"ORDER","reference":"12345678903","status":"NEW","amount":null}` + "\n" +
+				`{"error":"statement is incomplete"}` + "\n",
+			wantErr: errRows,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockstatementRepository(ctrl)
+			repo.EXPECT().Iterate(gomock.Any(), models.ModelID("user"), gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ models.ModelID, _, _ time.Time, fn func(*models.StatementRow) error) error {
+					for i, row := range testRows() {
+						if tt.fail && i == 1 {
+							return errRows
+						}
+						if err := fn(row); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+
+			var buf bytes.Buffer
+			err := NewService(repo).Export(context.Background(), "user", testRows()[0].OccurredAt, testRows()[1].OccurredAt, tt.format, &buf)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
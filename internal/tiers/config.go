@@ -0,0 +1,93 @@
+package tiers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+type (
+	Tier struct {
+		Name string `json:"name"`
+		// Threshold is the sum of processed accruals within the window
+		// needed to reach the tier.
+		Threshold float64 `json:"threshold"`
+		// Multiplier applies to accruals credited while in the tier.
+		Multiplier float64  `json:"multiplier"`
+		Benefits   []string `json:"benefits"`
+	}
+	Config struct {
+		Window time.Duration
+		Tiers  []Tier
+	}
+)
+
+// DefaultConfig is used when no tiers file is configured.
+func DefaultConfig(window time.Duration) Config {
+	return Config{
+		Window: window,
+		Tiers: []Tier{
+			{Name: "bronze", Threshold: 0, Multiplier: 1, Benefits: []string{"base accruals"}},
+			{Name: "silver", Threshold: 1000, Multiplier: 1.05, Benefits: []string{"+5% on accruals"}},
+			{Name: "gold", Threshold: 5000, Multiplier: 1.1, Benefits: []string{"+10% on accruals"}},
+		},
+	}
+}
+
+// LoadConfig reads tiers from a JSON file of the form
+// {"window": "8760h", "tiers": [{"name": "bronze", "threshold": 0, ...}]}.
+// A missing window keeps the given default.
+func LoadConfig(path string, window time.Duration) (Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("read tiers: %w", err)
+	}
+
+	var raw struct {
+		Window string `json:"window"`
+		Tiers  []Tier `json:"tiers"`
+	}
+	if err = json.Unmarshal(b, &raw); err != nil {
+		return Config{}, fmt.Errorf("parse tiers: %w", err)
+	}
+
+	conf := Config{Window: window, Tiers: raw.Tiers}
+	if raw.Window != "" {
+		if conf.Window, err = time.ParseDuration(raw.Window); err != nil {
+			return Config{}, fmt.Errorf("parse tiers window: %w", err)
+		}
+	}
+
+	return conf, conf.validate()
+}
+
+func (c *Config) validate() error {
+	if len(c.Tiers) == 0 {
+		return fmt.Errorf("tiers: at least one tier is required")
+	}
+	sort.Slice(c.Tiers, func(i, j int) bool {
+		return c.Tiers[i].Threshold < c.Tiers[j].Threshold
+	})
+	if c.Tiers[0].Threshold != 0 {
+		return fmt.Errorf("tiers: the lowest tier must have a zero threshold")
+	}
+	for _, t := range c.Tiers {
+		if t.Multiplier < 1 {
+			return fmt.Errorf("tiers: multiplier of %q must be at least 1", t.Name)
+		}
+	}
+	return nil
+}
+
+// tierFor returns the index of the highest tier reached by total.
+func (c *Config) tierFor(total float64) int {
+	idx := 0
+	for i, t := range c.Tiers {
+		if total >= t.Threshold {
+			idx = i
+		}
+	}
+	return idx
+}
@@ -0,0 +1,36 @@
+package tiers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tiers.json")
+	body := `{"window": "720h", "tiers": [
+		{"name": "gold", "threshold": 500, "multiplier": 1.2},
+		{"name": "bronze", "threshold": 0, "multiplier": 1}
+	]}`
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+
+	conf, err := LoadConfig(path, time.Hour)
+	require.NoError(t, err)
+
+	assert.Equal(t, 720*time.Hour, conf.Window)
+	assert.Equal(t, "bronze", conf.Tiers[0].Name)
+	assert.Equal(t, 0, conf.tierFor(499.99))
+	assert.Equal(t, 1, conf.tierFor(500))
+}
+
+func TestLoadConfig_invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tiers.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"tiers": [{"name": "silver", "threshold": 10, "multiplier": 1}]}`), 0o600))
+
+	_, err := LoadConfig(path, time.Hour)
+	assert.Error(t, err)
+}
@@ -0,0 +1,12 @@
+package dto
+
+type TierResponse struct {
+	Tier          string   `json:"tier"`
+	Multiplier    float64  `json:"multiplier"`
+	Benefits      []string `json:"benefits"`
+	Accrued       float64  `json:"accrued"`
+	NextTier      string   `json:"next_tier,omitempty"`
+	NextThreshold float64  `json:"next_threshold,omitempty"`
+	Remaining     float64  `json:"remaining"`
+	Progress      float64  `json:"progress"`
+}
@@ -0,0 +1,39 @@
+package tiers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tiers/dto"
+)
+
+type (
+	tierService interface {
+		GetUserTier(ctx context.Context, userID models.ModelID) (dto.TierResponse, error)
+	}
+	handler struct {
+		service tierService
+	}
+)
+
+func newHandler(ts tierService) *handler {
+	return &handler{ts}
+}
+
+func (h *handler) getTier(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.SendStatus(fiber.StatusUnauthorized)
+	}
+
+	out, err := h.service.GetUserTier(c.Context(), models.ModelID(userID))
+	if err != nil {
+		logger.Log.Error("tiers:handler:getTier", "StatusInternalServerError", err)
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(out)
+}
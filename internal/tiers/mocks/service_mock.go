@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MocktierRepository is a mock of tierRepository interface.
+type MocktierRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MocktierRepositoryMockRecorder
+}
+
+// MocktierRepositoryMockRecorder is the mock recorder for MocktierRepository.
+type MocktierRepositoryMockRecorder struct {
+	mock *MocktierRepository
+}
+
+// NewMocktierRepository creates a new mock instance.
+func NewMocktierRepository(ctrl *gomock.Controller) *MocktierRepository {
+	mock := &MocktierRepository{ctrl: ctrl}
+	mock.recorder = &MocktierRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktierRepository) EXPECT() *MocktierRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindBonus mocks base method.
+func (m *MocktierRepository) FindBonus(ctx context.Context, userID models.ModelID, number string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBonus", ctx, userID, number)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBonus indicates an expected call of FindBonus.
+func (mr *MocktierRepositoryMockRecorder) FindBonus(ctx, userID, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBonus", reflect.TypeOf((*MocktierRepository)(nil).FindBonus), ctx, userID, number)
+}
+
+// FindSumOfProcessedAccruals mocks base method.
+func (m *MocktierRepository) FindSumOfProcessedAccruals(ctx context.Context, userID models.ModelID, window time.Duration) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSumOfProcessedAccruals", ctx, userID, window)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSumOfProcessedAccruals indicates an expected call of FindSumOfProcessedAccruals.
+func (mr *MocktierRepositoryMockRecorder) FindSumOfProcessedAccruals(ctx, userID, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSumOfProcessedAccruals", reflect.TypeOf((*MocktierRepository)(nil).FindSumOfProcessedAccruals), ctx, userID, window)
+}
+
+// FindTier mocks base method.
+func (m *MocktierRepository) FindTier(ctx context.Context, userID models.ModelID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTier", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTier indicates an expected call of FindTier.
+func (mr *MocktierRepositoryMockRecorder) FindTier(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTier", reflect.TypeOf((*MocktierRepository)(nil).FindTier), ctx, userID)
+}
+
+// SaveBonus mocks base method.
+func (m *MocktierRepository) SaveBonus(ctx context.Context, entry *models.BalanceEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBonus", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveBonus indicates an expected call of SaveBonus.
+func (mr *MocktierRepositoryMockRecorder) SaveBonus(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBonus", reflect.TypeOf((*MocktierRepository)(nil).SaveBonus), ctx, entry)
+}
+
+// UpdateTier mocks base method.
+func (m *MocktierRepository) UpdateTier(ctx context.Context, userID models.ModelID, tier string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTier", ctx, userID, tier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTier indicates an expected call of UpdateTier.
+func (mr *MocktierRepositoryMockRecorder) UpdateTier(ctx, userID, tier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTier", reflect.TypeOf((*MocktierRepository)(nil).UpdateTier), ctx, userID, tier)
+}
@@ -0,0 +1,94 @@
+package tiers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+func (r *Repository) FindSumOfProcessedAccruals(ctx context.Context, userID models.ModelID, window time.Duration) (float64, error) {
+	var total float64
+
+	query := `
+		SELECT COALESCE(SUM(accrual), 0)
+		FROM orders
+		WHERE user_id = $1 AND status = $2 AND updated_at >= NOW() - $3 * INTERVAL '1 second'
+	`
+
+	err := r.db.QueryRowContext(ctx, query, userID, models.OrderProcessed, window.Seconds()).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (r *Repository) FindTier(ctx context.Context, userID models.ModelID) (string, error) {
+	var tier string
+
+	query := `
+		SELECT tier FROM users WHERE id = $1
+	`
+
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&tier)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", apperrors.ErrNotFound
+		}
+		return "", err
+	}
+
+	return tier, nil
+}
+
+func (r *Repository) UpdateTier(ctx context.Context, userID models.ModelID, tier string) error {
+	query := `
+		UPDATE users SET tier = $1 WHERE id = $2
+	`
+	_, err := r.db.ExecContext(ctx, query, tier, userID)
+	return err
+}
+
+// SaveBonus credits a tier bonus once per order. Revisions of the bonus are
+// separate entries and are not deduplicated.
+func (r *Repository) SaveBonus(ctx context.Context, entry *models.BalanceEntry) error {
+	query := `
+		INSERT INTO balance_entries (user_id, kind, amount, reference, comment)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, kind, reference) WHERE kind = 'TIER_BONUS' DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, entry.UserID, entry.Kind, entry.Amount, entry.Reference, entry.Comment)
+	return err
+}
+
+// FindBonus returns the tier bonus credited for the order so far, revisions
+// included.
+func (r *Repository) FindBonus(ctx context.Context, userID models.ModelID, number string) (float64, error) {
+	var total float64
+
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM balance_entries
+		WHERE user_id = $1 AND reference = $2 AND kind IN ($3, $4)
+	`
+
+	err := r.db.QueryRowContext(ctx, query, userID, number, models.EntryTierBonus, models.EntryTierBonusRevision).
+		Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
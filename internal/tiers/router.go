@@ -0,0 +1,19 @@
+package tiers
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserMiddleware interface {
+	Auth(c *fiber.Ctx) error
+}
+
+func SetupRouter(
+	r fiber.Router,
+	mw UserMiddleware,
+	ts tierService,
+) {
+	handle := newHandler(ts)
+
+	r.Get("tier", mw.Auth, handle.getTier)
+}
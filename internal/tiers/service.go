@@ -0,0 +1,150 @@
+package tiers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tiers/dto"
+)
+
+type (
+	tierRepository interface {
+		FindSumOfProcessedAccruals(ctx context.Context, userID models.ModelID, window time.Duration) (float64, error)
+		FindTier(ctx context.Context, userID models.ModelID) (string, error)
+		UpdateTier(ctx context.Context, userID models.ModelID, tier string) error
+		SaveBonus(ctx context.Context, entry *models.BalanceEntry) error
+		FindBonus(ctx context.Context, userID models.ModelID, number string) (float64, error)
+	}
+	Service struct {
+		conf           Config
+		tierRepository tierRepository
+	}
+)
+
+func NewService(conf Config, tr tierRepository) *Service {
+	return &Service{conf, tr}
+}
+
+// OnOrderProcessed recalculates the user's tier and credits the tier bonus
+// for the order's accrual.
+func (s *Service) OnOrderProcessed(ctx context.Context, order *models.Order) error {
+	idx, _, err := s.recalculate(ctx, order.UserID)
+	if err != nil {
+		return err
+	}
+
+	if !order.Accrual.Valid {
+		return nil
+	}
+
+	bonus := math.Round(order.Accrual.Float64*(s.conf.Tiers[idx].Multiplier-1)*100) / 100
+	if bonus <= 0 {
+		return nil
+	}
+
+	return s.tierRepository.SaveBonus(ctx, &models.BalanceEntry{
+		UserID:    order.UserID,
+		Kind:      models.EntryTierBonus,
+		Amount:    bonus,
+		Reference: sql.NullString{String: order.Number, Valid: true},
+	})
+}
+
+// OnAccrualRevised moves the tier bonus of a revised order along with its
+// accrual. The bonus keeps the rate it was credited at; an order revised up
+// from nothing gets the rate of the tier the user has reached.
+func (s *Service) OnAccrualRevised(ctx context.Context, order *models.Order, rev *models.AccrualRevision) error {
+	credited, err := s.tierRepository.FindBonus(ctx, order.UserID, order.Number)
+	if err != nil {
+		return err
+	}
+
+	var rate float64
+	if rev.PreviousAccrual > 0 {
+		rate = credited / rev.PreviousAccrual
+	} else {
+		idx, _, err := s.reached(ctx, order.UserID)
+		if err != nil {
+			return err
+		}
+		rate = s.conf.Tiers[idx].Multiplier - 1
+	}
+
+	bonus := math.Round(rev.NewAccrual*rate*100) / 100
+	delta := math.Round((bonus-credited)*100) / 100
+	if delta == 0 {
+		return nil
+	}
+
+	return s.tierRepository.SaveBonus(ctx, &models.BalanceEntry{
+		UserID:    order.UserID,
+		Kind:      models.EntryTierBonusRevision,
+		Amount:    delta,
+		Reference: sql.NullString{String: order.Number, Valid: true},
+		Comment:   sql.NullString{String: fmt.Sprintf("tier bonus revised from %.2f to %.2f", credited, bonus), Valid: true},
+	})
+}
+
+// GetUserTier computes the tier reached within the window without storing
+// it, the stored tier only moves on the accrual path.
+func (s *Service) GetUserTier(ctx context.Context, userID models.ModelID) (dto.TierResponse, error) {
+	idx, total, err := s.reached(ctx, userID)
+	if err != nil {
+		return dto.TierResponse{}, err
+	}
+
+	tier := s.conf.Tiers[idx]
+	out := dto.TierResponse{
+		Tier:       tier.Name,
+		Multiplier: tier.Multiplier,
+		Benefits:   tier.Benefits,
+		Accrued:    total,
+		Progress:   1,
+	}
+
+	if idx+1 < len(s.conf.Tiers) {
+		next := s.conf.Tiers[idx+1]
+		out.NextTier = next.Name
+		out.NextThreshold = next.Threshold
+		out.Remaining = next.Threshold - total
+		out.Progress = (total - tier.Threshold) / (next.Threshold - tier.Threshold)
+	}
+
+	return out, nil
+}
+
+// reached returns the index of the tier reached within the window and the
+// accruals it is based on.
+func (s *Service) reached(ctx context.Context, userID models.ModelID) (int, float64, error) {
+	total, err := s.tierRepository.FindSumOfProcessedAccruals(ctx, userID, s.conf.Window)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return s.conf.tierFor(total), total, nil
+}
+
+// recalculate returns the tier reached within the window and stores it if it
+// changed.
+func (s *Service) recalculate(ctx context.Context, userID models.ModelID) (int, float64, error) {
+	idx, total, err := s.reached(ctx, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	current, err := s.tierRepository.FindTier(ctx, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if current != s.conf.Tiers[idx].Name {
+		if err = s.tierRepository.UpdateTier(ctx, userID, s.conf.Tiers[idx].Name); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return idx, total, nil
+}
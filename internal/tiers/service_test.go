@@ -0,0 +1,117 @@
+package tiers
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tiers/mocks"
+)
+
+func TestService_GetUserTier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// no FindTier or UpdateTier expected: reads must not write
+	repo := mocks.NewMocktierRepository(ctrl)
+	repo.EXPECT().FindSumOfProcessedAccruals(gomock.Any(), models.ModelID("user"), time.Hour).Return(1500.0, nil)
+
+	out, err := NewService(DefaultConfig(time.Hour), repo).GetUserTier(context.Background(), "user")
+	require.NoError(t, err)
+
+	assert.Equal(t, "silver", out.Tier)
+	assert.Equal(t, "gold", out.NextTier)
+	assert.Equal(t, 3500.0, out.Remaining)
+	assert.InDelta(t, 0.125, out.Progress, 1e-9)
+}
+
+func TestService_OnOrderProcessed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMocktierRepository(ctrl)
+	repo.EXPECT().FindSumOfProcessedAccruals(gomock.Any(), models.ModelID("user"), time.Hour).Return(6000.0, nil)
+	repo.EXPECT().FindTier(gomock.Any(), models.ModelID("user")).Return("silver", nil)
+	repo.EXPECT().UpdateTier(gomock.Any(), models.ModelID("user"), "gold").Return(nil)
+	repo.EXPECT().SaveBonus(gomock.Any(), &models.BalanceEntry{
+		UserID:    "user",
+		Kind:      models.EntryTierBonus,
+		Amount:    10,
+		Reference: sql.NullString{String: "12345678903", Valid: true},
+	}).Return(nil)
+
+	err := NewService(DefaultConfig(time.Hour), repo).OnOrderProcessed(context.Background(), &models.Order{
+		UserID:  "user",
+		Number:  "12345678903",
+		Accrual: sql.NullFloat64{Float64: 100, Valid: true},
+	})
+	assert.NoError(t, err)
+}
+
+func TestService_OnAccrualRevised(t *testing.T) {
+	tests := []struct {
+		name     string
+		rev      models.AccrualRevision
+		credited float64
+		total    float64
+		want     float64
+		comment  string
+	}{
+		{
+			name:     "positive test #1, bonus follows the accrual down at the credited rate",
+			rev:      models.AccrualRevision{PreviousAccrual: 100, NewAccrual: 60},
+			credited: 10,
+			want:     -4,
+			comment:  "tier bonus revised from 10.00 to 6.00",
+		},
+		{
+			name:     "positive test #2, bonus follows the accrual up",
+			rev:      models.AccrualRevision{PreviousAccrual: 100, NewAccrual: 150},
+			credited: 5,
+			want:     2.5,
+			comment:  "tier bonus revised from 5.00 to 7.50",
+		},
+		{
+			name:    "positive test #3, revised up from nothing at the reached tier",
+			rev:     models.AccrualRevision{PreviousAccrual: 0, NewAccrual: 200},
+			total:   6000,
+			want:    20,
+			comment: "tier bonus revised from 0.00 to 20.00",
+		},
+		{
+			name: "positive test #4, no bonus was credited",
+			rev:  models.AccrualRevision{PreviousAccrual: 100, NewAccrual: 200},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMocktierRepository(ctrl)
+			repo.EXPECT().FindBonus(gomock.Any(), models.ModelID("user"), "12345678903").Return(tt.credited, nil)
+			if tt.rev.PreviousAccrual == 0 {
+				repo.EXPECT().FindSumOfProcessedAccruals(gomock.Any(), models.ModelID("user"), time.Hour).Return(tt.total, nil)
+			}
+			if tt.want != 0 {
+				repo.EXPECT().SaveBonus(gomock.Any(), &models.BalanceEntry{
+					UserID:    "user",
+					Kind:      models.EntryTierBonusRevision,
+					Amount:    tt.want,
+					Reference: sql.NullString{String: "12345678903", Valid: true},
+					Comment:   sql.NullString{String: tt.comment, Valid: true},
+				}).Return(nil)
+			}
+
+			order := &models.Order{UserID: "user", Number: "12345678903"}
+			err := NewService(DefaultConfig(time.Hour), repo).OnAccrualRevised(context.Background(), order, &tt.rev)
+			assert.NoError(t, err)
+		})
+	}
+}
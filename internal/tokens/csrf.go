@@ -0,0 +1,137 @@
+package tokens
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+// CSRF modes for requests authenticated by the access cookie.
+const (
+	// CSRFOrigin accepts requests whose Origin, or Referer, is the server
+	// itself or one of the trusted origins. Requests with neither header do
+	// not come from a browser and pass.
+	CSRFOrigin = "origin"
+	// CSRFDoubleSubmit requires the CSRFHeader, or the CSRFField of a form,
+	// to repeat the CSRFCookie handed out with the tokens.
+	CSRFDoubleSubmit = "double_submit"
+	CSRFOff          = "off"
+
+	CSRFCookie = "csrf_token"
+	CSRFHeader = "X-CSRF-Token"
+	CSRFField  = "csrf_token"
+)
+
+func parseCSRF(conf *CookieConfig) error {
+	switch strings.ToLower(conf.CSRF) {
+	case "":
+		conf.CSRF = CSRFOrigin
+	case CSRFOrigin, CSRFDoubleSubmit, CSRFOff:
+		conf.CSRF = strings.ToLower(conf.CSRF)
+	default:
+		return fmt.Errorf("unknown CSRF mode %q", conf.CSRF)
+	}
+
+	for i, o := range conf.TrustedOrigins {
+		u, err := url.Parse(strings.TrimSpace(o))
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("trusted origin %q must look like https://host[:port]", o)
+		}
+		conf.TrustedOrigins[i] = strings.ToLower(u.Scheme + "://" + u.Host)
+	}
+
+	return nil
+}
+
+// CheckCSRF tells whether a state-changing request authenticated by the
+// access cookie was made on purpose. Callers skip it for safe methods and
+// bearer tokens, which a foreign page can't attach.
+func (t *Transport) CheckCSRF(c *fiber.Ctx) error {
+	switch t.conf.CSRF {
+	case CSRFDoubleSubmit:
+		cookie, header := c.Cookies(CSRFCookie), c.Get(CSRFHeader)
+		if header == "" {
+			// plain HTML forms can't set headers
+			header = c.FormValue(CSRFField)
+		}
+		if cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) != 1 {
+			return apperrors.ErrCSRF
+		}
+	case CSRFOrigin:
+		origin := c.Get(fiber.HeaderOrigin)
+		if origin == "" {
+			referer := c.Get(fiber.HeaderReferer)
+			if referer == "" {
+				return nil
+			}
+			u, err := url.Parse(referer)
+			if err != nil {
+				return apperrors.ErrCSRF
+			}
+			origin = u.Scheme + "://" + u.Host
+		}
+		if !t.trustedOrigin(c, origin) {
+			return apperrors.ErrCSRF
+		}
+	}
+
+	return nil
+}
+
+// CSRFToken returns the value a form of the site must post in CSRFField. In
+// CSRFDoubleSubmit mode a browser without the CSRFCookie, e.g. one not
+// logged in yet, gets a new one for the session; other modes need none.
+func (t *Transport) CSRFToken(c *fiber.Ctx) string {
+	if t.conf.CSRF != CSRFDoubleSubmit {
+		return ""
+	}
+	if value := c.Cookies(CSRFCookie); value != "" {
+		return value
+	}
+
+	value := utils.GenerateSecret()
+	c.Cookie(&fiber.Cookie{
+		Name:     CSRFCookie,
+		Value:    value,
+		Path:     "/",
+		Secure:   t.conf.Secure,
+		SameSite: t.conf.SameSite,
+	})
+	return value
+}
+
+// trustedOrigin compares scheme and host of origin with the server's own, as
+// seen through trusted proxies, and the trusted origins.
+func (t *Transport) trustedOrigin(c *fiber.Ctx, origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		// e.g. "null" of sandboxed frames and privacy-sensitive redirects
+		return false
+	}
+
+	origin = strings.ToLower(u.Scheme + "://" + u.Host)
+	if origin == strings.ToLower(c.Protocol()+"://"+c.Hostname()) {
+		return true
+	}
+	for _, o := range t.conf.TrustedOrigins {
+		if o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSafeMethod reports whether the request method must not change state.
+func IsSafeMethod(c *fiber.Ctx) bool {
+	switch c.Method() {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions, fiber.MethodTrace:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package tokens
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport_CheckCSRF(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		headers map[string]string
+		cookie  string
+		form    string
+		code    int
+	}{
+		{
+			name: "origin: no Origin nor Referer",
+			mode: CSRFOrigin,
+			code: http.StatusOK,
+		},
+		{
+			name:    "origin: same host",
+			mode:    CSRFOrigin,
+			headers: map[string]string{"Origin": "http://example.com"},
+			code:    http.StatusOK,
+		},
+		{
+			name:    "origin: same host, other scheme",
+			mode:    CSRFOrigin,
+			headers: map[string]string{"Origin": "https://example.com"},
+			code:    http.StatusForbidden,
+		},
+		{
+			name:    "origin: same host, other port",
+			mode:    CSRFOrigin,
+			headers: map[string]string{"Origin": "http://example.com:8080"},
+			code:    http.StatusForbidden,
+		},
+		{
+			name:    "origin: trusted origin",
+			mode:    CSRFOrigin,
+			headers: map[string]string{"Origin": "https://App.Example.org"},
+			code:    http.StatusOK,
+		},
+		{
+			name:    "origin: trusted host, other scheme",
+			mode:    CSRFOrigin,
+			headers: map[string]string{"Origin": "http://app.example.org"},
+			code:    http.StatusForbidden,
+		},
+		{
+			name:    "origin: foreign origin",
+			mode:    CSRFOrigin,
+			headers: map[string]string{"Origin": "https://evil.example.net"},
+			code:    http.StatusForbidden,
+		},
+		{
+			name:    "origin: opaque origin",
+			mode:    CSRFOrigin,
+			headers: map[string]string{"Origin": "null"},
+			code:    http.StatusForbidden,
+		},
+		{
+			name:    "origin: foreign referer",
+			mode:    CSRFOrigin,
+			headers: map[string]string{"Referer": "https://evil.example.net/page"},
+			code:    http.StatusForbidden,
+		},
+		{
+			name:    "double submit: matching header",
+			mode:    CSRFDoubleSubmit,
+			headers: map[string]string{CSRFHeader: "secret"},
+			cookie:  "secret",
+			code:    http.StatusOK,
+		},
+		{
+			name:    "double submit: wrong header",
+			mode:    CSRFDoubleSubmit,
+			headers: map[string]string{CSRFHeader: "other"},
+			cookie:  "secret",
+			code:    http.StatusForbidden,
+		},
+		{
+			name:   "double submit: matching form field",
+			mode:   CSRFDoubleSubmit,
+			cookie: "secret",
+			form:   CSRFField + "=secret",
+			code:   http.StatusOK,
+		},
+		{
+			name:   "double submit: wrong form field",
+			mode:   CSRFDoubleSubmit,
+			cookie: "secret",
+			form:   CSRFField + "=other",
+			code:   http.StatusForbidden,
+		},
+		{
+			name: "double submit: no cookie",
+			mode: CSRFDoubleSubmit,
+			code: http.StatusForbidden,
+		},
+		{
+			name:    "off",
+			mode:    CSRFOff,
+			headers: map[string]string{"Origin": "https://evil.example.net"},
+			code:    http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, err := NewTransport(CookieConfig{
+				CSRF:           tt.mode,
+				TrustedOrigins: []string{"https://app.example.org"},
+			})
+			require.NoError(t, err)
+
+			app := fiber.New()
+			app.Post("/", func(c *fiber.Ctx) error {
+				if err := transport.CheckCSRF(c); err != nil {
+					return c.SendStatus(http.StatusForbidden)
+				}
+				return c.SendStatus(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/", strings.NewReader(tt.form))
+			if tt.form != "" {
+				req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: CSRFCookie, Value: tt.cookie})
+			}
+
+			resp, err := app.Test(req, 100)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+
+func TestTransport_CSRFToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		cookie  string
+		want    string
+		setsNew bool
+	}{
+		{name: "origin: no token", mode: CSRFOrigin},
+		{name: "double submit: existing cookie reused", mode: CSRFDoubleSubmit, cookie: "secret", want: "secret"},
+		{name: "double submit: new cookie", mode: CSRFDoubleSubmit, setsNew: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, err := NewTransport(CookieConfig{CSRF: tt.mode})
+			require.NoError(t, err)
+
+			var got string
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				got = transport.CSRFToken(c)
+				return c.SendStatus(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: CSRFCookie, Value: tt.cookie})
+			}
+			resp, err := app.Test(req, 100)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			if !tt.setsNew {
+				assert.Equal(t, tt.want, got)
+				assert.Empty(t, resp.Cookies())
+				return
+			}
+			require.NotEmpty(t, got)
+			require.Len(t, resp.Cookies(), 1)
+			assert.Equal(t, CSRFCookie, resp.Cookies()[0].Name)
+			assert.Equal(t, got, resp.Cookies()[0].Value)
+		})
+	}
+}
+
+func TestNewTransport_CSRF(t *testing.T) {
+	_, err := NewTransport(CookieConfig{CSRF: "maybe"})
+	assert.Error(t, err)
+
+	_, err = NewTransport(CookieConfig{TrustedOrigins: []string{"app.example.org"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,167 @@
+package tokens
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type (
+	denylistRepository interface {
+		Revoke(ctx context.Context, userID models.ModelID, jti string, expiresAt time.Time) error
+		RevokeAll(ctx context.Context, userID models.ModelID, exceptJTI string, expiresAt time.Time) ([]string, error)
+		IsRevoked(ctx context.Context, jti string) (bool, error)
+		DeleteExpired(ctx context.Context) (int64, error)
+	}
+	cacheEntry struct {
+		revoked   bool
+		expiresAt time.Time
+	}
+	// Denylist keeps revoked jtis in Postgres and caches lookups in memory.
+	// Lookups are cached for cacheTTL, so revocations made by other instances
+	// are seen within that window. Local revocations are cached until the
+	// token expires. The cache holds at most cacheSize jtis, evicted ones are
+	// looked up in Postgres again.
+	Denylist struct {
+		cacheTTL   time.Duration
+		cacheSize  int
+		repository denylistRepository
+
+		mu    sync.RWMutex
+		cache map[string]cacheEntry
+	}
+)
+
+// evictScan bounds the search for an expired entry to evict from a full
+// cache, so a put stays cheap under a flood of distinct tokens.
+const evictScan = 8
+
+func NewDenylist(cacheTTL time.Duration, cacheSize int, repository denylistRepository) *Denylist {
+	return &Denylist{
+		cacheTTL:   cacheTTL,
+		cacheSize:  cacheSize,
+		repository: repository,
+		cache:      make(map[string]cacheEntry),
+	}
+}
+
+func (d *Denylist) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	now := time.Now()
+
+	d.mu.RLock()
+	entry, ok := d.cache[jti]
+	d.mu.RUnlock()
+	if ok && entry.expiresAt.After(now) {
+		return entry.revoked, nil
+	}
+
+	revoked, err := d.repository.IsRevoked(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+
+	d.put(jti, cacheEntry{revoked, now.Add(d.cacheTTL)})
+
+	return revoked, nil
+}
+
+func (d *Denylist) Revoke(ctx context.Context, userID models.ModelID, jti string, expiresAt time.Time) error {
+	if err := d.repository.Revoke(ctx, userID, jti, expiresAt); err != nil {
+		return err
+	}
+	d.put(jti, cacheEntry{true, expiresAt})
+
+	return nil
+}
+
+func (d *Denylist) RevokeAll(ctx context.Context, userID models.ModelID, exceptJTI string, expiresAt time.Time) error {
+	jtis, err := d.repository.RevokeAll(ctx, userID, exceptJTI, expiresAt)
+	if err != nil {
+		return err
+	}
+	for _, jti := range jtis {
+		d.put(jti, cacheEntry{true, expiresAt})
+	}
+
+	return nil
+}
+
+// CollectGarbage purges expired entries from the cache and Postgres every
+// interval until ctx is done.
+func (d *Denylist) CollectGarbage(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			d.purge(time.Now())
+
+			n, err := d.repository.DeleteExpired(ctx)
+			if err != nil {
+				logger.Log.Error("denylist:collectGarbage", "DeleteExpired", err)
+				continue
+			}
+			logger.Log.Debug("denylist:collectGarbage", "deleted", n)
+		}
+	}()
+}
+
+// Cache records a revocation already stored in Postgres, e.g. by
+// Repository.Rotate, so this instance sees it before a cached lookup expires.
+func (d *Denylist) Cache(jti string, expiresAt time.Time) {
+	d.put(jti, cacheEntry{true, expiresAt})
+}
+
+func (d *Denylist) put(jti string, entry cacheEntry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.cache[jti]; !ok && d.cacheSize > 0 && len(d.cache) >= d.cacheSize {
+		d.evict(time.Now())
+	}
+	d.cache[jti] = entry
+}
+
+// evict drops one entry, an expired one if the first few looked at include
+// it. The caller holds d.mu.
+func (d *Denylist) evict(now time.Time) {
+	var victim string
+	scanned := 0
+	for jti, entry := range d.cache {
+		if scanned == 0 {
+			victim = jti
+		}
+		if !entry.expiresAt.After(now) {
+			victim = jti
+			break
+		}
+		if scanned++; scanned == evictScan {
+			break
+		}
+	}
+	delete(d.cache, victim)
+}
+
+func (d *Denylist) purge(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for jti, entry := range d.cache {
+		if !entry.expiresAt.After(now) {
+			delete(d.cache, jti)
+		}
+	}
+}
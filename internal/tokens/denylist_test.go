@@ -0,0 +1,106 @@
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens/mocks"
+)
+
+func TestDenylist_IsRevoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockdenylistRepository(ctrl)
+	d := NewDenylist(time.Minute, 10, repo)
+	ctx := context.Background()
+
+	repo.EXPECT().IsRevoked(gomock.Any(), "a").Return(false, nil).Times(1)
+	for i := 0; i < 3; i++ {
+		revoked, err := d.IsRevoked(ctx, "a")
+		require.NoError(t, err)
+		assert.False(t, revoked, "lookups within the cache TTL hit the cache")
+	}
+
+	repo.EXPECT().Revoke(gomock.Any(), models.ModelID("user"), "a", gomock.Any()).Return(nil)
+	require.NoError(t, d.Revoke(ctx, "user", "a", time.Now().Add(time.Hour)))
+
+	revoked, err := d.IsRevoked(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, revoked, "a local revocation replaces the cached lookup")
+
+	d.cache["a"] = cacheEntry{true, time.Now().Add(-time.Second)}
+	repo.EXPECT().IsRevoked(gomock.Any(), "a").Return(true, nil)
+	revoked, err = d.IsRevoked(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, revoked, "expired entries are looked up again")
+}
+
+func TestDenylist_Cache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockdenylistRepository(ctrl)
+	d := NewDenylist(time.Minute, 10, repo)
+	ctx := context.Background()
+
+	repo.EXPECT().IsRevoked(gomock.Any(), "a").Return(false, nil).Times(1)
+	revoked, err := d.IsRevoked(ctx, "a")
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	// revoked in Postgres by a reused refresh token
+	d.Cache("a", time.Now().Add(time.Hour))
+
+	revoked, err = d.IsRevoked(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, revoked, "the cached lookup is replaced without asking Postgres")
+}
+
+func TestDenylist_cacheSize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockdenylistRepository(ctrl)
+	repo.EXPECT().IsRevoked(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+
+	d := NewDenylist(time.Minute, 5, repo)
+	ctx := context.Background()
+
+	for i := 0; i < 100; i++ {
+		_, err := d.IsRevoked(ctx, fmt.Sprintf("jti-%d", i))
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(d.cache), 5)
+	}
+
+	d.cache = map[string]cacheEntry{
+		"live-1":  {false, time.Now().Add(time.Minute)},
+		"live-2":  {false, time.Now().Add(time.Minute)},
+		"live-3":  {false, time.Now().Add(time.Minute)},
+		"live-4":  {false, time.Now().Add(time.Minute)},
+		"expired": {false, time.Now().Add(-time.Minute)},
+	}
+	_, err := d.IsRevoked(ctx, "new")
+	require.NoError(t, err)
+	assert.Len(t, d.cache, 5)
+	assert.NotContains(t, d.cache, "expired", "an expired entry is evicted first")
+	assert.Contains(t, d.cache, "new")
+}
+
+func TestDenylist_purge(t *testing.T) {
+	d := NewDenylist(time.Minute, 0, nil)
+	now := time.Now()
+	d.cache["old"] = cacheEntry{true, now.Add(-time.Second)}
+	d.cache["new"] = cacheEntry{true, now.Add(time.Second)}
+
+	d.purge(now)
+
+	assert.Equal(t, map[string]cacheEntry{"new": {true, now.Add(time.Second)}}, d.cache)
+}
@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+type (
+	RefreshPayload struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	TokensResponse struct {
+		TokenType        string    `json:"token_type"`
+		AccessToken      string    `json:"access_token"`
+		AccessExpiresAt  time.Time `json:"access_expires_at"`
+		RefreshToken     string    `json:"refresh_token"`
+		RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	}
+)
@@ -0,0 +1,146 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+type (
+	tokenService interface {
+		Refresh(ctx context.Context, refreshToken string, client appdto.Client) (appdto.Tokens, error)
+		Logout(ctx context.Context, claims *appdto.Claims) error
+		LogoutAll(ctx context.Context, userID models.ModelID) error
+		Sessions(ctx context.Context, userID models.ModelID, currentJTI string) ([]dto.SessionResponse, error)
+		RevokeSession(ctx context.Context, userID, id models.ModelID) (string, error)
+	}
+	handler struct {
+		service   tokenService
+		transport *Transport
+	}
+)
+
+func newHandler(ts tokenService, transport *Transport) *handler {
+	return &handler{ts, transport}
+}
+
+// refresh takes the refresh token from its cookie or, for clients without
+// cookies, from the JSON body. Body clients get the new tokens in the body.
+// It skips the CSRF check: a forged refresh only rotates the victim's own
+// cookies, and sessions predating double-submit mode get their csrf_token
+// here.
+func (h *handler) refresh(c *fiber.Ctx) error {
+	token := c.Cookies(RefreshCookie)
+	fromBody := false
+	if token == "" {
+		var body dto.RefreshPayload
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+		}
+		token, fromBody = body.RefreshToken, true
+	}
+
+	tokens, err := h.service.Refresh(c.Context(), token, ClientOf(c))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTokenReused) {
+			logger.Log.Warn("tokens:handler:refresh", "reuse detected, family revoked", c.IP())
+		}
+		if errors.Is(err, apperrors.ErrInvalidToken) || errors.Is(err, apperrors.ErrTokenReused) {
+			return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+		}
+		if errors.Is(err, apperrors.ErrInactive) {
+			return c.Status(fiber.StatusForbidden).SendString(http.StatusText(fiber.StatusForbidden))
+		}
+		logger.Log.Error("tokens:handler:refresh", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+
+	return h.transport.Write(c, tokens, fromBody || WantsJSON(c))
+}
+
+func (h *handler) logout(c *fiber.Ctx) error {
+	claims, ok := c.Locals("token").(*appdto.Claims)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+	}
+
+	if err := h.service.Logout(c.Context(), claims); err != nil {
+		logger.Log.Error("tokens:handler:logout", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+
+	h.transport.Clear(c)
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *handler) logoutAll(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+	}
+
+	if err := h.service.LogoutAll(c.Context(), models.ModelID(userID)); err != nil {
+		logger.Log.Error("tokens:handler:logoutAll", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+
+	h.transport.Clear(c)
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *handler) sessions(c *fiber.Ctx) error {
+	claims, ok := c.Locals("token").(*appdto.Claims)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+	}
+
+	out, err := h.service.Sessions(c.Context(), models.ModelID(claims.SUB), claims.JTI)
+	if err != nil {
+		logger.Log.Error("tokens:handler:sessions", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+	if len(out) == 0 {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(out)
+}
+
+// revokeSession ends one session. Ending the current one logs the caller out.
+func (h *handler) revokeSession(c *fiber.Ctx) error {
+	claims, ok := c.Locals("token").(*appdto.Claims)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+	}
+
+	id := c.Params("id")
+	if !utils.IsGUID(id) {
+		return c.Status(fiber.StatusNotFound).SendString(http.StatusText(fiber.StatusNotFound))
+	}
+
+	jti, err := h.service.RevokeSession(c.Context(), models.ModelID(claims.SUB), models.ModelID(id))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).SendString(http.StatusText(fiber.StatusNotFound))
+		}
+		logger.Log.Error("tokens:handler:revokeSession", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+
+	if jti == claims.JTI {
+		h.transport.Clear(c)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
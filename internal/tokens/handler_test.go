@@ -0,0 +1,281 @@
+package tokens
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens/mocks"
+)
+
+func newTestHandler(t *testing.T, ts tokenService) *handler {
+	transport, err := NewTransport(CookieConfig{})
+	require.NoError(t, err)
+	return newHandler(ts, transport)
+}
+
+func Test_refresh(t *testing.T) {
+	tests := []struct {
+		name    string
+		cookie  string
+		body    string
+		token   string
+		err     error
+		code    int
+		cookies bool
+	}{
+		{
+			name:    "positive test #1, refresh token from the cookie",
+			cookie:  "old",
+			token:   "old",
+			code:    http.StatusOK,
+			cookies: true,
+		},
+		{
+			name:    "positive test #2, refresh token from the body",
+			body:    `{"refresh_token":"old"}`,
+			token:   "old",
+			code:    http.StatusOK,
+			cookies: true,
+		},
+		{
+			name:   "negative test #3, reused token",
+			cookie: "old",
+			token:  "old",
+			err:    apperrors.ErrTokenReused,
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "negative test #4, unknown token",
+			cookie: "old",
+			token:  "old",
+			err:    apperrors.ErrInvalidToken,
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "negative test #5, disabled account",
+			cookie: "old",
+			token:  "old",
+			err:    apperrors.ErrInactive,
+			code:   http.StatusForbidden,
+		},
+		{
+			name:   "negative test #6, unknown service error",
+			cookie: "old",
+			token:  "old",
+			err:    errors.New("db is down"),
+			code:   http.StatusInternalServerError,
+		},
+		{
+			name: "negative test #7, no token",
+			body: `{"refresh_token":`,
+			code: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ts := mocks.NewMocktokenService(ctrl)
+			if tt.token != "" {
+				ts.EXPECT().Refresh(gomock.Any(), tt.token, gomock.Any()).
+					Return(appdto.Tokens{AccessToken: "access", RefreshToken: "new"}, tt.err)
+			}
+
+			app := fiber.New()
+			app.Post("/", newTestHandler(t, ts).refresh)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: RefreshCookie, Value: tt.cookie})
+			}
+
+			resp, err := app.Test(req, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+
+			var refreshed bool
+			for _, c := range resp.Cookies() {
+				if c.Name == RefreshCookie && c.Value == "new" {
+					refreshed = true
+				}
+			}
+			assert.Equal(t, tt.cookies, refreshed)
+		})
+	}
+}
+
+func Test_sessions(t *testing.T) {
+	tests := []struct {
+		name    string
+		claims  *appdto.Claims
+		out     []dto.SessionResponse
+		err     error
+		service bool
+		code    int
+	}{
+		{
+			name: "negative test #1, unauthenticated",
+			code: http.StatusUnauthorized,
+		},
+		{
+			name:    "negative test #2, unknown service error",
+			claims:  &appdto.Claims{SUB: "user", JTI: "jti"},
+			service: true,
+			err:     errors.New("db is down"),
+			code:    http.StatusInternalServerError,
+		},
+		{
+			name:    "positive test #3, no sessions",
+			claims:  &appdto.Claims{SUB: "user", JTI: "jti"},
+			service: true,
+			code:    http.StatusNoContent,
+		},
+		{
+			name:    "positive test #4, sessions of the caller",
+			claims:  &appdto.Claims{SUB: "user", JTI: "jti"},
+			service: true,
+			out:     []dto.SessionResponse{{ID: "phone", Current: true}},
+			code:    http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ts := mocks.NewMocktokenService(ctrl)
+			if tt.service {
+				ts.EXPECT().Sessions(gomock.Any(), models.ModelID("user"), "jti").Return(tt.out, tt.err)
+			}
+
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				if tt.claims != nil {
+					c.Locals("token", tt.claims)
+				}
+				return c.Next()
+			}, newTestHandler(t, ts).sessions)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil), 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+
+func Test_revokeSession(t *testing.T) {
+	const sessionID = "0b6f3a52-6d0e-4f0b-8a5e-3c1d2e4f5a6b"
+
+	tests := []struct {
+		name    string
+		claims  *appdto.Claims
+		id      string
+		jti     string
+		err     error
+		service bool
+		code    int
+		cleared bool
+	}{
+		{
+			name: "negative test #1, unauthenticated",
+			id:   sessionID,
+			code: http.StatusUnauthorized,
+		},
+		{
+			name:   "negative test #2, malformed id",
+			claims: &appdto.Claims{SUB: "user", JTI: "jti"},
+			id:     "phone",
+			code:   http.StatusNotFound,
+		},
+		{
+			name:    "negative test #3, session of another user",
+			claims:  &appdto.Claims{SUB: "user", JTI: "jti"},
+			id:      sessionID,
+			service: true,
+			err:     apperrors.ErrNotFound,
+			code:    http.StatusNotFound,
+		},
+		{
+			name:    "negative test #4, unknown service error",
+			claims:  &appdto.Claims{SUB: "user", JTI: "jti"},
+			id:      sessionID,
+			service: true,
+			err:     errors.New("db is down"),
+			code:    http.StatusInternalServerError,
+		},
+		{
+			name:    "positive test #5, another session of the caller",
+			claims:  &appdto.Claims{SUB: "user", JTI: "jti"},
+			id:      sessionID,
+			service: true,
+			jti:     "jti-phone",
+			code:    http.StatusNoContent,
+		},
+		{
+			name:    "positive test #6, the current session logs out",
+			claims:  &appdto.Claims{SUB: "user", JTI: "jti"},
+			id:      sessionID,
+			service: true,
+			jti:     "jti",
+			code:    http.StatusNoContent,
+			cleared: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ts := mocks.NewMocktokenService(ctrl)
+			if tt.service {
+				ts.EXPECT().RevokeSession(gomock.Any(), models.ModelID("user"), models.ModelID(tt.id)).Return(tt.jti, tt.err)
+			}
+
+			app := fiber.New()
+			app.Delete("/:id", func(c *fiber.Ctx) error {
+				if tt.claims != nil {
+					c.Locals("token", tt.claims)
+				}
+				return c.Next()
+			}, newTestHandler(t, ts).revokeSession)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/"+tt.id, nil), 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+
+			var cleared bool
+			for _, c := range resp.Cookies() {
+				if c.Name == RefreshCookie && c.Value == "" {
+					cleared = true
+				}
+			}
+			assert.Equal(t, tt.cleared, cleared)
+		})
+	}
+}
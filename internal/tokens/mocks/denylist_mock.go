@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: denylist.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockdenylistRepository is a mock of denylistRepository interface.
+type MockdenylistRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockdenylistRepositoryMockRecorder
+}
+
+// MockdenylistRepositoryMockRecorder is the mock recorder for MockdenylistRepository.
+type MockdenylistRepositoryMockRecorder struct {
+	mock *MockdenylistRepository
+}
+
+// NewMockdenylistRepository creates a new mock instance.
+func NewMockdenylistRepository(ctrl *gomock.Controller) *MockdenylistRepository {
+	mock := &MockdenylistRepository{ctrl: ctrl}
+	mock.recorder = &MockdenylistRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockdenylistRepository) EXPECT() *MockdenylistRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpired mocks base method.
+func (m *MockdenylistRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockdenylistRepositoryMockRecorder) DeleteExpired(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockdenylistRepository)(nil).DeleteExpired), ctx)
+}
+
+// IsRevoked mocks base method.
+func (m *MockdenylistRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRevoked", ctx, jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRevoked indicates an expected call of IsRevoked.
+func (mr *MockdenylistRepositoryMockRecorder) IsRevoked(ctx, jti interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*MockdenylistRepository)(nil).IsRevoked), ctx, jti)
+}
+
+// Revoke mocks base method.
+func (m *MockdenylistRepository) Revoke(ctx context.Context, userID models.ModelID, jti string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userID, jti, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockdenylistRepositoryMockRecorder) Revoke(ctx, userID, jti, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockdenylistRepository)(nil).Revoke), ctx, userID, jti, expiresAt)
+}
+
+// RevokeAll mocks base method.
+func (m *MockdenylistRepository) RevokeAll(ctx context.Context, userID models.ModelID, exceptJTI string, expiresAt time.Time) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAll", ctx, userID, exceptJTI, expiresAt)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAll indicates an expected call of RevokeAll.
+func (mr *MockdenylistRepositoryMockRecorder) RevokeAll(ctx, userID, exceptJTI, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAll", reflect.TypeOf((*MockdenylistRepository)(nil).RevokeAll), ctx, userID, exceptJTI, expiresAt)
+}
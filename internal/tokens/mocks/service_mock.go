@@ -0,0 +1,258 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockJWTService is a mock of JWTService interface.
+type MockJWTService struct {
+	ctrl     *gomock.Controller
+	recorder *MockJWTServiceMockRecorder
+}
+
+// MockJWTServiceMockRecorder is the mock recorder for MockJWTService.
+type MockJWTServiceMockRecorder struct {
+	mock *MockJWTService
+}
+
+// NewMockJWTService creates a new mock instance.
+func NewMockJWTService(ctrl *gomock.Controller) *MockJWTService {
+	mock := &MockJWTService{ctrl: ctrl}
+	mock.recorder = &MockJWTServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJWTService) EXPECT() *MockJWTServiceMockRecorder {
+	return m.recorder
+}
+
+// BuildJWTString mocks base method.
+func (m *MockJWTService) BuildJWTString(userID models.ModelID, role, jti string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildJWTString", userID, role, jti)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuildJWTString indicates an expected call of BuildJWTString.
+func (mr *MockJWTServiceMockRecorder) BuildJWTString(userID, role, jti interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildJWTString", reflect.TypeOf((*MockJWTService)(nil).BuildJWTString), userID, role, jti)
+}
+
+// MockrefreshTokenRepository is a mock of refreshTokenRepository interface.
+type MockrefreshTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockrefreshTokenRepositoryMockRecorder
+}
+
+// MockrefreshTokenRepositoryMockRecorder is the mock recorder for MockrefreshTokenRepository.
+type MockrefreshTokenRepositoryMockRecorder struct {
+	mock *MockrefreshTokenRepository
+}
+
+// NewMockrefreshTokenRepository creates a new mock instance.
+func NewMockrefreshTokenRepository(ctrl *gomock.Controller) *MockrefreshTokenRepository {
+	mock := &MockrefreshTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockrefreshTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockrefreshTokenRepository) EXPECT() *MockrefreshTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Rotate mocks base method.
+func (m *MockrefreshTokenRepository) Rotate(ctx context.Context, hash string, next *models.RefreshToken, ip string, denyUntil time.Time) (*models.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", ctx, hash, next, ip, denyUntil)
+	ret0, _ := ret[0].(*models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockrefreshTokenRepositoryMockRecorder) Rotate(ctx, hash, next, ip, denyUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockrefreshTokenRepository)(nil).Rotate), ctx, hash, next, ip, denyUntil)
+}
+
+// Save mocks base method.
+func (m *MockrefreshTokenRepository) Save(ctx context.Context, t *models.RefreshToken, session *models.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, t, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockrefreshTokenRepositoryMockRecorder) Save(ctx, t, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockrefreshTokenRepository)(nil).Save), ctx, t, session)
+}
+
+// MocksessionRepository is a mock of sessionRepository interface.
+type MocksessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MocksessionRepositoryMockRecorder
+}
+
+// MocksessionRepositoryMockRecorder is the mock recorder for MocksessionRepository.
+type MocksessionRepositoryMockRecorder struct {
+	mock *MocksessionRepository
+}
+
+// NewMocksessionRepository creates a new mock instance.
+func NewMocksessionRepository(ctrl *gomock.Controller) *MocksessionRepository {
+	mock := &MocksessionRepository{ctrl: ctrl}
+	mock.recorder = &MocksessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocksessionRepository) EXPECT() *MocksessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindSession mocks base method.
+func (m *MocksessionRepository) FindSession(ctx context.Context, userID, id models.ModelID) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSession", ctx, userID, id)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSession indicates an expected call of FindSession.
+func (mr *MocksessionRepositoryMockRecorder) FindSession(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSession", reflect.TypeOf((*MocksessionRepository)(nil).FindSession), ctx, userID, id)
+}
+
+// FindSessions mocks base method.
+func (m *MocksessionRepository) FindSessions(ctx context.Context, userID models.ModelID) ([]models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSessions", ctx, userID)
+	ret0, _ := ret[0].([]models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSessions indicates an expected call of FindSessions.
+func (mr *MocksessionRepositoryMockRecorder) FindSessions(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSessions", reflect.TypeOf((*MocksessionRepository)(nil).FindSessions), ctx, userID)
+}
+
+// MockaccountRepository is a mock of accountRepository interface.
+type MockaccountRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockaccountRepositoryMockRecorder
+}
+
+// MockaccountRepositoryMockRecorder is the mock recorder for MockaccountRepository.
+type MockaccountRepositoryMockRecorder struct {
+	mock *MockaccountRepository
+}
+
+// NewMockaccountRepository creates a new mock instance.
+func NewMockaccountRepository(ctrl *gomock.Controller) *MockaccountRepository {
+	mock := &MockaccountRepository{ctrl: ctrl}
+	mock.recorder = &MockaccountRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockaccountRepository) EXPECT() *MockaccountRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindAccount mocks base method.
+func (m *MockaccountRepository) FindAccount(ctx context.Context, userID models.ModelID) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAccount", ctx, userID)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAccount indicates an expected call of FindAccount.
+func (mr *MockaccountRepositoryMockRecorder) FindAccount(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAccount", reflect.TypeOf((*MockaccountRepository)(nil).FindAccount), ctx, userID)
+}
+
+// Mockrevoker is a mock of revoker interface.
+type Mockrevoker struct {
+	ctrl     *gomock.Controller
+	recorder *MockrevokerMockRecorder
+}
+
+// MockrevokerMockRecorder is the mock recorder for Mockrevoker.
+type MockrevokerMockRecorder struct {
+	mock *Mockrevoker
+}
+
+// NewMockrevoker creates a new mock instance.
+func NewMockrevoker(ctrl *gomock.Controller) *Mockrevoker {
+	mock := &Mockrevoker{ctrl: ctrl}
+	mock.recorder = &MockrevokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockrevoker) EXPECT() *MockrevokerMockRecorder {
+	return m.recorder
+}
+
+// Cache mocks base method.
+func (m *Mockrevoker) Cache(jti string, expiresAt time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Cache", jti, expiresAt)
+}
+
+// Cache indicates an expected call of Cache.
+func (mr *MockrevokerMockRecorder) Cache(jti, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cache", reflect.TypeOf((*Mockrevoker)(nil).Cache), jti, expiresAt)
+}
+
+// Revoke mocks base method.
+func (m *Mockrevoker) Revoke(ctx context.Context, userID models.ModelID, jti string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userID, jti, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockrevokerMockRecorder) Revoke(ctx, userID, jti, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*Mockrevoker)(nil).Revoke), ctx, userID, jti, expiresAt)
+}
+
+// RevokeAll mocks base method.
+func (m *Mockrevoker) RevokeAll(ctx context.Context, userID models.ModelID, exceptJTI string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAll", ctx, userID, exceptJTI, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAll indicates an expected call of RevokeAll.
+func (mr *MockrevokerMockRecorder) RevokeAll(ctx, userID, exceptJTI, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAll", reflect.TypeOf((*Mockrevoker)(nil).RevokeAll), ctx, userID, exceptJTI, expiresAt)
+}
@@ -0,0 +1,357 @@
+package tokens
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+const revokeSessionQuery = `
+	UPDATE sessions SET revoked_at = NOW()
+	WHERE jti = $1 AND revoked_at IS NULL
+`
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+// FindAccount reads what tokens need to know about the user.
+func (r *Repository) FindAccount(ctx context.Context, userID models.ModelID) (*models.User, error) {
+	user := models.User{ID: userID}
+	query := `
+		SELECT role, disabled_at FROM users WHERE id = $1
+	`
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&user.Role, &user.DisabledAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Save stores the first refresh token of a family along with its session.
+func (r *Repository) Save(ctx context.Context, t *models.RefreshToken, session *models.Session) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO refresh_tokens (user_id, family_id, jti, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err = tx.ExecContext(ctx, query, t.UserID, t.FamilyID, t.JTI, t.TokenHash, t.ExpiresAt); err != nil {
+		return err
+	}
+
+	sessionQuery := `
+		INSERT INTO sessions (user_id, jti, ip, user_agent, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err = tx.ExecContext(ctx, sessionQuery, session.UserID, session.JTI, session.IP, session.UserAgent, session.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Rotate exchanges the refresh token with the given hash for next, which
+// inherits the user, family and jti of the old token, and records ip as the
+// last seen address of the session. Presenting a token that was already
+// rotated or revoked revokes the whole family, denylists its jti until
+// denyUntil and returns the old token with apperrors.ErrTokenReused.
+func (r *Repository) Rotate(
+	ctx context.Context,
+	hash string,
+	next *models.RefreshToken,
+	ip string,
+	denyUntil time.Time,
+) (*models.RefreshToken, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var old models.RefreshToken
+	query := `
+		SELECT id, user_id, family_id, jti, expires_at, rotated_at, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+		FOR UPDATE
+	`
+	err = tx.QueryRowContext(ctx, query, hash).Scan(
+		&old.ID,
+		&old.UserID,
+		&old.FamilyID,
+		&old.JTI,
+		&old.ExpiresAt,
+		&old.RotatedAt,
+		&old.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if old.RotatedAt.Valid || old.RevokedAt.Valid {
+		revokeQuery := `
+			UPDATE refresh_tokens SET revoked_at = NOW()
+			WHERE family_id = $1 AND revoked_at IS NULL
+		`
+		if _, err = tx.ExecContext(ctx, revokeQuery, old.FamilyID); err != nil {
+			return nil, err
+		}
+		if _, err = tx.ExecContext(ctx, revokeSessionQuery, old.JTI); err != nil {
+			return nil, err
+		}
+		// the access token of the family must not outlive the detection
+		denyQuery := `
+			INSERT INTO revoked_tokens (jti, user_id, expires_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (jti) DO UPDATE SET expires_at = GREATEST(revoked_tokens.expires_at, EXCLUDED.expires_at)
+		`
+		if _, err = tx.ExecContext(ctx, denyQuery, old.JTI, old.UserID, denyUntil); err != nil {
+			return nil, err
+		}
+		if err = tx.Commit(); err != nil {
+			return nil, err
+		}
+		return &old, apperrors.ErrTokenReused
+	}
+
+	if !old.ExpiresAt.After(time.Now()) {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	rotateQuery := `
+		UPDATE refresh_tokens SET rotated_at = NOW() WHERE id = $1
+	`
+	if _, err = tx.ExecContext(ctx, rotateQuery, old.ID); err != nil {
+		return nil, err
+	}
+
+	next.UserID = old.UserID
+	next.FamilyID = old.FamilyID
+	next.JTI = old.JTI
+
+	insertQuery := `
+		INSERT INTO refresh_tokens (user_id, family_id, jti, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err = tx.ExecContext(ctx, insertQuery, next.UserID, next.FamilyID, next.JTI, next.TokenHash, next.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionQuery := `
+		UPDATE sessions SET last_seen_at = NOW(), ip = $2, expires_at = $3
+		WHERE jti = $1
+	`
+	if _, err = tx.ExecContext(ctx, sessionQuery, next.JTI, ip, next.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	return &old, tx.Commit()
+}
+
+// Revoke denylists jti until expiresAt and revokes its refresh token family.
+func (r *Repository) Revoke(ctx context.Context, userID models.ModelID, jti string, expiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	denyQuery := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`
+	if _, err = tx.ExecContext(ctx, denyQuery, jti, userID, expiresAt); err != nil {
+		return err
+	}
+
+	revokeQuery := `
+		UPDATE refresh_tokens SET revoked_at = NOW()
+		WHERE jti = $1 AND revoked_at IS NULL
+	`
+	if _, err = tx.ExecContext(ctx, revokeQuery, jti); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, revokeSessionQuery, jti); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RevokeAll denylists every jti of the user's live refresh token families
+// but exceptJTI until expiresAt and revokes the families. It returns the
+// revoked jtis.
+func (r *Repository) RevokeAll(ctx context.Context, userID models.ModelID, exceptJTI string, expiresAt time.Time) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE refresh_tokens SET revoked_at = NOW()
+		WHERE user_id = $1 AND revoked_at IS NULL AND jti <> $2
+		RETURNING jti
+	`
+	rows, err := tx.QueryContext(ctx, query, userID, exceptJTI)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seen := make(map[string]struct{})
+	jtis := make([]string, 0)
+	for rows.Next() {
+		var jti string
+		if err = rows.Scan(&jti); err != nil {
+			return nil, err
+		}
+		if _, ok := seen[jti]; ok {
+			continue
+		}
+		seen[jti] = struct{}{}
+		jtis = append(jtis, jti)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	denyQuery := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`
+	for _, jti := range jtis {
+		if _, err = tx.ExecContext(ctx, denyQuery, jti, userID, expiresAt); err != nil {
+			return nil, err
+		}
+		if _, err = tx.ExecContext(ctx, revokeSessionQuery, jti); err != nil {
+			return nil, err
+		}
+	}
+
+	return jtis, tx.Commit()
+}
+
+func (r *Repository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1 AND expires_at > NOW())
+	`
+	if err := r.db.QueryRowContext(ctx, query, jti).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// DeleteExpired drops denylist entries, refresh tokens and sessions past
+// their expiry, the access and refresh tokens they guard are rejected anyway.
+func (r *Repository) DeleteExpired(ctx context.Context) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var total int64
+	for _, query := range []string{
+		`DELETE FROM revoked_tokens WHERE expires_at <= NOW()`,
+		`DELETE FROM refresh_tokens WHERE expires_at <= NOW()`,
+		`DELETE FROM sessions WHERE expires_at <= NOW()`,
+	} {
+		res, err := tx.ExecContext(ctx, query)
+		if err != nil {
+			return 0, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+
+	return total, tx.Commit()
+}
+
+// FindSessions returns the live sessions of the user, most recently seen
+// first.
+func (r *Repository) FindSessions(ctx context.Context, userID models.ModelID) ([]models.Session, error) {
+	query := `
+		SELECT id, user_id, jti, ip, user_agent, expires_at, last_seen_at, created_at
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_seen_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.Session
+	for rows.Next() {
+		var s models.Session
+		if err = rows.Scan(
+			&s.ID,
+			&s.UserID,
+			&s.JTI,
+			&s.IP,
+			&s.UserAgent,
+			&s.ExpiresAt,
+			&s.LastSeenAt,
+			&s.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+
+	return out, rows.Err()
+}
+
+// FindSession returns the live session with the given id of the user.
+func (r *Repository) FindSession(ctx context.Context, userID, id models.ModelID) (*models.Session, error) {
+	s := models.Session{ID: id, UserID: userID}
+	query := `
+		SELECT jti, expires_at FROM sessions
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL AND expires_at > NOW()
+	`
+	if err := r.db.QueryRowContext(ctx, query, id, userID).Scan(&s.JTI, &s.ExpiresAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *Repository) TouchSession(ctx context.Context, jti string) error {
+	query := `
+		UPDATE sessions SET last_seen_at = NOW() WHERE jti = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, jti)
+	return err
+}
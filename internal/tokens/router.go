@@ -0,0 +1,24 @@
+package tokens
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserMiddleware interface {
+	Auth(c *fiber.Ctx) error
+}
+
+func SetupRouter(
+	r fiber.Router,
+	mw UserMiddleware,
+	transport *Transport,
+	ts tokenService,
+) {
+	handle := newHandler(ts, transport)
+
+	r.Post("token/refresh", handle.refresh)
+	r.Post("logout", mw.Auth, handle.logout)
+	r.Post("logout-all", mw.Auth, handle.logoutAll)
+	r.Get("sessions", mw.Auth, handle.sessions)
+	r.Delete("sessions/:id", mw.Auth, handle.revokeSession)
+}
@@ -0,0 +1,211 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+type (
+	JWTService interface {
+		BuildJWTString(userID models.ModelID, role, jti string) (string, error)
+	}
+	refreshTokenRepository interface {
+		Save(ctx context.Context, t *models.RefreshToken, session *models.Session) error
+		Rotate(
+			ctx context.Context,
+			hash string,
+			next *models.RefreshToken,
+			ip string,
+			denyUntil time.Time,
+		) (*models.RefreshToken, error)
+	}
+	sessionRepository interface {
+		FindSessions(ctx context.Context, userID models.ModelID) ([]models.Session, error)
+		FindSession(ctx context.Context, userID, id models.ModelID) (*models.Session, error)
+	}
+	accountRepository interface {
+		FindAccount(ctx context.Context, userID models.ModelID) (*models.User, error)
+	}
+	revoker interface {
+		Revoke(ctx context.Context, userID models.ModelID, jti string, expiresAt time.Time) error
+		RevokeAll(ctx context.Context, userID models.ModelID, exceptJTI string, expiresAt time.Time) error
+		Cache(jti string, expiresAt time.Time)
+	}
+	Service struct {
+		accessTTL              time.Duration
+		refreshTTL             time.Duration
+		jwtService             JWTService
+		refreshTokenRepository refreshTokenRepository
+		accountRepository      accountRepository
+		sessionRepository      sessionRepository
+		revoker                revoker
+	}
+)
+
+func NewService(
+	accessTTL, refreshTTL time.Duration,
+	jwtService JWTService,
+	rr refreshTokenRepository,
+	ar accountRepository,
+	sr sessionRepository,
+	revoker revoker,
+) *Service {
+	return &Service{accessTTL, refreshTTL, jwtService, rr, ar, sr, revoker}
+}
+
+// Issue starts a new token family and session for the user, e.g. on login.
+// Disabled accounts get apperrors.ErrInactive.
+func (s *Service) Issue(ctx context.Context, userID models.ModelID, client appdto.Client) (appdto.Tokens, error) {
+	account, err := s.account(ctx, userID)
+	if err != nil {
+		return appdto.Tokens{}, err
+	}
+
+	raw, rt := s.newRefreshToken()
+	rt.UserID = userID
+	rt.FamilyID = models.ModelID(utils.GenerateGUID())
+	rt.JTI = utils.GenerateGUID()
+
+	session := &models.Session{
+		UserID:    userID,
+		JTI:       rt.JTI,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		ExpiresAt: rt.ExpiresAt,
+	}
+	if err = s.refreshTokenRepository.Save(ctx, rt, session); err != nil {
+		return appdto.Tokens{}, err
+	}
+
+	return s.tokens(rt, account.Role, raw)
+}
+
+// Refresh rotates the refresh token and issues a new access token with the
+// jti of the family. The role is read afresh, so role changes apply on the
+// next refresh. A reused refresh token revokes the family along with its
+// access token.
+func (s *Service) Refresh(ctx context.Context, refreshToken string, client appdto.Client) (appdto.Tokens, error) {
+	if refreshToken == "" {
+		return appdto.Tokens{}, apperrors.ErrInvalidToken
+	}
+
+	raw, next := s.newRefreshToken()
+	denyUntil := time.Now().Add(s.accessTTL)
+	old, err := s.refreshTokenRepository.Rotate(ctx, utils.HashSecret(refreshToken), next, client.IP, denyUntil)
+	if errors.Is(err, apperrors.ErrTokenReused) && old != nil {
+		// the repository denylisted the jti, don't wait for a cached lookup
+		// to expire
+		s.revoker.Cache(old.JTI, denyUntil)
+	}
+	if err != nil {
+		return appdto.Tokens{}, err
+	}
+
+	account, err := s.account(ctx, next.UserID)
+	if err != nil {
+		return appdto.Tokens{}, err
+	}
+
+	return s.tokens(next, account.Role, raw)
+}
+
+// Logout revokes the access token with the given claims and its refresh
+// token family.
+func (s *Service) Logout(ctx context.Context, claims *appdto.Claims) error {
+	expiresAt := time.Now().Add(s.accessTTL)
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	return s.revoker.Revoke(ctx, models.ModelID(claims.SUB), claims.JTI, expiresAt)
+}
+
+// LogoutAll revokes every session of the user.
+func (s *Service) LogoutAll(ctx context.Context, userID models.ModelID) error {
+	return s.RevokeSessions(ctx, userID, "")
+}
+
+// RevokeSessions revokes the sessions of the user but the one of exceptJTI.
+// Access tokens live at most accessTTL, so the denylist entries can expire
+// after that.
+func (s *Service) RevokeSessions(ctx context.Context, userID models.ModelID, exceptJTI string) error {
+	return s.revoker.RevokeAll(ctx, userID, exceptJTI, time.Now().Add(s.accessTTL))
+}
+
+// Sessions lists the live sessions of the user, marking the one of
+// currentJTI.
+func (s *Service) Sessions(ctx context.Context, userID models.ModelID, currentJTI string) ([]dto.SessionResponse, error) {
+	sessions, err := s.sessionRepository.FindSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		out = append(out, dto.SessionResponse{
+			ID:         string(session.ID),
+			IP:         session.IP,
+			UserAgent:  session.UserAgent,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.JTI == currentJTI,
+		})
+	}
+	return out, nil
+}
+
+// RevokeSession ends the session with the given id of the user and returns
+// its jti.
+func (s *Service) RevokeSession(ctx context.Context, userID, id models.ModelID) (string, error) {
+	session, err := s.sessionRepository.FindSession(ctx, userID, id)
+	if err != nil {
+		return "", err
+	}
+
+	if err = s.revoker.Revoke(ctx, userID, session.JTI, time.Now().Add(s.accessTTL)); err != nil {
+		return "", err
+	}
+	return session.JTI, nil
+}
+
+func (s *Service) newRefreshToken() (string, *models.RefreshToken) {
+	raw := utils.GenerateSecret()
+
+	return raw, &models.RefreshToken{
+		TokenHash: utils.HashSecret(raw),
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	}
+}
+
+func (s *Service) account(ctx context.Context, userID models.ModelID) (*models.User, error) {
+	account, err := s.accountRepository.FindAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account.DisabledAt.Valid {
+		return nil, apperrors.ErrInactive
+	}
+	return account, nil
+}
+
+func (s *Service) tokens(rt *models.RefreshToken, role, raw string) (appdto.Tokens, error) {
+	access, err := s.jwtService.BuildJWTString(rt.UserID, role, rt.JTI)
+	if err != nil {
+		return appdto.Tokens{}, err
+	}
+
+	return appdto.Tokens{
+		AccessToken:      access,
+		AccessExpiresAt:  time.Now().Add(s.accessTTL),
+		RefreshToken:     raw,
+		RefreshExpiresAt: rt.ExpiresAt,
+	}, nil
+}
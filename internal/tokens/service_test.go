@@ -0,0 +1,200 @@
+package tokens
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens/mocks"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+type serviceMocks struct {
+	jwt      *mocks.MockJWTService
+	refresh  *mocks.MockrefreshTokenRepository
+	accounts *mocks.MockaccountRepository
+	sessions *mocks.MocksessionRepository
+	revoker  *mocks.Mockrevoker
+}
+
+func newTestService(ctrl *gomock.Controller) (*Service, serviceMocks) {
+	m := serviceMocks{
+		jwt:      mocks.NewMockJWTService(ctrl),
+		refresh:  mocks.NewMockrefreshTokenRepository(ctrl),
+		accounts: mocks.NewMockaccountRepository(ctrl),
+		sessions: mocks.NewMocksessionRepository(ctrl),
+		revoker:  mocks.NewMockrevoker(ctrl),
+	}
+	return NewService(time.Minute, time.Hour, m.jwt, m.refresh, m.accounts, m.sessions, m.revoker), m
+}
+
+// family mimics Repository.Rotate: the token with the given hash is swapped
+// for next, which inherits the user and jti, and presenting a rotated token
+// revokes the family and denylists its jti.
+type family struct {
+	userID     models.ModelID
+	jti        string
+	current    string
+	rotated    map[string]bool
+	revoked    bool
+	deniedTill time.Time
+}
+
+func (f *family) rotate(_ context.Context, hash string, next *models.RefreshToken, _ string, denyUntil time.Time) (*models.RefreshToken, error) {
+	switch {
+	case f.rotated[hash], f.revoked:
+		f.revoked = true
+		if denyUntil.After(f.deniedTill) {
+			f.deniedTill = denyUntil
+		}
+		return &models.RefreshToken{UserID: f.userID, JTI: f.jti, TokenHash: hash}, apperrors.ErrTokenReused
+	case hash != f.current:
+		return nil, apperrors.ErrInvalidToken
+	}
+	f.rotated[hash] = true
+	f.current = next.TokenHash
+	next.UserID, next.JTI = f.userID, f.jti
+	return &models.RefreshToken{TokenHash: hash}, nil
+}
+
+func TestService_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestService(ctrl)
+	ctx := context.Background()
+
+	first := utils.GenerateSecret()
+	f := &family{userID: "user", jti: "jti", current: utils.HashSecret(first), rotated: map[string]bool{}}
+	m.refresh.EXPECT().Rotate(gomock.Any(), gomock.Any(), gomock.Any(), "10.0.0.1", gomock.Any()).DoAndReturn(f.rotate).AnyTimes()
+	// a reuse also denylists the jti in the local cache
+	m.revoker.EXPECT().Cache("jti", gomock.Any()).Times(2)
+
+	// the role is read on every refresh, so a promotion shows on the next one
+	gomock.InOrder(
+		m.accounts.EXPECT().FindAccount(gomock.Any(), models.ModelID("user")).Return(&models.User{Role: models.RoleUser}, nil),
+		m.accounts.EXPECT().FindAccount(gomock.Any(), models.ModelID("user")).Return(&models.User{Role: models.RoleAdmin}, nil),
+	)
+	gomock.InOrder(
+		m.jwt.EXPECT().BuildJWTString(models.ModelID("user"), models.RoleUser, "jti").Return("access-1", nil),
+		m.jwt.EXPECT().BuildJWTString(models.ModelID("user"), models.RoleAdmin, "jti").Return("access-2", nil),
+	)
+
+	client := appdto.Client{IP: "10.0.0.1"}
+
+	second, err := service.Refresh(ctx, first, client)
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", second.AccessToken)
+	assert.NotEqual(t, first, second.RefreshToken, "the refresh token rotates")
+	assert.Equal(t, utils.HashSecret(second.RefreshToken), f.current, "only the hash is stored")
+	assert.WithinDuration(t, time.Now().Add(time.Hour), second.RefreshExpiresAt, time.Second)
+
+	third, err := service.Refresh(ctx, second.RefreshToken, client)
+	require.NoError(t, err)
+	assert.Equal(t, "access-2", third.AccessToken)
+
+	_, err = service.Refresh(ctx, first, client)
+	assert.ErrorIs(t, err, apperrors.ErrTokenReused, "a rotated token is a reuse")
+	assert.WithinDuration(t, time.Now().Add(time.Minute), f.deniedTill, time.Second, "the access token is revoked for its lifetime")
+
+	_, err = service.Refresh(ctx, third.RefreshToken, client)
+	assert.ErrorIs(t, err, apperrors.ErrTokenReused, "reuse revokes the newest token of the family too")
+
+	_, err = service.Refresh(ctx, "", client)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidToken)
+}
+
+func TestService_RefreshInactive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestService(ctrl)
+
+	m.refresh.EXPECT().Rotate(gomock.Any(), utils.HashSecret("token"), gomock.Any(), "", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, next *models.RefreshToken, _ string, _ time.Time) (*models.RefreshToken, error) {
+			next.UserID = "user"
+			return &models.RefreshToken{}, nil
+		})
+	m.accounts.EXPECT().FindAccount(gomock.Any(), models.ModelID("user")).
+		Return(&models.User{DisabledAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil)
+
+	_, err := service.Refresh(context.Background(), "token", appdto.Client{})
+	assert.ErrorIs(t, err, apperrors.ErrInactive)
+}
+
+func TestService_IssueInactive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestService(ctrl)
+
+	// no token is saved for a disabled account
+	m.accounts.EXPECT().FindAccount(gomock.Any(), models.ModelID("user")).
+		Return(&models.User{DisabledAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil)
+
+	_, err := service.Issue(context.Background(), "user", appdto.Client{})
+	assert.ErrorIs(t, err, apperrors.ErrInactive)
+}
+
+func TestService_Sessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestService(ctrl)
+
+	m.sessions.EXPECT().FindSessions(gomock.Any(), models.ModelID("user")).Return([]models.Session{
+		{ID: "laptop", JTI: "jti-laptop", IP: "10.0.0.1"},
+		{ID: "phone", JTI: "jti-phone", IP: "10.0.0.2"},
+	}, nil)
+
+	out, err := service.Sessions(context.Background(), "user", "jti-phone")
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	assert.Equal(t, "laptop", out[0].ID)
+	assert.False(t, out[0].Current)
+	assert.Equal(t, "phone", out[1].ID)
+	assert.True(t, out[1].Current, "the session of the caller's jti is marked")
+}
+
+func TestService_RevokeSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := serviceMocks{
+		sessions: mocks.NewMocksessionRepository(ctrl),
+	}
+	denylistRepository := mocks.NewMockdenylistRepository(ctrl)
+	denylist := NewDenylist(time.Minute, 10, denylistRepository)
+	service := NewService(time.Minute, time.Hour, nil, nil, nil, m.sessions, denylist)
+	ctx := context.Background()
+
+	// sessions are looked up by owner, another user's id is not found
+	m.sessions.EXPECT().FindSession(gomock.Any(), models.ModelID("other"), models.ModelID("phone")).Return(nil, apperrors.ErrNotFound)
+	_, err := service.RevokeSession(ctx, "other", "phone")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	// the jti is denylisted until the last access token issued for it expires
+	m.sessions.EXPECT().FindSession(gomock.Any(), models.ModelID("user"), models.ModelID("phone")).
+		Return(&models.Session{ID: "phone", UserID: "user", JTI: "jti-phone"}, nil)
+	denylistRepository.EXPECT().Revoke(gomock.Any(), models.ModelID("user"), "jti-phone", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ models.ModelID, _ string, expiresAt time.Time) error {
+			assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+			return nil
+		})
+
+	jti, err := service.RevokeSession(ctx, "user", "phone")
+	require.NoError(t, err)
+	assert.Equal(t, "jti-phone", jti)
+
+	revoked, err := denylist.IsRevoked(ctx, "jti-phone")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
@@ -0,0 +1,56 @@
+package tokens
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type (
+	sessionTouchRepository interface {
+		TouchSession(ctx context.Context, jti string) error
+	}
+	// SessionTracker records when sessions were last seen. Writes are
+	// throttled to one per session every precision.
+	SessionTracker struct {
+		precision  time.Duration
+		repository sessionTouchRepository
+
+		mu       sync.Mutex
+		seen     map[string]time.Time
+		purgedAt time.Time
+	}
+)
+
+func NewSessionTracker(precision time.Duration, repository sessionTouchRepository) *SessionTracker {
+	return &SessionTracker{
+		precision:  precision,
+		repository: repository,
+		seen:       make(map[string]time.Time),
+		purgedAt:   time.Now(),
+	}
+}
+
+// Seen records a request of the session of jti.
+func (t *SessionTracker) Seen(ctx context.Context, jti string) error {
+	now := time.Now()
+
+	t.mu.Lock()
+	if now.Sub(t.purgedAt) >= t.precision {
+		for k, at := range t.seen {
+			if now.Sub(at) >= t.precision {
+				delete(t.seen, k)
+			}
+		}
+		t.purgedAt = now
+	}
+	at, ok := t.seen[jti]
+	if ok && now.Sub(at) < t.precision {
+		t.mu.Unlock()
+		return nil
+	}
+	t.seen[jti] = now
+	t.mu.Unlock()
+
+	return t.repository.TouchSession(ctx, jti)
+}
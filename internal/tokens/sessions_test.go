@@ -0,0 +1,33 @@
+package tokens
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type touchCounter map[string]int
+
+func (t touchCounter) TouchSession(_ context.Context, jti string) error {
+	t[jti]++
+	return nil
+}
+
+func TestSessionTracker_Seen(t *testing.T) {
+	touched := touchCounter{}
+	tracker := NewSessionTracker(time.Minute, touched)
+	ctx := context.Background()
+
+	require.NoError(t, tracker.Seen(ctx, "a"))
+	require.NoError(t, tracker.Seen(ctx, "a"))
+	require.NoError(t, tracker.Seen(ctx, "b"))
+	assert.Equal(t, 1, touched["a"], "repeated requests within precision are written once")
+	assert.Equal(t, 1, touched["b"])
+
+	tracker.seen["a"] = time.Now().Add(-2 * time.Minute)
+	require.NoError(t, tracker.Seen(ctx, "a"))
+	assert.Equal(t, 2, touched["a"], "requests after precision are written again")
+}
@@ -0,0 +1,158 @@
+package tokens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+const (
+	AccessCookie  = "token"
+	RefreshCookie = "refresh_token"
+	// the refresh token is only sent to the token endpoints
+	refreshCookiePath = "/api/user/token"
+	bearerScheme      = "Bearer"
+)
+
+type (
+	// CookieConfig sets the flags of the access token cookie, the refresh
+	// token cookie is always HttpOnly. CSRF is the mode protecting requests
+	// authenticated by the cookie, see CheckCSRF; TrustedOrigins are the
+	// origins other than the server itself allowed in CSRFOrigin mode.
+	CookieConfig struct {
+		SameSite       string
+		HTTPOnly       bool
+		Secure         bool
+		CSRF           string
+		TrustedOrigins []string
+	}
+	// Transport hands issued tokens to the client: as cookies for browsers,
+	// in the Authorization header and optionally the JSON body for the rest.
+	Transport struct {
+		conf CookieConfig
+	}
+)
+
+func NewTransport(conf CookieConfig) (*Transport, error) {
+	switch strings.ToLower(conf.SameSite) {
+	case "":
+		conf.SameSite = fiber.CookieSameSiteLaxMode
+	case strings.ToLower(fiber.CookieSameSiteLaxMode),
+		strings.ToLower(fiber.CookieSameSiteStrictMode),
+		strings.ToLower(fiber.CookieSameSiteNoneMode):
+	default:
+		return nil, fmt.Errorf("unknown cookie SameSite mode %q", conf.SameSite)
+	}
+	if strings.EqualFold(conf.SameSite, fiber.CookieSameSiteNoneMode) && !conf.Secure {
+		return nil, fmt.Errorf("cookie SameSite=None requires Secure")
+	}
+	if err := parseCSRF(&conf); err != nil {
+		return nil, err
+	}
+
+	return &Transport{conf}, nil
+}
+
+// Write sends issued tokens as cookies and in the Authorization header, and
+// in the body as well when asJSON is set.
+func (t *Transport) Write(c *fiber.Ctx, issued appdto.Tokens, asJSON bool) error {
+	t.SetCookies(c, issued)
+	c.Set(fiber.HeaderAuthorization, bearerScheme+" "+issued.AccessToken)
+
+	if asJSON {
+		return c.Status(fiber.StatusOK).JSON(dto.TokensResponse{
+			TokenType:        bearerScheme,
+			AccessToken:      issued.AccessToken,
+			AccessExpiresAt:  issued.AccessExpiresAt,
+			RefreshToken:     issued.RefreshToken,
+			RefreshExpiresAt: issued.RefreshExpiresAt,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// Clear expires the token cookies on the client.
+func (t *Transport) Clear(c *fiber.Ctx) {
+	t.SetCookies(c, appdto.Tokens{
+		AccessExpiresAt:  time.Unix(0, 0),
+		RefreshExpiresAt: time.Unix(0, 0),
+	})
+}
+
+// SetCookies sends issued tokens as cookies only, for responses that
+// redirect the browser instead of answering the API client.
+func (t *Transport) SetCookies(c *fiber.Ctx, issued appdto.Tokens) {
+	if t.conf.CSRF == CSRFDoubleSubmit {
+		// scripts of the site read it to echo it in CSRFHeader
+		value := ""
+		if issued.AccessToken != "" {
+			value = utils.GenerateSecret()
+		}
+		c.Cookie(&fiber.Cookie{
+			Name:     CSRFCookie,
+			Value:    value,
+			Path:     "/",
+			Expires:  issued.RefreshExpiresAt,
+			Secure:   t.conf.Secure,
+			SameSite: t.conf.SameSite,
+		})
+	}
+	c.Cookie(&fiber.Cookie{
+		Name:     AccessCookie,
+		Value:    issued.AccessToken,
+		Path:     "/",
+		Expires:  issued.AccessExpiresAt,
+		HTTPOnly: t.conf.HTTPOnly,
+		Secure:   t.conf.Secure,
+		SameSite: t.conf.SameSite,
+	})
+	c.Cookie(&fiber.Cookie{
+		Name:     RefreshCookie,
+		Value:    issued.RefreshToken,
+		Path:     refreshCookiePath,
+		Expires:  issued.RefreshExpiresAt,
+		HTTPOnly: true,
+		Secure:   t.conf.Secure,
+		SameSite: t.conf.SameSite,
+	})
+}
+
+// WantsJSON reports whether the client asked for a JSON body.
+func WantsJSON(c *fiber.Ctx) bool {
+	return strings.Contains(c.Get(fiber.HeaderAccept), fiber.MIMEApplicationJSON)
+}
+
+// maxUserAgent bounds the user agent kept with a session.
+const maxUserAgent = 255
+
+// ClientOf describes the client of the request for its session.
+func ClientOf(c *fiber.Ctx) appdto.Client {
+	ua := c.Get(fiber.HeaderUserAgent)
+	if len(ua) > maxUserAgent {
+		ua = strings.ToValidUTF8(ua[:maxUserAgent], "")
+	}
+	return appdto.Client{IP: c.IP(), UserAgent: ua}
+}
+
+// ExtractToken returns the access token of the request. A bearer token in the
+// Authorization header takes precedence over the cookie; a malformed header
+// is not silently replaced by the cookie.
+func ExtractToken(c *fiber.Ctx) (string, error) {
+	if header := c.Get(fiber.HeaderAuthorization); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, bearerScheme) || strings.TrimSpace(token) == "" {
+			return "", apperrors.ErrInvalidToken
+		}
+		return strings.TrimSpace(token), nil
+	}
+
+	return c.Cookies(AccessCookie), nil
+}
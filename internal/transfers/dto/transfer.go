@@ -0,0 +1,6 @@
+package dto
+
+type TransferPayload struct {
+	Login string  `json:"login"`
+	Sum   float64 `json:"sum"`
+}
@@ -0,0 +1,56 @@
+package transfers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/transfers/dto"
+)
+
+type (
+	transferService interface {
+		Transfer(ctx context.Context, senderID models.ModelID, d dto.TransferPayload) error
+	}
+	handler struct {
+		service transferService
+	}
+)
+
+func newHandler(ts transferService) *handler {
+	return &handler{ts}
+}
+
+func (h *handler) transfer(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.SendStatus(fiber.StatusUnauthorized)
+	}
+
+	var body dto.TransferPayload
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	}
+
+	switch err := h.service.Transfer(c.Context(), models.ModelID(userID), body); {
+	case err == nil:
+		return c.SendStatus(fiber.StatusOK)
+	case errors.Is(err, apperrors.ErrInsufficientFunds):
+		return c.SendStatus(fiber.StatusPaymentRequired)
+	case errors.Is(err, apperrors.ErrNotFound):
+		return c.SendStatus(fiber.StatusNotFound)
+	case errors.Is(err, apperrors.ErrInvalidAmount),
+		errors.Is(err, apperrors.ErrInvalidRecipient),
+		errors.Is(err, apperrors.ErrLimitExceeded):
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(err.Error())
+	default:
+		logger.Log.Error("transfers:handler:transfer", "StatusInternalServerError", err)
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+}
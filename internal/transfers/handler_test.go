@@ -0,0 +1,110 @@
+package transfers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/transfers/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/transfers/mocks"
+)
+
+func Test_transfer(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		userID  string
+		err     error
+		service bool
+		code    int
+	}{
+		{
+			name: "negative test #1, unauthenticated",
+			body: `{"login":"bob","sum":10}`,
+			code: http.StatusUnauthorized,
+		},
+		{
+			name:   "negative test #2, bad entity",
+			body:   `{"login":`,
+			userID: "alice",
+			code:   http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "negative test #3, insufficient funds",
+			body:    `{"login":"bob","sum":10}`,
+			userID:  "alice",
+			service: true,
+			err:     apperrors.ErrInsufficientFunds,
+			code:    http.StatusPaymentRequired,
+		},
+		{
+			name:    "negative test #4, unknown recipient",
+			body:    `{"login":"bob","sum":10}`,
+			userID:  "alice",
+			service: true,
+			err:     apperrors.ErrNotFound,
+			code:    http.StatusNotFound,
+		},
+		{
+			name:    "negative test #5, limit exceeded",
+			body:    `{"login":"bob","sum":10}`,
+			userID:  "alice",
+			service: true,
+			err:     apperrors.ErrLimitExceeded,
+			code:    http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "negative test #6, unknown service error",
+			body:    `{"login":"bob","sum":10}`,
+			userID:  "alice",
+			service: true,
+			err:     errors.New("db is down"),
+			code:    http.StatusInternalServerError,
+		},
+		{
+			name:    "positive test #7, transferred",
+			body:    `{"login":"bob","sum":10}`,
+			userID:  "alice",
+			service: true,
+			code:    http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ts := mocks.NewMocktransferService(ctrl)
+			if tt.service {
+				ts.EXPECT().Transfer(gomock.Any(), gomock.Any(), dto.TransferPayload{Login: "bob", Sum: 10}).Return(tt.err)
+			}
+
+			app := fiber.New()
+			h := newHandler(ts)
+			app.Post("/", func(c *fiber.Ctx) error {
+				if tt.userID != "" {
+					c.Locals("user_id", tt.userID)
+				}
+				return c.Next()
+			}, h.transfer)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
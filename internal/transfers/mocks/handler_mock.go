@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	dto "github.com/dkmelnik/go-musthave-diploma/internal/transfers/dto"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MocktransferService is a mock of transferService interface.
+type MocktransferService struct {
+	ctrl     *gomock.Controller
+	recorder *MocktransferServiceMockRecorder
+}
+
+// MocktransferServiceMockRecorder is the mock recorder for MocktransferService.
+type MocktransferServiceMockRecorder struct {
+	mock *MocktransferService
+}
+
+// NewMocktransferService creates a new mock instance.
+func NewMocktransferService(ctrl *gomock.Controller) *MocktransferService {
+	mock := &MocktransferService{ctrl: ctrl}
+	mock.recorder = &MocktransferServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktransferService) EXPECT() *MocktransferServiceMockRecorder {
+	return m.recorder
+}
+
+// Transfer mocks base method.
+func (m *MocktransferService) Transfer(ctx context.Context, senderID models.ModelID, d dto.TransferPayload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", ctx, senderID, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MocktransferServiceMockRecorder) Transfer(ctx, senderID, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MocktransferService)(nil).Transfer), ctx, senderID, d)
+}
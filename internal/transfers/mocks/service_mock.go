@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockuserRepository is a mock of userRepository interface.
+type MockuserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockuserRepositoryMockRecorder
+}
+
+// MockuserRepositoryMockRecorder is the mock recorder for MockuserRepository.
+type MockuserRepositoryMockRecorder struct {
+	mock *MockuserRepository
+}
+
+// NewMockuserRepository creates a new mock instance.
+func NewMockuserRepository(ctrl *gomock.Controller) *MockuserRepository {
+	mock := &MockuserRepository{ctrl: ctrl}
+	mock.recorder = &MockuserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockuserRepository) EXPECT() *MockuserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindOneByLogin mocks base method.
+func (m *MockuserRepository) FindOneByLogin(ctx context.Context, login string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOneByLogin", ctx, login)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOneByLogin indicates an expected call of FindOneByLogin.
+func (mr *MockuserRepositoryMockRecorder) FindOneByLogin(ctx, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOneByLogin", reflect.TypeOf((*MockuserRepository)(nil).FindOneByLogin), ctx, login)
+}
+
+// MocktransferRepository is a mock of transferRepository interface.
+type MocktransferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MocktransferRepositoryMockRecorder
+}
+
+// MocktransferRepositoryMockRecorder is the mock recorder for MocktransferRepository.
+type MocktransferRepositoryMockRecorder struct {
+	mock *MocktransferRepository
+}
+
+// NewMocktransferRepository creates a new mock instance.
+func NewMocktransferRepository(ctrl *gomock.Controller) *MocktransferRepository {
+	mock := &MocktransferRepository{ctrl: ctrl}
+	mock.recorder = &MocktransferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktransferRepository) EXPECT() *MocktransferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Transfer mocks base method.
+func (m *MocktransferRepository) Transfer(ctx context.Context, t *models.Transfer, check func(float64, float64) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", ctx, t, check)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MocktransferRepositoryMockRecorder) Transfer(ctx, t, check interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MocktransferRepository)(nil).Transfer), ctx, t, check)
+}
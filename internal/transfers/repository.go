@@ -0,0 +1,77 @@
+package transfers
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/ledger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+// Transfer debits the sender and credits the recipient in one transaction.
+// Both accounts are locked before check is called, so the balance and the
+// amount already sent today cannot change until the entries are written.
+func (r *Repository) Transfer(
+	ctx context.Context,
+	t *models.Transfer,
+	check func(balance, sentToday float64) error,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = ledger.LockAccounts(ctx, tx, t.SenderID, t.RecipientID); err != nil {
+		return err
+	}
+
+	balance, err := ledger.CurrentBalance(ctx, tx, t.SenderID)
+	if err != nil {
+		return err
+	}
+
+	var sentToday float64
+	sentQuery := `
+		SELECT COALESCE(-SUM(amount), 0)
+		FROM balance_entries
+		WHERE user_id = $1 AND kind = $2 AND created_at >= date_trunc('day', NOW())
+	`
+	err = tx.QueryRowContext(ctx, sentQuery, t.SenderID, models.EntryTransferOut).Scan(&sentToday)
+	if err != nil {
+		return err
+	}
+
+	if err = check(balance, sentToday); err != nil {
+		return err
+	}
+
+	insertQuery := `
+		INSERT INTO balance_entries (user_id, kind, amount, counterparty_id, reference)
+		VALUES ($1, $2, $3, $4, $5), ($4, $6, $7, $1, $5)
+	`
+	_, err = tx.ExecContext(
+		ctx,
+		insertQuery,
+		t.SenderID,
+		models.EntryTransferOut,
+		-t.Amount,
+		t.RecipientID,
+		t.ID,
+		models.EntryTransferIn,
+		t.Amount,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
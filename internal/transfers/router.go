@@ -0,0 +1,23 @@
+package transfers
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserMiddleware interface {
+	Auth(c *fiber.Ctx) error
+}
+
+func SetupRouter(
+	r fiber.Router,
+	minAmount float64,
+	dailyLimit float64,
+	mw UserMiddleware,
+	ur userRepository,
+	tr transferRepository,
+) {
+	service := NewService(minAmount, dailyLimit, ur, tr)
+	handle := newHandler(service)
+
+	r.Post("balance/transfer", mw.Auth, handle.transfer)
+}
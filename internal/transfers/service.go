@@ -0,0 +1,63 @@
+package transfers
+
+import (
+	"context"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/transfers/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+type (
+	userRepository interface {
+		FindOneByLogin(ctx context.Context, login string) (*models.User, error)
+	}
+	transferRepository interface {
+		Transfer(ctx context.Context, t *models.Transfer, check func(balance, sentToday float64) error) error
+	}
+	Service struct {
+		minAmount          float64
+		dailyLimit         float64
+		userRepository     userRepository
+		transferRepository transferRepository
+	}
+)
+
+// NewService creates a transfer service. A zero dailyLimit disables the
+// daily cap.
+func NewService(minAmount, dailyLimit float64, ur userRepository, tr transferRepository) *Service {
+	return &Service{minAmount, dailyLimit, ur, tr}
+}
+
+func (s *Service) Transfer(ctx context.Context, senderID models.ModelID, d dto.TransferPayload) error {
+	if d.Sum <= 0 || d.Sum < s.minAmount {
+		return apperrors.ErrInvalidAmount
+	}
+
+	recipient, err := s.userRepository.FindOneByLogin(ctx, d.Login)
+	if err != nil {
+		return err
+	}
+
+	if recipient.ID == senderID {
+		return apperrors.ErrInvalidRecipient
+	}
+
+	t := &models.Transfer{
+		ID:          models.ModelID(utils.GenerateGUID()),
+		SenderID:    senderID,
+		RecipientID: recipient.ID,
+		Amount:      d.Sum,
+	}
+
+	return s.transferRepository.Transfer(ctx, t, func(balance, sentToday float64) error {
+		if balance-d.Sum < 0 {
+			return apperrors.ErrInsufficientFunds
+		}
+		if s.dailyLimit > 0 && sentToday+d.Sum > s.dailyLimit {
+			return apperrors.ErrLimitExceeded
+		}
+		return nil
+	})
+}
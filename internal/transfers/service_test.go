@@ -0,0 +1,111 @@
+package transfers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/transfers/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/transfers/mocks"
+)
+
+func TestService_Transfer(t *testing.T) {
+	tests := []struct {
+		name      string
+		payload   dto.TransferPayload
+		recipient *models.User
+		findErr   error
+		balance   float64
+		sentToday float64
+		want      error
+	}{
+		{
+			name:      "positive test #1, transfer within balance and limit",
+			payload:   dto.TransferPayload{Login: "bob", Sum: 100},
+			recipient: &models.User{ID: "bob"},
+			balance:   500,
+			sentToday: 100,
+		},
+		{
+			name:    "negative test #2, non-positive sum",
+			payload: dto.TransferPayload{Login: "bob", Sum: 0},
+			want:    apperrors.ErrInvalidAmount,
+		},
+		{
+			name:    "negative test #3, sum under the minimum",
+			payload: dto.TransferPayload{Login: "bob", Sum: 0.5},
+			want:    apperrors.ErrInvalidAmount,
+		},
+		{
+			name:    "negative test #4, unknown recipient",
+			payload: dto.TransferPayload{Login: "nobody", Sum: 10},
+			findErr: apperrors.ErrNotFound,
+			want:    apperrors.ErrNotFound,
+		},
+		{
+			name:      "negative test #5, transfer to oneself",
+			payload:   dto.TransferPayload{Login: "alice", Sum: 10},
+			recipient: &models.User{ID: "alice"},
+			want:      apperrors.ErrInvalidRecipient,
+		},
+		{
+			name:      "negative test #6, insufficient funds",
+			payload:   dto.TransferPayload{Login: "bob", Sum: 100},
+			recipient: &models.User{ID: "bob"},
+			balance:   99,
+			want:      apperrors.ErrInsufficientFunds,
+		},
+		{
+			name:      "negative test #7, daily limit exceeded",
+			payload:   dto.TransferPayload{Login: "bob", Sum: 100},
+			recipient: &models.User{ID: "bob"},
+			balance:   500,
+			sentToday: 950,
+			want:      apperrors.ErrLimitExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ur := mocks.NewMockuserRepository(ctrl)
+			tr := mocks.NewMocktransferRepository(ctrl)
+			if tt.recipient != nil || tt.findErr != nil {
+				ur.EXPECT().FindOneByLogin(gomock.Any(), tt.payload.Login).Return(tt.recipient, tt.findErr)
+			}
+			if tt.recipient != nil && tt.recipient.ID != "alice" {
+				tr.EXPECT().Transfer(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, m *models.Transfer, check func(balance, sentToday float64) error) error {
+						assert.Equal(t, models.ModelID("alice"), m.SenderID)
+						assert.Equal(t, tt.recipient.ID, m.RecipientID)
+						assert.Equal(t, tt.payload.Sum, m.Amount)
+						return check(tt.balance, tt.sentToday)
+					})
+			}
+
+			err := NewService(1, 1000, ur, tr).Transfer(context.Background(), "alice", tt.payload)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestService_TransferWithoutDailyLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ur := mocks.NewMockuserRepository(ctrl)
+	ur.EXPECT().FindOneByLogin(gomock.Any(), "bob").Return(&models.User{ID: "bob"}, nil)
+	tr := mocks.NewMocktransferRepository(ctrl)
+	tr.EXPECT().Transfer(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *models.Transfer, check func(balance, sentToday float64) error) error {
+			return check(1e9, 1e9)
+		})
+
+	err := NewService(1, 0, ur, tr).Transfer(context.Background(), "alice", dto.TransferPayload{Login: "bob", Sum: 10})
+	assert.NoError(t, err)
+}
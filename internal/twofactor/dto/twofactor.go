@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	Enrollment struct {
+		Secret        string   `json:"secret"`
+		URI           string   `json:"otpauth_uri"`
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	CodePayload struct {
+		// Code is a TOTP code or, except for activation, a recovery code.
+		Code string `json:"code"`
+	}
+	ChallengePayload struct {
+		ChallengeToken string `json:"challenge_token"`
+		Code           string `json:"code"`
+	}
+)
+
+func (r *CodePayload) Validate() error {
+	if strings.TrimSpace(r.Code) == "" {
+		return fmt.Errorf("code is required")
+	}
+
+	return nil
+}
+
+func (r *ChallengePayload) Validate() error {
+	if strings.TrimSpace(r.ChallengeToken) == "" {
+		return fmt.Errorf("challenge_token is required")
+	}
+	if strings.TrimSpace(r.Code) == "" {
+		return fmt.Errorf("code is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package twofactor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens"
+	"github.com/dkmelnik/go-musthave-diploma/internal/twofactor/dto"
+)
+
+type (
+	twoFactorService interface {
+		Enroll(ctx context.Context, userID models.ModelID) (dto.Enrollment, error)
+		Activate(ctx context.Context, userID models.ModelID, code string) error
+		Disable(ctx context.Context, userID models.ModelID, code string, client appdto.Client) error
+		Complete(ctx context.Context, d dto.ChallengePayload, client appdto.Client) (appdto.Tokens, error)
+	}
+	tokenTransport interface {
+		Write(c *fiber.Ctx, issued appdto.Tokens, asJSON bool) error
+	}
+	handler struct {
+		service   twoFactorService
+		transport tokenTransport
+	}
+)
+
+func newHandler(service twoFactorService, transport tokenTransport) *handler {
+	return &handler{service, transport}
+}
+
+func (h *handler) enroll(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+	}
+
+	enrollment, err := h.service.Enroll(c.Context(), models.ModelID(userID))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrIsExist) {
+			return c.Status(fiber.StatusConflict).SendString(http.StatusText(fiber.StatusConflict))
+		}
+		logger.Log.Error("twofactor:handler:enroll", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(enrollment)
+}
+
+func (h *handler) activate(c *fiber.Ctx) error {
+	return h.withCode(c, "twofactor:handler:activate", h.service.Activate)
+}
+
+func (h *handler) disable(c *fiber.Ctx) error {
+	client := tokens.ClientOf(c)
+	return h.withCode(c, "twofactor:handler:disable", func(ctx context.Context, userID models.ModelID, code string) error {
+		return h.service.Disable(ctx, userID, code, client)
+	})
+}
+
+func (h *handler) withCode(
+	c *fiber.Ctx,
+	op string,
+	action func(ctx context.Context, userID models.ModelID, code string) error,
+) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+	}
+
+	var body dto.CodePayload
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	}
+	if err := body.Validate(); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(err.Error())
+	}
+
+	switch err := action(c.Context(), models.ModelID(userID), body.Code); {
+	case err == nil:
+		return c.SendStatus(fiber.StatusOK)
+	case errors.Is(err, apperrors.ErrInvalidCredentials):
+		return c.Status(fiber.StatusForbidden).SendString(http.StatusText(fiber.StatusForbidden))
+	case errors.Is(err, apperrors.ErrIsExist):
+		return c.Status(fiber.StatusConflict).SendString(http.StatusText(fiber.StatusConflict))
+	case errors.Is(err, apperrors.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).SendString(http.StatusText(fiber.StatusNotFound))
+	case errors.Is(err, apperrors.ErrTooManyAttempts):
+		return tooManyAttempts(c, err)
+	default:
+		logger.Log.Error(op, "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+}
+
+// complete finishes a login that answered with a challenge.
+func (h *handler) complete(c *fiber.Ctx) error {
+	var body dto.ChallengePayload
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	}
+	if err := body.Validate(); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(err.Error())
+	}
+
+	issued, err := h.service.Complete(c.Context(), body, tokens.ClientOf(c))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidToken) || errors.Is(err, apperrors.ErrInvalidCredentials) {
+			return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+		}
+		if errors.Is(err, apperrors.ErrInactive) {
+			return c.Status(fiber.StatusForbidden).SendString(http.StatusText(fiber.StatusForbidden))
+		}
+		if errors.Is(err, apperrors.ErrTooManyAttempts) {
+			return tooManyAttempts(c, err)
+		}
+		logger.Log.Error("twofactor:handler:complete", "StatusInternalServerError", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+
+	return h.transport.Write(c, issued, tokens.WantsJSON(c))
+}
+
+// tooManyAttempts answers a locked out login, with Retry-After when known.
+func tooManyAttempts(c *fiber.Ctx, err error) error {
+	var retry *apperrors.RetryError
+	if errors.As(err, &retry) {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retry.RetryAfter.Seconds()))))
+	}
+	return c.Status(fiber.StatusTooManyRequests).SendString(http.StatusText(fiber.StatusTooManyRequests))
+}
@@ -0,0 +1,134 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	dto0 "github.com/dkmelnik/go-musthave-diploma/internal/twofactor/dto"
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MocktwoFactorService is a mock of twoFactorService interface.
+type MocktwoFactorService struct {
+	ctrl     *gomock.Controller
+	recorder *MocktwoFactorServiceMockRecorder
+}
+
+// MocktwoFactorServiceMockRecorder is the mock recorder for MocktwoFactorService.
+type MocktwoFactorServiceMockRecorder struct {
+	mock *MocktwoFactorService
+}
+
+// NewMocktwoFactorService creates a new mock instance.
+func NewMocktwoFactorService(ctrl *gomock.Controller) *MocktwoFactorService {
+	mock := &MocktwoFactorService{ctrl: ctrl}
+	mock.recorder = &MocktwoFactorServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktwoFactorService) EXPECT() *MocktwoFactorServiceMockRecorder {
+	return m.recorder
+}
+
+// Activate mocks base method.
+func (m *MocktwoFactorService) Activate(ctx context.Context, userID models.ModelID, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Activate", ctx, userID, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Activate indicates an expected call of Activate.
+func (mr *MocktwoFactorServiceMockRecorder) Activate(ctx, userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Activate", reflect.TypeOf((*MocktwoFactorService)(nil).Activate), ctx, userID, code)
+}
+
+// Complete mocks base method.
+func (m *MocktwoFactorService) Complete(ctx context.Context, d dto0.ChallengePayload, client dto.Client) (dto.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, d, client)
+	ret0, _ := ret[0].(dto.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MocktwoFactorServiceMockRecorder) Complete(ctx, d, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MocktwoFactorService)(nil).Complete), ctx, d, client)
+}
+
+// Disable mocks base method.
+func (m *MocktwoFactorService) Disable(ctx context.Context, userID models.ModelID, code string, client dto.Client) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", ctx, userID, code, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MocktwoFactorServiceMockRecorder) Disable(ctx, userID, code, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MocktwoFactorService)(nil).Disable), ctx, userID, code, client)
+}
+
+// Enroll mocks base method.
+func (m *MocktwoFactorService) Enroll(ctx context.Context, userID models.ModelID) (dto0.Enrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enroll", ctx, userID)
+	ret0, _ := ret[0].(dto0.Enrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enroll indicates an expected call of Enroll.
+func (mr *MocktwoFactorServiceMockRecorder) Enroll(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MocktwoFactorService)(nil).Enroll), ctx, userID)
+}
+
+// MocktokenTransport is a mock of tokenTransport interface.
+type MocktokenTransport struct {
+	ctrl     *gomock.Controller
+	recorder *MocktokenTransportMockRecorder
+}
+
+// MocktokenTransportMockRecorder is the mock recorder for MocktokenTransport.
+type MocktokenTransportMockRecorder struct {
+	mock *MocktokenTransport
+}
+
+// NewMocktokenTransport creates a new mock instance.
+func NewMocktokenTransport(ctrl *gomock.Controller) *MocktokenTransport {
+	mock := &MocktokenTransport{ctrl: ctrl}
+	mock.recorder = &MocktokenTransportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktokenTransport) EXPECT() *MocktokenTransportMockRecorder {
+	return m.recorder
+}
+
+// Write mocks base method.
+func (m *MocktokenTransport) Write(c *fiber.Ctx, issued dto.Tokens, asJSON bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Write", c, issued, asJSON)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Write indicates an expected call of Write.
+func (mr *MocktokenTransportMockRecorder) Write(c, issued, asJSON interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MocktokenTransport)(nil).Write), c, issued, asJSON)
+}
@@ -0,0 +1,339 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	dto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MocksecretSealer is a mock of secretSealer interface.
+type MocksecretSealer struct {
+	ctrl     *gomock.Controller
+	recorder *MocksecretSealerMockRecorder
+}
+
+// MocksecretSealerMockRecorder is the mock recorder for MocksecretSealer.
+type MocksecretSealerMockRecorder struct {
+	mock *MocksecretSealer
+}
+
+// NewMocksecretSealer creates a new mock instance.
+func NewMocksecretSealer(ctrl *gomock.Controller) *MocksecretSealer {
+	mock := &MocksecretSealer{ctrl: ctrl}
+	mock.recorder = &MocksecretSealerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocksecretSealer) EXPECT() *MocksecretSealerMockRecorder {
+	return m.recorder
+}
+
+// Open mocks base method.
+func (m *MocksecretSealer) Open(sealed string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", sealed)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MocksecretSealerMockRecorder) Open(sealed interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MocksecretSealer)(nil).Open), sealed)
+}
+
+// Seal mocks base method.
+func (m *MocksecretSealer) Seal(plaintext []byte) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Seal", plaintext)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Seal indicates an expected call of Seal.
+func (mr *MocksecretSealerMockRecorder) Seal(plaintext interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Seal", reflect.TypeOf((*MocksecretSealer)(nil).Seal), plaintext)
+}
+
+// MocktwoFactorRepository is a mock of twoFactorRepository interface.
+type MocktwoFactorRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MocktwoFactorRepositoryMockRecorder
+}
+
+// MocktwoFactorRepositoryMockRecorder is the mock recorder for MocktwoFactorRepository.
+type MocktwoFactorRepositoryMockRecorder struct {
+	mock *MocktwoFactorRepository
+}
+
+// NewMocktwoFactorRepository creates a new mock instance.
+func NewMocktwoFactorRepository(ctrl *gomock.Controller) *MocktwoFactorRepository {
+	mock := &MocktwoFactorRepository{ctrl: ctrl}
+	mock.recorder = &MocktwoFactorRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktwoFactorRepository) EXPECT() *MocktwoFactorRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AttemptChallenge mocks base method.
+func (m *MocktwoFactorRepository) AttemptChallenge(ctx context.Context, tokenHash string, maxAttempts int) (models.ModelID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttemptChallenge", ctx, tokenHash, maxAttempts)
+	ret0, _ := ret[0].(models.ModelID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttemptChallenge indicates an expected call of AttemptChallenge.
+func (mr *MocktwoFactorRepositoryMockRecorder) AttemptChallenge(ctx, tokenHash, maxAttempts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttemptChallenge", reflect.TypeOf((*MocktwoFactorRepository)(nil).AttemptChallenge), ctx, tokenHash, maxAttempts)
+}
+
+// CompleteChallenge mocks base method.
+func (m *MocktwoFactorRepository) CompleteChallenge(ctx context.Context, tokenHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteChallenge", ctx, tokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteChallenge indicates an expected call of CompleteChallenge.
+func (mr *MocktwoFactorRepositoryMockRecorder) CompleteChallenge(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteChallenge", reflect.TypeOf((*MocktwoFactorRepository)(nil).CompleteChallenge), ctx, tokenHash)
+}
+
+// DeleteExpiredChallenges mocks base method.
+func (m *MocktwoFactorRepository) DeleteExpiredChallenges(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredChallenges", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredChallenges indicates an expected call of DeleteExpiredChallenges.
+func (mr *MocktwoFactorRepositoryMockRecorder) DeleteExpiredChallenges(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredChallenges", reflect.TypeOf((*MocktwoFactorRepository)(nil).DeleteExpiredChallenges), ctx)
+}
+
+// Disable mocks base method.
+func (m *MocktwoFactorRepository) Disable(ctx context.Context, userID models.ModelID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MocktwoFactorRepositoryMockRecorder) Disable(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MocktwoFactorRepository)(nil).Disable), ctx, userID)
+}
+
+// Enable mocks base method.
+func (m *MocktwoFactorRepository) Enable(ctx context.Context, userID models.ModelID, step int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable", ctx, userID, step)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *MocktwoFactorRepositoryMockRecorder) Enable(ctx, userID, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MocktwoFactorRepository)(nil).Enable), ctx, userID, step)
+}
+
+// FindState mocks base method.
+func (m *MocktwoFactorRepository) FindState(ctx context.Context, userID models.ModelID) (*models.TwoFactorState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindState", ctx, userID)
+	ret0, _ := ret[0].(*models.TwoFactorState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindState indicates an expected call of FindState.
+func (mr *MocktwoFactorRepositoryMockRecorder) FindState(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindState", reflect.TypeOf((*MocktwoFactorRepository)(nil).FindState), ctx, userID)
+}
+
+// SaveChallenge mocks base method.
+func (m *MocktwoFactorRepository) SaveChallenge(ctx context.Context, userID models.ModelID, tokenHash string, expiresAt time.Time, maxOpen int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveChallenge", ctx, userID, tokenHash, expiresAt, maxOpen)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveChallenge indicates an expected call of SaveChallenge.
+func (mr *MocktwoFactorRepositoryMockRecorder) SaveChallenge(ctx, userID, tokenHash, expiresAt, maxOpen interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveChallenge", reflect.TypeOf((*MocktwoFactorRepository)(nil).SaveChallenge), ctx, userID, tokenHash, expiresAt, maxOpen)
+}
+
+// SavePending mocks base method.
+func (m *MocktwoFactorRepository) SavePending(ctx context.Context, userID models.ModelID, sealedSecret string, codeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePending", ctx, userID, sealedSecret, codeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePending indicates an expected call of SavePending.
+func (mr *MocktwoFactorRepositoryMockRecorder) SavePending(ctx, userID, sealedSecret, codeHashes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePending", reflect.TypeOf((*MocktwoFactorRepository)(nil).SavePending), ctx, userID, sealedSecret, codeHashes)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MocktwoFactorRepository) UseRecoveryCode(ctx context.Context, userID models.ModelID, codeHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, userID, codeHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MocktwoFactorRepositoryMockRecorder) UseRecoveryCode(ctx, userID, codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MocktwoFactorRepository)(nil).UseRecoveryCode), ctx, userID, codeHash)
+}
+
+// UseStep mocks base method.
+func (m *MocktwoFactorRepository) UseStep(ctx context.Context, userID models.ModelID, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseStep", ctx, userID, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseStep indicates an expected call of UseStep.
+func (mr *MocktwoFactorRepositoryMockRecorder) UseStep(ctx, userID, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseStep", reflect.TypeOf((*MocktwoFactorRepository)(nil).UseStep), ctx, userID, step)
+}
+
+// MockTokenIssuer is a mock of TokenIssuer interface.
+type MockTokenIssuer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenIssuerMockRecorder
+}
+
+// MockTokenIssuerMockRecorder is the mock recorder for MockTokenIssuer.
+type MockTokenIssuerMockRecorder struct {
+	mock *MockTokenIssuer
+}
+
+// NewMockTokenIssuer creates a new mock instance.
+func NewMockTokenIssuer(ctrl *gomock.Controller) *MockTokenIssuer {
+	mock := &MockTokenIssuer{ctrl: ctrl}
+	mock.recorder = &MockTokenIssuerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenIssuer) EXPECT() *MockTokenIssuerMockRecorder {
+	return m.recorder
+}
+
+// Issue mocks base method.
+func (m *MockTokenIssuer) Issue(ctx context.Context, userID models.ModelID, client dto.Client) (dto.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Issue", ctx, userID, client)
+	ret0, _ := ret[0].(dto.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Issue indicates an expected call of Issue.
+func (mr *MockTokenIssuerMockRecorder) Issue(ctx, userID, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issue", reflect.TypeOf((*MockTokenIssuer)(nil).Issue), ctx, userID, client)
+}
+
+// MockLoginGuard is a mock of LoginGuard interface.
+type MockLoginGuard struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginGuardMockRecorder
+}
+
+// MockLoginGuardMockRecorder is the mock recorder for MockLoginGuard.
+type MockLoginGuardMockRecorder struct {
+	mock *MockLoginGuard
+}
+
+// NewMockLoginGuard creates a new mock instance.
+func NewMockLoginGuard(ctrl *gomock.Controller) *MockLoginGuard {
+	mock := &MockLoginGuard{ctrl: ctrl}
+	mock.recorder = &MockLoginGuardMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginGuard) EXPECT() *MockLoginGuardMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLoginGuard) Check(ctx context.Context, login, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, login, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLoginGuardMockRecorder) Check(ctx, login, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLoginGuard)(nil).Check), ctx, login, ip)
+}
+
+// Fail mocks base method.
+func (m *MockLoginGuard) Fail(ctx context.Context, login, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", ctx, login, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockLoginGuardMockRecorder) Fail(ctx, login, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockLoginGuard)(nil).Fail), ctx, login, ip)
+}
+
+// Succeed mocks base method.
+func (m *MockLoginGuard) Succeed(ctx context.Context, login, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Succeed", ctx, login, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Succeed indicates an expected call of Succeed.
+func (mr *MockLoginGuardMockRecorder) Succeed(ctx, login, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Succeed", reflect.TypeOf((*MockLoginGuard)(nil).Succeed), ctx, login, ip)
+}
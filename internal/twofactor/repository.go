@@ -0,0 +1,229 @@
+package twofactor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+func (r *Repository) FindState(ctx context.Context, userID models.ModelID) (*models.TwoFactorState, error) {
+	var s models.TwoFactorState
+	query := `
+		SELECT login, totp_secret, totp_enabled_at FROM users WHERE id = $1
+	`
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&s.Login, &s.Secret, &s.EnabledAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// SavePending stores a not yet activated secret with its recovery codes,
+// replacing a previous pending enrollment. Active 2FA is left untouched.
+func (r *Repository) SavePending(ctx context.Context, userID models.ModelID, sealedSecret string, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users SET totp_secret = $2, totp_last_step = NULL
+		WHERE id = $1 AND totp_enabled_at IS NULL
+	`
+	res, err := tx.ExecContext(ctx, query, userID, sealedSecret)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperrors.ErrIsExist
+	}
+
+	if err = replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) Enable(ctx context.Context, userID models.ModelID, step int64) error {
+	query := `
+		UPDATE users SET totp_enabled_at = NOW(), totp_last_step = $2
+		WHERE id = $1 AND totp_secret IS NOT NULL AND totp_enabled_at IS NULL
+	`
+	_, err := r.db.ExecContext(ctx, query, userID, step)
+	return err
+}
+
+func (r *Repository) Disable(ctx context.Context, userID models.ModelID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = NULL
+		WHERE id = $1
+	`
+	if _, err = tx.ExecContext(ctx, query, userID); err != nil {
+		return err
+	}
+	if err = replaceRecoveryCodes(ctx, tx, userID, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseStep records step as used and reports false when it, or a later one,
+// was used already, so a code can't be replayed.
+func (r *Repository) UseStep(ctx context.Context, userID models.ModelID, step int64) (bool, error) {
+	query := `
+		UPDATE users SET totp_last_step = $2
+		WHERE id = $1 AND (totp_last_step IS NULL OR totp_last_step < $2)
+	`
+	res, err := r.db.ExecContext(ctx, query, userID, step)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// UseRecoveryCode spends the recovery code with the given hash.
+func (r *Repository) UseRecoveryCode(ctx context.Context, userID models.ModelID, codeHash string) (bool, error) {
+	query := `
+		UPDATE totp_recovery_codes SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+	res, err := r.db.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// SaveChallenge stores a new challenge of the user. Of the open ones only the
+// newest maxOpen keep working, older ones expire.
+func (r *Repository) SaveChallenge(ctx context.Context, userID models.ModelID, tokenHash string, expiresAt time.Time, maxOpen int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	insertQuery := `
+		INSERT INTO two_factor_challenges (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+	`
+	if _, err = tx.ExecContext(ctx, insertQuery, userID, tokenHash, expiresAt); err != nil {
+		return err
+	}
+
+	expireQuery := `
+		UPDATE two_factor_challenges SET expires_at = NOW()
+		WHERE user_id = $1 AND used_at IS NULL AND expires_at > NOW() AND id NOT IN (
+			SELECT id FROM two_factor_challenges
+			WHERE user_id = $1 AND used_at IS NULL AND expires_at > NOW()
+			ORDER BY created_at DESC, expires_at DESC
+			LIMIT $2
+		)
+	`
+	if _, err = tx.ExecContext(ctx, expireQuery, userID, maxOpen); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AttemptChallenge counts an attempt on a live challenge and returns its
+// user. Challenges that are unknown, used, expired or out of attempts yield
+// apperrors.ErrInvalidToken.
+func (r *Repository) AttemptChallenge(ctx context.Context, tokenHash string, maxAttempts int) (models.ModelID, error) {
+	var userID models.ModelID
+	query := `
+		UPDATE two_factor_challenges SET attempts = attempts + 1
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW() AND attempts < $2
+		RETURNING user_id
+	`
+	err := r.db.QueryRowContext(ctx, query, tokenHash, maxAttempts).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", apperrors.ErrInvalidToken
+		}
+		return "", err
+	}
+	return userID, nil
+}
+
+func (r *Repository) CompleteChallenge(ctx context.Context, tokenHash string) error {
+	query := `
+		UPDATE two_factor_challenges SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL
+	`
+	res, err := r.db.ExecContext(ctx, query, tokenHash)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperrors.ErrInvalidToken
+	}
+	return nil
+}
+
+// DeleteExpiredChallenges drops challenges that can't be completed anymore.
+func (r *Repository) DeleteExpiredChallenges(ctx context.Context) (int64, error) {
+	query := `
+		DELETE FROM two_factor_challenges WHERE expires_at <= NOW() OR used_at IS NOT NULL
+	`
+	res, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID models.ModelID, codeHashes []string) error {
+	deleteQuery := `
+		DELETE FROM totp_recovery_codes WHERE user_id = $1
+	`
+	if _, err := tx.ExecContext(ctx, deleteQuery, userID); err != nil {
+		return err
+	}
+
+	insertQuery := `
+		INSERT INTO totp_recovery_codes (user_id, code_hash) VALUES ($1, $2)
+	`
+	for _, hash := range codeHashes {
+		if _, err := tx.ExecContext(ctx, insertQuery, userID, hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
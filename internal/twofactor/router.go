@@ -0,0 +1,23 @@
+package twofactor
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserMiddleware interface {
+	Auth(c *fiber.Ctx) error
+}
+
+func SetupRouter(
+	r fiber.Router,
+	mw UserMiddleware,
+	transport tokenTransport,
+	ts twoFactorService,
+) {
+	handle := newHandler(ts, transport)
+
+	r.Post("2fa/enroll", mw.Auth, handle.enroll)
+	r.Post("2fa/activate", mw.Auth, handle.activate)
+	r.Delete("2fa", mw.Auth, handle.disable)
+	r.Post("login/2fa", handle.complete)
+}
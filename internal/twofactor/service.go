@@ -0,0 +1,286 @@
+package twofactor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/twofactor/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+const (
+	recoveryCodes    = 10
+	recoveryCodeSize = 10
+	// wrong codes a challenge takes before the password has to be entered
+	// again
+	challengeAttempts = 5
+	// open challenges of a user, logging in again expires the oldest
+	maxOpenChallenges = 3
+)
+
+type (
+	secretSealer interface {
+		Seal(plaintext []byte) (string, error)
+		Open(sealed string) ([]byte, error)
+	}
+	twoFactorRepository interface {
+		FindState(ctx context.Context, userID models.ModelID) (*models.TwoFactorState, error)
+		SavePending(ctx context.Context, userID models.ModelID, sealedSecret string, codeHashes []string) error
+		Enable(ctx context.Context, userID models.ModelID, step int64) error
+		Disable(ctx context.Context, userID models.ModelID) error
+		UseStep(ctx context.Context, userID models.ModelID, step int64) (bool, error)
+		UseRecoveryCode(ctx context.Context, userID models.ModelID, codeHash string) (bool, error)
+		SaveChallenge(ctx context.Context, userID models.ModelID, tokenHash string, expiresAt time.Time, maxOpen int) error
+		AttemptChallenge(ctx context.Context, tokenHash string, maxAttempts int) (models.ModelID, error)
+		CompleteChallenge(ctx context.Context, tokenHash string) error
+		DeleteExpiredChallenges(ctx context.Context) (int64, error)
+	}
+	TokenIssuer interface {
+		Issue(ctx context.Context, userID models.ModelID, client appdto.Client) (appdto.Tokens, error)
+	}
+	// LoginGuard throttles code guesses the same way as password guesses.
+	LoginGuard interface {
+		Check(ctx context.Context, login, ip string) error
+		Fail(ctx context.Context, login, ip string) error
+		Succeed(ctx context.Context, login, ip string) error
+	}
+	Service struct {
+		issuer       string
+		challengeTTL time.Duration
+		sealer       secretSealer
+		repository   twoFactorRepository
+		tokenIssuer  TokenIssuer
+		loginGuard   LoginGuard
+	}
+)
+
+func NewService(
+	issuer string,
+	challengeTTL time.Duration,
+	sealer secretSealer,
+	repository twoFactorRepository,
+	tokenIssuer TokenIssuer,
+	loginGuard LoginGuard,
+) *Service {
+	return &Service{issuer, challengeTTL, sealer, repository, tokenIssuer, loginGuard}
+}
+
+// Enroll starts a pending enrollment. 2FA becomes active once a code from
+// the authenticator app is confirmed with Activate.
+func (s *Service) Enroll(ctx context.Context, userID models.ModelID) (dto.Enrollment, error) {
+	st, err := s.repository.FindState(ctx, userID)
+	if err != nil {
+		return dto.Enrollment{}, err
+	}
+	if st.EnabledAt.Valid {
+		return dto.Enrollment{}, apperrors.ErrIsExist
+	}
+
+	secret := generateSecret()
+	sealed, err := s.sealer.Seal(secret)
+	if err != nil {
+		return dto.Enrollment{}, err
+	}
+
+	codes := make([]string, recoveryCodes)
+	hashes := make([]string, recoveryCodes)
+	for i := range codes {
+		c := utils.GenerateCode(recoveryCodeSize)
+		codes[i] = c[:recoveryCodeSize/2] + "-" + c[recoveryCodeSize/2:]
+		hashes[i] = utils.HashSecret(c)
+	}
+
+	if err = s.repository.SavePending(ctx, userID, sealed, hashes); err != nil {
+		return dto.Enrollment{}, err
+	}
+
+	return dto.Enrollment{
+		Secret:        b32.EncodeToString(secret),
+		URI:           uri(s.issuer, st.Login, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// Activate turns on a pending enrollment. Only a TOTP code proves the app
+// was set up, recovery codes are not accepted.
+func (s *Service) Activate(ctx context.Context, userID models.ModelID, code string) error {
+	st, err := s.repository.FindState(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if st.EnabledAt.Valid {
+		return apperrors.ErrIsExist
+	}
+	if !st.Secret.Valid {
+		return apperrors.ErrNotFound
+	}
+
+	secret, err := s.sealer.Open(st.Secret.String)
+	if err != nil {
+		return err
+	}
+	step, ok := validate(secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return apperrors.ErrInvalidCredentials
+	}
+
+	return s.repository.Enable(ctx, userID, step)
+}
+
+// Disable turns 2FA off after checking a code. Wrong codes count against
+// the login like failed logins do.
+func (s *Service) Disable(ctx context.Context, userID models.ModelID, code string, client appdto.Client) error {
+	st, err := s.repository.FindState(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !st.EnabledAt.Valid {
+		return apperrors.ErrNotFound
+	}
+
+	if err = s.guardedVerify(ctx, userID, st, code, client); err != nil {
+		return err
+	}
+
+	return s.repository.Disable(ctx, userID)
+}
+
+// Challenge is called once the password is checked. It returns nil when the
+// user has no 2FA, otherwise an *apperrors.ChallengeError to complete with
+// Complete.
+func (s *Service) Challenge(ctx context.Context, userID models.ModelID) error {
+	st, err := s.repository.FindState(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !st.EnabledAt.Valid {
+		return nil
+	}
+
+	token := utils.GenerateSecret()
+	expiresAt := time.Now().Add(s.challengeTTL)
+	if err = s.repository.SaveChallenge(ctx, userID, utils.HashSecret(token), expiresAt, maxOpenChallenges); err != nil {
+		return err
+	}
+
+	return &apperrors.ChallengeError{Token: token, ExpiresAt: expiresAt}
+}
+
+// Complete issues the tokens of a challenged login once code is valid. Wrong
+// codes count against the login like wrong passwords, a challenge takes at
+// most challengeAttempts of them.
+func (s *Service) Complete(ctx context.Context, d dto.ChallengePayload, client appdto.Client) (appdto.Tokens, error) {
+	tokenHash := utils.HashSecret(d.ChallengeToken)
+
+	userID, err := s.repository.AttemptChallenge(ctx, tokenHash, challengeAttempts)
+	if err != nil {
+		return appdto.Tokens{}, err
+	}
+
+	st, err := s.repository.FindState(ctx, userID)
+	if err != nil {
+		return appdto.Tokens{}, err
+	}
+	if err = s.guardedVerify(ctx, userID, st, d.Code, client); err != nil {
+		return appdto.Tokens{}, err
+	}
+
+	if err = s.repository.CompleteChallenge(ctx, tokenHash); err != nil {
+		return appdto.Tokens{}, err
+	}
+
+	return s.tokenIssuer.Issue(ctx, userID, client)
+}
+
+// guardedVerify runs verify behind the login guard of the user's login and
+// the client's IP.
+func (s *Service) guardedVerify(ctx context.Context, userID models.ModelID, st *models.TwoFactorState, code string, client appdto.Client) error {
+	if err := s.loginGuard.Check(ctx, st.Login, client.IP); err != nil {
+		return err
+	}
+
+	err := s.verify(ctx, userID, st, code)
+	if errors.Is(err, apperrors.ErrInvalidCredentials) {
+		if ferr := s.loginGuard.Fail(ctx, st.Login, client.IP); ferr != nil {
+			return ferr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.loginGuard.Succeed(ctx, st.Login, client.IP)
+}
+
+// verify accepts a TOTP code not used before or an unused recovery code.
+func (s *Service) verify(ctx context.Context, userID models.ModelID, st *models.TwoFactorState, code string) error {
+	if !st.EnabledAt.Valid || !st.Secret.Valid {
+		return apperrors.ErrInvalidCredentials
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == digits {
+		secret, err := s.sealer.Open(st.Secret.String)
+		if err != nil {
+			return err
+		}
+		step, ok := validate(secret, code, time.Now())
+		if !ok {
+			return apperrors.ErrInvalidCredentials
+		}
+		fresh, err := s.repository.UseStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return apperrors.ErrInvalidCredentials
+		}
+		return nil
+	}
+
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	used, err := s.repository.UseRecoveryCode(ctx, userID, utils.HashSecret(normalized))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return apperrors.ErrInvalidCredentials
+	}
+
+	return nil
+}
+
+// CollectGarbage deletes finished challenges every interval until ctx is
+// done.
+func (s *Service) CollectGarbage(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			n, err := s.repository.DeleteExpiredChallenges(ctx)
+			if err != nil {
+				logger.Log.Error("twofactor:collectGarbage", "DeleteExpiredChallenges", err)
+				continue
+			}
+			logger.Log.Debug("twofactor:collectGarbage", "deleted", n)
+		}
+	}()
+}
@@ -0,0 +1,206 @@
+package twofactor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/twofactor/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/twofactor/mocks"
+	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
+)
+
+type serviceMocks struct {
+	sealer     *mocks.MocksecretSealer
+	repository *mocks.MocktwoFactorRepository
+	issuer     *mocks.MockTokenIssuer
+	guard      *mocks.MockLoginGuard
+}
+
+func newTestService(ctrl *gomock.Controller) (*Service, serviceMocks) {
+	m := serviceMocks{
+		sealer:     mocks.NewMocksecretSealer(ctrl),
+		repository: mocks.NewMocktwoFactorRepository(ctrl),
+		issuer:     mocks.NewMockTokenIssuer(ctrl),
+		guard:      mocks.NewMockLoginGuard(ctrl),
+	}
+	return NewService("Gophermart", 5*time.Minute, m.sealer, m.repository, m.issuer, m.guard), m
+}
+
+func enabledState() *models.TwoFactorState {
+	return &models.TwoFactorState{
+		Login:     "alice",
+		Secret:    sql.NullString{String: "sealed", Valid: true},
+		EnabledAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+}
+
+func TestService_Complete(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	totp := code(secret, step(time.Now()))
+	client := appdto.Client{IP: "10.0.0.1"}
+	issued := appdto.Tokens{AccessToken: "access"}
+	locked := &apperrors.RetryError{Err: apperrors.ErrTooManyAttempts, RetryAfter: time.Minute}
+
+	tests := []struct {
+		name    string
+		code    string
+		prepare func(m serviceMocks, tokenHash string)
+		want    error
+	}{
+		{
+			name: "positive test #1, fresh TOTP code",
+			code: totp,
+			prepare: func(m serviceMocks, tokenHash string) {
+				m.repository.EXPECT().AttemptChallenge(gomock.Any(), tokenHash, challengeAttempts).Return(models.ModelID("user"), nil)
+				m.repository.EXPECT().FindState(gomock.Any(), models.ModelID("user")).Return(enabledState(), nil)
+				m.guard.EXPECT().Check(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+				m.sealer.EXPECT().Open("sealed").Return(secret, nil)
+				m.repository.EXPECT().UseStep(gomock.Any(), models.ModelID("user"), gomock.Any()).Return(true, nil)
+				m.guard.EXPECT().Succeed(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+				m.repository.EXPECT().CompleteChallenge(gomock.Any(), tokenHash).Return(nil)
+				m.issuer.EXPECT().Issue(gomock.Any(), models.ModelID("user"), client).Return(issued, nil)
+			},
+		},
+		{
+			name: "positive test #2, unused recovery code",
+			code: "abcde-fghij",
+			prepare: func(m serviceMocks, tokenHash string) {
+				m.repository.EXPECT().AttemptChallenge(gomock.Any(), tokenHash, challengeAttempts).Return(models.ModelID("user"), nil)
+				m.repository.EXPECT().FindState(gomock.Any(), models.ModelID("user")).Return(enabledState(), nil)
+				m.guard.EXPECT().Check(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+				m.repository.EXPECT().UseRecoveryCode(gomock.Any(), models.ModelID("user"), utils.HashSecret("ABCDEFGHIJ")).Return(true, nil)
+				m.guard.EXPECT().Succeed(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+				m.repository.EXPECT().CompleteChallenge(gomock.Any(), tokenHash).Return(nil)
+				m.issuer.EXPECT().Issue(gomock.Any(), models.ModelID("user"), client).Return(issued, nil)
+			},
+		},
+		{
+			name: "negative test #3, replayed TOTP code",
+			code: totp,
+			prepare: func(m serviceMocks, tokenHash string) {
+				m.repository.EXPECT().AttemptChallenge(gomock.Any(), tokenHash, challengeAttempts).Return(models.ModelID("user"), nil)
+				m.repository.EXPECT().FindState(gomock.Any(), models.ModelID("user")).Return(enabledState(), nil)
+				m.guard.EXPECT().Check(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+				m.sealer.EXPECT().Open("sealed").Return(secret, nil)
+				m.repository.EXPECT().UseStep(gomock.Any(), models.ModelID("user"), gomock.Any()).Return(false, nil)
+				m.guard.EXPECT().Fail(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+			},
+			want: apperrors.ErrInvalidCredentials,
+		},
+		{
+			name: "negative test #4, recovery code used already",
+			code: "abcde-fghij",
+			prepare: func(m serviceMocks, tokenHash string) {
+				m.repository.EXPECT().AttemptChallenge(gomock.Any(), tokenHash, challengeAttempts).Return(models.ModelID("user"), nil)
+				m.repository.EXPECT().FindState(gomock.Any(), models.ModelID("user")).Return(enabledState(), nil)
+				m.guard.EXPECT().Check(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+				m.repository.EXPECT().UseRecoveryCode(gomock.Any(), models.ModelID("user"), utils.HashSecret("ABCDEFGHIJ")).Return(false, nil)
+				m.guard.EXPECT().Fail(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+			},
+			want: apperrors.ErrInvalidCredentials,
+		},
+		{
+			name: "negative test #5, wrong TOTP code",
+			code: "000000",
+			prepare: func(m serviceMocks, tokenHash string) {
+				m.repository.EXPECT().AttemptChallenge(gomock.Any(), tokenHash, challengeAttempts).Return(models.ModelID("user"), nil)
+				m.repository.EXPECT().FindState(gomock.Any(), models.ModelID("user")).Return(enabledState(), nil)
+				m.guard.EXPECT().Check(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+				m.sealer.EXPECT().Open("sealed").Return([]byte("another secret 12345"), nil)
+				m.guard.EXPECT().Fail(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+			},
+			want: apperrors.ErrInvalidCredentials,
+		},
+		{
+			name: "negative test #6, challenge out of attempts",
+			code: totp,
+			prepare: func(m serviceMocks, tokenHash string) {
+				m.repository.EXPECT().AttemptChallenge(gomock.Any(), tokenHash, challengeAttempts).Return(models.ModelID(""), apperrors.ErrInvalidToken)
+			},
+			want: apperrors.ErrInvalidToken,
+		},
+		{
+			name: "negative test #7, login locked out",
+			code: totp,
+			prepare: func(m serviceMocks, tokenHash string) {
+				m.repository.EXPECT().AttemptChallenge(gomock.Any(), tokenHash, challengeAttempts).Return(models.ModelID("user"), nil)
+				m.repository.EXPECT().FindState(gomock.Any(), models.ModelID("user")).Return(enabledState(), nil)
+				m.guard.EXPECT().Check(gomock.Any(), "alice", "10.0.0.1").Return(locked)
+			},
+			want: apperrors.ErrTooManyAttempts,
+		},
+		{
+			name: "negative test #8, challenge spent concurrently",
+			code: totp,
+			prepare: func(m serviceMocks, tokenHash string) {
+				m.repository.EXPECT().AttemptChallenge(gomock.Any(), tokenHash, challengeAttempts).Return(models.ModelID("user"), nil)
+				m.repository.EXPECT().FindState(gomock.Any(), models.ModelID("user")).Return(enabledState(), nil)
+				m.guard.EXPECT().Check(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+				m.sealer.EXPECT().Open("sealed").Return(secret, nil)
+				m.repository.EXPECT().UseStep(gomock.Any(), models.ModelID("user"), gomock.Any()).Return(true, nil)
+				m.guard.EXPECT().Succeed(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+				m.repository.EXPECT().CompleteChallenge(gomock.Any(), tokenHash).Return(apperrors.ErrInvalidToken)
+			},
+			want: apperrors.ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newTestService(ctrl)
+			tt.prepare(m, utils.HashSecret("challenge"))
+
+			got, err := s.Complete(context.Background(), dto.ChallengePayload{ChallengeToken: "challenge", Code: tt.code}, client)
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+				assert.Empty(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, issued, got)
+		})
+	}
+}
+
+func TestService_Challenge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, m := newTestService(ctrl)
+
+	m.repository.EXPECT().FindState(gomock.Any(), models.ModelID("user")).Return(enabledState(), nil)
+	m.repository.EXPECT().SaveChallenge(gomock.Any(), models.ModelID("user"), gomock.Any(), gomock.Any(), maxOpenChallenges).Return(nil)
+
+	err := s.Challenge(context.Background(), "user")
+
+	var challenge *apperrors.ChallengeError
+	assert.True(t, errors.As(err, &challenge))
+	assert.NotEmpty(t, challenge.Token)
+}
+
+func TestService_Disable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, m := newTestService(ctrl)
+	client := appdto.Client{IP: "10.0.0.1"}
+
+	m.repository.EXPECT().FindState(gomock.Any(), models.ModelID("user")).Return(enabledState(), nil)
+	m.guard.EXPECT().Check(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+	m.sealer.EXPECT().Open("sealed").Return([]byte("12345678901234567890"), nil)
+	m.guard.EXPECT().Fail(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+
+	err := s.Disable(context.Background(), "user", "000000", client)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidCredentials)
+}
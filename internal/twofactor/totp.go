@@ -0,0 +1,85 @@
+package twofactor
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// RFC 6238 parameters every authenticator app supports.
+const (
+	secretSize = 20
+	period     = 30
+	digits     = 6
+	// codes of the neighbouring steps are accepted for clock drift
+	skew = 1
+)
+
+var b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func generateSecret() []byte {
+	b := make([]byte, secretSize)
+	_, _ = rand.Read(b)
+	return b
+}
+
+func step(t time.Time) int64 {
+	return t.Unix() / period
+}
+
+// code is the HOTP value (RFC 4226) of the secret at counter.
+func code(secret []byte, counter int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
+
+// validate returns the step the code belongs to, or false when it matches
+// none within the skew.
+func validate(secret []byte, candidate string, now time.Time) (int64, bool) {
+	if len(candidate) != digits {
+		return 0, false
+	}
+
+	current := step(now)
+	for s := current - skew; s <= current+skew; s++ {
+		if subtle.ConstantTimeCompare([]byte(code(secret, s)), []byte(candidate)) == 1 {
+			return s, true
+		}
+	}
+
+	return 0, false
+}
+
+// uri is the otpauth:// key URI authenticator apps import, usually from a
+// QR code.
+func uri(issuer, account string, secret []byte) string {
+	v := url.Values{}
+	v.Set("secret", b32.EncodeToString(secret))
+	v.Set("issuer", issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(digits))
+	v.Set("period", fmt.Sprint(period))
+
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + v.Encode()
+}
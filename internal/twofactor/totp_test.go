@@ -0,0 +1,57 @@
+package twofactor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 appendix B test vectors for SHA1, truncated to 6 digits.
+func TestCode(t *testing.T) {
+	secret := []byte("12345678901234567890")
+
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, code(secret, step(time.Unix(tt.unix, 0))))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1111111109, 0)
+	current := step(now)
+
+	s, ok := validate(secret, code(secret, current), now)
+	assert.True(t, ok)
+	assert.Equal(t, current, s)
+
+	s, ok = validate(secret, code(secret, current-1), now)
+	assert.True(t, ok, "previous step is accepted")
+	assert.Equal(t, current-1, s)
+
+	_, ok = validate(secret, code(secret, current+2), now)
+	assert.False(t, ok, "steps beyond the skew are rejected")
+
+	_, ok = validate(secret, "12345", now)
+	assert.False(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	got := uri("Gophermart", "alice", []byte("12345678901234567890"))
+
+	assert.True(t, strings.HasPrefix(got, "otpauth://totp/Gophermart:alice?"))
+	assert.Contains(t, got, "secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+	assert.Contains(t, got, "issuer=Gophermart")
+}
@@ -2,7 +2,11 @@ package dto
 
 import (
 	"fmt"
-	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/credentials"
 )
 
 type LoginPayload struct {
@@ -10,14 +14,27 @@ type LoginPayload struct {
 	Password string `json:"password"`
 }
 
-func (r *LoginPayload) Validate() error {
-	if len(strings.TrimSpace(r.Login)) < 2 {
-		return fmt.Errorf("login must be at least 2 characters long")
-	}
+// ChallengeResponse is sent instead of tokens when the login needs a second
+// factor, see POST /api/user/login/2fa.
+type ChallengeResponse struct {
+	ChallengeToken string    `json:"challenge_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
 
-	if len(strings.TrimSpace(r.Password)) < 4 {
-		return fmt.Errorf("password must be at least 6 characters long")
+// Validate only requires both fields, existing credentials may predate the
+// current policy. Logins are still capped at its maximum length, no longer
+// one can exist and the login guard keys on it.
+func (r *LoginPayload) Validate(p *credentials.Policy) error {
+	v := &apperrors.ValidationError{}
+	switch n := utf8.RuneCountInString(r.Login); {
+	case n == 0:
+		v.Add("login", credentials.CodeRequired, "login is required")
+	case n > p.LoginMaxLength():
+		v.Add("login", credentials.CodeTooLong, fmt.Sprintf("login must be at most %d characters long", p.LoginMaxLength()))
+	}
+	if r.Password == "" {
+		v.Add("password", credentials.CodeRequired, "password is required")
 	}
 
-	return nil
+	return v.OrNil()
 }
@@ -1,23 +1,26 @@
 package dto
 
 import (
-	"fmt"
-	"strings"
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/credentials"
 )
 
+// CodeUnknownReferral is reported for a referral_code no user has.
+const CodeUnknownReferral = "unknown_referral_code"
+
 type RegisterPayload struct {
 	Login    string `json:"login"`
 	Password string `json:"password"`
+	// ReferralCode is an optional code of the user who invited this one.
+	ReferralCode string `json:"referral_code"`
 }
 
-func (r *RegisterPayload) Validate() error {
-	if len(strings.TrimSpace(r.Login)) < 2 {
-		return fmt.Errorf("login must be at least 2 characters long")
-	}
-
-	if len(strings.TrimSpace(r.Password)) < 4 {
-		return fmt.Errorf("password must be at least 6 characters long")
-	}
+// Validate returns an *apperrors.ValidationError listing every rule of p
+// the credentials break.
+func (r *RegisterPayload) Validate(p *credentials.Policy) error {
+	v := &apperrors.ValidationError{}
+	p.CheckLogin(v, "login", r.Login)
+	p.CheckPassword(v, "password", r.Login, r.Password)
 
-	return nil
+	return v.OrNil()
 }
@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"log"
+	"math"
 	"net/http"
-	"time"
+	"strconv"
 
 	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/credentials"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens"
 	"github.com/dkmelnik/go-musthave-diploma/internal/users/dto"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,28 +19,21 @@ import (
 
 type (
 	userService interface {
-		Register(ctx context.Context, dto dto.RegisterPayload) (string, error)
-		Authenticate(ctx context.Context, dto dto.LoginPayload) (string, error)
+		Register(ctx context.Context, payload dto.RegisterPayload, client appdto.Client) (appdto.Tokens, error)
+		Authenticate(ctx context.Context, payload dto.LoginPayload, client appdto.Client) (appdto.Tokens, error)
+	}
+	tokenTransport interface {
+		Write(c *fiber.Ctx, issued appdto.Tokens, asJSON bool) error
 	}
 	handler struct {
-		tokenExp time.Duration
-		service  userService
+		service   userService
+		transport tokenTransport
+		policy    *credentials.Policy
 	}
 )
 
-func newHandler(tokenExp time.Duration, service userService) *handler {
-	return &handler{tokenExp, service}
-}
-
-func (h *handler) setCookie(c *fiber.Ctx, token string) {
-	c.Cookie(&fiber.Cookie{
-		Name:  "token",
-		Value: token,
-		Path:  "/",
-		//HTTPOnly: true,
-		Expires: time.Now().Add(h.tokenExp),
-		//Secure:   true,
-	})
+func newHandler(service userService, transport tokenTransport, policy *credentials.Policy) *handler {
+	return &handler{service, transport, policy}
 }
 
 func (h *handler) register(c *fiber.Ctx) error {
@@ -46,22 +43,25 @@ func (h *handler) register(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
 	}
 
-	if err := body.Validate(); err != nil {
-		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	if err := body.Validate(h.policy); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(err)
 	}
 
-	token, err := h.service.Register(c.Context(), body)
+	issued, err := h.service.Register(c.Context(), body, tokens.ClientOf(c))
 	if err != nil {
 		log.Println(err)
 		if errors.Is(err, apperrors.ErrIsExist) {
 			return c.Status(fiber.StatusConflict).SendString(http.StatusText(fiber.StatusConflict))
 		}
+		if errors.Is(err, apperrors.ErrInvalidReferralCode) {
+			v := &apperrors.ValidationError{}
+			v.Add("referral_code", dto.CodeUnknownReferral, "referral_code is unknown")
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(v)
+		}
 		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
 	}
 
-	h.setCookie(c, token)
-
-	return c.SendStatus(fiber.StatusOK)
+	return h.transport.Write(c, issued, tokens.WantsJSON(c))
 
 }
 
@@ -72,11 +72,11 @@ func (h *handler) authenticate(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
 	}
 
-	if err := body.Validate(); err != nil {
-		return c.Status(fiber.StatusUnprocessableEntity).SendString(http.StatusText(fiber.StatusUnprocessableEntity))
+	if err := body.Validate(h.policy); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(err)
 	}
 
-	token, err := h.service.Authenticate(c.Context(), body)
+	issued, err := h.service.Authenticate(c.Context(), body, tokens.ClientOf(c))
 	if err != nil {
 		if errors.Is(err, apperrors.ErrNotFound) {
 			return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
@@ -84,10 +84,23 @@ func (h *handler) authenticate(c *fiber.Ctx) error {
 		if errors.Is(err, apperrors.ErrInvalidCredentials) {
 			return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
 		}
+		if errors.Is(err, apperrors.ErrInactive) {
+			return c.Status(fiber.StatusForbidden).SendString(http.StatusText(fiber.StatusForbidden))
+		}
+		var challenge *apperrors.ChallengeError
+		if errors.As(err, &challenge) {
+			return c.Status(fiber.StatusAccepted).JSON(dto.ChallengeResponse{
+				ChallengeToken: challenge.Token,
+				ExpiresAt:      challenge.ExpiresAt,
+			})
+		}
+		var retry *apperrors.RetryError
+		if errors.As(err, &retry) {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retry.RetryAfter.Seconds()))))
+			return c.Status(fiber.StatusTooManyRequests).SendString(http.StatusText(fiber.StatusTooManyRequests))
+		}
 		return c.Status(fiber.StatusInternalServerError).SendString(err.Error())
 	}
 
-	h.setCookie(c, token)
-
-	return c.SendStatus(fiber.StatusOK)
+	return h.transport.Write(c, issued, tokens.WantsJSON(c))
 }
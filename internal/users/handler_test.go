@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
 	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
 	"net/http"
 	"net/http/httptest"
@@ -15,6 +16,9 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/dkmelnik/go-musthave-diploma/internal/credentials"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens"
+	"github.com/dkmelnik/go-musthave-diploma/internal/users/dto"
 	"github.com/dkmelnik/go-musthave-diploma/internal/users/mocks"
 )
 
@@ -32,6 +36,7 @@ type (
 		prepare testPrepareFunc
 		body    map[string]interface{}
 		method  string
+		accept  string
 		want    want
 		wantErr bool
 	}
@@ -46,7 +51,7 @@ func Test_registration(t *testing.T) {
 			wantErr: true,
 			want: want{
 				code:        http.StatusUnprocessableEntity,
-				contentType: "text/plain; charset=utf-8",
+				contentType: "application/json",
 			},
 		},
 		{
@@ -76,13 +81,26 @@ func Test_registration(t *testing.T) {
 			},
 		},
 		{
-			name: "negative test #4, login exist",
+			name: "negative test #4, password breaks the policy",
+			body: map[string]interface{}{
+				"login":    "testtest21@",
+				"password": "testtest21@",
+			},
+			method:  http.MethodPost,
+			wantErr: true,
+			want: want{
+				code:        http.StatusUnprocessableEntity,
+				contentType: "application/json",
+			},
+		},
+		{
+			name: "negative test #5, login exist",
 			prepare: func(f *servicesMock) {
-				f.userService.EXPECT().Register(gomock.Any(), gomock.Any()).Return("", apperrors.ErrIsExist).AnyTimes()
+				f.userService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Return(appdto.Tokens{}, apperrors.ErrIsExist).AnyTimes()
 			},
 			body: map[string]interface{}{
 				"login":    "testtest21@",
-				"password": "12213123123",
+				"password": "Gopher-12213123",
 			},
 			method:  http.MethodPost,
 			wantErr: true,
@@ -92,13 +110,13 @@ func Test_registration(t *testing.T) {
 			},
 		},
 		{
-			name: "negative test #5, unknown service error",
+			name: "negative test #6, unknown service error",
 			prepare: func(f *servicesMock) {
-				f.userService.EXPECT().Register(gomock.Any(), gomock.Any()).Return("", errors.New("some error")).AnyTimes()
+				f.userService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Return(appdto.Tokens{}, errors.New("some error")).AnyTimes()
 			},
 			body: map[string]interface{}{
 				"login":    "testtest21@",
-				"password": "12213123123",
+				"password": "Gopher-12213123",
 			},
 			method:  http.MethodPost,
 			wantErr: true,
@@ -108,13 +126,30 @@ func Test_registration(t *testing.T) {
 			},
 		},
 		{
-			name: "positive test #6, user saved, token returned",
+			name: "negative test #7, invalid referral code",
+			prepare: func(f *servicesMock) {
+				f.userService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Return(appdto.Tokens{}, apperrors.ErrInvalidReferralCode).AnyTimes()
+			},
+			body: map[string]interface{}{
+				"login":         "testtest21@",
+				"password":      "Gopher-12213123",
+				"referral_code": "UNKNOWN",
+			},
+			method:  http.MethodPost,
+			wantErr: true,
+			want: want{
+				code:        http.StatusUnprocessableEntity,
+				contentType: "application/json",
+			},
+		},
+		{
+			name: "positive test #8, user saved, token returned",
 			prepare: func(f *servicesMock) {
-				f.userService.EXPECT().Register(gomock.Any(), gomock.Any()).Return("token", nil).AnyTimes()
+				f.userService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Return(appdto.Tokens{AccessToken: "token", AccessExpiresAt: time.Now().Add(time.Hour)}, nil).AnyTimes()
 			},
 			body: map[string]interface{}{
 				"login":    "testtest21@",
-				"password": "12213123123",
+				"password": "Gopher-12213123",
 			},
 			method:  http.MethodPost,
 			wantErr: false,
@@ -123,6 +158,23 @@ func Test_registration(t *testing.T) {
 				contentType: "text/plain; charset=utf-8",
 			},
 		},
+		{
+			name: "positive test #9, token returned in JSON body",
+			prepare: func(f *servicesMock) {
+				f.userService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Return(appdto.Tokens{AccessToken: "token", AccessExpiresAt: time.Now().Add(time.Hour)}, nil).AnyTimes()
+			},
+			body: map[string]interface{}{
+				"login":    "testtest21@",
+				"password": "Gopher-12213123",
+			},
+			method:  http.MethodPost,
+			accept:  "application/json",
+			wantErr: false,
+			want: want{
+				code:        http.StatusOK,
+				contentType: "application/json",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -137,6 +189,9 @@ func Test_registration(t *testing.T) {
 
 			req := httptest.NewRequest(tt.method, "/register", strings.NewReader(string(bts)))
 			req.Header.Set("Content-Type", "application/json")
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
 
 			resp, err := ts.Test(req, 100)
 			if err != nil {
@@ -152,11 +207,40 @@ func Test_registration(t *testing.T) {
 				setCookieHeader := resp.Header.Get("Set-Cookie")
 				assert.True(t, setCookieHeader != "",
 					"Не удалось обнаружить авторизационные данные в ответе", string(dump))
+				assert.Equal(t, "Bearer token", resp.Header.Get("Authorization"))
 			}
 		})
 	}
 }
 
+func Test_registerUnknownReferral(t *testing.T) {
+	ts := mockAndRegisterHandlers(t, func(f *servicesMock) {
+		f.userService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Return(appdto.Tokens{}, apperrors.ErrInvalidReferralCode).AnyTimes()
+	})
+	defer ts.Shutdown()
+
+	body := `{"login":"testtest21@","password":"Gopher-12213123","referral_code":"UNKNOWN"}`
+	req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := ts.Test(req, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var verr apperrors.ValidationError
+	if err = json.NewDecoder(resp.Body).Decode(&verr); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, []apperrors.FieldError{{
+		Field:   "referral_code",
+		Code:    dto.CodeUnknownReferral,
+		Message: "referral_code is unknown",
+	}}, verr.Errors)
+}
+
 func Test_authenticate(t *testing.T) {
 	tests := []testCase{
 		{
@@ -166,7 +250,7 @@ func Test_authenticate(t *testing.T) {
 			wantErr: true,
 			want: want{
 				code:        http.StatusUnprocessableEntity,
-				contentType: "text/plain; charset=utf-8",
+				contentType: "application/json",
 			},
 		},
 		{
@@ -198,7 +282,7 @@ func Test_authenticate(t *testing.T) {
 		{
 			name: "negative test #4, login not exist",
 			prepare: func(f *servicesMock) {
-				f.userService.EXPECT().Authenticate(gomock.Any(), gomock.Any()).Return("", apperrors.ErrNotFound).AnyTimes()
+				f.userService.EXPECT().Authenticate(gomock.Any(), gomock.Any(), gomock.Any()).Return(appdto.Tokens{}, apperrors.ErrNotFound).AnyTimes()
 			},
 			body: map[string]interface{}{
 				"login":    "testtest21@",
@@ -214,7 +298,7 @@ func Test_authenticate(t *testing.T) {
 		{
 			name: "negative test #5, invalid password",
 			prepare: func(f *servicesMock) {
-				f.userService.EXPECT().Authenticate(gomock.Any(), gomock.Any()).Return("", apperrors.ErrInvalidCredentials).AnyTimes()
+				f.userService.EXPECT().Authenticate(gomock.Any(), gomock.Any(), gomock.Any()).Return(appdto.Tokens{}, apperrors.ErrInvalidCredentials).AnyTimes()
 			},
 			body: map[string]interface{}{
 				"login":    "testtest21@",
@@ -230,7 +314,7 @@ func Test_authenticate(t *testing.T) {
 		{
 			name: "negative test #6, unknown service error",
 			prepare: func(f *servicesMock) {
-				f.userService.EXPECT().Authenticate(gomock.Any(), gomock.Any()).Return("", errors.New("some error")).AnyTimes()
+				f.userService.EXPECT().Authenticate(gomock.Any(), gomock.Any(), gomock.Any()).Return(appdto.Tokens{}, errors.New("some error")).AnyTimes()
 			},
 			body: map[string]interface{}{
 				"login":    "testtest21@",
@@ -244,9 +328,41 @@ func Test_authenticate(t *testing.T) {
 			},
 		},
 		{
-			name: "positive test #7, authenticate success, token returned",
+			name: "negative test #7, too many attempts",
 			prepare: func(f *servicesMock) {
-				f.userService.EXPECT().Authenticate(gomock.Any(), gomock.Any()).Return("token", nil).AnyTimes()
+				f.userService.EXPECT().Authenticate(gomock.Any(), gomock.Any(), gomock.Any()).Return(appdto.Tokens{}, &apperrors.RetryError{Err: apperrors.ErrTooManyAttempts, RetryAfter: 1500 * time.Millisecond}).AnyTimes()
+			},
+			body: map[string]interface{}{
+				"login":    "testtest21@",
+				"password": "12213123123",
+			},
+			method:  http.MethodPost,
+			wantErr: true,
+			want: want{
+				code:        http.StatusTooManyRequests,
+				contentType: "text/plain; charset=utf-8",
+			},
+		},
+		{
+			name: "positive test #8, second factor required",
+			prepare: func(f *servicesMock) {
+				f.userService.EXPECT().Authenticate(gomock.Any(), gomock.Any(), gomock.Any()).Return(appdto.Tokens{}, &apperrors.ChallengeError{Token: "challenge", ExpiresAt: time.Now().Add(time.Minute)}).AnyTimes()
+			},
+			body: map[string]interface{}{
+				"login":    "testtest21@",
+				"password": "12213123123",
+			},
+			method:  http.MethodPost,
+			wantErr: true,
+			want: want{
+				code:        http.StatusAccepted,
+				contentType: "application/json",
+			},
+		},
+		{
+			name: "positive test #9, authenticate success, token returned",
+			prepare: func(f *servicesMock) {
+				f.userService.EXPECT().Authenticate(gomock.Any(), gomock.Any(), gomock.Any()).Return(appdto.Tokens{AccessToken: "token", AccessExpiresAt: time.Now().Add(time.Hour)}, nil).AnyTimes()
 			},
 			body: map[string]interface{}{
 				"login":    "testtest21@",
@@ -259,6 +375,36 @@ func Test_authenticate(t *testing.T) {
 				contentType: "text/plain; charset=utf-8",
 			},
 		},
+		{
+			name: "positive test #10, token returned in JSON body",
+			prepare: func(f *servicesMock) {
+				f.userService.EXPECT().Authenticate(gomock.Any(), gomock.Any(), gomock.Any()).Return(appdto.Tokens{AccessToken: "token", AccessExpiresAt: time.Now().Add(time.Hour)}, nil).AnyTimes()
+			},
+			body: map[string]interface{}{
+				"login":    "testtest21@",
+				"password": "12213123123",
+			},
+			method:  http.MethodPost,
+			accept:  "application/json",
+			wantErr: false,
+			want: want{
+				code:        http.StatusOK,
+				contentType: "application/json",
+			},
+		},
+		{
+			name: "negative test #11, login longer than the policy allows",
+			body: map[string]interface{}{
+				"login":    strings.Repeat("a", 65),
+				"password": "12213123123",
+			},
+			method:  http.MethodPost,
+			wantErr: true,
+			want: want{
+				code:        http.StatusUnprocessableEntity,
+				contentType: "application/json",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -272,6 +418,9 @@ func Test_authenticate(t *testing.T) {
 
 			req := httptest.NewRequest(tt.method, "/login", strings.NewReader(string(bts)))
 			req.Header.Set("Content-Type", "application/json")
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
 
 			resp, err := ts.Test(req, 100)
 			if err != nil {
@@ -287,6 +436,7 @@ func Test_authenticate(t *testing.T) {
 				setCookieHeader := resp.Header.Get("Set-Cookie")
 				assert.True(t, setCookieHeader != "",
 					"Не удалось обнаружить авторизационные данные в ответе", string(dump))
+				assert.Equal(t, "Bearer token", resp.Header.Get("Authorization"))
 			}
 		})
 	}
@@ -308,7 +458,25 @@ func mockAndRegisterHandlers(t *testing.T, prepare testPrepareFunc) *fiber.App {
 		us = f.userService
 	}
 
-	h := newHandler(time.Hour, us)
+	transport, err := tokens.NewTransport(tokens.CookieConfig{HTTPOnly: true, Secure: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := credentials.New(credentials.Config{
+		LoginMinLength:     3,
+		LoginMaxLength:     64,
+		LoginCharset:       "a-zA-Z0-9._@+-",
+		PasswordMinLength:  8,
+		PasswordMaxLength:  128,
+		PasswordMinClasses: 2,
+		BanCommon:          true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := newHandler(us, transport, policy)
 	app.Post("/register", h.register)
 	app.Post("/login", h.authenticate)
 
@@ -1,31 +1,63 @@
 package users
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens"
 )
 
 type (
+	Denylist interface {
+		IsRevoked(ctx context.Context, jti string) (bool, error)
+	}
+	Sessions interface {
+		Seen(ctx context.Context, jti string) error
+	}
+	APIKeys interface {
+		Verify(ctx context.Context, raw, scope string) (models.ModelID, error)
+	}
+	CSRF interface {
+		CheckCSRF(c *fiber.Ctx) error
+	}
 	MiddlewareManager struct {
 		jwtService JWTService
+		denylist   Denylist
+		sessions   Sessions
+		apiKeys    APIKeys
+		csrf       CSRF
 	}
 )
 
-func NewMiddlewareManager(jwtService JWTService) *MiddlewareManager {
-	return &MiddlewareManager{jwtService}
+// APIKeyHeader carries API keys of machine clients.
+const APIKeyHeader = "X-API-Key"
+
+func NewMiddlewareManager(
+	jwtService JWTService,
+	denylist Denylist,
+	sessions Sessions,
+	apiKeys APIKeys,
+	csrf CSRF,
+) *MiddlewareManager {
+	return &MiddlewareManager{jwtService, denylist, sessions, apiKeys, csrf}
 }
 
+// Auth accepts access tokens of live sessions. Revoking a session denylists
+// its jti, so the token stops working here at once. State-changing requests
+// authenticated by the cookie must pass the CSRF check as well.
 func (m *MiddlewareManager) Auth(c *fiber.Ctx) error {
-	cookie := c.Cookies("token")
-	if cookie == "" {
+	raw, err := tokens.ExtractToken(c)
+	if err != nil || raw == "" {
 		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
 	}
 
-	token, err := m.jwtService.ParseToken(cookie)
+	token, err := m.jwtService.ParseToken(raw)
 	if err != nil {
 		if errors.Is(err, apperrors.ErrInvalidToken) {
 			return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
@@ -33,8 +65,76 @@ func (m *MiddlewareManager) Auth(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
 	}
 
+	revoked, err := m.denylist.IsRevoked(c.Context(), token.JTI)
+	if err != nil {
+		logger.Log.Error("users:middleware:auth", "IsRevoked", err)
+		return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+	}
+	if revoked {
+		return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+	}
+
+	if c.Get(fiber.HeaderAuthorization) == "" && !tokens.IsSafeMethod(c) {
+		if err = m.csrf.CheckCSRF(c); err != nil {
+			logger.Log.Warn("users:middleware:auth", "CheckCSRF", c.IP())
+			return c.Status(fiber.StatusForbidden).SendString(http.StatusText(fiber.StatusForbidden))
+		}
+	}
+
+	if err = m.sessions.Seen(c.Context(), token.JTI); err != nil {
+		logger.Log.Error("users:middleware:auth", "Seen", err)
+	}
+
+	role := token.Role
+	if role == "" {
+		role = models.RoleUser
+	}
+
 	c.Locals("user_id", token.SUB)
+	c.Locals("role", role)
 	c.Locals("token", token)
 
 	return c.Next()
 }
+
+// RequireRole lets through users holding one of roles. It runs after Auth.
+func (m *MiddlewareManager) RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, _ := c.Locals("role").(string)
+		for _, r := range roles {
+			if r == role {
+				return c.Next()
+			}
+		}
+		return c.Status(fiber.StatusForbidden).SendString(http.StatusText(fiber.StatusForbidden))
+	}
+}
+
+// AuthOrKey accepts an API key granted scope in place of a login session.
+// Requests without the key header go through Auth. Keys act with the user
+// role whatever the role of their owner.
+func (m *MiddlewareManager) AuthOrKey(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		raw := c.Get(APIKeyHeader)
+		if raw == "" {
+			return m.Auth(c)
+		}
+
+		userID, err := m.apiKeys.Verify(c.Context(), raw, scope)
+		switch {
+		case err == nil:
+		case errors.Is(err, apperrors.ErrInvalidToken):
+			return c.Status(fiber.StatusUnauthorized).SendString(http.StatusText(fiber.StatusUnauthorized))
+		case errors.Is(err, apperrors.ErrInsufficientScope):
+			return c.Status(fiber.StatusForbidden).SendString(http.StatusText(fiber.StatusForbidden))
+		default:
+			logger.Log.Error("users:middleware:authOrKey", "Verify", err)
+			return c.Status(fiber.StatusInternalServerError).SendString(http.StatusText(fiber.StatusInternalServerError))
+		}
+
+		c.Locals("user_id", string(userID))
+		c.Locals("role", models.RoleUser)
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,326 @@
+package users
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/users/mocks"
+)
+
+func Test_auth(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		cookie  string
+		prepare func(jwt *mocks.MockJWTService, dl *mocks.MockDenylist)
+		code    int
+	}{
+		{
+			name: "negative test #1, no credentials",
+			code: http.StatusUnauthorized,
+		},
+		{
+			name:   "negative test #2, malformed Authorization header",
+			header: "Basic dXNlcjpwYXNz",
+			cookie: "cookie-token",
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "negative test #3, invalid token",
+			header: "Bearer bad",
+			prepare: func(jwt *mocks.MockJWTService, dl *mocks.MockDenylist) {
+				jwt.EXPECT().ParseToken("bad").Return(nil, apperrors.ErrInvalidToken)
+			},
+			code: http.StatusUnauthorized,
+		},
+		{
+			name:   "negative test #4, revoked token",
+			cookie: "cookie-token",
+			prepare: func(jwt *mocks.MockJWTService, dl *mocks.MockDenylist) {
+				jwt.EXPECT().ParseToken("cookie-token").Return(&appdto.Claims{SUB: "user", JTI: "jti"}, nil)
+				dl.EXPECT().IsRevoked(gomock.Any(), "jti").Return(true, nil)
+			},
+			code: http.StatusUnauthorized,
+		},
+		{
+			name:   "positive test #5, cookie token",
+			cookie: "cookie-token",
+			prepare: func(jwt *mocks.MockJWTService, dl *mocks.MockDenylist) {
+				jwt.EXPECT().ParseToken("cookie-token").Return(&appdto.Claims{SUB: "user", JTI: "jti"}, nil)
+				dl.EXPECT().IsRevoked(gomock.Any(), "jti").Return(false, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "positive test #6, bearer token takes precedence over cookie",
+			header: "Bearer header-token",
+			cookie: "cookie-token",
+			prepare: func(jwt *mocks.MockJWTService, dl *mocks.MockDenylist) {
+				jwt.EXPECT().ParseToken("header-token").Return(&appdto.Claims{SUB: "user", JTI: "jti"}, nil)
+				dl.EXPECT().IsRevoked(gomock.Any(), "jti").Return(false, nil)
+			},
+			code: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			jwtService := mocks.NewMockJWTService(ctrl)
+			denylist := mocks.NewMockDenylist(ctrl)
+			if tt.prepare != nil {
+				tt.prepare(jwtService, denylist)
+			}
+
+			sessions := mocks.NewMockSessions(ctrl)
+			sessions.EXPECT().Seen(gomock.Any(), "jti").Return(nil).AnyTimes()
+
+			app := fiber.New()
+			app.Get("/", NewMiddlewareManager(jwtService, denylist, sessions, mocks.NewMockAPIKeys(ctrl), mocks.NewMockCSRF(ctrl)).Auth, func(c *fiber.Ctx) error {
+				return c.SendString(c.Locals("user_id").(string))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "token", Value: tt.cookie})
+			}
+
+			resp, err := app.Test(req, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+
+func Test_requireRole(t *testing.T) {
+	tests := []struct {
+		name string
+		role string
+		code int
+	}{
+		{
+			name: "negative test #1, token without role",
+			code: http.StatusForbidden,
+		},
+		{
+			name: "negative test #2, user role",
+			role: models.RoleUser,
+			code: http.StatusForbidden,
+		},
+		{
+			name: "positive test #3, support role",
+			role: models.RoleSupport,
+			code: http.StatusOK,
+		},
+		{
+			name: "positive test #4, admin role",
+			role: models.RoleAdmin,
+			code: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			jwtService := mocks.NewMockJWTService(ctrl)
+			denylist := mocks.NewMockDenylist(ctrl)
+			jwtService.EXPECT().ParseToken("token").Return(&appdto.Claims{SUB: "user", JTI: "jti", Role: tt.role}, nil)
+			denylist.EXPECT().IsRevoked(gomock.Any(), "jti").Return(false, nil)
+
+			sessions := mocks.NewMockSessions(ctrl)
+			sessions.EXPECT().Seen(gomock.Any(), "jti").Return(nil)
+
+			mw := NewMiddlewareManager(jwtService, denylist, sessions, mocks.NewMockAPIKeys(ctrl), mocks.NewMockCSRF(ctrl))
+			app := fiber.New()
+			app.Get("/", mw.Auth, mw.RequireRole(models.RoleSupport, models.RoleAdmin), func(c *fiber.Ctx) error {
+				return c.SendStatus(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer token")
+
+			resp, err := app.Test(req, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+
+func Test_authOrKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		cookie  string
+		prepare func(jwt *mocks.MockJWTService, dl *mocks.MockDenylist, keys *mocks.MockAPIKeys)
+		code    int
+	}{
+		{
+			name: "negative test #1, no credentials",
+			code: http.StatusUnauthorized,
+		},
+		{
+			name: "negative test #2, unknown key",
+			key:  "gm_unknown",
+			prepare: func(jwt *mocks.MockJWTService, dl *mocks.MockDenylist, keys *mocks.MockAPIKeys) {
+				keys.EXPECT().Verify(gomock.Any(), "gm_unknown", models.ScopeBalanceRead).Return(models.ModelID(""), apperrors.ErrInvalidToken)
+			},
+			code: http.StatusUnauthorized,
+		},
+		{
+			name: "negative test #3, key without the scope",
+			key:  "gm_key",
+			prepare: func(jwt *mocks.MockJWTService, dl *mocks.MockDenylist, keys *mocks.MockAPIKeys) {
+				keys.EXPECT().Verify(gomock.Any(), "gm_key", models.ScopeBalanceRead).Return(models.ModelID(""), apperrors.ErrInsufficientScope)
+			},
+			code: http.StatusForbidden,
+		},
+		{
+			name: "positive test #4, key with the scope",
+			key:  "gm_key",
+			prepare: func(jwt *mocks.MockJWTService, dl *mocks.MockDenylist, keys *mocks.MockAPIKeys) {
+				keys.EXPECT().Verify(gomock.Any(), "gm_key", models.ScopeBalanceRead).Return(models.ModelID("user"), nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "positive test #5, falls back to the session",
+			cookie: "cookie-token",
+			prepare: func(jwt *mocks.MockJWTService, dl *mocks.MockDenylist, keys *mocks.MockAPIKeys) {
+				jwt.EXPECT().ParseToken("cookie-token").Return(&appdto.Claims{SUB: "user", JTI: "jti"}, nil)
+				dl.EXPECT().IsRevoked(gomock.Any(), "jti").Return(false, nil)
+			},
+			code: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			jwtService := mocks.NewMockJWTService(ctrl)
+			denylist := mocks.NewMockDenylist(ctrl)
+			apiKeys := mocks.NewMockAPIKeys(ctrl)
+			if tt.prepare != nil {
+				tt.prepare(jwtService, denylist, apiKeys)
+			}
+
+			sessions := mocks.NewMockSessions(ctrl)
+			sessions.EXPECT().Seen(gomock.Any(), "jti").Return(nil).AnyTimes()
+
+			mw := NewMiddlewareManager(jwtService, denylist, sessions, apiKeys, mocks.NewMockCSRF(ctrl))
+			app := fiber.New()
+			app.Get("/", mw.AuthOrKey(models.ScopeBalanceRead), func(c *fiber.Ctx) error {
+				assert.Equal(t, "user", c.Locals("user_id"))
+				assert.Equal(t, models.RoleUser, c.Locals("role"))
+				return c.SendStatus(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.key != "" {
+				req.Header.Set(APIKeyHeader, tt.key)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "token", Value: tt.cookie})
+			}
+
+			resp, err := app.Test(req, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
+
+func Test_authCSRF(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		header string
+		csrf   error
+		code   int
+	}{
+		{
+			name:   "negative test #1, cookie mutation failing the check",
+			method: http.MethodPost,
+			csrf:   apperrors.ErrCSRF,
+			code:   http.StatusForbidden,
+		},
+		{
+			name:   "positive test #2, cookie mutation passing the check",
+			method: http.MethodPost,
+			code:   http.StatusOK,
+		},
+		{
+			name:   "positive test #3, cookie read is not checked",
+			method: http.MethodGet,
+			code:   http.StatusOK,
+		},
+		{
+			name:   "positive test #4, bearer mutation is not checked",
+			method: http.MethodPost,
+			header: "Bearer token",
+			code:   http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			jwtService := mocks.NewMockJWTService(ctrl)
+			jwtService.EXPECT().ParseToken(gomock.Any()).Return(&appdto.Claims{SUB: "user", JTI: "jti"}, nil)
+			denylist := mocks.NewMockDenylist(ctrl)
+			denylist.EXPECT().IsRevoked(gomock.Any(), "jti").Return(false, nil)
+			sessions := mocks.NewMockSessions(ctrl)
+			sessions.EXPECT().Seen(gomock.Any(), "jti").Return(nil).AnyTimes()
+			csrf := mocks.NewMockCSRF(ctrl)
+			if tt.method == http.MethodPost && tt.header == "" {
+				csrf.EXPECT().CheckCSRF(gomock.Any()).Return(tt.csrf)
+			}
+
+			mw := NewMiddlewareManager(jwtService, denylist, sessions, mocks.NewMockAPIKeys(ctrl), csrf)
+			app := fiber.New()
+			app.Add(tt.method, "/", mw.Auth, func(c *fiber.Ctx) error {
+				return c.SendStatus(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, "/", nil)
+			req.AddCookie(&http.Cookie{Name: "token", Value: "cookie-token"})
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			resp, err := app.Test(req, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+		})
+	}
+}
@@ -8,7 +8,9 @@ import (
 	context "context"
 	reflect "reflect"
 
-	dto "github.com/dkmelnik/go-musthave-diploma/internal/users/dto"
+	dto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	dto0 "github.com/dkmelnik/go-musthave-diploma/internal/users/dto"
+	fiber "github.com/gofiber/fiber/v2"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -36,31 +38,68 @@ func (m *MockuserService) EXPECT() *MockuserServiceMockRecorder {
 }
 
 // Authenticate mocks base method.
-func (m *MockuserService) Authenticate(ctx context.Context, dto dto.LoginPayload) (string, error) {
+func (m *MockuserService) Authenticate(ctx context.Context, payload dto0.LoginPayload, client dto.Client) (dto.Tokens, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authenticate", ctx, dto)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Authenticate", ctx, payload, client)
+	ret0, _ := ret[0].(dto.Tokens)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Authenticate indicates an expected call of Authenticate.
-func (mr *MockuserServiceMockRecorder) Authenticate(ctx, dto interface{}) *gomock.Call {
+func (mr *MockuserServiceMockRecorder) Authenticate(ctx, payload, client interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockuserService)(nil).Authenticate), ctx, dto)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockuserService)(nil).Authenticate), ctx, payload, client)
 }
 
 // Register mocks base method.
-func (m *MockuserService) Register(ctx context.Context, dto dto.RegisterPayload) (string, error) {
+func (m *MockuserService) Register(ctx context.Context, payload dto0.RegisterPayload, client dto.Client) (dto.Tokens, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Register", ctx, dto)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Register", ctx, payload, client)
+	ret0, _ := ret[0].(dto.Tokens)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Register indicates an expected call of Register.
-func (mr *MockuserServiceMockRecorder) Register(ctx, dto interface{}) *gomock.Call {
+func (mr *MockuserServiceMockRecorder) Register(ctx, payload, client interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockuserService)(nil).Register), ctx, dto)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockuserService)(nil).Register), ctx, payload, client)
+}
+
+// MocktokenTransport is a mock of tokenTransport interface.
+type MocktokenTransport struct {
+	ctrl     *gomock.Controller
+	recorder *MocktokenTransportMockRecorder
+}
+
+// MocktokenTransportMockRecorder is the mock recorder for MocktokenTransport.
+type MocktokenTransportMockRecorder struct {
+	mock *MocktokenTransport
+}
+
+// NewMocktokenTransport creates a new mock instance.
+func NewMocktokenTransport(ctrl *gomock.Controller) *MocktokenTransport {
+	mock := &MocktokenTransport{ctrl: ctrl}
+	mock.recorder = &MocktokenTransportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktokenTransport) EXPECT() *MocktokenTransportMockRecorder {
+	return m.recorder
+}
+
+// Write mocks base method.
+func (m *MocktokenTransport) Write(c *fiber.Ctx, issued dto.Tokens, asJSON bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Write", c, issued, asJSON)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Write indicates an expected call of Write.
+func (mr *MocktokenTransportMockRecorder) Write(c, issued, asJSON interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MocktokenTransport)(nil).Write), c, issued, asJSON)
 }
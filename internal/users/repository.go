@@ -6,6 +6,7 @@ import (
 	"errors"
 
 	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/db/pg"
 	"github.com/dkmelnik/go-musthave-diploma/internal/models"
 )
 
@@ -17,15 +18,42 @@ func NewRepository(db *sql.DB) *Repository {
 	return &Repository{db}
 }
 
-func (r *Repository) Save(ctx context.Context, m *models.User) (models.ModelID, error) {
+// Save stores m and, when referrerID is set, the pending referral from
+// referrerID in the same transaction, so a user never exists without the
+// referral they signed up with. A login taken by a concurrent Save yields
+// apperrors.ErrIsExist.
+func (r *Repository) Save(ctx context.Context, m *models.User, referrerID models.ModelID) (models.ModelID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
 	var id models.ModelID
 	query := `
-		INSERT INTO users (login, password) 
-		VALUES ($1, $2) 
+		INSERT INTO users (login, password, referral_code) 
+		VALUES ($1, $2, $3) 
 		RETURNING id
 	`
-	err := r.db.QueryRowContext(ctx, query, m.Login, m.Password).Scan(&id)
+	err = tx.QueryRowContext(ctx, query, m.Login, m.Password, m.ReferralCode).Scan(&id)
 	if err != nil {
+		if pg.IsUniqueViolation(err) {
+			return "", apperrors.ErrIsExist
+		}
+		return "", err
+	}
+
+	if referrerID != "" {
+		referralQuery := `
+			INSERT INTO referrals (referrer_id, referee_id)
+			VALUES ($1, $2)
+		`
+		if _, err = tx.ExecContext(ctx, referralQuery, referrerID, id); err != nil {
+			return "", err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
 		return "", err
 	}
 	return id, nil
@@ -49,9 +77,16 @@ func (r *Repository) IsEntryByLogin(ctx context.Context, login string) (bool, er
 func (r *Repository) FindOneByLogin(ctx context.Context, login string) (*models.User, error) {
 	var user models.User
 	query := `
-		SELECT id, login, password, created_at FROM users WHERE login = $1 LIMIT 1
+		SELECT id, login, password, email, email_verified_at, created_at FROM users WHERE login = $1 LIMIT 1
 	`
-	err := r.db.QueryRowContext(ctx, query, login).Scan(&user.ID, &user.Login, &user.Password, &user.CreatedAt)
+	err := r.db.QueryRowContext(ctx, query, login).Scan(
+		&user.ID,
+		&user.Login,
+		&user.Password,
+		&user.Email,
+		&user.EmailVerifiedAt,
+		&user.CreatedAt,
+	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, apperrors.ErrNotFound
@@ -60,3 +95,41 @@ func (r *Repository) FindOneByLogin(ctx context.Context, login string) (*models.
 	}
 	return &user, nil
 }
+
+func (r *Repository) FindOneByID(ctx context.Context, id models.ModelID) (*models.User, error) {
+	var user models.User
+	query := `
+		SELECT id, login, created_at FROM users WHERE id = $1 LIMIT 1
+	`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&user.ID, &user.Login, &user.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *Repository) FindOneByReferralCode(ctx context.Context, code string) (*models.User, error) {
+	var user models.User
+	query := `
+		SELECT id, login, referral_code, created_at FROM users WHERE referral_code = $1 LIMIT 1
+	`
+	err := r.db.QueryRowContext(ctx, query, code).Scan(&user.ID, &user.Login, &user.ReferralCode, &user.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *Repository) UpdatePassword(ctx context.Context, userID models.ModelID, hash string) error {
+	query := `
+		UPDATE users SET password = $2 WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, userID, hash)
+	return err
+}
@@ -1,20 +1,25 @@
 package users
 
 import (
-	"time"
-
 	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/credentials"
+	"github.com/dkmelnik/go-musthave-diploma/internal/tokens"
 )
 
 func SetupRouter(
 	r fiber.Router,
-	tokenExp time.Duration,
-	jwtService JWTService,
+	transport *tokens.Transport,
+	policy *credentials.Policy,
+	hasher PasswordHasher,
+	tokenIssuer TokenIssuer,
+	loginGuard LoginGuard,
+	twoFactor TwoFactor,
 	userRepository UserRepository,
 ) {
 
-	us := NewService(jwtService, userRepository)
-	handle := newHandler(tokenExp, us)
+	us := NewService(hasher, tokenIssuer, loginGuard, twoFactor, userRepository)
+	handle := newHandler(us, transport, policy)
 
 	r.Post("register", handle.register)
 	r.Post("login", handle.authenticate)
@@ -2,11 +2,12 @@ package users
 
 import (
 	"context"
-
-	"golang.org/x/crypto/bcrypt"
+	"errors"
+	"strings"
 
 	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
 	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/logger"
 	"github.com/dkmelnik/go-musthave-diploma/internal/models"
 	"github.com/dkmelnik/go-musthave-diploma/internal/users/dto"
 	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
@@ -14,72 +15,143 @@ import (
 
 type (
 	UserRepository interface {
-		Save(ctx context.Context, m *models.User) (models.ModelID, error)
+		Save(ctx context.Context, m *models.User, referrerID models.ModelID) (models.ModelID, error)
 		IsEntryByLogin(ctx context.Context, login string) (bool, error)
 		FindOneByLogin(ctx context.Context, login string) (*models.User, error)
+		FindOneByReferralCode(ctx context.Context, code string) (*models.User, error)
+		UpdatePassword(ctx context.Context, userID models.ModelID, hash string) error
+	}
+	PasswordHasher interface {
+		Hash(password string) (string, error)
+		Verify(hash, password string) (needsRehash bool, err error)
 	}
 	JWTService interface {
-		BuildJWTString(userID models.ModelID, jti string) (string, error)
+		BuildJWTString(userID models.ModelID, role, jti string) (string, error)
 		ParseToken(tokenString string) (*appdto.Claims, error)
 	}
+	TokenIssuer interface {
+		Issue(ctx context.Context, userID models.ModelID, client appdto.Client) (appdto.Tokens, error)
+	}
+	LoginGuard interface {
+		Check(ctx context.Context, login, ip string) error
+		Fail(ctx context.Context, login, ip string) error
+		Succeed(ctx context.Context, login, ip string) error
+	}
+	TwoFactor interface {
+		Challenge(ctx context.Context, userID models.ModelID) error
+	}
 	Service struct {
-		cost           int
-		jwtService     JWTService
+		hasher         PasswordHasher
+		tokenIssuer    TokenIssuer
+		loginGuard     LoginGuard
+		twoFactor      TwoFactor
 		userRepository UserRepository
 	}
 )
 
-func NewService(jwtService JWTService, userRepository UserRepository) *Service {
-	return &Service{bcrypt.DefaultCost, jwtService, userRepository}
+func NewService(
+	hasher PasswordHasher,
+	tokenIssuer TokenIssuer,
+	loginGuard LoginGuard,
+	twoFactor TwoFactor,
+	userRepository UserRepository,
+) *Service {
+	return &Service{hasher, tokenIssuer, loginGuard, twoFactor, userRepository}
 }
 
-func (s *Service) Register(ctx context.Context, dto dto.RegisterPayload) (string, error) {
+func (s *Service) Register(ctx context.Context, dto dto.RegisterPayload, client appdto.Client) (appdto.Tokens, error) {
 	exist, err := s.userRepository.IsEntryByLogin(ctx, dto.Login)
 	if err != nil {
-		return "", err
+		return appdto.Tokens{}, err
 	}
 
 	if exist {
-		return "", apperrors.ErrIsExist
+		return appdto.Tokens{}, apperrors.ErrIsExist
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(dto.Password), s.cost)
-	if err != nil {
-		return "", err
+	var referrerID models.ModelID
+	if code := strings.ToUpper(strings.TrimSpace(dto.ReferralCode)); code != "" {
+		referrer, err := s.userRepository.FindOneByReferralCode(ctx, code)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrNotFound) {
+				return appdto.Tokens{}, apperrors.ErrInvalidReferralCode
+			}
+			return appdto.Tokens{}, err
+		}
+		referrerID = referrer.ID
 	}
 
-	userID, err := s.userRepository.Save(ctx, &models.User{
-		Login:    dto.Login,
-		Password: string(hashedPassword),
-	})
-
+	hashedPassword, err := s.hasher.Hash(dto.Password)
 	if err != nil {
-		return "", err
+		return appdto.Tokens{}, err
 	}
 
-	token, err := s.jwtService.BuildJWTString(userID, utils.GenerateGUID())
+	userID, err := s.userRepository.Save(ctx, &models.User{
+		Login:        dto.Login,
+		Password:     hashedPassword,
+		ReferralCode: utils.GenerateCode(10),
+	}, referrerID)
+
 	if err != nil {
-		return "", err
+		return appdto.Tokens{}, err
 	}
 
-	return token, nil
+	return s.tokenIssuer.Issue(ctx, userID, client)
 }
 
-func (s *Service) Authenticate(ctx context.Context, dto dto.LoginPayload) (string, error) {
+// Authenticate checks the credentials of a login attempt from client. Locked out
+// logins and IPs are rejected before the password hash is computed. Users
+// with 2FA get an *apperrors.ChallengeError instead of tokens.
+func (s *Service) Authenticate(ctx context.Context, dto dto.LoginPayload, client appdto.Client) (appdto.Tokens, error) {
+	if err := s.loginGuard.Check(ctx, dto.Login, client.IP); err != nil {
+		return appdto.Tokens{}, err
+	}
 
 	user, err := s.userRepository.FindOneByLogin(ctx, dto.Login)
 	if err != nil {
-		return "", err
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return appdto.Tokens{}, s.fail(ctx, dto.Login, client.IP, err)
+		}
+		return appdto.Tokens{}, err
 	}
 
-	if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(dto.Password)); err != nil {
-		return "", apperrors.ErrInvalidCredentials
+	needsRehash, err := s.hasher.Verify(user.Password, dto.Password)
+	if err != nil {
+		return appdto.Tokens{}, s.fail(ctx, dto.Login, client.IP, apperrors.ErrInvalidCredentials)
+	}
+	if needsRehash {
+		s.rehash(ctx, user.ID, dto.Password)
+	}
+
+	// with 2FA the login only succeeds once the challenge is completed, the
+	// attempt stays counted until then
+	if err = s.twoFactor.Challenge(ctx, user.ID); err != nil {
+		return appdto.Tokens{}, err
+	}
+
+	if err = s.loginGuard.Succeed(ctx, dto.Login, client.IP); err != nil {
+		return appdto.Tokens{}, err
 	}
 
-	token, err := s.jwtService.BuildJWTString(user.ID, utils.GenerateGUID())
+	return s.tokenIssuer.Issue(ctx, user.ID, client)
+}
+
+// rehash upgrades the stored hash to the current algorithm and parameters.
+// The login goes on if that fails, the next one will try again.
+func (s *Service) rehash(ctx context.Context, userID models.ModelID, password string) {
+	hash, err := s.hasher.Hash(password)
+	if err == nil {
+		err = s.userRepository.UpdatePassword(ctx, userID, hash)
+	}
 	if err != nil {
-		return "", err
+		logger.Log.Error("users:rehash", "UpdatePassword", err)
 	}
+}
 
-	return token, nil
+// fail records a failed attempt and returns cause unless recording failed.
+func (s *Service) fail(ctx context.Context, login, ip string, cause error) error {
+	if err := s.loginGuard.Fail(ctx, login, ip); err != nil {
+		return err
+	}
+	return cause
 }
@@ -0,0 +1,99 @@
+package users
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/users/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/users/mocks"
+)
+
+type serviceMocks struct {
+	users     *mocks.MockUserRepository
+	hasher    *mocks.MockPasswordHasher
+	issuer    *mocks.MockTokenIssuer
+	guard     *mocks.MockLoginGuard
+	twoFactor *mocks.MockTwoFactor
+}
+
+func newTestService(ctrl *gomock.Controller) (*Service, serviceMocks) {
+	m := serviceMocks{
+		users:     mocks.NewMockUserRepository(ctrl),
+		hasher:    mocks.NewMockPasswordHasher(ctrl),
+		issuer:    mocks.NewMockTokenIssuer(ctrl),
+		guard:     mocks.NewMockLoginGuard(ctrl),
+		twoFactor: mocks.NewMockTwoFactor(ctrl),
+	}
+	return NewService(m.hasher, m.issuer, m.guard, m.twoFactor, m.users), m
+}
+
+func TestService_Authenticate(t *testing.T) {
+	user := &models.User{ID: "user", Login: "alice", Password: "hash"}
+	client := appdto.Client{IP: "10.0.0.1"}
+	issued := appdto.Tokens{AccessToken: "access"}
+	challenge := &apperrors.ChallengeError{Token: "challenge", ExpiresAt: time.Now().Add(time.Minute)}
+
+	tests := []struct {
+		name    string
+		prepare func(m serviceMocks)
+		want    error
+	}{
+		{
+			name: "positive test #1, login without 2FA succeeds",
+			prepare: func(m serviceMocks) {
+				gomock.InOrder(
+					m.guard.EXPECT().Check(gomock.Any(), "alice", "10.0.0.1").Return(nil),
+					m.users.EXPECT().FindOneByLogin(gomock.Any(), "alice").Return(user, nil),
+					m.hasher.EXPECT().Verify("hash", "secret").Return(false, nil),
+					m.twoFactor.EXPECT().Challenge(gomock.Any(), user.ID).Return(nil),
+					m.guard.EXPECT().Succeed(gomock.Any(), "alice", "10.0.0.1").Return(nil),
+					m.issuer.EXPECT().Issue(gomock.Any(), user.ID, client).Return(issued, nil),
+				)
+			},
+		},
+		{
+			name: "positive test #2, challenged login stays counted",
+			prepare: func(m serviceMocks) {
+				m.guard.EXPECT().Check(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+				m.users.EXPECT().FindOneByLogin(gomock.Any(), "alice").Return(user, nil)
+				m.hasher.EXPECT().Verify("hash", "secret").Return(false, nil)
+				m.twoFactor.EXPECT().Challenge(gomock.Any(), user.ID).Return(challenge)
+			},
+			want: apperrors.ErrTwoFactorRequired,
+		},
+		{
+			name: "negative test #3, wrong password",
+			prepare: func(m serviceMocks) {
+				m.guard.EXPECT().Check(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+				m.users.EXPECT().FindOneByLogin(gomock.Any(), "alice").Return(user, nil)
+				m.hasher.EXPECT().Verify("hash", "secret").Return(false, apperrors.ErrInvalidCredentials)
+				m.guard.EXPECT().Fail(gomock.Any(), "alice", "10.0.0.1").Return(nil)
+			},
+			want: apperrors.ErrInvalidCredentials,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s, m := newTestService(ctrl)
+			tt.prepare(m)
+
+			got, err := s.Authenticate(context.Background(), dto.LoginPayload{Login: "alice", Password: "secret"}, client)
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, issued, got)
+		})
+	}
+}
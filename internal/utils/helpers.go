@@ -2,9 +2,13 @@ package utils
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"net/http/httputil"
+	"regexp"
 
 	"github.com/theplant/luhn"
 )
@@ -17,6 +21,41 @@ func GenerateGUID() string {
 
 	return uuid
 }
+
+var guidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// IsGUID reports whether s looks like an id produced by GenerateGUID or
+// the database.
+func IsGUID(s string) bool {
+	return guidPattern.MatchString(s)
+}
+
+// GenerateCode returns a random code of n characters that is easy to read
+// and type: digits and upper case letters without 0, 1, I and O.
+func GenerateCode(n int) string {
+	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	for i := range b {
+		b[i] = alphabet[int(b[i])%len(alphabet)]
+	}
+	return string(b)
+}
+
+// GenerateSecret returns a random url-safe secret for opaque tokens sent to
+// users. Store only its HashSecret.
+func GenerateSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// HashSecret returns the hex sha256 of a secret made by GenerateSecret.
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
 func CheckStrOnLuhn(number string) bool {
 	var sum int
 	alternate := false
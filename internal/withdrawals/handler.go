@@ -12,6 +12,7 @@ import (
 	"github.com/dkmelnik/go-musthave-diploma/internal/models"
 	"github.com/dkmelnik/go-musthave-diploma/internal/utils"
 	"github.com/dkmelnik/go-musthave-diploma/internal/withdrawals/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/withdrawals/policy"
 )
 
 type (
@@ -49,7 +50,11 @@ func (h *handler) withdrawAccrual(c *fiber.Ctx) error {
 		return c.SendStatus(fiber.StatusUnprocessableEntity)
 	}
 
+	var violation *policy.Violation
+
 	switch err = h.service.WithdrawAccrual(c.Context(), models.ModelID(userID), body); {
+	case errors.As(err, &violation):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(violation)
 	case errors.Is(err, apperrors.ErrInsufficientFunds):
 		return c.SendStatus(fiber.StatusPaymentRequired)
 	case err == nil:
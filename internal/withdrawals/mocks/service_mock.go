@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/dkmelnik/go-musthave-diploma/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockwithdrawalRepository is a mock of withdrawalRepository interface.
+type MockwithdrawalRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockwithdrawalRepositoryMockRecorder
+}
+
+// MockwithdrawalRepositoryMockRecorder is the mock recorder for MockwithdrawalRepository.
+type MockwithdrawalRepositoryMockRecorder struct {
+	mock *MockwithdrawalRepository
+}
+
+// NewMockwithdrawalRepository creates a new mock instance.
+func NewMockwithdrawalRepository(ctrl *gomock.Controller) *MockwithdrawalRepository {
+	mock := &MockwithdrawalRepository{ctrl: ctrl}
+	mock.recorder = &MockwithdrawalRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockwithdrawalRepository) EXPECT() *MockwithdrawalRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Find mocks base method.
+func (m *MockwithdrawalRepository) Find(ctx context.Context, userID models.ModelID) ([]*models.Withdrawal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, userID)
+	ret0, _ := ret[0].([]*models.Withdrawal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockwithdrawalRepositoryMockRecorder) Find(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockwithdrawalRepository)(nil).Find), ctx, userID)
+}
+
+// IsEntryByOrderNumber mocks base method.
+func (m *MockwithdrawalRepository) IsEntryByOrderNumber(ctx context.Context, orderNumber string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEntryByOrderNumber", ctx, orderNumber)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsEntryByOrderNumber indicates an expected call of IsEntryByOrderNumber.
+func (mr *MockwithdrawalRepositoryMockRecorder) IsEntryByOrderNumber(ctx, orderNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEntryByOrderNumber", reflect.TypeOf((*MockwithdrawalRepository)(nil).IsEntryByOrderNumber), ctx, orderNumber)
+}
+
+// Withdraw mocks base method.
+func (m *MockwithdrawalRepository) Withdraw(ctx context.Context, w *models.Withdrawal, largeAmount float64, check func(float64, models.WithdrawalUsage) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Withdraw", ctx, w, largeAmount, check)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Withdraw indicates an expected call of Withdraw.
+func (mr *MockwithdrawalRepositoryMockRecorder) Withdraw(ctx, w, largeAmount, check interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockwithdrawalRepository)(nil).Withdraw), ctx, w, largeAmount, check)
+}
+
+// MockwithdrawalPolicy is a mock of withdrawalPolicy interface.
+type MockwithdrawalPolicy struct {
+	ctrl     *gomock.Controller
+	recorder *MockwithdrawalPolicyMockRecorder
+}
+
+// MockwithdrawalPolicyMockRecorder is the mock recorder for MockwithdrawalPolicy.
+type MockwithdrawalPolicyMockRecorder struct {
+	mock *MockwithdrawalPolicy
+}
+
+// NewMockwithdrawalPolicy creates a new mock instance.
+func NewMockwithdrawalPolicy(ctrl *gomock.Controller) *MockwithdrawalPolicy {
+	mock := &MockwithdrawalPolicy{ctrl: ctrl}
+	mock.recorder = &MockwithdrawalPolicyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockwithdrawalPolicy) EXPECT() *MockwithdrawalPolicyMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockwithdrawalPolicy) Check(amount float64, usage models.WithdrawalUsage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", amount, usage)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockwithdrawalPolicyMockRecorder) Check(amount, usage interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockwithdrawalPolicy)(nil).Check), amount, usage)
+}
+
+// LargeAmount mocks base method.
+func (m *MockwithdrawalPolicy) LargeAmount() float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LargeAmount")
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// LargeAmount indicates an expected call of LargeAmount.
+func (mr *MockwithdrawalPolicyMockRecorder) LargeAmount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LargeAmount", reflect.TypeOf((*MockwithdrawalPolicy)(nil).LargeAmount))
+}
@@ -0,0 +1,122 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+const (
+	CodeAmountNotPositive  = "amount_not_positive"
+	CodeAmountBelowMin     = "amount_below_min"
+	CodeAmountAboveMax     = "amount_above_max"
+	CodeInvalidIncrement   = "invalid_increment"
+	CodeDailyCapExceeded   = "daily_cap_exceeded"
+	CodeMonthlyCapExceeded = "monthly_cap_exceeded"
+	CodeCooldownActive     = "cooldown_active"
+)
+
+// Rules configure the withdrawal policy. A zero value disables the rule.
+type Rules struct {
+	MinAmount   float64  `json:"min_amount"`
+	MaxAmount   float64  `json:"max_amount"`
+	DailyCap    float64  `json:"daily_cap"`
+	MonthlyCap  float64  `json:"monthly_cap"`
+	Increment   float64  `json:"increment"`
+	LargeAmount float64  `json:"large_amount"`
+	Cooldown    Duration `json:"cooldown"`
+}
+
+// Duration reads durations written as "24h" from a policy file.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Violation is returned when a withdrawal breaks a rule.
+type Violation struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (v *Violation) Error() string {
+	return v.Message
+}
+
+type Policy struct {
+	rules Rules
+}
+
+func New(rules Rules) *Policy {
+	return &Policy{rules}
+}
+
+// Load reads rules from a JSON file. Fields missing from the file keep their
+// value from base.
+func Load(path string, base Rules) (Rules, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return base, fmt.Errorf("read withdrawal policy: %w", err)
+	}
+	if err = json.Unmarshal(b, &base); err != nil {
+		return base, fmt.Errorf("parse withdrawal policy: %w", err)
+	}
+	return base, nil
+}
+
+// LargeAmount is the threshold from which a withdrawal starts the cooldown.
+func (p *Policy) LargeAmount() float64 {
+	return p.rules.LargeAmount
+}
+
+// Check validates amount against the rules given the user's usage.
+func (p *Policy) Check(amount float64, usage models.WithdrawalUsage) error {
+	r := p.rules
+
+	if amount <= 0 {
+		return &Violation{CodeAmountNotPositive, "amount must be positive"}
+	}
+	if r.MinAmount > 0 && amount < r.MinAmount {
+		return &Violation{CodeAmountBelowMin, fmt.Sprintf("amount must be at least %g", r.MinAmount)}
+	}
+	if r.MaxAmount > 0 && amount > r.MaxAmount {
+		return &Violation{CodeAmountAboveMax, fmt.Sprintf("amount must not exceed %g", r.MaxAmount)}
+	}
+	if r.Increment > 0 && !isMultiple(amount, r.Increment) {
+		return &Violation{CodeInvalidIncrement, fmt.Sprintf("amount must be a multiple of %g", r.Increment)}
+	}
+	if r.DailyCap > 0 && usage.Day+amount > r.DailyCap {
+		return &Violation{CodeDailyCapExceeded, fmt.Sprintf("daily cap of %g exceeded", r.DailyCap)}
+	}
+	if r.MonthlyCap > 0 && usage.Month+amount > r.MonthlyCap {
+		return &Violation{CodeMonthlyCapExceeded, fmt.Sprintf("monthly cap of %g exceeded", r.MonthlyCap)}
+	}
+	cooldown := time.Duration(r.Cooldown)
+	if r.LargeAmount > 0 && cooldown > 0 && usage.HasLarge && usage.SinceLastLarge < cooldown {
+		return &Violation{
+			CodeCooldownActive,
+			fmt.Sprintf("withdrawals are paused for %s after a large withdrawal", (cooldown - usage.SinceLastLarge).Round(time.Second)),
+		}
+	}
+
+	return nil
+}
+
+func isMultiple(amount, step float64) bool {
+	n := math.Round(amount / step)
+	return math.Abs(n*step-amount) < 1e-9
+}
@@ -0,0 +1,63 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	p := New(Rules{
+		MinAmount:   10,
+		MaxAmount:   1000,
+		DailyCap:    1500,
+		MonthlyCap:  3000,
+		Increment:   0.5,
+		LargeAmount: 500,
+		Cooldown:    Duration(time.Hour),
+	})
+
+	tests := []struct {
+		name   string
+		amount float64
+		usage  models.WithdrawalUsage
+		code   string
+	}{
+		{name: "negative amount", amount: -1, code: CodeAmountNotPositive},
+		{name: "zero amount", amount: 0, code: CodeAmountNotPositive},
+		{name: "below min", amount: 5, code: CodeAmountBelowMin},
+		{name: "above max", amount: 1001, code: CodeAmountAboveMax},
+		{name: "bad increment", amount: 10.3, code: CodeInvalidIncrement},
+		{name: "daily cap", amount: 600, usage: models.WithdrawalUsage{Day: 1000}, code: CodeDailyCapExceeded},
+		{name: "monthly cap", amount: 600, usage: models.WithdrawalUsage{Month: 2500}, code: CodeMonthlyCapExceeded},
+		{
+			name:   "cooldown active",
+			amount: 10,
+			usage:  models.WithdrawalUsage{HasLarge: true, SinceLastLarge: time.Minute},
+			code:   CodeCooldownActive,
+		},
+		{
+			name:   "cooldown passed",
+			amount: 10,
+			usage:  models.WithdrawalUsage{HasLarge: true, SinceLastLarge: 2 * time.Hour},
+		},
+		{name: "allowed", amount: 10.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.Check(tt.amount, tt.usage)
+			if tt.code == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var v *Violation
+			if assert.True(t, errors.As(err, &v)) {
+				assert.Equal(t, tt.code, v.Code)
+			}
+		})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/ledger"
 	"github.com/dkmelnik/go-musthave-diploma/internal/models"
 )
 
@@ -17,25 +19,64 @@ func NewRepository(db *sql.DB) *Repository {
 	return &Repository{db}
 }
 
-func (r *Repository) Save(ctx context.Context, order *models.Withdrawal) error {
+// Withdraw saves w while the user's account is locked. check receives the
+// balance and the withdrawal usage, largeAmount sets the threshold used for
+// usage.SinceLastLarge.
+func (r *Repository) Withdraw(
+	ctx context.Context,
+	w *models.Withdrawal,
+	largeAmount float64,
+	check func(balance float64, usage models.WithdrawalUsage) error,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = ledger.LockAccounts(ctx, tx, w.UserID); err != nil {
+		return err
+	}
+
+	balance, err := ledger.CurrentBalance(ctx, tx, w.UserID)
+	if err != nil {
+		return err
+	}
+
+	var (
+		usage          models.WithdrawalUsage
+		sinceLastLarge sql.NullFloat64
+	)
+	usageQuery := `
+		SELECT
+			COALESCE(SUM(amount) FILTER (WHERE created_at >= date_trunc('day', NOW())), 0),
+			COALESCE(SUM(amount) FILTER (WHERE created_at >= date_trunc('month', NOW())), 0),
+			EXTRACT(EPOCH FROM NOW() - MAX(created_at) FILTER (WHERE amount >= $2))
+		FROM withdrawals
+		WHERE user_id = $1
+	`
+	err = tx.QueryRowContext(ctx, usageQuery, w.UserID, largeAmount).Scan(&usage.Day, &usage.Month, &sinceLastLarge)
+	if err != nil {
+		return err
+	}
+	if sinceLastLarge.Valid {
+		usage.HasLarge = true
+		usage.SinceLastLarge = time.Duration(sinceLastLarge.Float64 * float64(time.Second))
+	}
+
+	if err = check(balance, usage); err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO withdrawals (user_id, order_number, amount)
 		VALUES ($1, $2, $3)
-		RETURNING id
 	`
-
-	_, err := r.db.ExecContext(
-		ctx,
-		query,
-		order.UserID,
-		order.OrderNumber,
-		order.Amount,
-	)
-	if err != nil {
+	if _, err = tx.ExecContext(ctx, query, w.UserID, w.OrderNumber, w.Amount); err != nil {
 		return err
 	}
 
-	return nil
+	return tx.Commit()
 }
 
 func (r *Repository) IsEntryByOrderNumber(ctx context.Context, orderNumber string) (bool, error) {
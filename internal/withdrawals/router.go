@@ -2,21 +2,24 @@ package withdrawals
 
 import (
 	"github.com/gofiber/fiber/v2"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
 )
 
 type UserMiddleware interface {
 	Auth(c *fiber.Ctx) error
+	AuthOrKey(scope string) fiber.Handler
 }
 
 func SetupRouter(
 	r fiber.Router,
 	mw UserMiddleware,
-	bs balanceService,
+	wp withdrawalPolicy,
 	wr withdrawalRepository,
 ) {
-	us := NewService(bs, wr)
+	us := NewService(wp, wr)
 	handle := newHandler(us)
 
-	r.Post("balance/withdraw", mw.Auth, handle.withdrawAccrual)
+	r.Post("balance/withdraw", mw.AuthOrKey(models.ScopeWithdraw), handle.withdrawAccrual)
 	r.Get("withdrawals", mw.Auth, handle.getAllWithdrawals)
 }
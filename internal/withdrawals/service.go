@@ -4,45 +4,50 @@ import (
 	"context"
 
 	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
-	appdto "github.com/dkmelnik/go-musthave-diploma/internal/dto"
 	"github.com/dkmelnik/go-musthave-diploma/internal/models"
 	"github.com/dkmelnik/go-musthave-diploma/internal/withdrawals/dto"
 )
 
 type (
 	withdrawalRepository interface {
-		Save(ctx context.Context, order *models.Withdrawal) error
+		Withdraw(
+			ctx context.Context,
+			w *models.Withdrawal,
+			largeAmount float64,
+			check func(balance float64, usage models.WithdrawalUsage) error,
+		) error
 		IsEntryByOrderNumber(ctx context.Context, orderNumber string) (bool, error)
 		Find(ctx context.Context, userID models.ModelID) ([]*models.Withdrawal, error)
 	}
-	balanceService interface {
-		GetCurrentBalance(ctx context.Context, userID models.ModelID) (appdto.Balance, error)
+	withdrawalPolicy interface {
+		Check(amount float64, usage models.WithdrawalUsage) error
+		LargeAmount() float64
 	}
 	Service struct {
-		balanceService       balanceService
+		policy               withdrawalPolicy
 		withdrawalRepository withdrawalRepository
 	}
 )
 
-func NewService(bs balanceService, ws withdrawalRepository) *Service {
-	return &Service{bs, ws}
+func NewService(p withdrawalPolicy, ws withdrawalRepository) *Service {
+	return &Service{p, ws}
 }
 
 func (s *Service) WithdrawAccrual(ctx context.Context, userID models.ModelID, d dto.WithdrawalPayload) error {
-
-	balance, err := s.balanceService.GetCurrentBalance(ctx, userID)
-	if err != nil {
-		return err
-	}
-
-	if balance.Current-d.Sum < 0 {
-		return apperrors.ErrInsufficientFunds
-	}
-
-	return s.withdrawalRepository.Save(ctx, &models.Withdrawal{
+	w := &models.Withdrawal{
 		UserID:      userID,
 		OrderNumber: d.Order,
 		Amount:      d.Sum,
+	}
+
+	return s.withdrawalRepository.Withdraw(ctx, w, s.policy.LargeAmount(), func(balance float64, usage models.WithdrawalUsage) error {
+		if err := s.policy.Check(d.Sum, usage); err != nil {
+			return err
+		}
+		if balance-d.Sum < 0 {
+			return apperrors.ErrInsufficientFunds
+		}
+		return nil
 	})
 }
 
@@ -0,0 +1,135 @@
+package withdrawals
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dkmelnik/go-musthave-diploma/internal/apperrors"
+	"github.com/dkmelnik/go-musthave-diploma/internal/models"
+	"github.com/dkmelnik/go-musthave-diploma/internal/withdrawals/dto"
+	"github.com/dkmelnik/go-musthave-diploma/internal/withdrawals/mocks"
+	"github.com/dkmelnik/go-musthave-diploma/internal/withdrawals/policy"
+)
+
+func TestService_WithdrawAccrual(t *testing.T) {
+	rules := policy.Rules{
+		MinAmount:   10,
+		MaxAmount:   1000,
+		DailyCap:    1500,
+		MonthlyCap:  3000,
+		Increment:   5,
+		LargeAmount: 500,
+		Cooldown:    policy.Duration(time.Hour),
+	}
+
+	tests := []struct {
+		name    string
+		sum     float64
+		balance float64
+		usage   models.WithdrawalUsage
+		code    string
+		want    error
+	}{
+		{
+			name:    "positive test #1, within balance and rules",
+			sum:     100,
+			balance: 500,
+		},
+		{
+			name:    "negative test #2, amount not positive",
+			sum:     0,
+			balance: 500,
+			code:    policy.CodeAmountNotPositive,
+		},
+		{
+			name:    "negative test #3, amount below the minimum",
+			sum:     5,
+			balance: 500,
+			code:    policy.CodeAmountBelowMin,
+		},
+		{
+			name:    "negative test #4, amount above the maximum",
+			sum:     1005,
+			balance: 5000,
+			code:    policy.CodeAmountAboveMax,
+		},
+		{
+			name:    "negative test #5, amount off the increment",
+			sum:     101,
+			balance: 500,
+			code:    policy.CodeInvalidIncrement,
+		},
+		{
+			name:    "negative test #6, daily cap exceeded",
+			sum:     100,
+			balance: 5000,
+			usage:   models.WithdrawalUsage{Day: 1450, Month: 1450},
+			code:    policy.CodeDailyCapExceeded,
+		},
+		{
+			name:    "negative test #7, monthly cap exceeded",
+			sum:     100,
+			balance: 5000,
+			usage:   models.WithdrawalUsage{Month: 2950},
+			code:    policy.CodeMonthlyCapExceeded,
+		},
+		{
+			name:    "negative test #8, cooldown after a large withdrawal",
+			sum:     100,
+			balance: 5000,
+			usage:   models.WithdrawalUsage{HasLarge: true, SinceLastLarge: 10 * time.Minute},
+			code:    policy.CodeCooldownActive,
+		},
+		{
+			name:    "negative test #9, insufficient funds",
+			sum:     100,
+			balance: 95,
+			want:    apperrors.ErrInsufficientFunds,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockwithdrawalRepository(ctrl)
+			repo.EXPECT().Withdraw(gomock.Any(), gomock.Any(), rules.LargeAmount, gomock.Any()).DoAndReturn(
+				func(_ context.Context, w *models.Withdrawal, _ float64, check func(float64, models.WithdrawalUsage) error) error {
+					assert.Equal(t, models.ModelID("user"), w.UserID)
+					assert.Equal(t, "12345678903", w.OrderNumber)
+					assert.Equal(t, tt.sum, w.Amount)
+					return check(tt.balance, tt.usage)
+				})
+
+			s := NewService(policy.New(rules), repo)
+			err := s.WithdrawAccrual(context.Background(), "user", dto.WithdrawalPayload{Order: "12345678903", Sum: tt.sum})
+
+			if tt.code != "" {
+				var violation *policy.Violation
+				require.True(t, errors.As(err, &violation), "want a policy violation, got %v", err)
+				assert.Equal(t, tt.code, violation.Code)
+				return
+			}
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestService_WithdrawAccrualRepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockwithdrawalRepository(ctrl)
+	repo.EXPECT().Withdraw(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(apperrors.ErrIsExist)
+
+	s := NewService(policy.New(policy.Rules{}), repo)
+	err := s.WithdrawAccrual(context.Background(), "user", dto.WithdrawalPayload{Order: "12345678903", Sum: 10})
+
+	assert.ErrorIs(t, err, apperrors.ErrIsExist)
+}